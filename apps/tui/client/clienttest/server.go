@@ -0,0 +1,269 @@
+// Package clienttest provides an in-process fake Brain MCP server for tests.
+//
+// The fake speaks the streamable HTTP transport the real server uses: it
+// serves /health and /mcp, issues an Mcp-Session-Id on initialize, rejects
+// requests for unknown sessions with 404, and frames responses as SSE. Tests
+// register scripted tool handlers and inspect the calls they received:
+//
+//	srv := clienttest.NewServer(t)
+//	srv.Handle("list_projects", func(map[string]any) (*client.ToolResult, error) {
+//		return clienttest.JSON([]string{"brain"}), nil
+//	})
+//	srv.Install() // client.EnsureServerRunning now connects to srv
+package clienttest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// ToolHandler answers a tools/call with the given arguments. Returning an
+// error answers with a JSON-RPC error instead of a tool result.
+type ToolHandler func(args map[string]any) (*client.ToolResult, error)
+
+// Call is one tools/call request received by the server.
+type Call struct {
+	Tool      string
+	Args      map[string]any
+	SessionID string
+}
+
+// Server is a fake Brain MCP server backed by httptest.
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	mu       sync.Mutex
+	session  string
+	sessions int
+	handlers map[string]ToolHandler
+	calls    []Call
+}
+
+// NewServer starts a fake server that is closed when the test ends. It also
+// points the client's runtime dir at a temporary directory, so saved session
+// IDs never touch the real one.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	s := &Server{t: t, handlers: map[string]ToolHandler{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle registers the handler for a tool, replacing any previous one.
+func (s *Server) Handle(tool string, h ToolHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[tool] = h
+}
+
+// Calls returns every tools/call received so far, in order.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the calls received for one tool.
+func (s *Server) CallsTo(tool string) []Call {
+	var calls []Call
+	for _, c := range s.Calls() {
+		if c.Tool == tool {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Expire forgets the current session, as a server restart would, so the next
+// request with the old Mcp-Session-Id is rejected.
+func (s *Server) Expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = ""
+}
+
+// Client returns a client connected to the server with an initialized session.
+func (s *Server) Client() *client.BrainClient {
+	s.t.Helper()
+	c, err := s.connect(context.Background())
+	if err != nil {
+		s.t.Fatalf("clienttest: %v", err)
+	}
+	return c
+}
+
+// Install makes client.EnsureServerRunning connect to this server until the
+// test ends.
+func (s *Server) Install() {
+	restore := client.SetConnectFunc(s.connect)
+	s.t.Cleanup(restore)
+}
+
+func (s *Server) connect(ctx context.Context) (*client.BrainClient, error) {
+	c := client.NewBrainClient(s.URL)
+	if err := c.InitializeContext(ctx); err != nil {
+		return nil, fmt.Errorf("🧠 failed to initialize session: %w", err)
+	}
+	return c, nil
+}
+
+// Text returns a tool result with a single text item.
+func Text(text string) *client.ToolResult {
+	return &client.ToolResult{Content: []client.ContentItem{{Type: "text", Text: text}}}
+}
+
+// JSON returns a tool result whose text is v encoded as JSON, the way the
+// server's tools report structured data.
+func JSON(v any) *client.ToolResult {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("clienttest.JSON: %v", err))
+	}
+	return Text(string(data))
+}
+
+// Error returns a tool result flagged isError with the given message.
+func Error(msg string) *client.ToolResult {
+	r := Text(msg)
+	r.IsError = true
+	return r
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/health" && r.Method == http.MethodGet:
+		s.serveHealth(w)
+	case r.URL.Path == "/mcp" && r.Method == http.MethodPost:
+		s.serveMCP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveHealth(w http.ResponseWriter) {
+	s.mu.Lock()
+	var sessions []client.Session
+	if s.session != "" {
+		sessions = append(sessions, client.Session{ID: s.session})
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(client.HealthStatus{
+		Status:       "ok",
+		Server:       "brain-fake",
+		SessionCount: len(sessions),
+		Sessions:     sessions,
+	})
+}
+
+func (s *Server) serveMCP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		ID     *int64          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	if req.Method == "initialize" {
+		s.sessions++
+		s.session = fmt.Sprintf("fake-session-%d", s.sessions)
+		sessionID = s.session
+	} else if sessionID == "" || sessionID != s.session {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32001,"message":"Session not found"}}`)
+		return
+	}
+	w.Header().Set("Mcp-Session-Id", sessionID)
+
+	if req.ID == nil { // notification
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	result, rpcErr := s.dispatch(req.Method, req.Params, sessionID)
+	msg := map[string]any{"jsonrpc": "2.0", "id": *req.ID}
+	if rpcErr != nil {
+		msg["error"] = rpcErr
+	} else {
+		msg["result"] = result
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+}
+
+// dispatch answers one request. The caller holds s.mu.
+func (s *Server) dispatch(method string, params json.RawMessage, sessionID string) (any, *client.RPCError) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": "2025-11-25",
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "brain-fake", "version": "0.0.0"},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		names := make([]string, 0, len(s.handlers))
+		for name := range s.handlers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		tools := make([]client.Tool, len(names))
+		for i, name := range names {
+			tools[i] = client.Tool{Name: name, InputSchema: json.RawMessage(`{"type":"object"}`)}
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		var p client.ToolCallParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &client.RPCError{Code: -32602, Message: err.Error()}
+		}
+		s.calls = append(s.calls, Call{Tool: p.Name, Args: p.Arguments, SessionID: sessionID})
+		h, ok := s.handlers[p.Name]
+		if !ok {
+			return nil, &client.RPCError{Code: -32602, Message: fmt.Sprintf("Tool %s not found", p.Name)}
+		}
+		// Handlers run without the lock so they may inspect the server.
+		s.mu.Unlock()
+		result, err := h(p.Arguments)
+		s.mu.Lock()
+		if err != nil {
+			return nil, &client.RPCError{Code: -32603, Message: err.Error()}
+		}
+		return result, nil
+	default:
+		return nil, &client.RPCError{Code: -32601, Message: "Method not found: " + method}
+	}
+}
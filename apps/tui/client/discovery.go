@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownTool is returned by GetTool when the server has no such tool.
+var ErrUnknownTool = errors.New("🧠 unknown tool")
+
+// Tool describes a tool exposed by the server, as returned by tools/list.
+type Tool struct {
+	Name         string           `json:"name"`
+	Title        string           `json:"title,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	InputSchema  json.RawMessage  `json:"inputSchema,omitempty"`
+	OutputSchema json.RawMessage  `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are the server's hints about a tool's behavior.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// Resource describes a resource exposed by the server, as returned by resources/list.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is one item returned by resources/read. Text resources set
+// Text; binary resources set Blob to base64-encoded data.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// Prompt describes a prompt template exposed by the server, as returned by prompts/list.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument is an argument accepted by a prompt template.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ListTools returns every tool the server exposes, following pagination.
+func (c *BrainClient) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	err := c.paginate(ctx, "tools/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		tools = append(tools, page.Tools...)
+		return page.NextCursor, nil
+	})
+	return tools, err
+}
+
+// GetTool returns the named tool from tools/list.
+func (c *BrainClient) GetTool(ctx context.Context, name string) (*Tool, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tools {
+		if tools[i].Name == name {
+			return &tools[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownTool, name)
+}
+
+// ListResources returns every resource the server exposes, following pagination.
+func (c *BrainClient) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	err := c.paginate(ctx, "resources/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Resources  []Resource `json:"resources"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		resources = append(resources, page.Resources...)
+		return page.NextCursor, nil
+	})
+	return resources, err
+}
+
+// ReadResource returns the contents of the resource at uri.
+func (c *BrainClient) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	var result struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	if err := c.request(ctx, "resources/read", map[string]interface{}{"uri": uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// ListPrompts returns every prompt the server exposes, following pagination.
+func (c *BrainClient) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	var prompts []Prompt
+	err := c.paginate(ctx, "prompts/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Prompts    []Prompt `json:"prompts"`
+			NextCursor string   `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		prompts = append(prompts, page.Prompts...)
+		return page.NextCursor, nil
+	})
+	return prompts, err
+}
+
+// paginate calls a */list method until the server stops returning a cursor.
+// addPage decodes one result and returns its nextCursor.
+func (c *BrainClient) paginate(ctx context.Context, method string, addPage func(json.RawMessage) (string, error)) error {
+	cursor := ""
+	for {
+		var params interface{} // omitted, not null, on the first page
+		if cursor != "" {
+			params = map[string]interface{}{"cursor": cursor}
+		}
+		var raw json.RawMessage
+		if err := c.request(ctx, method, params, &raw); err != nil {
+			return err
+		}
+		next, err := addPage(raw)
+		if err != nil {
+			return fmt.Errorf("🧠 failed to decode %s result: %w", method, err)
+		}
+		if next == "" || next == cursor {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// request sends a read-only MCP request and decodes its result into result.
+func (c *BrainClient) request(ctx context.Context, method string, params interface{}, result interface{}) error {
+	rpcResp, err := c.call(ctx, method, true, func(int64) interface{} { return params }, nil)
+	if err != nil {
+		return err
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("🧠 MCP error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("🧠 failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
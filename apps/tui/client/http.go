@@ -1,50 +1,82 @@
-// Package client provides an HTTP client for Brain MCP server communication.
+// Package client provides a client for Brain MCP server communication over
+// streamable HTTP or stdio.
 package client
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"os/exec"
-	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 )
 
-// PID file location for headless server management
-const pidFile = "/tmp/brain-mcp.pid"
-
-// Session file location for session persistence across CLI invocations
-const sessionFile = "/tmp/brain-session.id"
-
-// BrainClient manages HTTP connections to the Brain MCP server.
+// BrainClient speaks MCP to the Brain server over a Transport.
 type BrainClient struct {
-	baseURL    string
-	httpClient *http.Client
-	sessionID  string
-	requestID  int64
+	transport Transport
+	requestID int64
+	retry     RetryPolicy
+
+	initMu         sync.Mutex // serializes session recovery
+	persistSession bool       // save recovered sessions to the session file
+	tracer         Tracer     // records wire traffic; nil when tracing is off
 }
 
-// NewBrainClient creates a new client configured to connect to the Brain MCP server.
-// Timeout reduced from 10 minutes to 5 minutes for improved responsiveness.
+// DefaultCallTimeout bounds a request whose context carries no deadline of its own.
+// Reduced from 10 minutes to 5 minutes for improved responsiveness.
 // With batch API optimization, 700 notes complete in <2 minutes.
+const DefaultCallTimeout = 5 * time.Minute
+
+// cancelNotifyTimeout bounds the best-effort notifications/cancelled request
+// sent after a caller abandons an in-flight call.
+const cancelNotifyTimeout = 2 * time.Second
+
+// NewBrainClient creates a new client configured to connect to the Brain MCP server.
+// Deadlines are per call: pass a context with a deadline to the *Context methods,
+// otherwise DefaultCallTimeout applies.
 func NewBrainClient(baseURL string) *BrainClient {
-	return &BrainClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
-	}
+	return NewClientWithTransport(NewHTTPTransport(baseURL))
+}
+
+// NewClientWithTransport creates a client that sends requests over t.
+// Tracing follows BRAIN_TRACE; see SetTracer to override it.
+func NewClientWithTransport(t Transport) *BrainClient {
+	tracer, _ := TracerFromEnv()
+	return &BrainClient{transport: t, retry: DefaultRetryPolicy, tracer: tracer}
+}
+
+// SetRetryPolicy replaces DefaultRetryPolicy for this client.
+func (c *BrainClient) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+}
+
+// Transport returns the transport the client sends requests over.
+func (c *BrainClient) Transport() Transport {
+	return c.transport
+}
+
+// Close releases the transport. For stdio this stops the server process.
+func (c *BrainClient) Close() error {
+	return c.transport.Close()
 }
 
-// DefaultClient returns a client configured for localhost:8765
+// httpTransport returns the client's transport if it is streamable HTTP.
+// Session persistence only applies to HTTP: a stdio server lives and dies
+// with the client.
+func (c *BrainClient) httpTransport() (*HTTPTransport, bool) {
+	t, ok := c.transport.(*HTTPTransport)
+	return t, ok
+}
+
+// DefaultClient returns a client for the server address resolved by LoadLauncher,
+// falling back to 127.0.0.1:8765 when the launcher config cannot be read.
 func DefaultClient() *BrainClient {
-	return NewBrainClient("http://127.0.0.1:8765")
+	l, err := LoadLauncher()
+	if err != nil {
+		return NewBrainClient(fmt.Sprintf("http://%s:%d", DefaultHost, DefaultPort))
+	}
+	return l.Client()
 }
 
 // HealthStatus represents the server health response.
@@ -82,6 +114,13 @@ type RPCResponse struct {
 	Error   *RPCError       `json:"error,omitempty"`
 }
 
+// RPCNotification represents a JSON-RPC 2.0 notification (a request without an ID).
+type RPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 // RPCError represents a JSON-RPC error.
 type RPCError struct {
 	Code    int    `json:"code"`
@@ -135,6 +174,12 @@ func parseResponse(data []byte) (*RPCResponse, error) {
 type ToolCallParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
+}
+
+// RequestMeta carries MCP request metadata such as the progress token.
+type RequestMeta struct {
+	ProgressToken int64 `json:"progressToken,omitempty"`
 }
 
 // ToolResult represents the result of a tool call.
@@ -151,47 +196,57 @@ type ContentItem struct {
 
 // Health checks if the Brain MCP server is running and returns its status.
 func (c *BrainClient) Health() (*HealthStatus, error) {
-	resp, err := c.httpClient.Get(c.baseURL + "/health")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("🧠 health check failed: status %d", resp.StatusCode)
-	}
+	return c.HealthContext(context.Background())
+}
 
-	var status HealthStatus
-	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
-		return nil, fmt.Errorf("🧠 failed to decode health response: %w", err)
+// HealthContext is like Health but aborts when ctx is cancelled.
+func (c *BrainClient) HealthContext(ctx context.Context) (*HealthStatus, error) {
+	hc, ok := c.transport.(healthChecker)
+	if !ok {
+		return nil, fmt.Errorf("🧠 transport does not support health checks")
 	}
 
-	return &status, nil
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+	return hc.Health(ctx)
 }
 
 // IsRunning returns true if the server is responding to health checks.
 func (c *BrainClient) IsRunning() bool {
-	status, err := c.Health()
+	return c.isRunning(context.Background())
+}
+
+func (c *BrainClient) isRunning(ctx context.Context) bool {
+	status, err := c.HealthContext(ctx)
 	return err == nil && status.Status == "ok"
 }
 
-// loadSessionID loads a previously saved session ID from disk.
+// loadSessionID loads a previously saved session ID from the runtime dir.
 func loadSessionID() string {
-	data, err := os.ReadFile(sessionFile)
+	id, err := readRuntimeFile(sessionFileName)
 	if err != nil {
 		return ""
 	}
-	return strings.TrimSpace(string(data))
+	return id
 }
 
-// saveSessionID saves the session ID to disk for reuse.
+// saveSessionID saves the session ID to the runtime dir for reuse.
+// Persistence is best effort: without it the next invocation starts a new session.
 func saveSessionID(sessionID string) {
-	os.WriteFile(sessionFile, []byte(sessionID), 0644)
+	_ = writeRuntimeFile(sessionFileName, sessionID)
+}
+
+// clearSessionID removes the saved session if it is still the stale one, so
+// the next CLI invocation does not try it again.
+func clearSessionID(stale string) {
+	if loadSessionID() == stale {
+		removeRuntimeFile(sessionFileName)
+	}
 }
 
 // isValidSession checks if a session ID is recognized by the server.
-func (c *BrainClient) isValidSession(sessionID string) bool {
-	health, err := c.Health()
+func (c *BrainClient) isValidSession(ctx context.Context, sessionID string) bool {
+	health, err := c.HealthContext(ctx)
 	if err != nil {
 		return false
 	}
@@ -204,13 +259,35 @@ func (c *BrainClient) isValidSession(sessionID string) bool {
 }
 
 // Initialize sends the MCP initialize request and stores the session ID.
-// It uses session persistence to reuse sessions across CLI invocations.
+// It uses session persistence to reuse sessions across CLI invocations; if the
+// saved session later turns out to be stale, calls recover transparently.
 func (c *BrainClient) Initialize() error {
+	return c.InitializeContext(context.Background())
+}
+
+// InitializeContext is like Initialize but aborts when ctx is cancelled.
+func (c *BrainClient) InitializeContext(ctx context.Context) error {
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
+
 	// Try to reuse existing session from disk
-	savedSession := loadSessionID()
-	if savedSession != "" && c.isValidSession(savedSession) {
-		c.sessionID = savedSession
-		return nil
+	if ht, ok := c.httpTransport(); ok {
+		c.persistSession = true
+		savedSession := loadSessionID()
+		if savedSession != "" && c.isValidSession(ctx, savedSession) {
+			ht.SetSessionID(savedSession)
+			return nil
+		}
+	}
+	return c.initialize(ctx)
+}
+
+// initialize performs the MCP handshake, starting a new session. The caller
+// holds initMu.
+func (c *BrainClient) initialize(ctx context.Context) error {
+	ht, isHTTP := c.httpTransport()
+	if isHTTP {
+		ht.SetSessionID("")
 	}
 
 	params := map[string]interface{}{
@@ -222,44 +299,29 @@ func (c *BrainClient) Initialize() error {
 		"capabilities": map[string]interface{}{},
 	}
 
-	resp, err := c.sendRequest("initialize", params)
+	rpcResp, err := c.roundTrip(ctx, c.nextID(), "initialize", params, nil)
 	if err != nil {
 		return fmt.Errorf("🧠 initialize failed: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Store session ID from response header
-	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
-		c.sessionID = sid
-	}
-
-	// Read body to check for errors
-	body, _ := io.ReadAll(resp.Body)
-	jsonData := extractSSEData(string(body))
-
-	// If there's a response, check for errors
-	if jsonData != "" {
-		rpcResp, err := parseResponse([]byte(jsonData))
-		if err == nil && rpcResp.Error != nil {
-			// Check if it's a server error - try to get an existing session
-			if strings.Contains(rpcResp.Error.Message, "already initialized") ||
-				strings.Contains(rpcResp.Error.Message, "Internal server error") {
-				// Try to get a valid session from health endpoint
-				health, err := c.Health()
-				if err == nil && len(health.Sessions) > 0 {
-					// Use the most recent session
-					c.sessionID = health.Sessions[len(health.Sessions)-1].ID
-					saveSessionID(c.sessionID)
-					return nil
-				}
-			}
+	if rpcResp.Error != nil {
+		// A server that keeps a single MCP session refuses a second initialize
+		// but still names its session in the reply to this request. That session
+		// was assigned to us; anything else (e.g., a session listed by /health)
+		// belongs to another client and is never adopted.
+		alreadyInitialized := strings.Contains(rpcResp.Error.Message, "already initialized")
+		if !isHTTP || !alreadyInitialized || ht.SessionID() == "" {
 			return fmt.Errorf("🧠 initialize failed: %s", rpcResp.Error.Message)
 		}
 	}
 
+	if err := c.sendNotification(ctx, "notifications/initialized", nil); err != nil {
+		return fmt.Errorf("🧠 initialize failed: %w", err)
+	}
+
 	// Save session ID for future use
-	if c.sessionID != "" {
-		saveSessionID(c.sessionID)
+	if isHTTP && c.persistSession && ht.SessionID() != "" {
+		saveSessionID(ht.SessionID())
 	}
 
 	return nil
@@ -267,31 +329,39 @@ func (c *BrainClient) Initialize() error {
 
 // CallTool invokes an MCP tool and returns the raw result.
 func (c *BrainClient) CallTool(name string, args map[string]interface{}) (*ToolResult, error) {
-	params := ToolCallParams{
-		Name:      name,
-		Arguments: args,
-	}
-
-	resp, err := c.sendRequest("tools/call", params)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.CallToolContext(context.Background(), name, args)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("🧠 failed to read response: %w", err)
-	}
+// CallToolContext invokes an MCP tool, aborting the request when ctx is cancelled.
+func (c *BrainClient) CallToolContext(ctx context.Context, name string, args map[string]interface{}) (*ToolResult, error) {
+	return c.CallToolWithProgressContext(ctx, name, args, nil)
+}
 
-	// Parse SSE format: "event: message\ndata: {...}\n\n"
-	jsonData := extractSSEData(string(body))
-	if jsonData == "" {
-		return nil, fmt.Errorf("🧠 no data in SSE response")
-	}
+// CallToolWithProgress invokes an MCP tool and streams any progress
+// notifications the server sends to onProgress before returning the final result.
+// Long-running tools such as generate_embeddings report progress this way.
+func (c *BrainClient) CallToolWithProgress(name string, args map[string]interface{}, onProgress ProgressFunc) (*ToolResult, error) {
+	return c.CallToolWithProgressContext(context.Background(), name, args, onProgress)
+}
 
-	rpcResp, err := parseResponse([]byte(jsonData))
+// CallToolWithProgressContext combines CallToolContext and CallToolWithProgress.
+// When ctx is cancelled mid-call the server is sent notifications/cancelled for
+// the request so it can stop work.
+func (c *BrainClient) CallToolWithProgressContext(ctx context.Context, name string, args map[string]interface{}, onProgress ProgressFunc) (*ToolResult, error) {
+	rpcResp, err := c.call(ctx, "tools/call", IsIdempotentTool(name), func(id int64) interface{} {
+		params := ToolCallParams{
+			Name:      name,
+			Arguments: args,
+		}
+		if onProgress != nil {
+			// The request ID doubles as the progress token so notifications can be
+			// correlated without extra bookkeeping.
+			params.Meta = &RequestMeta{ProgressToken: id}
+		}
+		return params
+	}, onProgress)
 	if err != nil {
-		return nil, fmt.Errorf("🧠 failed to parse RPC response: %w", err)
+		return nil, err
 	}
 
 	if rpcResp.Error != nil {
@@ -309,21 +379,14 @@ func (c *BrainClient) CallTool(name string, args map[string]interface{}) (*ToolR
 	return &result, nil
 }
 
-// extractSSEData extracts JSON data from SSE format response
-func extractSSEData(body string) string {
-	lines := strings.Split(body, "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "data: ") {
-			return strings.TrimPrefix(line, "data: ")
-		}
-	}
-	// If no SSE format, assume it's raw JSON
-	return strings.TrimSpace(body)
-}
-
 // CallToolJSON calls a tool and unmarshals the result into the provided struct.
 func (c *BrainClient) CallToolJSON(name string, args map[string]interface{}, result interface{}) error {
-	toolResult, err := c.CallTool(name, args)
+	return c.CallToolJSONContext(context.Background(), name, args, result)
+}
+
+// CallToolJSONContext is like CallToolJSON but aborts when ctx is cancelled.
+func (c *BrainClient) CallToolJSONContext(ctx context.Context, name string, args map[string]interface{}, result interface{}) error {
+	toolResult, err := c.CallToolContext(ctx, name, args)
 	if err != nil {
 		return err
 	}
@@ -352,188 +415,82 @@ func (r *ToolResult) GetText() string {
 	return ""
 }
 
-// SessionID returns the current session ID (for debugging).
+// SessionID returns the current HTTP session ID (for debugging).
+// It is empty for stdio, which has no sessions.
 func (c *BrainClient) SessionID() string {
-	return c.sessionID
+	if ht, ok := c.httpTransport(); ok {
+		return ht.SessionID()
+	}
+	return ""
 }
 
-func (c *BrainClient) sendRequest(method string, params interface{}) (*http.Response, error) {
-	id := atomic.AddInt64(&c.requestID, 1)
+// nextID returns a fresh JSON-RPC request ID.
+func (c *BrainClient) nextID() int64 {
+	return atomic.AddInt64(&c.requestID, 1)
+}
+
+// withDefaultTimeout applies DefaultCallTimeout when ctx has no deadline.
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultCallTimeout)
+}
 
-	req := RPCRequest{
+// roundTrip sends a request and waits for the response with the same ID,
+// forwarding progress notifications along the way.
+// If ctx ends before the response arrives, the server is told to cancel the request.
+func (c *BrainClient) roundTrip(ctx context.Context, id int64, method string, params interface{}, onProgress ProgressFunc) (*RPCResponse, error) {
+	callCtx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	req := &RPCRequest{
 		JSONRPC: "2.0",
 		Method:  method,
 		ID:      id,
 		Params:  params,
 	}
-
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("🧠 failed to marshal request: %w", err)
+	start := time.Now()
+	rpcResp, err := c.transport.RoundTrip(callCtx, req, onProgress)
+	if c.tracer != nil {
+		c.traceExchange(start, req, rpcResp, err, err != nil && callCtx.Err() != nil)
 	}
-
-	httpReq, err := http.NewRequest("POST", c.baseURL+"/mcp", bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("🧠 failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json, text/event-stream")
-	if c.sessionID != "" {
-		httpReq.Header.Set("Mcp-Session-Id", c.sessionID)
-	}
-
-	return c.httpClient.Do(httpReq)
-}
-
-// EnsureServerRunning checks if server is running and starts it if not.
-// Returns the client ready to use.
-func EnsureServerRunning() (*BrainClient, error) {
-	client := DefaultClient()
-
-	// Check if already running
-	if client.IsRunning() {
-		if err := client.Initialize(); err != nil {
-			return nil, fmt.Errorf("🧠 failed to initialize session: %w", err)
-		}
-		return client, nil
-	}
-
-	// Start the server
-	fmt.Println("🧠 Starting Brain MCP server...")
-	bunPath := os.Getenv("HOME") + "/.bun/bin/bun"
-	serverPath := os.Getenv("HOME") + "/Dev/brain/apps/mcp/src/index.ts"
-
-	cmd := exec.Command(bunPath, "run", serverPath)
-	cmd.Env = append(os.Environ(), "BRAIN_TRANSPORT=http")
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("🧠 failed to start server: %w", err)
-	}
-
-	// Don't wait for it - let it run in background
-	go cmd.Wait()
-
-	// Wait for server to be ready (up to 60 seconds - may need to start Ollama)
-	for i := 0; i < 600; i++ {
-		time.Sleep(100 * time.Millisecond)
-		if client.IsRunning() {
-			if err := client.Initialize(); err != nil {
-				return nil, fmt.Errorf("🧠 failed to initialize session: %w", err)
-			}
-			fmt.Println("🧠 Server ready")
-			return client, nil
-		}
-		// Progress indicator every 5 seconds
-		if i > 0 && i%50 == 0 {
-			fmt.Printf("   Still starting... (%ds)\n", i/10)
+		if callCtx.Err() != nil {
+			c.cancelRequest(id, method, callCtx.Err())
+			return nil, fmt.Errorf("🧠 %s cancelled: %w", method, callCtx.Err())
 		}
+		return nil, err
 	}
-
-	return nil, fmt.Errorf("🧠 server failed to start within timeout")
+	return rpcResp, nil
 }
 
-// StartServer starts the MCP server in background and writes PID file.
-// For headless CLI usage (brain mcp start).
-func StartServer() error {
-	if DefaultClient().IsRunning() {
-		return fmt.Errorf("🧠 server already running")
+// cancelRequest tells the server to stop work on an abandoned request.
+// It is best effort: failures are ignored since the caller has already given up.
+// Per the MCP spec, initialize requests are never cancelled.
+func (c *BrainClient) cancelRequest(id int64, method string, cause error) {
+	if method == "initialize" {
+		return
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), cancelNotifyTimeout)
+	defer cancel()
 
-	bunPath := os.Getenv("HOME") + "/.bun/bin/bun"
-	serverPath := os.Getenv("HOME") + "/Dev/brain/apps/mcp/src/index.ts"
-
-	cmd := exec.Command(bunPath, "run", serverPath)
-	cmd.Env = append(os.Environ(), "BRAIN_TRANSPORT=http")
-
-	// Detach from terminal so it survives shell exit
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-	}
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("🧠 failed to start: %w", err)
-	}
-
-	// Write PID file
-	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", cmd.Process.Pid)), 0644); err != nil {
-		return fmt.Errorf("🧠 failed to write pid file: %w", err)
-	}
-
-	// Wait for server to be ready (up to 60 seconds - may need to start Ollama)
-	fmt.Println("🧠 Starting server (may take a moment if starting Ollama)...")
-	for i := 0; i < 600; i++ {
-		time.Sleep(100 * time.Millisecond)
-		if DefaultClient().IsRunning() {
-			fmt.Printf("🧠 Brain MCP started (PID %d)\n", cmd.Process.Pid)
-			return nil
-		}
-		// Progress indicator every 10 seconds
-		if i > 0 && i%100 == 0 {
-			fmt.Printf("   Still starting... (%ds)\n", i/10)
-		}
-	}
-
-	return fmt.Errorf("🧠 server failed to start within timeout")
-}
-
-// StopServer stops the MCP server using PID file or lsof fallback.
-func StopServer() error {
-	var stoppedPid int
-
-	// First try PID file
-	data, err := os.ReadFile(pidFile)
-	if err == nil {
-		pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
-		if pid > 0 {
-			if proc, err := os.FindProcess(pid); err == nil {
-				if err := proc.Signal(syscall.SIGTERM); err == nil {
-					os.Remove(pidFile)
-					time.Sleep(500 * time.Millisecond)
-					fmt.Printf("🧠 Brain MCP stopped (PID %d)\n", pid)
-					return nil
-				}
-			}
-		}
-	}
-
-	// Fallback: find process by port using lsof
-	cmd := exec.Command("lsof", "-ti", ":8765")
-	output, err := cmd.Output()
-	if err == nil && len(output) > 0 {
-		pid, _ := strconv.Atoi(strings.TrimSpace(string(output)))
-		if pid > 0 {
-			if proc, err := os.FindProcess(pid); err == nil {
-				if err := proc.Signal(syscall.SIGTERM); err == nil {
-					stoppedPid = pid
-				}
-			}
-		}
-	}
-
-	os.Remove(pidFile)
-
-	if stoppedPid > 0 {
-		time.Sleep(500 * time.Millisecond)
-		fmt.Printf("🧠 Brain MCP stopped (PID %d)\n", stoppedPid)
-		return nil
-	}
-
-	return fmt.Errorf("🧠 no running server found")
+	_ = c.sendNotification(ctx, "notifications/cancelled", map[string]interface{}{
+		"requestId": id,
+		"reason":    cause.Error(),
+	})
 }
 
-// ServerStatus returns current server status as a formatted string.
-func ServerStatus() (string, error) {
-	client := DefaultClient()
-	if !client.IsRunning() {
-		return "stopped", nil
-	}
-
-	health, err := client.Health()
-	if err != nil {
-		return "error", err
+// sendNotification sends a JSON-RPC notification over the transport.
+func (c *BrainClient) sendNotification(ctx context.Context, method string, params interface{}) error {
+	start := time.Now()
+	err := c.transport.Notify(ctx, &RPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if c.tracer != nil {
+		c.traceNotification(start, method, params, err)
 	}
-
-	return fmt.Sprintf("running (uptime: %.0fs, sessions: %d, memory: %dMB)",
-		health.Uptime, health.SessionCount, health.MemoryUsage/1024/1024), nil
+	return err
 }
@@ -0,0 +1,492 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/peterkloss/brain-tui/internal/installer"
+)
+
+// Defaults matching apps/mcp/src/config (BRAIN_HTTP_HOST / BRAIN_HTTP_PORT).
+const (
+	DefaultHost    = "127.0.0.1"
+	DefaultPort    = 8765
+	DefaultRuntime = "bun"
+)
+
+// DefaultReadyTimeout is how long Start waits for the server to answer health
+// checks. Startup may need to bring up Ollama, so this is generous.
+const DefaultReadyTimeout = 60 * time.Second
+
+// Environment variables that override the launcher config file.
+const (
+	EnvLauncherConfig = "BRAIN_MCP_LAUNCHER_CONFIG" // path to the launcher config file
+	EnvRuntime        = "BRAIN_MCP_RUNTIME"         // runtime binary (default: bun)
+	EnvEntry          = "BRAIN_MCP_ENTRY"           // server entry point
+	EnvHost           = "BRAIN_HTTP_HOST"           // shared with the server itself
+	EnvPort           = "BRAIN_HTTP_PORT"           // shared with the server itself
+)
+
+// mcpEntryPath is the server entry point relative to a Brain checkout or the
+// XDG data directory.
+var mcpEntryPath = filepath.Join("apps", "mcp", "src", "index.ts")
+
+// Launcher describes how to start the Brain MCP server as a background HTTP
+// process. It is resolved by LoadLauncher from, in increasing precedence:
+// built-in defaults, the launcher config file, and environment variables.
+//
+// Runtime is executed as: Runtime [run Entry] Args..., so a test can point
+// Runtime at any binary that serves /health and /mcp and leave Entry empty.
+type Launcher struct {
+	// Runtime is the binary that runs the server (e.g., "bun" or an absolute path).
+	Runtime string `json:"runtime,omitempty"`
+	// Entry is the server entry point passed as "run <entry>". Empty to omit.
+	Entry string `json:"entry,omitempty"`
+	// Args are extra arguments appended after the entry point.
+	Args []string `json:"args,omitempty"`
+	// Host and Port are where the server listens and the client connects.
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+	// Env holds extra environment variables for the server process.
+	Env map[string]string `json:"env,omitempty"`
+	// Dir is the working directory for the server process. Empty inherits ours.
+	Dir string `json:"dir,omitempty"`
+	// ReadyTimeout bounds how long Start waits for the server to become healthy.
+	ReadyTimeout time.Duration `json:"-"`
+}
+
+// LauncherConfigPath returns the launcher config file location:
+// $BRAIN_MCP_LAUNCHER_CONFIG, or ~/.config/brain/mcp-launcher.json.
+func LauncherConfigPath() string {
+	if p := os.Getenv(EnvLauncherConfig); p != "" {
+		return p
+	}
+	return filepath.Join(xdg.ConfigHome, "brain", "mcp-launcher.json")
+}
+
+// LoadLauncher resolves the launcher from defaults, the config file, and the
+// environment. A missing config file is not an error; a malformed one is.
+func LoadLauncher() (*Launcher, error) {
+	l := &Launcher{
+		Runtime:      DefaultRuntime,
+		Host:         DefaultHost,
+		Port:         DefaultPort,
+		ReadyTimeout: DefaultReadyTimeout,
+	}
+
+	path := LauncherConfigPath()
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, l); err != nil {
+			return nil, fmt.Errorf("🧠 parse launcher config %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("🧠 read launcher config: %w", err)
+	}
+
+	if v := os.Getenv(EnvRuntime); v != "" {
+		l.Runtime = v
+	}
+	if v := os.Getenv(EnvEntry); v != "" {
+		l.Entry = v
+	}
+	if v := os.Getenv(EnvHost); v != "" {
+		l.Host = v
+	}
+	if v := os.Getenv(EnvPort); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil || port <= 0 {
+			return nil, fmt.Errorf("🧠 invalid %s %q", EnvPort, v)
+		}
+		l.Port = port
+	}
+
+	if l.Entry == "" && l.Runtime == DefaultRuntime {
+		l.Entry = defaultEntry()
+	}
+	if l.Entry != "" {
+		entry, err := installer.ExpandHome(l.Entry)
+		if err != nil {
+			return nil, err
+		}
+		l.Entry = entry
+	}
+	return l, nil
+}
+
+// defaultEntry locates the server entry point: a Brain checkout containing the
+// current directory (development), then the XDG data directory (installed).
+func defaultEntry() string {
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			candidate := filepath.Join(dir, mcpEntryPath)
+			if _, err := os.Stat(filepath.Join(dir, installer.ConfigFile)); err == nil {
+				if _, err := os.Stat(candidate); err == nil {
+					return candidate
+				}
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	return filepath.Join(installer.DataDir(), mcpEntryPath)
+}
+
+// BaseURL returns the HTTP address the server listens on.
+func (l *Launcher) BaseURL() string {
+	return fmt.Sprintf("http://%s:%d", l.Host, l.Port)
+}
+
+// Client returns a BrainClient for this launcher's server address.
+func (l *Launcher) Client() *BrainClient {
+	return NewBrainClient(l.BaseURL())
+}
+
+// resolveRuntime finds the runtime binary on PATH, falling back to ~/.bun/bin
+// for bun since installers often do not add it to PATH for non-login shells.
+func (l *Launcher) resolveRuntime() (string, error) {
+	runtime, err := installer.ExpandHome(l.Runtime)
+	if err != nil {
+		return "", err
+	}
+	if path, err := exec.LookPath(runtime); err == nil {
+		return path, nil
+	}
+	if runtime == DefaultRuntime {
+		home, _ := os.UserHomeDir()
+		fallback := filepath.Join(home, ".bun", "bin", "bun")
+		if _, err := os.Stat(fallback); err == nil {
+			return fallback, nil
+		}
+	}
+	return "", fmt.Errorf("🧠 runtime %q not found (set %s or \"runtime\" in %s)", l.Runtime, EnvRuntime, LauncherConfigPath())
+}
+
+// Command builds the HTTP server command: Runtime [run Entry] Args..., with the
+// HTTP transport, host, port, and extra env applied on top of our environment.
+func (l *Launcher) Command() (*exec.Cmd, error) {
+	return l.command(TransportHTTP)
+}
+
+// command builds the server command for the given transport. Host and port
+// are only passed for HTTP.
+func (l *Launcher) command(transport string) (*exec.Cmd, error) {
+	runtime, err := l.resolveRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	if l.Entry != "" {
+		if _, err := os.Stat(l.Entry); err != nil {
+			return nil, fmt.Errorf("🧠 server entry point %s not found (set %s or \"entry\" in %s)", l.Entry, EnvEntry, LauncherConfigPath())
+		}
+		args = append(args, "run", l.Entry)
+	}
+	args = append(args, l.Args...)
+
+	cmd := exec.Command(runtime, args...)
+	cmd.Dir = l.Dir
+	cmd.Env = append(os.Environ(), "BRAIN_TRANSPORT="+transport)
+	if transport == TransportHTTP {
+		cmd.Env = append(cmd.Env,
+			EnvHost+"="+l.Host,
+			EnvPort+"="+strconv.Itoa(l.Port),
+		)
+	}
+	keys := make([]string, 0, len(l.Env))
+	for k := range l.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+l.Env[k])
+	}
+	return cmd, nil
+}
+
+// Start launches the server detached from the terminal so it survives shell
+// exit, records its PID, and waits until it answers health checks.
+// Progress messages are written to out.
+func (l *Launcher) Start(ctx context.Context, out io.Writer) (int, error) {
+	if l.Client().isRunning(ctx) {
+		return 0, fmt.Errorf("🧠 server already running")
+	}
+
+	cmd, err := l.Command()
+	if err != nil {
+		return 0, err
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("🧠 failed to start server: %w", err)
+	}
+	pid := cmd.Process.Pid
+
+	// Reap the child if it exits while we are still running; report early exit.
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	if err := writeRuntimeFile(pidFileName, strconv.Itoa(pid)); err != nil {
+		return pid, fmt.Errorf("🧠 failed to write pid file: %w", err)
+	}
+
+	if err := l.waitReady(ctx, out, exited); err != nil {
+		return pid, err
+	}
+	return pid, nil
+}
+
+// StartStdio launches the server as a child process speaking MCP over its
+// stdin and stdout, and returns a client for it. The server exits when the
+// client is closed. Server logs written to stderr go to errOut (nil discards).
+func (l *Launcher) StartStdio(errOut io.Writer) (*BrainClient, error) {
+	cmd, err := l.command(TransportStdio)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = errOut
+
+	t, err := StartStdioTransport(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithTransport(t), nil
+}
+
+// waitReady polls the health endpoint until the server answers, the process
+// exits, ReadyTimeout elapses, or ctx is cancelled.
+func (l *Launcher) waitReady(ctx context.Context, out io.Writer, exited <-chan error) error {
+	timeout := l.ReadyTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	c := l.Client()
+	start := time.Now()
+	lastReport := start
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("🧠 waiting for server: %w", ctx.Err())
+		case err := <-exited:
+			removeRuntimeFile(pidFileName)
+			if err == nil {
+				err = errors.New("exited")
+			}
+			return fmt.Errorf("🧠 server process stopped during startup: %w", err)
+		case <-deadline:
+			return fmt.Errorf("🧠 server failed to start within timeout")
+		case <-ticker.C:
+		}
+
+		if c.isRunning(ctx) {
+			return nil
+		}
+		// Progress indicator every 5 seconds
+		if time.Since(lastReport) >= 5*time.Second {
+			lastReport = time.Now()
+			fmt.Fprintf(out, "   Still starting... (%ds)\n", int(time.Since(start).Seconds()))
+		}
+	}
+}
+
+// EnsureServerRunning checks if server is running and starts it if not.
+// Returns the client ready to use.
+func EnsureServerRunning() (*BrainClient, error) {
+	return EnsureServerRunningContext(context.Background())
+}
+
+// EnsureServerRunningContext is like EnsureServerRunning but stops waiting for
+// the server when ctx is cancelled. Startup messages go to stderr so commands
+// whose stdout is consumed by hooks stay machine-readable.
+//
+// With BRAIN_MCP_TRANSPORT=stdio the server is started as a child process for
+// this client alone instead; the caller should Close the client when done.
+func EnsureServerRunningContext(ctx context.Context) (*BrainClient, error) {
+	connectMu.RLock()
+	fn := connect
+	connectMu.RUnlock()
+	return fn(ctx)
+}
+
+// ConnectFunc returns a client with an initialized session.
+type ConnectFunc func(ctx context.Context) (*BrainClient, error)
+
+var (
+	connectMu sync.RWMutex
+	connect   ConnectFunc = ensureServerRunning
+)
+
+// SetConnectFunc replaces how EnsureServerRunning obtains its client and
+// returns a function that restores the previous one. Tests use it to point
+// commands at an in-process server (see package clienttest).
+func SetConnectFunc(fn ConnectFunc) (restore func()) {
+	connectMu.Lock()
+	prev := connect
+	connect = fn
+	connectMu.Unlock()
+	return func() {
+		connectMu.Lock()
+		connect = prev
+		connectMu.Unlock()
+	}
+}
+
+// ensureServerRunning is the default ConnectFunc: it launches the configured
+// server when needed and initializes a session.
+func ensureServerRunning(ctx context.Context) (*BrainClient, error) {
+	kind, err := TransportKind()
+	if err != nil {
+		return nil, err
+	}
+	l, err := LoadLauncher()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == TransportStdio {
+		client, err := l.StartStdio(nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.InitializeContext(ctx); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("🧠 failed to initialize session: %w", err)
+		}
+		return client, nil
+	}
+
+	client := l.Client()
+
+	// Check if already running
+	if !client.isRunning(ctx) {
+		fmt.Fprintln(os.Stderr, "🧠 Starting Brain MCP server...")
+		if _, err := l.Start(ctx, os.Stderr); err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(os.Stderr, "🧠 Server ready")
+	}
+
+	if err := client.InitializeContext(ctx); err != nil {
+		return nil, fmt.Errorf("🧠 failed to initialize session: %w", err)
+	}
+	return client, nil
+}
+
+// StartServer starts the MCP server in background and writes PID file.
+// For headless CLI usage (brain mcp start).
+func StartServer() error {
+	l, err := LoadLauncher()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🧠 Starting server (may take a moment if starting Ollama)...")
+	pid, err := l.Start(context.Background(), os.Stdout)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🧠 Brain MCP started (PID %d)\n", pid)
+	return nil
+}
+
+// StopServer stops the MCP server using the PID file, falling back to the
+// process listening on the launcher port. Either way, only a process verified
+// by IsServerProcess is signalled.
+func StopServer() error {
+	l, err := LoadLauncher()
+	if err != nil {
+		return err
+	}
+
+	// First try PID file
+	if data, err := readRuntimeFile(pidFileName); err == nil {
+		pid, _ := strconv.Atoi(data)
+		if l.IsServerProcess(pid) {
+			if proc, err := os.FindProcess(pid); err == nil {
+				if err := proc.Signal(syscall.SIGTERM); err == nil {
+					removeRuntimeFile(pidFileName)
+					time.Sleep(500 * time.Millisecond)
+					fmt.Printf("🧠 Brain MCP stopped (PID %d)\n", pid)
+					return nil
+				}
+			}
+		}
+	}
+
+	// Fallback: find process by port using lsof
+	var stoppedPid int
+	cmd := exec.Command("lsof", "-ti", fmt.Sprintf(":%d", l.Port))
+	output, err := cmd.Output()
+	if err == nil {
+		for _, line := range strings.Fields(string(output)) {
+			pid, _ := strconv.Atoi(line)
+			if !l.IsServerProcess(pid) {
+				continue
+			}
+			if proc, err := os.FindProcess(pid); err == nil {
+				if err := proc.Signal(syscall.SIGTERM); err == nil {
+					stoppedPid = pid
+					break
+				}
+			}
+		}
+	}
+
+	removeRuntimeFile(pidFileName)
+
+	if stoppedPid > 0 {
+		time.Sleep(500 * time.Millisecond)
+		fmt.Printf("🧠 Brain MCP stopped (PID %d)\n", stoppedPid)
+		return nil
+	}
+
+	return fmt.Errorf("🧠 no running server found")
+}
+
+// RestartServer stops the server if it is running and starts it again.
+func RestartServer() error {
+	_ = StopServer() // Ignore error if not running
+	time.Sleep(500 * time.Millisecond)
+	return StartServer()
+}
+
+// ServerStatus returns current server status as a formatted string.
+func ServerStatus() (string, error) {
+	client := DefaultClient()
+	if !client.IsRunning() {
+		return "stopped", nil
+	}
+
+	health, err := client.Health()
+	if err != nil {
+		return "error", err
+	}
+
+	return fmt.Sprintf("running (uptime: %.0fs, sessions: %d, memory: %dMB)",
+		health.Uptime, health.SessionCount, health.MemoryUsage/1024/1024), nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// IsServerProcess reports whether pid is a Brain server started from this
+// launcher and owned by the current user. It checks the process command line
+// (/proc/<pid>/cmdline on Linux, ps elsewhere), so a stale or planted PID
+// never leads to signalling an unrelated process.
+func (l *Launcher) IsServerProcess(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	uid, args, err := processInfo(pid)
+	if err != nil || uid != os.Getuid() || len(args) == 0 {
+		return false
+	}
+	return l.matchesCommand(args)
+}
+
+// matchesCommand reports whether args look like Command's: the entry point
+// when there is one, otherwise the runtime followed by every launcher arg.
+func (l *Launcher) matchesCommand(args []string) bool {
+	if l.Entry != "" {
+		for _, arg := range args {
+			if arg == l.Entry {
+				return true
+			}
+		}
+		return false
+	}
+
+	if filepath.Base(args[0]) != filepath.Base(l.Runtime) {
+		return false
+	}
+	for _, want := range l.Args {
+		found := false
+		for _, arg := range args[1:] {
+			if arg == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// processInfo returns the owner and command line of a running process.
+func processInfo(pid int) (uid int, args []string, err error) {
+	if runtime.GOOS == "linux" {
+		procDir := filepath.Join("/proc", strconv.Itoa(pid))
+		info, err := os.Stat(procDir)
+		if err != nil {
+			return 0, nil, err
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return 0, nil, fmt.Errorf("🧠 cannot determine owner of process %d", pid)
+		}
+		data, err := os.ReadFile(filepath.Join(procDir, "cmdline"))
+		if err != nil {
+			return 0, nil, err
+		}
+		data = bytes.TrimRight(data, "\x00")
+		if len(data) == 0 {
+			return 0, nil, fmt.Errorf("🧠 process %d has no command line", pid)
+		}
+		return int(st.Uid), strings.Split(string(data), "\x00"), nil
+	}
+
+	// macOS has no /proc. ps joins arguments with spaces, which is enough to
+	// find the entry point or launcher args.
+	out, err := exec.Command("ps", "-o", "uid=,command=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0, nil, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return 0, nil, fmt.Errorf("🧠 process %d not found", pid)
+	}
+	uid, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	return uid, fields[1:], nil
+}
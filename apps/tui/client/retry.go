@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how BrainClient retries failed calls. Only idempotent
+// calls are retried after transport failures; a request rejected because the
+// session expired was never processed, so it is always resent once after the
+// session has been re-established.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. It doubles with each
+	// further attempt, up to MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy rides out a server restart of a few seconds.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    4 * time.Second,
+}
+
+// Backoff returns the delay before retry number attempt (starting at 1):
+// exponential in attempt, capped at MaxDelay, with the upper half jittered
+// so that concurrent clients do not retry in lockstep.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// idempotentTools are read-only tools that are safe to resend after a failure
+// whose outcome is unknown (connection reset, 5xx, broken stream).
+var idempotentTools = map[string]bool{
+	"analyze_project":           true,
+	"bootstrap_context":         true,
+	"build_context":             true,
+	"config_get":                true,
+	"find_duplicates":           true,
+	"get_project_details":       true,
+	"get_workflow":              true,
+	"list_directory":            true,
+	"list_features_by_priority": true,
+	"list_memory_projects":      true,
+	"list_projects":             true,
+	"list_workflows":            true,
+	"read_note":                 true,
+	"search":                    true,
+	"search_notes":              true,
+}
+
+// IsIdempotentTool reports whether the named tool is retried after transport
+// failures.
+func IsIdempotentTool(name string) bool {
+	return idempotentTools[name]
+}
+
+// isSessionError reports whether an MCP error means the server does not know
+// the client's session. The SDK uses -32001 for "Session not found".
+func isSessionError(e *RPCError) bool {
+	if e.Code == -32001 {
+		return true
+	}
+	msg := strings.ToLower(e.Message)
+	for _, s := range []string{"session not found", "unknown session", "invalid session", "session expired", "not initialized"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryable reports whether a failed idempotent call may succeed if resent:
+// connection failures, broken response streams, and server-side 5xx errors.
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// call sends a request, recovering an expired session once and retrying
+// idempotent requests with jittered exponential backoff. params is rebuilt for
+// each attempt since every attempt gets a fresh request ID.
+func (c *BrainClient) call(ctx context.Context, method string, idempotent bool, params func(id int64) interface{}, onProgress ProgressFunc) (*RPCResponse, error) {
+	policy := c.retry
+	recovered := false
+	for attempt := 1; ; attempt++ {
+		id := c.nextID()
+		staleSession := c.SessionID()
+		rpcResp, err := c.roundTrip(ctx, id, method, params(id), onProgress)
+		if err == nil && rpcResp.Error != nil && isSessionError(rpcResp.Error) {
+			err = fmt.Errorf("%w: %s", ErrSessionExpired, rpcResp.Error.Message)
+		}
+		if err == nil {
+			return rpcResp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		if errors.Is(err, ErrSessionExpired) && !recovered {
+			recovered = true
+			if rerr := c.recoverSession(ctx, staleSession); rerr != nil {
+				return nil, fmt.Errorf("🧠 session recovery failed: %w (after %v)", rerr, err)
+			}
+			attempt-- // the rejected request does not count as an attempt
+			continue
+		}
+
+		if !idempotent || !isRetryable(err) || attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+		select {
+		case <-time.After(policy.Backoff(attempt)):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("🧠 %s cancelled: %w", method, ctx.Err())
+		}
+	}
+}
+
+// recoverSession starts a new session after the server rejected stale.
+// Concurrent callers that hit the same expired session share one recovery:
+// whoever finds the session already replaced simply retries with it.
+func (c *BrainClient) recoverSession(ctx context.Context, stale string) error {
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
+
+	if current := c.SessionID(); current != "" && current != stale {
+		return nil
+	}
+	if c.persistSession && stale != "" {
+		clearSessionID(stale)
+	}
+	return c.initialize(ctx)
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Runtime file names, relative to RuntimeDir.
+const (
+	pidFileName     = "brain-mcp.pid"
+	sessionFileName = "brain-session.id"
+)
+
+// maxRuntimeFileSize bounds reads of runtime files, which hold a single ID.
+const maxRuntimeFileSize = 4096
+
+// RuntimeDir returns the per-user directory for the server PID file and the
+// saved session ID, creating it with mode 0700 if needed:
+// $XDG_RUNTIME_DIR/brain, or $TMPDIR/brain-<uid> when XDG_RUNTIME_DIR is unset.
+//
+// On a shared machine a fixed path under /tmp can be pre-created or symlinked
+// by another user, so the directory is refused if it is a symlink or owned by
+// someone else, and tightened to 0700 if group or others can access it.
+func RuntimeDir() (string, error) {
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("brain-%d", os.Getuid()))
+	// The XDG spec says relative paths must be ignored.
+	if xdgDir := os.Getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(xdgDir) {
+		dir = filepath.Join(xdgDir, "brain")
+	}
+
+	if err := os.Mkdir(dir, 0700); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("🧠 create runtime dir: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", fmt.Errorf("🧠 runtime dir: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("🧠 runtime dir %s is not a directory", dir)
+	}
+	if err := checkOwner(dir, info); err != nil {
+		return "", err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return "", fmt.Errorf("🧠 secure runtime dir: %w", err)
+		}
+	}
+	return dir, nil
+}
+
+// checkOwner fails unless info belongs to the current user.
+func checkOwner(path string, info os.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("🧠 %s is owned by uid %d, not the current user", path, st.Uid)
+	}
+	return nil
+}
+
+// runtimeFilePath returns the path of a runtime file inside RuntimeDir.
+func runtimeFilePath(name string) (string, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// readRuntimeFile returns the trimmed contents of a runtime file. Symlinks,
+// non-regular files, files owned by another user, and files writable by
+// group or others are rejected.
+func readRuntimeFile(name string) (string, error) {
+	path, err := runtimeFilePath(name)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := checkRuntimeFile(path, f); err != nil {
+		return "", err
+	}
+	if info, _ := f.Stat(); info.Mode().Perm()&0022 != 0 {
+		return "", fmt.Errorf("🧠 %s is writable by other users", path)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, maxRuntimeFileSize))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeRuntimeFile replaces the contents of a runtime file, creating it with
+// mode 0600. It refuses to follow a symlink or write a file it does not own.
+func writeRuntimeFile(name, value string) error {
+	path, err := runtimeFilePath(name)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|syscall.O_NOFOLLOW, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Check before truncating so a file we should not touch is left intact.
+	if err := checkRuntimeFile(path, f); err != nil {
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteString(value); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// removeRuntimeFile deletes a runtime file. A symlink is removed, not followed.
+func removeRuntimeFile(name string) {
+	if path, err := runtimeFilePath(name); err == nil {
+		os.Remove(path)
+	}
+}
+
+// checkRuntimeFile fails unless the open file is a regular file owned by the
+// current user.
+func checkRuntimeFile(path string, f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("🧠 %s is not a regular file", path)
+	}
+	return checkOwner(path, info)
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Progress represents an MCP notifications/progress update sent by the server
+// while a request is still in flight.
+type Progress struct {
+	Token    any     `json:"progressToken"`
+	Progress float64 `json:"progress"`
+	Total    float64 `json:"total,omitempty"`
+	Message  string  `json:"message,omitempty"`
+}
+
+// Percent returns progress as a percentage of Total, or -1 if Total is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return p.Progress / p.Total * 100
+}
+
+// ProgressFunc receives progress notifications for an in-flight request.
+// It is called synchronously from the goroutine reading the response stream.
+type ProgressFunc func(Progress)
+
+// ProgressChannel adapts a channel to a ProgressFunc. Sends never block:
+// updates are dropped when the channel is full so a slow reader cannot
+// stall the response stream.
+func ProgressChannel(ch chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// sseEvent is a single event parsed from a text/event-stream body.
+type sseEvent struct {
+	Event string
+	ID    string
+	Data  string
+}
+
+// readSSE parses a text/event-stream from r and calls fn for each complete event.
+// Multiple data lines within one event are joined with newlines, per the SSE spec.
+// Parsing stops early when fn returns done=true or an error.
+func readSSE(r io.Reader, fn func(ev sseEvent) (done bool, err error)) error {
+	scanner := bufio.NewScanner(r)
+	// Tool results (full note content, embeddings summaries) can be large.
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var ev sseEvent
+	var data []string
+	dispatch := func() (bool, error) {
+		if len(data) == 0 {
+			ev = sseEvent{}
+			return false, nil
+		}
+		ev.Data = strings.Join(data, "\n")
+		done, err := fn(ev)
+		ev = sseEvent{}
+		data = data[:0]
+		return done, err
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment / keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.Event = value
+		case "id":
+			ev.ID = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Flush a trailing event that was not terminated by a blank line.
+	_, err := dispatch()
+	return err
+}
+
+// rpcMessage is any JSON-RPC message that can appear on the response stream:
+// a response (ID set, no method), a notification (method, no ID), or a
+// server-initiated request (both).
+type rpcMessage struct {
+	ID     *int64          `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// readResponse consumes an HTTP response body and returns the JSON-RPC response
+// whose ID matches id. Progress notifications received before the response are
+// forwarded to onProgress (which may be nil). Both SSE and plain JSON bodies
+// are supported.
+func readResponse(resp *http.Response, id int64, onProgress ProgressFunc) (*RPCResponse, error) {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("🧠 failed to read response: %w", err)
+		}
+		data := strings.TrimSpace(string(body))
+		if data == "" {
+			return nil, fmt.Errorf("🧠 empty response body")
+		}
+		return parseResponse([]byte(data))
+	}
+
+	var result *RPCResponse
+	err := readSSE(resp.Body, func(ev sseEvent) (bool, error) {
+		if ev.Event != "" && ev.Event != "message" {
+			return false, nil
+		}
+		return handleMessage([]byte(ev.Data), id, onProgress, &result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to read SSE stream: %w", err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("🧠 no response for request %d in SSE stream", id)
+	}
+	return result, nil
+}
+
+// handleMessage dispatches one JSON-RPC message from a response stream.
+// It reports done=true once the response matching id has been stored in result.
+func handleMessage(data []byte, id int64, onProgress ProgressFunc, result **RPCResponse) (bool, error) {
+	var msg rpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		// Skip events that are not JSON-RPC messages rather than failing the call.
+		return false, nil
+	}
+
+	switch {
+	case msg.Method == "notifications/progress":
+		if onProgress != nil {
+			var p Progress
+			if err := json.Unmarshal(msg.Params, &p); err == nil {
+				onProgress(p)
+			}
+		}
+		return false, nil
+	case msg.Method != "":
+		// Other notifications and server requests are not handled by this client.
+		return false, nil
+	case msg.ID != nil && *msg.ID == id:
+		resp, err := parseResponse(data)
+		if err != nil {
+			return false, err
+		}
+		*result = resp
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// stdioCloseTimeout is how long Close waits for the server to exit after its
+// stdin is closed before killing it.
+const stdioCloseTimeout = 2 * time.Second
+
+// errTransportClosed is returned for calls made after Close.
+var errTransportClosed = errors.New("🧠 stdio transport closed")
+
+// StdioTransport runs the Brain MCP server as a child process and exchanges
+// newline-delimited JSON-RPC messages over its stdin and stdout. It needs no
+// listening port or long-lived daemon, which suits hooks running in CI.
+type StdioTransport struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex // serializes writes to stdin
+
+	mu      sync.Mutex
+	pending map[int64]*pendingCall
+	err     error // set once the read loop stops
+
+	done      chan struct{} // closed when the read loop stops
+	exited    chan struct{} // closed when the process has been reaped
+	closeOnce sync.Once
+}
+
+// pendingCall is a request awaiting its response from the server.
+type pendingCall struct {
+	resp       chan *RPCResponse
+	onProgress ProgressFunc
+}
+
+// StartStdioTransport starts cmd and speaks MCP over its stdin and stdout.
+// cmd must not have Stdin or Stdout set; its Stderr is left as configured.
+func StartStdioTransport(cmd *exec.Cmd) (*StdioTransport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to open server stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to open server stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("🧠 failed to start server: %w", err)
+	}
+
+	t := &StdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[int64]*pendingCall),
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+	go t.readLoop(stdout)
+	return t, nil
+}
+
+// Health reports ok while the server process is running. The server's HTTP
+// health endpoint is not available over stdio.
+func (t *StdioTransport) Health(ctx context.Context) (*HealthStatus, error) {
+	select {
+	case <-t.done:
+		return nil, t.readErr()
+	default:
+		return &HealthStatus{Status: "ok", Server: "stdio"}, nil
+	}
+}
+
+// RoundTrip writes req to the server and waits for the response with the same
+// ID, forwarding progress notifications for it to onProgress.
+func (t *StdioTransport) RoundTrip(ctx context.Context, req *RPCRequest, onProgress ProgressFunc) (*RPCResponse, error) {
+	call := &pendingCall{resp: make(chan *RPCResponse, 1), onProgress: onProgress}
+
+	t.mu.Lock()
+	if t.err != nil {
+		err := t.err
+		t.mu.Unlock()
+		return nil, err
+	}
+	t.pending[req.ID] = call
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, req.ID)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-call.resp:
+		return resp, nil
+	case <-t.done:
+		// The response may have been delivered just before the loop stopped.
+		select {
+		case resp := <-call.resp:
+			return resp, nil
+		default:
+		}
+		return nil, t.readErr()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Notify writes a notification to the server.
+func (t *StdioTransport) Notify(ctx context.Context, n *RPCNotification) error {
+	select {
+	case <-t.done:
+		return t.readErr()
+	default:
+	}
+	return t.write(n)
+}
+
+// Close closes the server's stdin, which tells a stdio MCP server to exit,
+// and kills the process if it does not exit promptly.
+func (t *StdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.writeMu.Lock()
+		t.stdin.Close()
+		t.writeMu.Unlock()
+
+		select {
+		case <-t.exited:
+		case <-time.After(stdioCloseTimeout):
+			t.cmd.Process.Kill()
+			<-t.exited
+		}
+	})
+	return nil
+}
+
+// write sends one message as a single line.
+func (t *StdioTransport) write(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("🧠 failed to marshal request: %w", err)
+	}
+	data = append(data, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(data); err != nil {
+		return fmt.Errorf("🧠 failed to write to server: %w", err)
+	}
+	return nil
+}
+
+// readErr returns why the read loop stopped.
+func (t *StdioTransport) readErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		return errTransportClosed
+	}
+	return t.err
+}
+
+// readLoop dispatches messages from the server's stdout until it closes, then
+// reaps the process and fails any calls still waiting.
+func (t *StdioTransport) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		t.dispatch(scanner.Bytes())
+	}
+	scanErr := scanner.Err()
+
+	waitErr := t.cmd.Wait()
+	close(t.exited)
+
+	err := errTransportClosed
+	switch {
+	case scanErr != nil:
+		err = fmt.Errorf("🧠 failed to read from server: %w", scanErr)
+	case waitErr != nil:
+		err = fmt.Errorf("🧠 server process stopped: %w", waitErr)
+	}
+
+	t.mu.Lock()
+	t.err = err
+	t.mu.Unlock()
+	close(t.done)
+}
+
+// dispatch routes one line from the server: responses to their pending call,
+// progress notifications to the call that asked for them, and server requests
+// to an immediate reply. Lines that are not JSON-RPC (stray logging) are skipped.
+func (t *StdioTransport) dispatch(line []byte) {
+	var msg rpcMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+
+	switch {
+	case msg.Method == "notifications/progress":
+		var p Progress
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return
+		}
+		if call := t.progressTarget(p.Token); call != nil && call.onProgress != nil {
+			call.onProgress(p)
+		}
+	case msg.Method != "" && msg.ID != nil:
+		t.replyToServer(*msg.ID, msg.Method)
+	case msg.Method != "":
+		// Other notifications are not handled by this client.
+	case msg.ID != nil:
+		resp, err := parseResponse(line)
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		call := t.pending[*msg.ID]
+		t.mu.Unlock()
+		if call != nil {
+			select {
+			case call.resp <- resp:
+			default: // duplicate response; the first one wins
+			}
+		}
+	}
+}
+
+// progressTarget finds the pending call for a progress token. BrainClient uses
+// the request ID as the token, so it arrives as a JSON number.
+func (t *StdioTransport) progressTarget(token any) *pendingCall {
+	id, ok := token.(float64)
+	if !ok {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pending[int64(id)]
+}
+
+// replyToServer answers a server-initiated request. Only ping is supported;
+// anything else gets method-not-found so the server does not wait forever.
+func (t *StdioTransport) replyToServer(id int64, method string) {
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if method == "ping" {
+		reply["result"] = map[string]interface{}{}
+	} else {
+		reply["error"] = RPCError{Code: -32601, Message: "method not found: " + method}
+	}
+	_ = t.write(reply)
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/clienttest"
+)
+
+func TestFakeServer_RecordsCallsAndRecoversSession(t *testing.T) {
+	srv := clienttest.NewServer(t)
+	srv.Handle("read_note", func(args map[string]any) (*client.ToolResult, error) {
+		return clienttest.Text("# " + args["identifier"].(string)), nil
+	})
+	c := srv.Client()
+	ctx := context.Background()
+
+	result, err := c.CallToolContext(ctx, "read_note", map[string]interface{}{"identifier": "ADR-001"})
+	if err != nil || result.GetText() != "# ADR-001" {
+		t.Fatalf("read_note = %+v, %v", result, err)
+	}
+
+	srv.Expire()
+	if _, err := c.CallToolContext(ctx, "read_note", map[string]interface{}{"identifier": "ADR-002"}); err != nil {
+		t.Fatalf("call after expiry: %v", err)
+	}
+
+	calls := srv.CallsTo("read_note")
+	if len(calls) != 2 || calls[0].SessionID == calls[1].SessionID {
+		t.Errorf("calls = %+v, want two calls on different sessions", calls)
+	}
+}
+
+func TestFakeServer_UnknownToolIsAnError(t *testing.T) {
+	srv := clienttest.NewServer(t)
+	if _, err := srv.Client().CallToolContext(context.Background(), "nope", nil); err == nil {
+		t.Error("expected an error for an unregistered tool")
+	}
+}
+
+func TestSetConnectFunc_RoutesEnsureServerRunning(t *testing.T) {
+	srv := clienttest.NewServer(t)
+	srv.Install()
+
+	c, err := client.EnsureServerRunning()
+	if err != nil {
+		t.Fatalf("EnsureServerRunning: %v", err)
+	}
+	defer c.Close()
+	if c.SessionID() != "fake-session-1" {
+		t.Errorf("session = %q, want the fake server's", c.SessionID())
+	}
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// blockingServer answers tools/call with an SSE stream that never completes and
+// reports any notifications/cancelled it receives on the returned channel.
+func blockingServer(t *testing.T) (*httptest.Server, <-chan map[string]any) {
+	t.Helper()
+	cancelled := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var msg struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		json.Unmarshal(body, &msg)
+
+		if msg.Method == "notifications/cancelled" {
+			cancelled <- msg.Params
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	return srv, cancelled
+}
+
+func TestCallToolContext_CancelSendsNotification(t *testing.T) {
+	srv, cancelled := blockingServer(t)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := client.NewBrainClient(srv.URL).CallToolContext(ctx, "generate_embeddings", map[string]interface{}{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	select {
+	case params := <-cancelled:
+		if id, _ := params["requestId"].(float64); id != 1 {
+			t.Errorf("cancelled requestId = %v, want 1", params["requestId"])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not receive notifications/cancelled")
+	}
+}
+
+func TestCallToolContext_Deadline(t *testing.T) {
+	srv, cancelled := blockingServer(t)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.NewBrainClient(srv.URL).CallToolContext(ctx, "search", map[string]interface{}{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call took %v, deadline not honoured", elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not receive notifications/cancelled")
+	}
+}
+
+func TestHealthContext_Cancelled(t *testing.T) {
+	srv, _ := blockingServer(t)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.NewBrainClient(srv.URL).HealthContext(ctx); err == nil {
+		t.Fatal("expected error from cancelled health check")
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// discoveryServer answers the MCP list/read methods. tools/list is split into
+// two pages to exercise cursor handling.
+func discoveryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     int64           `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.Unmarshal(body, &req)
+		var params struct {
+			Cursor string `json:"cursor"`
+			URI    string `json:"uri"`
+		}
+		json.Unmarshal(req.Params, &params)
+
+		var result any
+		switch {
+		case req.Method == "tools/list" && params.Cursor == "":
+			result = map[string]any{
+				"tools": []map[string]any{{
+					"name":        "search",
+					"description": "Search notes\nLonger text",
+					"inputSchema": map[string]any{"type": "object", "properties": map[string]any{"query": map[string]string{"type": "string"}}},
+				}},
+				"nextCursor": "page-2",
+			}
+		case req.Method == "tools/list" && params.Cursor == "page-2":
+			result = map[string]any{"tools": []map[string]any{{"name": "read_note", "annotations": map[string]any{"readOnlyHint": true}}}}
+		case req.Method == "resources/list":
+			result = map[string]any{"resources": []map[string]any{{"uri": "brain://guide", "name": "guide", "mimeType": "text/markdown"}}}
+		case req.Method == "resources/read" && params.URI == "brain://guide":
+			result = map[string]any{"contents": []map[string]any{{"uri": params.URI, "text": "# Guide"}}}
+		case req.Method == "prompts/list":
+			result = map[string]any{"prompts": []map[string]any{{"name": "plan", "arguments": []map[string]any{{"name": "goal", "required": true}}}}}
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32602,"message":"bad request %s"}}`, req.ID, req.Method)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestListTools_FollowsPagination(t *testing.T) {
+	c := client.NewBrainClient(discoveryServer(t).URL)
+
+	tools, err := c.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "search" || tools[1].Name != "read_note" {
+		t.Fatalf("tools = %+v, want search and read_note", tools)
+	}
+	if len(tools[0].InputSchema) == 0 {
+		t.Error("search input schema missing")
+	}
+	if a := tools[1].Annotations; a == nil || a.ReadOnlyHint == nil || !*a.ReadOnlyHint {
+		t.Errorf("read_note annotations = %+v, want readOnlyHint", a)
+	}
+}
+
+func TestGetTool(t *testing.T) {
+	c := client.NewBrainClient(discoveryServer(t).URL)
+
+	tool, err := c.GetTool(context.Background(), "read_note")
+	if err != nil || tool.Name != "read_note" {
+		t.Fatalf("GetTool = %+v, %v", tool, err)
+	}
+	if _, err := c.GetTool(context.Background(), "nope"); !errors.Is(err, client.ErrUnknownTool) {
+		t.Errorf("err = %v, want ErrUnknownTool", err)
+	}
+}
+
+func TestResourcesAndPrompts(t *testing.T) {
+	c := client.NewBrainClient(discoveryServer(t).URL)
+	ctx := context.Background()
+
+	resources, err := c.ListResources(ctx)
+	if err != nil || len(resources) != 1 || resources[0].URI != "brain://guide" {
+		t.Fatalf("ListResources = %+v, %v", resources, err)
+	}
+
+	contents, err := c.ReadResource(ctx, "brain://guide")
+	if err != nil || len(contents) != 1 || contents[0].Text != "# Guide" {
+		t.Fatalf("ReadResource = %+v, %v", contents, err)
+	}
+	if _, err := c.ReadResource(ctx, "brain://missing"); err == nil {
+		t.Error("expected MCP error for unknown resource")
+	}
+
+	prompts, err := c.ListPrompts(ctx)
+	if err != nil || len(prompts) != 1 || !prompts[0].Arguments[0].Required {
+		t.Fatalf("ListPrompts = %+v, %v", prompts, err)
+	}
+}
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// fakeServerEnv marks the re-executed test binary as a fake Brain MCP server.
+const fakeServerEnv = "BRAIN_FAKE_MCP_SERVER"
+
+// TestFakeServerProcess is not a real test: when re-executed by the launcher
+// with fakeServerEnv set, the test binary serves /health on BRAIN_HTTP_PORT.
+func TestFakeServerProcess(t *testing.T) {
+	if os.Getenv(fakeServerEnv) != "1" {
+		t.Skip("helper process for launcher tests")
+	}
+	if os.Getenv("BRAIN_TRANSPORT") != "http" {
+		fmt.Fprintln(os.Stderr, "BRAIN_TRANSPORT not set to http")
+		os.Exit(2)
+	}
+	addr := net.JoinHostPort(os.Getenv("BRAIN_HTTP_HOST"), os.Getenv("BRAIN_HTTP_PORT"))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"status": "ok", "server": "fake"})
+	})
+	http.ListenAndServe(addr, mux)
+	os.Exit(1)
+}
+
+// freePort returns a TCP port that was free at the time of the call.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// isolateRuntimeDir keeps PID and session files out of the real runtime dir.
+func isolateRuntimeDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	return filepath.Join(dir, "brain")
+}
+
+// writeLauncherConfig writes cfg to a temp file and points the launcher at it.
+func writeLauncherConfig(t *testing.T, cfg map[string]any) {
+	t.Helper()
+	isolateRuntimeDir(t)
+	path := filepath.Join(t.TempDir(), "mcp-launcher.json")
+	data, _ := json.Marshal(cfg)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv(client.EnvLauncherConfig, path)
+	for _, env := range []string{client.EnvRuntime, client.EnvEntry, client.EnvHost, client.EnvPort} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestLoadLauncher_Defaults(t *testing.T) {
+	t.Setenv(client.EnvLauncherConfig, filepath.Join(t.TempDir(), "missing.json"))
+	for _, env := range []string{client.EnvRuntime, client.EnvEntry, client.EnvHost, client.EnvPort} {
+		t.Setenv(env, "")
+	}
+
+	l, err := client.LoadLauncher()
+	if err != nil {
+		t.Fatalf("LoadLauncher: %v", err)
+	}
+	if l.Runtime != client.DefaultRuntime || l.Port != client.DefaultPort || l.Host != client.DefaultHost {
+		t.Errorf("defaults = %s %s:%d", l.Runtime, l.Host, l.Port)
+	}
+	if !strings.HasSuffix(l.Entry, filepath.Join("apps", "mcp", "src", "index.ts")) {
+		t.Errorf("entry = %q, want .../apps/mcp/src/index.ts", l.Entry)
+	}
+}
+
+func TestLoadLauncher_EnvOverridesConfig(t *testing.T) {
+	writeLauncherConfig(t, map[string]any{
+		"runtime": "node",
+		"entry":   "/opt/brain/server.js",
+		"port":    9000,
+		"env":     map[string]string{"BRAIN_LOG_LEVEL": "debug"},
+	})
+	t.Setenv(client.EnvPort, "9100")
+
+	l, err := client.LoadLauncher()
+	if err != nil {
+		t.Fatalf("LoadLauncher: %v", err)
+	}
+	if l.Runtime != "node" || l.Entry != "/opt/brain/server.js" {
+		t.Errorf("config not applied: %+v", l)
+	}
+	if l.Port != 9100 {
+		t.Errorf("port = %d, want env override 9100", l.Port)
+	}
+	if l.BaseURL() != "http://127.0.0.1:9100" {
+		t.Errorf("BaseURL = %s", l.BaseURL())
+	}
+}
+
+func TestLoadLauncher_InvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp-launcher.json")
+	os.WriteFile(path, []byte("{not json"), 0600)
+	t.Setenv(client.EnvLauncherConfig, path)
+
+	if _, err := client.LoadLauncher(); err == nil {
+		t.Fatal("expected error for malformed launcher config")
+	}
+}
+
+func TestLauncherCommand_MissingEntry(t *testing.T) {
+	l := &client.Launcher{Runtime: os.Args[0], Entry: "/nonexistent/index.ts", Host: "127.0.0.1", Port: 1}
+	if _, err := l.Command(); err == nil {
+		t.Fatal("expected error for missing entry point")
+	}
+}
+
+func TestLauncherStart_FakeServer(t *testing.T) {
+	port := freePort(t)
+	writeLauncherConfig(t, map[string]any{
+		"runtime": os.Args[0],
+		"args":    []string{"-test.run=^TestFakeServerProcess$"},
+		"port":    port,
+		"env":     map[string]string{fakeServerEnv: "1"},
+	})
+
+	l, err := client.LoadLauncher()
+	if err != nil {
+		t.Fatalf("LoadLauncher: %v", err)
+	}
+	l.ReadyTimeout = 10 * time.Second
+
+	pid, err := l.Start(context.Background(), io.Discard)
+	if pid > 0 {
+		defer syscall.Kill(pid, syscall.SIGKILL)
+	}
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	status, err := l.Client().Health()
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if status.Server != "fake" {
+		t.Errorf("server = %q, want fake", status.Server)
+	}
+
+	if _, err := l.Start(context.Background(), io.Discard); err == nil {
+		t.Error("expected error starting a second server on the same port")
+	}
+}
+
+func TestLauncherStart_ProcessExits(t *testing.T) {
+	isolateRuntimeDir(t)
+	l := &client.Launcher{
+		Runtime:      os.Args[0],
+		Args:         []string{"-test.run=^$"}, // runs no tests and exits
+		Host:         "127.0.0.1",
+		Port:         freePort(t),
+		ReadyTimeout: 10 * time.Second,
+	}
+
+	start := time.Now()
+	if _, err := l.Start(context.Background(), io.Discard); err == nil {
+		t.Fatal("expected error when the server process exits during startup")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Start did not notice the process exit promptly")
+	}
+}
@@ -0,0 +1,316 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// sessionServer is an httptest stand-in for a streamable HTTP MCP server that
+// issues one session per initialize and rejects requests for other sessions.
+// Tool calls are answered by toolFn, which may return a status to fail with.
+type sessionServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	session     string
+	initCount   int
+	toolCalls   int
+	healthCalls int
+	// expiredStatus is how an unknown session is rejected (404 or 400); zero
+	// rejects it with a JSON-RPC "Session not found" error instead.
+	expiredStatus int
+	// expiredError adds a JSON-RPC "Session not found" error to an
+	// expiredStatus response.
+	expiredError bool
+	// invalidParams rejects tool calls with 400 and a JSON-RPC invalid
+	// params error.
+	invalidParams bool
+	// initError, when set, fails initialize with this JSON-RPC error message.
+	initError string
+	toolFn    func(call int) (status int)
+}
+
+func newSessionServer(t *testing.T) *sessionServer {
+	t.Helper()
+	s := &sessionServer{expiredStatus: http.StatusNotFound}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *sessionServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/health" {
+		s.healthCalls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":   "ok",
+			"sessions": []map[string]string{{"id": "other-client-session"}},
+		})
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	var msg struct {
+		ID     int64  `json:"id"`
+		Method string `json:"method"`
+	}
+	json.Unmarshal(body, &msg)
+
+	switch {
+	case msg.Method == "initialize":
+		s.initCount++
+		if s.initError != "" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32600,"message":%q}}`, msg.ID, s.initError)
+			return
+		}
+		s.session = fmt.Sprintf("session-%d", s.initCount)
+		w.Header().Set("Mcp-Session-Id", s.session)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"protocolVersion":"2025-11-25"}}`, msg.ID)
+		return
+	case r.Header.Get("Mcp-Session-Id") != s.session || s.session == "":
+		if s.expiredStatus != 0 {
+			w.WriteHeader(s.expiredStatus)
+			if s.expiredError {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32001,"message":"Session not found"}}`, msg.ID)
+			}
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32001,"message":"Session not found"}}`, msg.ID)
+		return
+	case msg.Method == "notifications/initialized":
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	s.toolCalls++
+	if s.invalidParams {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32602,"message":"Invalid params: query is required"}}`, msg.ID)
+		return
+	}
+	if s.toolFn != nil {
+		if status := s.toolFn(s.toolCalls); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+	fmt.Fprint(w, toolResponse(msg.ID, "ok"))
+}
+
+func (s *sessionServer) counts() (inits, tools, health int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initCount, s.toolCalls, s.healthCalls
+}
+
+// expire simulates a server restart: the current session is forgotten.
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = "restarted"
+}
+
+// fastRetries keeps backoff short so tests run quickly.
+var fastRetries = client.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// newTestClient returns a client for srv holding a session the server has forgotten.
+func newTestClient(srv *sessionServer) *client.BrainClient {
+	c := client.NewBrainClient(srv.URL)
+	c.SetRetryPolicy(fastRetries)
+	c.Transport().(*client.HTTPTransport).SetSessionID("stale-session")
+	return c
+}
+
+func TestCallTool_RecoversExpiredSession(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		status    int
+		withError bool
+	}{
+		{"http 404", http.StatusNotFound, false},
+		{"http 400", http.StatusBadRequest, false},
+		{"http 400 with session error", http.StatusBadRequest, true},
+		{"mcp error", 0, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newSessionServer(t)
+			srv.expiredStatus = tc.status
+			srv.expiredError = tc.withError
+			c := newTestClient(srv)
+
+			result, err := c.CallTool("write_note", map[string]interface{}{})
+			if err != nil {
+				t.Fatalf("CallTool: %v", err)
+			}
+			if result.GetText() != "ok" {
+				t.Errorf("result = %q, want ok", result.GetText())
+			}
+			if c.SessionID() != "session-1" {
+				t.Errorf("session = %q, want the newly issued session-1", c.SessionID())
+			}
+
+			// A second restart is recovered from as well.
+			srv.expire()
+			if _, err := c.CallTool("search", map[string]interface{}{}); err != nil {
+				t.Fatalf("CallTool after second restart: %v", err)
+			}
+			if inits, tools, _ := srv.counts(); inits != 2 || tools != 2 {
+				t.Errorf("initialize calls = %d, tool calls = %d; want 2 and 2", inits, tools)
+			}
+		})
+	}
+}
+
+func TestCallTool_BadRequestKeepsSession(t *testing.T) {
+	srv := newSessionServer(t)
+	srv.expiredStatus = http.StatusBadRequest
+	c := newTestClient(srv)
+	if _, err := c.CallTool("search", map[string]interface{}{}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	srv.mu.Lock()
+	srv.invalidParams = true
+	srv.mu.Unlock()
+	_, err := c.CallTool("search", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "Invalid params: query is required") {
+		t.Fatalf("err = %v, want the server's invalid params error", err)
+	}
+	if errors.Is(err, client.ErrSessionExpired) {
+		t.Errorf("err = %v, want no session expiry", err)
+	}
+	if inits, _, _ := srv.counts(); inits != 1 || c.SessionID() != "session-1" {
+		t.Errorf("initialize calls = %d, session = %q; want the session kept", inits, c.SessionID())
+	}
+}
+
+func TestCallTool_ConcurrentRecoveryInitializesOnce(t *testing.T) {
+	srv := newSessionServer(t)
+	c := newTestClient(srv)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.CallTool("search", map[string]interface{}{}); err != nil {
+				t.Errorf("CallTool: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if inits, _, _ := srv.counts(); inits != 1 {
+		t.Errorf("initialize calls = %d, want 1", inits)
+	}
+}
+
+func TestCallTool_RetriesIdempotentTool(t *testing.T) {
+	srv := newSessionServer(t)
+	srv.toolFn = func(call int) int {
+		if call < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}
+	c := newTestClient(srv)
+
+	if _, err := c.CallTool("search", map[string]interface{}{}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if _, tools, _ := srv.counts(); tools != 3 {
+		t.Errorf("tool calls = %d, want 3", tools)
+	}
+}
+
+func TestCallTool_GivesUpAfterMaxAttempts(t *testing.T) {
+	srv := newSessionServer(t)
+	srv.toolFn = func(int) int { return http.StatusBadGateway }
+	c := newTestClient(srv)
+
+	_, err := c.CallTool("search", map[string]interface{}{})
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("err = %v, want StatusError 502", err)
+	}
+	if _, tools, _ := srv.counts(); tools != fastRetries.MaxAttempts {
+		t.Errorf("tool calls = %d, want %d", tools, fastRetries.MaxAttempts)
+	}
+}
+
+func TestCallTool_DoesNotRetryNonIdempotentTool(t *testing.T) {
+	srv := newSessionServer(t)
+	srv.toolFn = func(int) int { return http.StatusServiceUnavailable }
+	c := newTestClient(srv)
+
+	if _, err := c.CallTool("write_note", map[string]interface{}{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, tools, _ := srv.counts(); tools != 1 {
+		t.Errorf("tool calls = %d, want 1 (write_note is not idempotent)", tools)
+	}
+}
+
+func TestCallTool_BackoffHonoursCancellation(t *testing.T) {
+	srv := newSessionServer(t)
+	srv.toolFn = func(int) int { return http.StatusServiceUnavailable }
+	c := newTestClient(srv)
+	c.SetRetryPolicy(client.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.CallToolContext(ctx, "search", map[string]interface{}{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("call took %v, backoff ignored the deadline", elapsed)
+	}
+}
+
+func TestInitialize_NeverAdoptsAnotherSession(t *testing.T) {
+	srv := newSessionServer(t)
+	srv.initError = "Server already initialized"
+	c := client.NewBrainClient(srv.URL)
+
+	if _, err := c.CallTool("search", map[string]interface{}{}); err == nil {
+		t.Fatal("expected error when the server refuses to initialize")
+	}
+	if c.SessionID() == "other-client-session" {
+		t.Error("client adopted a session listed by /health")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := client.RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, max := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		for i := 0; i < 50; i++ {
+			d := p.Backoff(attempt)
+			if d < max/2 || d > max {
+				t.Fatalf("Backoff(%d) = %v, want within [%v, %v]", attempt, d, max/2, max)
+			}
+		}
+	}
+}
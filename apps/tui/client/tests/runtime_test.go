@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+func TestRuntimeDir_UsesXDGRuntimeDir(t *testing.T) {
+	want := isolateRuntimeDir(t)
+
+	dir, err := client.RuntimeDir()
+	if err != nil {
+		t.Fatalf("RuntimeDir: %v", err)
+	}
+	if dir != want {
+		t.Errorf("RuntimeDir = %s, want %s", dir, want)
+	}
+	assertMode(t, dir, 0700)
+}
+
+func TestRuntimeDir_FallbackIsPerUser(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("TMPDIR", tmp)
+
+	dir, err := client.RuntimeDir()
+	if err != nil {
+		t.Fatalf("RuntimeDir: %v", err)
+	}
+	if want := filepath.Join(tmp, "brain-"+strconv.Itoa(os.Getuid())); dir != want {
+		t.Errorf("RuntimeDir = %s, want %s", dir, want)
+	}
+	assertMode(t, dir, 0700)
+}
+
+func TestRuntimeDir_RejectsSymlink(t *testing.T) {
+	dir := isolateRuntimeDir(t)
+	if err := os.Symlink(t.TempDir(), dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RuntimeDir(); err == nil {
+		t.Fatal("expected error for a symlinked runtime dir")
+	}
+}
+
+func TestRuntimeDir_TightensPermissions(t *testing.T) {
+	dir := isolateRuntimeDir(t)
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RuntimeDir(); err != nil {
+		t.Fatalf("RuntimeDir: %v", err)
+	}
+	assertMode(t, dir, 0700)
+}
+
+func TestInitialize_SavesSessionPrivately(t *testing.T) {
+	dir := isolateRuntimeDir(t)
+	srv := newSessionServer(t)
+
+	if err := client.NewBrainClient(srv.URL).Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	path := filepath.Join(dir, "brain-session.id")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("session file: %v", err)
+	}
+	if string(data) != "session-1" {
+		t.Errorf("saved session = %q, want session-1", data)
+	}
+	assertMode(t, path, 0600)
+}
+
+func TestInitialize_DoesNotFollowSessionSymlink(t *testing.T) {
+	dir := isolateRuntimeDir(t)
+	if _, err := client.RuntimeDir(); err != nil {
+		t.Fatal(err)
+	}
+	// Another user plants a link to a file naming their session.
+	target := filepath.Join(t.TempDir(), "planted")
+	os.WriteFile(target, []byte("other-client-session"), 0644)
+	if err := os.Symlink(target, filepath.Join(dir, "brain-session.id")); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newSessionServer(t)
+	c := client.NewBrainClient(srv.URL)
+	if err := c.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	if c.SessionID() != "session-1" {
+		t.Errorf("session = %q, want a new session rather than the planted one", c.SessionID())
+	}
+	if data, _ := os.ReadFile(target); string(data) != "other-client-session" {
+		t.Errorf("symlink target overwritten with %q", data)
+	}
+}
+
+func TestStopServer_IgnoresUnrelatedPID(t *testing.T) {
+	writeLauncherConfig(t, map[string]any{
+		"runtime": os.Args[0],
+		"args":    []string{"-test.run=^TestFakeServerProcess$"},
+		"port":    freePort(t),
+	})
+
+	bystander := exec.Command("sleep", "30")
+	if err := bystander.Start(); err != nil {
+		t.Skipf("sleep unavailable: %v", err)
+	}
+	defer bystander.Process.Kill()
+
+	dir, _ := client.RuntimeDir()
+	os.WriteFile(filepath.Join(dir, "brain-mcp.pid"), []byte(strconv.Itoa(bystander.Process.Pid)), 0600)
+
+	if err := client.StopServer(); err == nil {
+		t.Error("expected no server to be found")
+	}
+	if err := bystander.Process.Signal(syscall.Signal(0)); err != nil {
+		t.Errorf("unrelated process was signalled: %v", err)
+	}
+}
+
+func TestStopServer_StopsVerifiedServer(t *testing.T) {
+	writeLauncherConfig(t, map[string]any{
+		"runtime": os.Args[0],
+		"args":    []string{"-test.run=^TestFakeServerProcess$"},
+		"port":    freePort(t),
+		"env":     map[string]string{fakeServerEnv: "1"},
+	})
+	l, err := client.LoadLauncher()
+	if err != nil {
+		t.Fatalf("LoadLauncher: %v", err)
+	}
+	l.ReadyTimeout = 10 * time.Second
+
+	pid, err := l.Start(context.Background(), io.Discard)
+	if pid > 0 {
+		defer syscall.Kill(pid, syscall.SIGKILL)
+	}
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	dir, _ := client.RuntimeDir()
+	assertMode(t, filepath.Join(dir, "brain-mcp.pid"), 0600)
+	if !l.IsServerProcess(pid) {
+		t.Fatalf("IsServerProcess(%d) = false for the launched server", pid)
+	}
+	if l.IsServerProcess(os.Getpid()) {
+		t.Error("IsServerProcess = true for the test process itself")
+	}
+
+	if err := client.StopServer(); err != nil {
+		t.Fatalf("StopServer: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for syscall.Kill(pid, 0) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("server still running after StopServer")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// assertMode fails unless path has exactly the given permission bits.
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("%s mode = %o, want %o", path, got, want)
+	}
+}
@@ -0,0 +1,135 @@
+// Package tests provides tests for the Brain MCP client.
+//
+// Located at client/tests following the layout used by cmd/tests and
+// internal/installer/tests.
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// decodeRequest reads the JSON-RPC request ID and progress token from a tools/call body.
+func decodeRequest(t *testing.T, r *http.Request) (id int64, token int64) {
+	t.Helper()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("read request: %v", err)
+	}
+	var req struct {
+		ID     int64 `json:"id"`
+		Params struct {
+			Meta struct {
+				ProgressToken int64 `json:"progressToken"`
+			} `json:"_meta"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	return req.ID, req.Params.Meta.ProgressToken
+}
+
+func toolResponse(id int64, text string) string {
+	result, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"result": map[string]any{
+			"content": []map[string]string{{"type": "text", "text": text}},
+		},
+	})
+	return string(result)
+}
+
+func TestCallToolWithProgress_StreamsNotifications(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, token := decodeRequest(t, r)
+		if token != id {
+			t.Errorf("progress token = %d, want request id %d", token, id)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{\"progressToken\":%d,\"progress\":%d,\"total\":3,\"message\":\"batch %d\"}}\n\n", token, i, i)
+		}
+		// A response for a different request must be ignored.
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", toolResponse(id+100, "wrong"))
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", toolResponse(id, "done"))
+	}))
+	defer srv.Close()
+
+	var updates []client.Progress
+	c := client.NewBrainClient(srv.URL)
+	result, err := c.CallToolWithProgress("generate_embeddings", map[string]interface{}{}, func(p client.Progress) {
+		updates = append(updates, p)
+	})
+	if err != nil {
+		t.Fatalf("CallToolWithProgress: %v", err)
+	}
+	if got := result.GetText(); got != "done" {
+		t.Errorf("result text = %q, want %q", got, "done")
+	}
+	if len(updates) != 3 {
+		t.Fatalf("got %d progress updates, want 3", len(updates))
+	}
+	if updates[2].Message != "batch 3" || updates[2].Percent() != 100 {
+		t.Errorf("last update = %+v, want batch 3 at 100%%", updates[2])
+	}
+}
+
+func TestCallTool_MultiLineData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := decodeRequest(t, r)
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		// Split the JSON payload across several data lines.
+		payload := strings.Replace(toolResponse(id, "split"), ",", ",\ndata: ", 1)
+		fmt.Fprintf(w, ": keep-alive\nevent: message\ndata: %s\n\n", payload)
+	}))
+	defer srv.Close()
+
+	result, err := client.NewBrainClient(srv.URL).CallTool("search", map[string]interface{}{"query": "x"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := result.GetText(); got != "split" {
+		t.Errorf("result text = %q, want %q", got, "split")
+	}
+}
+
+func TestCallTool_PlainJSONResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, token := decodeRequest(t, r)
+		if token != 0 {
+			t.Errorf("progress token sent without a progress callback: %d", token)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, toolResponse(id, "plain"))
+	}))
+	defer srv.Close()
+
+	result, err := client.NewBrainClient(srv.URL).CallTool("search", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := result.GetText(); got != "plain" {
+		t.Errorf("result text = %q, want %q", got, "plain")
+	}
+}
+
+func TestCallTool_StreamWithoutResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{\"progressToken\":1,\"progress\":1}}\n\n")
+	}))
+	defer srv.Close()
+
+	if _, err := client.NewBrainClient(srv.URL).CallTool("search", map[string]interface{}{}); err == nil {
+		t.Fatal("expected error when stream ends without a matching response")
+	}
+}
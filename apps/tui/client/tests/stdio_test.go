@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// fakeStdioServerEnv marks the re-executed test binary as a fake stdio server.
+const fakeStdioServerEnv = "BRAIN_FAKE_STDIO_SERVER"
+
+// TestFakeStdioServerProcess is not a real test: when re-executed with
+// fakeStdioServerEnv set, the test binary answers newline-delimited JSON-RPC
+// on stdin/stdout. The "crash" tool makes it exit without replying.
+func TestFakeStdioServerProcess(t *testing.T) {
+	if os.Getenv(fakeStdioServerEnv) != "1" {
+		t.Skip("helper process for stdio tests")
+	}
+	if os.Getenv("BRAIN_TRANSPORT") != "stdio" {
+		fmt.Fprintln(os.Stderr, "BRAIN_TRANSPORT not set to stdio")
+		os.Exit(2)
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	initialized := false
+	fmt.Println("server starting") // stray log line the client must skip
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var msg struct {
+			ID     *int64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			os.Exit(3)
+		}
+
+		switch msg.Method {
+		case "initialize":
+			out.Encode(map[string]any{"jsonrpc": "2.0", "id": *msg.ID, "result": map[string]any{
+				"protocolVersion": "2025-11-25",
+				"serverInfo":      map[string]string{"name": "fake", "version": "0"},
+			}})
+		case "notifications/initialized":
+			initialized = true
+		case "tools/call":
+			var params struct {
+				Name string `json:"name"`
+				Meta struct {
+					ProgressToken int64 `json:"progressToken"`
+				} `json:"_meta"`
+			}
+			json.Unmarshal(msg.Params, &params)
+			if params.Name == "crash" {
+				os.Exit(1)
+			}
+			// A server-initiated ping must be answered by the client.
+			out.Encode(map[string]any{"jsonrpc": "2.0", "id": 900, "method": "ping"})
+			if params.Meta.ProgressToken != 0 {
+				out.Encode(map[string]any{"jsonrpc": "2.0", "method": "notifications/progress", "params": map[string]any{
+					"progressToken": params.Meta.ProgressToken, "progress": 1, "total": 2,
+				}})
+			}
+			text, _ := json.Marshal(map[string]any{"tool": params.Name, "initialized": initialized})
+			out.Encode(map[string]any{"jsonrpc": "2.0", "id": *msg.ID, "result": map[string]any{
+				"content": []map[string]string{{"type": "text", "text": string(text)}},
+			}})
+		}
+	}
+	os.Exit(0)
+}
+
+// stdioLauncher returns a launcher that runs the fake stdio server.
+func stdioLauncher() *client.Launcher {
+	return &client.Launcher{
+		Runtime: os.Args[0],
+		Args:    []string{"-test.run=^TestFakeStdioServerProcess$"},
+		Env:     map[string]string{fakeStdioServerEnv: "1"},
+	}
+}
+
+func TestStdioTransport_CallTool(t *testing.T) {
+	c, err := stdioLauncher().StartStdio(nil)
+	if err != nil {
+		t.Fatalf("StartStdio: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.InitializeContext(ctx); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if c.SessionID() != "" {
+		t.Errorf("SessionID = %q, want empty for stdio", c.SessionID())
+	}
+
+	var progress []client.Progress
+	result, err := c.CallToolWithProgressContext(ctx, "search", map[string]interface{}{"query": "x"}, func(p client.Progress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if want := `{"initialized":true,"tool":"search"}`; result.GetText() != want {
+		t.Errorf("result = %s, want %s", result.GetText(), want)
+	}
+	if len(progress) != 1 || progress[0].Percent() != 50 {
+		t.Errorf("progress = %+v, want one update at 50%%", progress)
+	}
+
+	var out struct {
+		Tool string `json:"tool"`
+	}
+	if err := c.CallToolJSONContext(ctx, "list_projects", map[string]interface{}{}, &out); err != nil {
+		t.Fatalf("CallToolJSON: %v", err)
+	}
+	if out.Tool != "list_projects" {
+		t.Errorf("tool = %q, want list_projects", out.Tool)
+	}
+}
+
+func TestStdioTransport_ProcessExit(t *testing.T) {
+	c, err := stdioLauncher().StartStdio(nil)
+	if err != nil {
+		t.Fatalf("StartStdio: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.CallToolContext(ctx, "crash", map[string]interface{}{}); err == nil {
+		t.Fatal("expected error when the server exits mid-call")
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("call waited for the deadline instead of noticing the exit")
+	}
+
+	if c.IsRunning() {
+		t.Error("IsRunning = true after the server exited")
+	}
+	if _, err := c.CallToolContext(ctx, "search", map[string]interface{}{}); err == nil {
+		t.Error("expected error calling a stopped server")
+	}
+}
+
+func TestEnsureServerRunning_StdioTransport(t *testing.T) {
+	l := stdioLauncher()
+	writeLauncherConfig(t, map[string]any{"runtime": l.Runtime, "args": l.Args, "env": l.Env})
+	t.Setenv(client.EnvTransport, client.TransportStdio)
+
+	c, err := client.EnsureServerRunningContext(context.Background())
+	if err != nil {
+		t.Fatalf("EnsureServerRunning: %v", err)
+	}
+	defer c.Close()
+
+	if _, ok := c.Transport().(*client.StdioTransport); !ok {
+		t.Fatalf("transport = %T, want *client.StdioTransport", c.Transport())
+	}
+	if _, err := c.CallTool("search", map[string]interface{}{}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+}
+
+func TestTransportKind(t *testing.T) {
+	for env, want := range map[string]string{"": client.TransportHTTP, "HTTP": client.TransportHTTP, " stdio ": client.TransportStdio} {
+		t.Setenv(client.EnvTransport, env)
+		got, err := client.TransportKind()
+		if err != nil || got != want {
+			t.Errorf("TransportKind(%q) = %q, %v; want %q", env, got, err, want)
+		}
+	}
+
+	t.Setenv(client.EnvTransport, "websocket")
+	if _, err := client.TransportKind(); err == nil {
+		t.Error("expected error for unknown transport")
+	}
+}
+
+func TestLauncherStartStdio_MissingEntry(t *testing.T) {
+	l := &client.Launcher{Runtime: os.Args[0], Entry: filepath.Join(t.TempDir(), "index.ts")}
+	if _, err := l.StartStdio(nil); err == nil {
+		t.Fatal("expected error for missing entry point")
+	}
+}
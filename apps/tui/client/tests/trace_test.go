@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/clienttest"
+)
+
+// traceLines decodes the JSON lines a WriterTracer wrote.
+func traceLines(t *testing.T, buf *bytes.Buffer) []client.TraceRecord {
+	t.Helper()
+	var records []client.TraceRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec client.TraceRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("bad trace line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestTracer_RecordsToolCalls(t *testing.T) {
+	srv := clienttest.NewServer(t)
+	srv.Handle("read_note", func(map[string]any) (*client.ToolResult, error) {
+		return clienttest.Text(strings.Repeat("x", 5000)), nil
+	})
+	srv.Handle("write_note", func(map[string]any) (*client.ToolResult, error) {
+		return clienttest.Error("permission denied"), nil
+	})
+	c := srv.Client()
+	var buf bytes.Buffer
+	c.SetTracer(client.NewWriterTracer(&buf))
+	ctx := context.Background()
+
+	c.CallToolContext(ctx, "read_note", map[string]interface{}{"identifier": "ADR-001"})
+	c.CallToolContext(ctx, "write_note", nil)
+	c.CallToolContext(ctx, "missing", nil)
+
+	records := traceLines(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	read := records[0]
+	if read.Method != "tools/call" || read.Tool != "read_note" || read.ID == 0 || read.Session != "fake-session-1" || read.Status != "ok" {
+		t.Errorf("read_note record = %+v", read)
+	}
+	if !strings.Contains(read.Request, `"identifier":"ADR-001"`) {
+		t.Errorf("request body = %q", read.Request)
+	}
+	if len(read.Response) > 2100 || !strings.Contains(read.Response, "bytes)") {
+		t.Errorf("response not truncated: %d bytes", len(read.Response))
+	}
+	if records[1].Status != "tool_error" {
+		t.Errorf("write_note status = %q, want tool_error", records[1].Status)
+	}
+	if records[2].Status != "rpc_error" || !strings.Contains(records[2].Error, "not found") {
+		t.Errorf("missing tool record = %+v", records[2])
+	}
+}
+
+func TestTracerFromEnv(t *testing.T) {
+	for value, wantNil := range map[string]bool{"": true, "0": true, "stderr": false, "file": false, "1": false} {
+		t.Setenv(client.EnvTrace, value)
+		tracer, err := client.TracerFromEnv()
+		if err != nil || (tracer == nil) != wantNil {
+			t.Errorf("BRAIN_TRACE=%q: tracer = %v, err = %v", value, tracer, err)
+		}
+	}
+	t.Setenv(client.EnvTrace, "syslog")
+	if _, err := client.TracerFromEnv(); err == nil {
+		t.Error("expected error for unknown BRAIN_TRACE value")
+	}
+}
+
+func TestFileTracer_RotatesAndReadsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	// Start just under the rotation size so the next record rotates.
+	if err := os.WriteFile(path, bytes.Repeat([]byte("\n"), 5<<20-10), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := client.NewFileTracer(path)
+	tracer.Trace(client.TraceRecord{Method: "tools/call", Tool: "search", Status: "ok"})
+	tracer.Trace(client.TraceRecord{Method: "tools/list", Status: "ok"})
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("no rotated file: %v", err)
+	}
+	assertMode(t, path, 0600)
+
+	records, err := client.ReadTraceRecords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Tool != "search" || records[1].Method != "tools/list" {
+		t.Errorf("records = %+v", records)
+	}
+}
@@ -0,0 +1,89 @@
+// Command gen writes the typed tool bindings (tools_gen.go) from the tool
+// schemas embedded in packages/validation.
+//
+// With -from-server it reads the schemas from a running server's tools/list
+// instead, which is useful for spotting tools or arguments the embedded
+// schemas are missing. The checked-in file is always generated from the
+// embedded schemas; the parity test in client/tools/tests enforces that.
+//
+// Usage:
+//
+//	go run ./gen -o tools_gen.go
+//	go run ./gen -from-server -o -
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/tools/internal/toolgen"
+	"github.com/peterkloss/brain/packages/validation"
+)
+
+// EmbeddedSource names the embedded schemas in the generated file header.
+const EmbeddedSource = "packages/validation/schemas/tools"
+
+func main() {
+	out := flag.String("o", "tools_gen.go", `output file ("-" for stdout)`)
+	fromServer := flag.Bool("from-server", false, "read schemas from the running server's tools/list")
+	flag.Parse()
+
+	if err := run(*out, *fromServer); err != nil {
+		fmt.Fprintf(os.Stderr, "gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, fromServer bool) error {
+	source := EmbeddedSource
+	var schemas []toolgen.ToolSchema
+	var err error
+	if fromServer {
+		source = "tools/list"
+		schemas, err = serverSchemas(context.Background())
+	} else {
+		schemas, err = toolgen.FromFS(validation.ToolSchemas())
+	}
+	if err != nil {
+		return err
+	}
+
+	code, err := toolgen.Generate(source, schemas)
+	if err != nil {
+		return err
+	}
+	if out == "-" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(out, code, 0644)
+}
+
+// serverSchemas fetches every tool's input schema from the running server.
+func serverSchemas(ctx context.Context) ([]toolgen.ToolSchema, error) {
+	brainClient, err := client.EnsureServerRunningContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer brainClient.Close()
+
+	tools, err := brainClient.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]toolgen.ToolSchema, 0, len(tools))
+	for _, t := range tools {
+		input := &toolgen.Schema{}
+		if len(t.InputSchema) > 0 {
+			if err := json.Unmarshal(t.InputSchema, input); err != nil {
+				return nil, fmt.Errorf("tool %s: %w", t.Name, err)
+			}
+		}
+		schemas = append(schemas, toolgen.ToolSchema{Name: t.Name, Description: t.Description, Input: input})
+	}
+	return schemas, nil
+}
@@ -0,0 +1,340 @@
+// Package toolgen generates the typed tool bindings in client/tools from MCP
+// tool input schemas, either the embedded schemas in packages/validation or
+// the schemas a running server reports via tools/list.
+package toolgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ResultTypes maps tool names to the hand-written type (in client/tools) that
+// the tool's text content decodes into. Tools not listed return the raw
+// *client.ToolResult.
+var ResultTypes = map[string]string{
+	"search": "SearchResponse",
+}
+
+// Schema is the subset of JSON Schema the generator understands.
+type Schema struct {
+	Type        any                `json:"type"`
+	Description string             `json:"description"`
+	Enum        []any              `json:"enum"`
+	Items       *Schema            `json:"items"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+
+	order []string // property names in declaration order
+}
+
+// UnmarshalJSON decodes a schema, remembering the order of its properties so
+// generated fields follow the schema rather than the alphabet.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	var raw struct {
+		plain
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = Schema(raw.plain)
+	if len(raw.Properties) == 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw.Properties))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+	s.Properties = map[string]*Schema{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+		prop := &Schema{}
+		if err := dec.Decode(prop); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+		s.Properties[name] = prop
+		s.order = append(s.order, name)
+	}
+	return nil
+}
+
+// ToolSchema is one tool's name, description and input schema.
+type ToolSchema struct {
+	Name        string
+	Description string
+	Input       *Schema
+}
+
+var (
+	toolNamePattern     = regexp.MustCompile(`^Schema for ([a-z_]+) tool arguments`)
+	schemaDescPrefix    = regexp.MustCompile(`^Schema for [^.]*? tool arguments\.\s*`)
+	schemaFileSuffix    = ".schema.json"
+	commentWidth        = 76
+	initialisms         = map[string]bool{"api": true, "id": true, "json": true, "mcp": true, "pr": true, "uri": true, "url": true}
+	pointerWhenOptional = map[string]bool{"int": true, "float64": true, "bool": true}
+)
+
+// FromFS reads every *.schema.json file under fsys. The tool name comes from
+// the schema description ("Schema for <tool> tool arguments"), falling back
+// to the file name with dashes turned into underscores.
+func FromFS(fsys fs.FS) ([]ToolSchema, error) {
+	var tools []ToolSchema
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, schemaFileSuffix) {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		input := &Schema{}
+		if err := json.Unmarshal(data, input); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		name := strings.ReplaceAll(strings.TrimSuffix(path.Base(p), schemaFileSuffix), "-", "_")
+		if m := toolNamePattern.FindStringSubmatch(input.Description); m != nil {
+			name = m[1]
+		}
+		tools = append(tools, ToolSchema{
+			Name:        name,
+			Description: schemaDescPrefix.ReplaceAllString(input.Description, ""),
+			Input:       input,
+		})
+		return nil
+	})
+	return tools, err
+}
+
+// Generate renders the bindings for tools as gofmt'd Go source for package
+// tools. source names where the schemas came from, for the file header.
+func Generate(source string, tools []ToolSchema) ([]byte, error) {
+	tools = append([]ToolSchema(nil), tools...)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	seen := map[string]bool{}
+	needsClient := false
+	for _, t := range tools {
+		if seen[GoName(t.Name)] {
+			return nil, fmt.Errorf("tool %s: duplicate Go name %s", t.Name, GoName(t.Name))
+		}
+		seen[GoName(t.Name)] = true
+		if ResultTypes[t.Name] == "" {
+			needsClient = true
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by toolgen from %s; DO NOT EDIT.\n\n", source)
+	b.WriteString("package tools\n\n")
+	b.WriteString("import (\n\t\"context\"\n")
+	if needsClient {
+		b.WriteString("\n\t\"github.com/peterkloss/brain-tui/client\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// Tool names.\nconst (\n")
+	for _, t := range tools {
+		fmt.Fprintf(&b, "\tTool%s = %q\n", GoName(t.Name), t.Name)
+	}
+	b.WriteString(")\n")
+
+	for _, t := range tools {
+		if err := writeTool(&b, t); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", t.Name, err)
+		}
+	}
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return out, nil
+}
+
+// writeTool emits a tool's enum types, argument struct and method.
+func writeTool(b *bytes.Buffer, t ToolSchema) error {
+	method := GoName(t.Name)
+	argsType := method + "Args"
+	input := t.Input
+	if input == nil {
+		input = &Schema{}
+	}
+	required := map[string]bool{}
+	for _, name := range input.Required {
+		required[name] = true
+	}
+
+	var fields bytes.Buffer
+	for _, name := range input.order {
+		prop := input.Properties[name]
+		goType, err := fieldType(b, t.Name, name, prop)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+			if pointerWhenOptional[goType] {
+				goType = "*" + goType
+			}
+		}
+		writeComment(&fields, "\t", prop.Description)
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", GoName(name), goType, tag)
+	}
+
+	fmt.Fprintf(b, "\n// %s are the arguments of the %s tool.\n", argsType, t.Name)
+	if fields.Len() == 0 {
+		fmt.Fprintf(b, "type %s struct{}\n", argsType)
+	} else {
+		fmt.Fprintf(b, "type %s struct {\n%s}\n", argsType, fields.String())
+	}
+
+	fmt.Fprintf(b, "\n// %s calls the %s tool.\n", method, t.Name)
+	if t.Description != "" {
+		b.WriteString("//\n")
+		writeComment(b, "", t.Description)
+	}
+	if result := ResultTypes[t.Name]; result != "" {
+		fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context, args %s) (*%s, error) {\n", method, argsType, result)
+		fmt.Fprintf(b, "\tvar result %s\n", result)
+		fmt.Fprintf(b, "\tif err := c.callJSON(ctx, Tool%s, args, &result); err != nil {\n\t\treturn nil, err\n\t}\n", method)
+		b.WriteString("\treturn &result, nil\n}\n")
+	} else {
+		fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context, args %s) (*client.ToolResult, error) {\n", method, argsType)
+		fmt.Fprintf(b, "\treturn c.call(ctx, Tool%s, args)\n}\n", method)
+	}
+	return nil
+}
+
+// fieldType returns the Go type for a property, emitting a named string type
+// with constants first when the property is a string enum.
+func fieldType(b *bytes.Buffer, tool, name string, prop *Schema) (string, error) {
+	if prop == nil {
+		return "any", nil
+	}
+	switch typeName(prop) {
+	case "string":
+		if len(prop.Enum) == 0 {
+			return "string", nil
+		}
+		enumType := GoName(tool) + GoName(name)
+		fmt.Fprintf(b, "\n// %s is the %s argument of the %s tool.\n", enumType, name, tool)
+		fmt.Fprintf(b, "type %s string\n\nconst (\n", enumType)
+		for _, v := range prop.Enum {
+			s, ok := v.(string)
+			if !ok {
+				return "", fmt.Errorf("non-string enum value %v", v)
+			}
+			fmt.Fprintf(b, "\t%s%s %s = %q\n", enumType, GoName(s), enumType, s)
+		}
+		b.WriteString(")\n")
+		return enumType, nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "object":
+		return "map[string]any", nil
+	case "array":
+		if prop.Items == nil {
+			return "[]any", nil
+		}
+		elem, err := fieldType(b, tool, name+"_item", prop.Items)
+		return "[]" + elem, err
+	default:
+		return "any", nil
+	}
+}
+
+// typeName returns the schema's single non-null type, or "" when the type is
+// missing or ambiguous (oneOf, several types).
+func typeName(s *Schema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		var types []string
+		for _, v := range t {
+			if v, ok := v.(string); ok && v != "null" {
+				types = append(types, v)
+			}
+		}
+		if len(types) == 1 {
+			return types[0]
+		}
+	}
+	return ""
+}
+
+// GoName converts a snake_case, kebab-case or camelCase identifier to an
+// exported Go name, e.g. "run_id" -> "RunID", "lastKnownGood" -> "LastKnownGood".
+func GoName(s string) string {
+	var out strings.Builder
+	for _, word := range splitWords(s) {
+		word = strings.ToLower(word)
+		if initialisms[word] {
+			out.WriteString(strings.ToUpper(word))
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		out.WriteString(string(r))
+	}
+	return out.String()
+}
+
+// splitWords splits on separators and on lower-to-upper case changes. An
+// all-caps word such as "DEFAULT" stays whole; an initialism followed by a
+// word, as in "RunIDList", splits before the last capital.
+func splitWords(s string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == ' ' || r == '.' }) {
+		r := []rune(part)
+		start := 0
+		for i := 1; i < len(r); i++ {
+			lowerToUpper := unicode.IsLower(r[i-1]) && unicode.IsUpper(r[i])
+			acronymEnd := i+1 < len(r) && unicode.IsUpper(r[i-1]) && unicode.IsUpper(r[i]) && unicode.IsLower(r[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(r[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(r[start:]))
+	}
+	return words
+}
+
+// writeComment writes text as a // comment wrapped at commentWidth.
+func writeComment(b *bytes.Buffer, indent, text string) {
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > commentWidth {
+			fmt.Fprintf(b, "%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
@@ -0,0 +1,21 @@
+package tools
+
+// SearchResult is one note returned by the search tool.
+type SearchResult struct {
+	Permalink       string  `json:"permalink"`
+	Title           string  `json:"title"`
+	SimilarityScore float64 `json:"similarity_score"`
+	Snippet         string  `json:"snippet"`
+	Source          string  `json:"source"`                // semantic, keyword or related
+	Depth           int     `json:"depth,omitempty"`       // 0 = direct match, 1+ = related via wikilinks
+	FullContent     string  `json:"fullContent,omitempty"` // set when full_context is requested
+}
+
+// SearchResponse is the search tool's result.
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+	Total   int            `json:"total"`
+	Query   string         `json:"query"`
+	Mode    string         `json:"mode"`
+	Depth   int            `json:"depth"`
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/tools"
+	"github.com/peterkloss/brain-tui/client/tools/internal/toolgen"
+	"github.com/peterkloss/brain/packages/validation"
+)
+
+// TestGeneratedBindingsMatchSchemas fails when tools_gen.go is out of date
+// with the embedded tool schemas. Run go generate ./client/tools to fix it.
+func TestGeneratedBindingsMatchSchemas(t *testing.T) {
+	schemas, err := toolgen.FromFS(validation.ToolSchemas())
+	if err != nil {
+		t.Fatalf("FromFS: %v", err)
+	}
+	want, err := toolgen.Generate("packages/validation/schemas/tools", schemas)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got, err := os.ReadFile("../tools_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("tools_gen.go is out of date with packages/validation/schemas/tools; run go generate ./client/tools")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for in, want := range map[string]string{
+		"run_id":                    "RunID",
+		"sessionId":                 "SessionID",
+		"lastKnownGood":             "LastKnownGood",
+		"DEFAULT":                   "Default",
+		"list_features_by_priority": "ListFeaturesByPriority",
+	} {
+		if got := toolgen.GoName(in); got != want {
+			t.Errorf("GoName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// toolServer answers tools/call with reply and records the arguments sent.
+func toolServer(t *testing.T, reply map[string]any, gotArgs *map[string]any) *client.BrainClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     int64 `json:"id"`
+			Params struct {
+				Arguments map[string]any `json:"arguments"`
+			} `json:"params"`
+		}
+		json.Unmarshal(body, &req)
+		*gotArgs = req.Params.Arguments
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": reply})
+	}))
+	t.Cleanup(srv.Close)
+	return client.NewBrainClient(srv.URL)
+}
+
+func TestSearch_SendsSchemaArgumentNames(t *testing.T) {
+	var args map[string]any
+	text := `{"results":[{"permalink":"notes/adr-001","title":"ADR 001","similarity_score":0.91,"snippet":"...","source":"semantic"}],"total":1,"query":"adr","mode":"auto","depth":0}`
+	c := toolServer(t, map[string]any{"content": []map[string]any{{"type": "text", "text": text}}}, &args)
+
+	resp, err := tools.New(c).Search(context.Background(), tools.SearchArgs{
+		Query:       "adr",
+		Mode:        tools.SearchModeHybrid,
+		Depth:       tools.Ptr(0),
+		FullContext: tools.Ptr(true),
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	want := map[string]any{"query": "adr", "mode": "hybrid", "depth": float64(0), "full_context": true}
+	if fmt.Sprint(args) != fmt.Sprint(want) {
+		t.Errorf("arguments = %v, want %v", args, want)
+	}
+	if resp.Total != 1 || resp.Results[0].Permalink != "notes/adr-001" || resp.Results[0].SimilarityScore != 0.91 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestCall_ToolErrorIsReturned(t *testing.T) {
+	var args map[string]any
+	c := toolServer(t, map[string]any{
+		"content": []map[string]any{{"type": "text", "text": "Project not found: nope"}},
+		"isError": true,
+	}, &args)
+
+	result, err := tools.New(c).GetProjectDetails(context.Background(), tools.GetProjectDetailsArgs{Project: "nope"})
+	if err == nil || !strings.Contains(err.Error(), "Project not found") {
+		t.Fatalf("err = %v, want the tool's error text", err)
+	}
+	if result == nil || !result.IsError {
+		t.Errorf("result = %+v, want the isError result", result)
+	}
+}
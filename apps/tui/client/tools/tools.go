@@ -0,0 +1,84 @@
+// Package tools provides typed bindings for the Brain MCP server's tools.
+//
+// The argument structs and methods in tools_gen.go are generated from the
+// tool schemas in packages/validation/schemas/tools; run go generate after
+// changing a schema. Result types for tools that return JSON live in
+// results.go.
+package tools
+
+//go:generate go run ./gen -o tools_gen.go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// Client calls server tools with typed arguments.
+type Client struct {
+	c          *client.BrainClient
+	onProgress client.ProgressFunc
+}
+
+// New wraps a connected BrainClient.
+func New(c *client.BrainClient) *Client {
+	return &Client{c: c}
+}
+
+// WithProgress returns a copy of the client that reports progress
+// notifications from long-running tools to fn.
+func (c *Client) WithProgress(fn client.ProgressFunc) *Client {
+	copied := *c
+	copied.onProgress = fn
+	return &copied
+}
+
+// Ptr returns a pointer to v, for optional numeric and boolean arguments.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// call sends args to the named tool. A result flagged isError is returned
+// along with an error carrying the tool's message.
+func (c *Client) call(ctx context.Context, name string, args any) (*client.ToolResult, error) {
+	params, err := toArguments(args)
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to encode %s arguments: %w", name, err)
+	}
+	result, err := c.c.CallToolWithProgressContext(ctx, name, params, c.onProgress)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return result, fmt.Errorf("🧠 %s failed: %s", name, result.GetText())
+	}
+	return result, nil
+}
+
+// callJSON calls the named tool and decodes its text content into result.
+func (c *Client) callJSON(ctx context.Context, name string, args any, result any) error {
+	toolResult, err := c.call(ctx, name, args)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(toolResult.GetText()), result); err != nil {
+		return fmt.Errorf("🧠 failed to decode %s result: %w", name, err)
+	}
+	return nil
+}
+
+// toArguments converts an argument struct to the map sent as tools/call
+// arguments, so omitempty fields are left for the server to default.
+func toArguments(args any) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
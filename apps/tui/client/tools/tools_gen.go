@@ -0,0 +1,472 @@
+// Code generated by toolgen from packages/validation/schemas/tools; DO NOT EDIT.
+
+package tools
+
+import (
+	"context"
+
+	"github.com/peterkloss/brain-tui/client"
+)
+
+// Tool names.
+const (
+	ToolActiveProject          = "active_project"
+	ToolBootstrapContext       = "bootstrap_context"
+	ToolConfigGet              = "config_get"
+	ToolConfigReset            = "config_reset"
+	ToolConfigRollback         = "config_rollback"
+	ToolConfigSet              = "config_set"
+	ToolConfigUpdateGlobal     = "config_update_global"
+	ToolConfigUpdateProject    = "config_update_project"
+	ToolCreateProject          = "create_project"
+	ToolDeleteProject          = "delete_project"
+	ToolEditProject            = "edit_project"
+	ToolGetProjectDetails      = "get_project_details"
+	ToolGetWorkflow            = "get_workflow"
+	ToolListFeaturesByPriority = "list_features_by_priority"
+	ToolListProjects           = "list_projects"
+	ToolListWorkflows          = "list_workflows"
+	ToolMigrateConfig          = "migrate_config"
+	ToolSearch                 = "search"
+	ToolSendWorkflowEvent      = "send_workflow_event"
+	ToolSession                = "session"
+)
+
+// ActiveProjectOperation is the operation argument of the active_project tool.
+type ActiveProjectOperation string
+
+const (
+	ActiveProjectOperationGet   ActiveProjectOperation = "get"
+	ActiveProjectOperationSet   ActiveProjectOperation = "set"
+	ActiveProjectOperationClear ActiveProjectOperation = "clear"
+)
+
+// ActiveProjectArgs are the arguments of the active_project tool.
+type ActiveProjectArgs struct {
+	// Operation to perform: get (default), set, or clear
+	Operation ActiveProjectOperation `json:"operation,omitempty"`
+	// Project name (required for set operation)
+	Project string `json:"project,omitempty"`
+}
+
+// ActiveProject calls the active_project tool.
+//
+// Unified tool for getting, setting, and clearing the active project.
+// Operations: get (returns current), set (requires project param), clear
+// (resets selection).
+func (c *Client) ActiveProject(ctx context.Context, args ActiveProjectArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolActiveProject, args)
+}
+
+// BootstrapContextArgs are the arguments of the bootstrap_context tool.
+type BootstrapContextArgs struct {
+	// Project to bootstrap context for. Auto-resolved from CWD if not specified.
+	Project string `json:"project,omitempty"`
+	// Timeframe for recent activity (e.g., '5d', '7d', 'today')
+	Timeframe string `json:"timeframe,omitempty"`
+	// Whether to include first-level referenced notes
+	IncludeReferenced *bool `json:"include_referenced,omitempty"`
+}
+
+// BootstrapContext calls the bootstrap_context tool.
+//
+// Provides semantic context for conversation initialization by querying active
+// features, recent decisions, open bugs, and related notes.
+func (c *Client) BootstrapContext(ctx context.Context, args BootstrapContextArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolBootstrapContext, args)
+}
+
+// ConfigGetArgs are the arguments of the config_get tool.
+type ConfigGetArgs struct {
+	// Specific config key to retrieve (e.g., 'logging.level',
+	// 'defaults.memories_location'). If not provided, returns entire config.
+	Key string `json:"key,omitempty"`
+}
+
+// ConfigGet calls the config_get tool.
+//
+// Retrieves Brain configuration values - either the entire config or a
+// specific field using dot notation.
+func (c *Client) ConfigGet(ctx context.Context, args ConfigGetArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolConfigGet, args)
+}
+
+// ConfigResetArgs are the arguments of the config_reset tool.
+type ConfigResetArgs struct {
+	// Specific config key to reset to default. If not provided with all=true,
+	// resets entire config.
+	Key string `json:"key,omitempty"`
+	// Reset entire configuration to defaults. Requires explicit confirmation.
+	All *bool `json:"all,omitempty"`
+}
+
+// ConfigReset calls the config_reset tool.
+//
+// Resets Brain configuration to defaults - either a specific field or the
+// entire configuration.
+func (c *Client) ConfigReset(ctx context.Context, args ConfigResetArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolConfigReset, args)
+}
+
+// ConfigRollbackTarget is the target argument of the config_rollback tool.
+type ConfigRollbackTarget string
+
+const (
+	ConfigRollbackTargetLastKnownGood ConfigRollbackTarget = "lastKnownGood"
+	ConfigRollbackTargetPrevious      ConfigRollbackTarget = "previous"
+)
+
+// ConfigRollbackArgs are the arguments of the config_rollback tool.
+type ConfigRollbackArgs struct {
+	// Rollback target: 'lastKnownGood' (baseline from startup) or 'previous' (most
+	// recent snapshot).
+	Target ConfigRollbackTarget `json:"target"`
+}
+
+// ConfigRollback calls the config_rollback tool.
+//
+// Restores configuration from snapshots created before risky operations.
+func (c *Client) ConfigRollback(ctx context.Context, args ConfigRollbackArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolConfigRollback, args)
+}
+
+// ConfigSetArgs are the arguments of the config_set tool.
+type ConfigSetArgs struct {
+	// Config key to set (e.g., 'logging.level', 'sync.delay_ms'). Use dot notation
+	// for nested keys.
+	Key string `json:"key"`
+	// Value to set. Type must match the expected type for the key.
+	Value any `json:"value"`
+}
+
+// ConfigSet calls the config_set tool.
+//
+// Sets a Brain configuration value and triggers reconfiguration if the change
+// affects projects.
+func (c *Client) ConfigSet(ctx context.Context, args ConfigSetArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolConfigSet, args)
+}
+
+// ConfigUpdateGlobalMemoriesMode is the memories_mode argument of the config_update_global tool.
+type ConfigUpdateGlobalMemoriesMode string
+
+const (
+	ConfigUpdateGlobalMemoriesModeDefault ConfigUpdateGlobalMemoriesMode = "DEFAULT"
+	ConfigUpdateGlobalMemoriesModeCode    ConfigUpdateGlobalMemoriesMode = "CODE"
+	ConfigUpdateGlobalMemoriesModeCustom  ConfigUpdateGlobalMemoriesMode = "CUSTOM"
+)
+
+// ConfigUpdateGlobalArgs are the arguments of the config_update_global tool.
+type ConfigUpdateGlobalArgs struct {
+	// New default memories location. Use ~ for home directory.
+	MemoriesLocation string `json:"memories_location,omitempty"`
+	// New default memories mode for new projects.
+	MemoriesMode ConfigUpdateGlobalMemoriesMode `json:"memories_mode,omitempty"`
+	// Whether to migrate memories for all affected projects when default location
+	// changes. Default: true.
+	MigrateAffected *bool `json:"migrate_affected,omitempty"`
+}
+
+// ConfigUpdateGlobal calls the config_update_global tool.
+//
+// Updates default settings that affect new projects and optionally migrates
+// existing projects using DEFAULT mode.
+func (c *Client) ConfigUpdateGlobal(ctx context.Context, args ConfigUpdateGlobalArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolConfigUpdateGlobal, args)
+}
+
+// ConfigUpdateProjectMemoriesMode is the memories_mode argument of the config_update_project tool.
+type ConfigUpdateProjectMemoriesMode string
+
+const (
+	ConfigUpdateProjectMemoriesModeDefault ConfigUpdateProjectMemoriesMode = "DEFAULT"
+	ConfigUpdateProjectMemoriesModeCode    ConfigUpdateProjectMemoriesMode = "CODE"
+	ConfigUpdateProjectMemoriesModeCustom  ConfigUpdateProjectMemoriesMode = "CUSTOM"
+)
+
+// ConfigUpdateProjectArgs are the arguments of the config_update_project tool.
+type ConfigUpdateProjectArgs struct {
+	// Project name to update.
+	Project string `json:"project"`
+	// New code path for the project. Use ~ for home directory.
+	CodePath string `json:"code_path,omitempty"`
+	// New memories path. Use 'DEFAULT', 'CODE', or an absolute path.
+	MemoriesPath string `json:"memories_path,omitempty"`
+	// Memories mode for the project.
+	MemoriesMode ConfigUpdateProjectMemoriesMode `json:"memories_mode,omitempty"`
+	// Whether to migrate memories to new location if path changes. Default: true.
+	Migrate *bool `json:"migrate,omitempty"`
+}
+
+// ConfigUpdateProject calls the config_update_project tool.
+//
+// Updates a project's configuration with optional migration of memories to a
+// new location.
+func (c *Client) ConfigUpdateProject(ctx context.Context, args ConfigUpdateProjectArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolConfigUpdateProject, args)
+}
+
+// CreateProjectArgs are the arguments of the create_project tool.
+type CreateProjectArgs struct {
+	// Project name to create
+	Name string `json:"name"`
+	// Code directory path (use ~ for home). Required.
+	CodePath string `json:"code_path"`
+	// Memories directory path. Options: 'DEFAULT'
+	// (${default_memories_location}/${name}), 'CODE' (${code_path}/docs), or
+	// absolute path. Defaults to 'DEFAULT'.
+	MemoriesPath string `json:"memories_path,omitempty"`
+}
+
+// CreateProject calls the create_project tool.
+//
+// Creates a new Brain memory project with required code_path and optional
+// memories_path.
+func (c *Client) CreateProject(ctx context.Context, args CreateProjectArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolCreateProject, args)
+}
+
+// DeleteProjectArgs are the arguments of the delete_project tool.
+type DeleteProjectArgs struct {
+	// Project name to delete
+	Project string `json:"project"`
+	// If true, also delete the notes directory. DESTRUCTIVE - defaults to false
+	// for safety.
+	DeleteNotes *bool `json:"delete_notes,omitempty"`
+}
+
+// DeleteProject calls the delete_project tool.
+//
+// Two-stage deletion with safety controls: Stage 1 removes config entries
+// (reversible), Stage 2 deletes notes directory (irreversible, only if
+// delete_notes=true).
+func (c *Client) DeleteProject(ctx context.Context, args DeleteProjectArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolDeleteProject, args)
+}
+
+// EditProjectArgs are the arguments of the edit_project tool.
+type EditProjectArgs struct {
+	// Project name to edit
+	Name string `json:"name"`
+	// Code directory path (use ~ for home). Required for editing.
+	CodePath string `json:"code_path"`
+	// Memories directory path. Options: 'DEFAULT'
+	// (${default_memories_location}/${name}), 'CODE' (${code_path}/docs), or
+	// absolute path. Defaults to 'DEFAULT' when not specified, except auto-updates
+	// to new code_path/docs if was ${old_code_path}/docs.
+	MemoriesPath string `json:"memories_path,omitempty"`
+}
+
+// EditProject calls the edit_project tool.
+//
+// Edits project metadata and configuration including code path and memories
+// path.
+func (c *Client) EditProject(ctx context.Context, args EditProjectArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolEditProject, args)
+}
+
+// GetProjectDetailsArgs are the arguments of the get_project_details tool.
+type GetProjectDetailsArgs struct {
+	// Project name to get details for
+	Project string `json:"project"`
+}
+
+// GetProjectDetails calls the get_project_details tool.
+//
+// Gets detailed information about a specific Brain memory project.
+func (c *Client) GetProjectDetails(ctx context.Context, args GetProjectDetailsArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolGetProjectDetails, args)
+}
+
+// GetWorkflowArgs are the arguments of the get_workflow tool.
+type GetWorkflowArgs struct {
+	// The workflow run ID to get details for
+	RunID string `json:"run_id"`
+}
+
+// GetWorkflow calls the get_workflow tool.
+//
+// Gets full workflow run details including status, timing, steps, and output.
+func (c *Client) GetWorkflow(ctx context.Context, args GetWorkflowArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolGetWorkflow, args)
+}
+
+// ListFeaturesByPriorityEntityType is the entity_type argument of the list_features_by_priority tool.
+type ListFeaturesByPriorityEntityType string
+
+const (
+	ListFeaturesByPriorityEntityTypeFeature ListFeaturesByPriorityEntityType = "feature"
+	ListFeaturesByPriorityEntityTypeTask    ListFeaturesByPriorityEntityType = "task"
+	ListFeaturesByPriorityEntityTypePhase   ListFeaturesByPriorityEntityType = "phase"
+)
+
+// ListFeaturesByPriorityFormat is the format argument of the list_features_by_priority tool.
+type ListFeaturesByPriorityFormat string
+
+const (
+	ListFeaturesByPriorityFormatList ListFeaturesByPriorityFormat = "list"
+	ListFeaturesByPriorityFormatTree ListFeaturesByPriorityFormat = "tree"
+)
+
+// ListFeaturesByPriorityArgs are the arguments of the list_features_by_priority tool.
+type ListFeaturesByPriorityArgs struct {
+	// Project to list features for. Auto-resolved from CWD if not specified.
+	Project string `json:"project,omitempty"`
+	// Type of entity to list. Default: feature
+	EntityType ListFeaturesByPriorityEntityType `json:"entity_type,omitempty"`
+	// Include completed items. Default: false
+	IncludeCompleted *bool `json:"include_completed,omitempty"`
+	// Output format. Default: list
+	Format ListFeaturesByPriorityFormat `json:"format,omitempty"`
+}
+
+// ListFeaturesByPriority calls the list_features_by_priority tool.
+//
+// Lists features ordered by dependency (topological sort) and priority
+// tie-breaking. Returns features sorted by: 1. Dependency order (dependencies
+// first), 2. Priority (lower number = higher priority).
+func (c *Client) ListFeaturesByPriority(ctx context.Context, args ListFeaturesByPriorityArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolListFeaturesByPriority, args)
+}
+
+// ListProjectsArgs are the arguments of the list_projects tool.
+type ListProjectsArgs struct{}
+
+// ListProjects calls the list_projects tool.
+//
+// Lists all available Brain memory projects. Returns a simple array of project
+// names. Use get_project_details for detailed information about a specific
+// project.
+func (c *Client) ListProjects(ctx context.Context, args ListProjectsArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolListProjects, args)
+}
+
+// ListWorkflowsArgs are the arguments of the list_workflows tool.
+type ListWorkflowsArgs struct{}
+
+// ListWorkflows calls the list_workflows tool.
+//
+// Lists all available workflow functions and their event triggers.
+func (c *Client) ListWorkflows(ctx context.Context, args ListWorkflowsArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolListWorkflows, args)
+}
+
+// MigrateConfigArgs are the arguments of the migrate_config tool.
+type MigrateConfigArgs struct {
+	// Preview migration without making changes.
+	DryRun *bool `json:"dry_run,omitempty"`
+	// Remove deprecated files after successful migration.
+	Cleanup *bool `json:"cleanup,omitempty"`
+}
+
+// MigrateConfig calls the migrate_config tool.
+//
+// Migrates Brain configuration from old format to new format.
+func (c *Client) MigrateConfig(ctx context.Context, args MigrateConfigArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolMigrateConfig, args)
+}
+
+// SearchMode is the mode argument of the search tool.
+type SearchMode string
+
+const (
+	SearchModeAuto     SearchMode = "auto"
+	SearchModeSemantic SearchMode = "semantic"
+	SearchModeKeyword  SearchMode = "keyword"
+	SearchModeHybrid   SearchMode = "hybrid"
+)
+
+// SearchArgs are the arguments of the search tool.
+type SearchArgs struct {
+	// Search query text
+	Query string `json:"query"`
+	// Maximum number of results to return
+	Limit *int `json:"limit,omitempty"`
+	// Similarity threshold for semantic search (0-1)
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Search mode: auto, semantic, keyword, or hybrid
+	Mode SearchMode `json:"mode,omitempty"`
+	// Relation depth: follow wikilinks N levels from results (0-3)
+	Depth *int `json:"depth,omitempty"`
+	// Project name to search in
+	Project string `json:"project,omitempty"`
+	// When true, include full note content instead of snippets (limited to 5000
+	// chars per note)
+	FullContext *bool `json:"full_context,omitempty"`
+}
+
+// Search calls the search tool.
+//
+// Defines input validation for semantic/keyword search with automatic fallback
+// behavior.
+func (c *Client) Search(ctx context.Context, args SearchArgs) (*SearchResponse, error) {
+	var result SearchResponse
+	if err := c.callJSON(ctx, ToolSearch, args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SendWorkflowEventArgs are the arguments of the send_workflow_event tool.
+type SendWorkflowEventArgs struct {
+	// The event name to trigger (e.g., 'feature/completion.requested')
+	EventName string `json:"event_name"`
+	// The payload required by the workflow
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// SendWorkflowEvent calls the send_workflow_event tool.
+//
+// Triggers a workflow by sending an event.
+func (c *Client) SendWorkflowEvent(ctx context.Context, args SendWorkflowEventArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolSendWorkflowEvent, args)
+}
+
+// SessionOperation is the operation argument of the session tool.
+type SessionOperation string
+
+const (
+	SessionOperationGet      SessionOperation = "get"
+	SessionOperationSet      SessionOperation = "set"
+	SessionOperationCreate   SessionOperation = "create"
+	SessionOperationPause    SessionOperation = "pause"
+	SessionOperationResume   SessionOperation = "resume"
+	SessionOperationComplete SessionOperation = "complete"
+)
+
+// SessionMode is the mode argument of the session tool.
+type SessionMode string
+
+const (
+	SessionModeAnalysis SessionMode = "analysis"
+	SessionModePlanning SessionMode = "planning"
+	SessionModeCoding   SessionMode = "coding"
+	SessionModeDisabled SessionMode = "disabled"
+)
+
+// SessionArgs are the arguments of the session tool.
+type SessionArgs struct {
+	// Operation: 'get' retrieves session state, 'set' updates it, 'create' starts
+	// new session, 'pause' pauses active session, 'resume' resumes paused session,
+	// 'complete' completes active session
+	Operation SessionOperation `json:"operation"`
+	// Workflow mode (for set operation): analysis (read-only), planning (design),
+	// coding (full access), disabled (no restrictions)
+	Mode SessionMode `json:"mode,omitempty"`
+	// Description of current task (for set operation)
+	Task string `json:"task,omitempty"`
+	// Active feature slug/path (for set operation)
+	Feature string `json:"feature,omitempty"`
+	// Session identifier (required for pause/resume/complete operations)
+	SessionID string `json:"sessionId,omitempty"`
+	// Session topic (required for create operation)
+	Topic string `json:"topic,omitempty"`
+}
+
+// Session calls the session tool.
+//
+// Unified session management with get/set/create/pause/resume/complete
+// operations for workflow mode, active task, feature, and session lifecycle.
+func (c *Client) Session(ctx context.Context, args SessionArgs) (*client.ToolResult, error) {
+	return c.call(ctx, ToolSession, args)
+}
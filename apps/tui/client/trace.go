@@ -0,0 +1,258 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/peterkloss/brain-tui/internal/installer"
+)
+
+// EnvTrace enables wire tracing: "stderr" writes records to stderr, "file"
+// (or "1") appends them to TracePath. Empty or "0" disables tracing.
+const EnvTrace = "BRAIN_TRACE"
+
+const (
+	TraceStderr = "stderr"
+	TraceFile   = "file"
+)
+
+const (
+	// traceBodyLimit truncates request and response bodies in trace records.
+	traceBodyLimit = 2048
+	// traceMaxBytes is the size at which the trace file is rotated.
+	traceMaxBytes = 5 << 20
+	// traceBackups is how many rotated trace files are kept.
+	traceBackups = 2
+)
+
+// TraceRecord describes one JSON-RPC exchange or notification.
+type TraceRecord struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Tool      string    `json:"tool,omitempty"`
+	ID        int64     `json:"id,omitempty"`
+	Session   string    `json:"session,omitempty"`
+	LatencyMS float64   `json:"latency_ms"`
+	// Status is ok, rpc_error, tool_error, error or cancelled.
+	Status     string `json:"status"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Request    string `json:"request,omitempty"`
+	Response   string `json:"response,omitempty"`
+}
+
+// Tracer receives a record for every request and notification a client sends.
+type Tracer interface {
+	Trace(TraceRecord)
+}
+
+// SetTracer makes the client report its traffic to t (nil disables tracing).
+func (c *BrainClient) SetTracer(t Tracer) {
+	c.tracer = t
+}
+
+// TracePath returns the JSONL file used when tracing to a file.
+func TracePath() string {
+	return filepath.Join(installer.CacheDir(), "trace.jsonl")
+}
+
+// TracerFromEnv returns the tracer selected by BRAIN_TRACE, or nil when
+// tracing is off.
+func TracerFromEnv() (Tracer, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvTrace))) {
+	case "", "0", "false", "off":
+		return nil, nil
+	case TraceStderr:
+		return NewWriterTracer(os.Stderr), nil
+	case TraceFile, "1", "true", "on":
+		return NewFileTracer(TracePath()), nil
+	default:
+		return nil, fmt.Errorf("🧠 unknown %s %q (want %s or %s)", EnvTrace, os.Getenv(EnvTrace), TraceStderr, TraceFile)
+	}
+}
+
+// WriterTracer writes records as JSON lines to a writer.
+type WriterTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterTracer returns a tracer writing JSON lines to w.
+func NewWriterTracer(w io.Writer) *WriterTracer {
+	return &WriterTracer{w: w}
+}
+
+// Trace writes rec. Write errors are ignored: tracing never fails a call.
+func (t *WriterTracer) Trace(rec TraceRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.w.Write(append(data, '\n'))
+}
+
+// FileTracer appends records to a JSONL file, rotating it once it exceeds
+// traceMaxBytes. The file is opened per record so that the many short-lived
+// processes hooks start can share it.
+type FileTracer struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTracer returns a tracer appending to path.
+func NewFileTracer(path string) *FileTracer {
+	return &FileTracer{path: path}
+}
+
+// Trace appends rec. Errors are ignored: tracing never fails a call.
+func (t *FileTracer) Trace(rec TraceRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0700); err != nil {
+		return
+	}
+	if info, err := os.Stat(t.path); err == nil && info.Size()+int64(len(data)) > traceMaxBytes {
+		rotateTraceFiles(t.path)
+	}
+	// Bodies may contain note content, so the file is private.
+	f, err := os.OpenFile(t.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// rotateTraceFiles shifts path to path.1, path.1 to path.2 and so on,
+// dropping the oldest.
+func rotateTraceFiles(path string) {
+	for i := traceBackups; i > 0; i-- {
+		from := path
+		if i > 1 {
+			from = fmt.Sprintf("%s.%d", path, i-1)
+		}
+		os.Rename(from, fmt.Sprintf("%s.%d", path, i))
+	}
+}
+
+// TraceFiles returns the trace file and its rotated backups, oldest first.
+func TraceFiles(path string) []string {
+	var files []string
+	for i := traceBackups; i > 0; i-- {
+		files = append(files, fmt.Sprintf("%s.%d", path, i))
+	}
+	return append(files, path)
+}
+
+// ReadTraceRecords reads records from path and its rotated backups, oldest
+// first. Missing files are skipped; malformed lines are ignored.
+func ReadTraceRecords(path string) ([]TraceRecord, error) {
+	var records []TraceRecord
+	for _, file := range TraceFiles(path) {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			var rec TraceRecord
+			if line != "" && json.Unmarshal([]byte(line), &rec) == nil {
+				records = append(records, rec)
+			}
+		}
+	}
+	return records, nil
+}
+
+// traceExchange reports one request/response pair to the client's tracer.
+func (c *BrainClient) traceExchange(start time.Time, req *RPCRequest, resp *RPCResponse, err error, cancelled bool) {
+	rec := TraceRecord{
+		Time:      start,
+		Method:    req.Method,
+		ID:        req.ID,
+		Session:   c.SessionID(),
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Status:    "ok",
+		Request:   traceBody(req.Params),
+	}
+	if p, ok := req.Params.(ToolCallParams); ok {
+		rec.Tool = p.Name
+	}
+
+	var statusErr *StatusError
+	switch {
+	case cancelled:
+		rec.Status = "cancelled"
+		rec.Error = err.Error()
+	case err != nil:
+		rec.Status = "error"
+		rec.Error = err.Error()
+		if errors.As(err, &statusErr) {
+			rec.HTTPStatus = statusErr.StatusCode
+		}
+	case resp.Error != nil:
+		rec.Status = "rpc_error"
+		rec.Error = fmt.Sprintf("%d: %s", resp.Error.Code, resp.Error.Message)
+	default:
+		rec.Response = truncateTrace(string(resp.Result))
+		var result struct {
+			IsError bool `json:"isError"`
+		}
+		if json.Unmarshal(resp.Result, &result) == nil && result.IsError {
+			rec.Status = "tool_error"
+		}
+	}
+	c.tracer.Trace(rec)
+}
+
+// traceNotification reports a notification sent to the server.
+func (c *BrainClient) traceNotification(start time.Time, method string, params interface{}, err error) {
+	rec := TraceRecord{
+		Time:      start,
+		Method:    method,
+		Session:   c.SessionID(),
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Status:    "ok",
+		Request:   traceBody(params),
+	}
+	if err != nil {
+		rec.Status = "error"
+		rec.Error = err.Error()
+	}
+	c.tracer.Trace(rec)
+}
+
+// traceBody encodes v for a trace record, truncated to traceBodyLimit.
+func traceBody(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("<unencodable: %v>", err)
+	}
+	return truncateTrace(string(data))
+}
+
+func truncateTrace(s string) string {
+	if len(s) <= traceBodyLimit {
+		return s
+	}
+	return fmt.Sprintf("%s...(+%d bytes)", s[:traceBodyLimit], len(s)-traceBodyLimit)
+}
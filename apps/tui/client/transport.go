@@ -0,0 +1,253 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Transport kinds, selectable with the --transport flag or BRAIN_MCP_TRANSPORT.
+const (
+	TransportHTTP  = "http"
+	TransportStdio = "stdio"
+
+	// EnvTransport selects the transport used by EnsureServerRunning.
+	EnvTransport = "BRAIN_MCP_TRANSPORT"
+)
+
+// TransportKind returns the transport selected by BRAIN_MCP_TRANSPORT,
+// defaulting to streamable HTTP.
+func TransportKind() (string, error) {
+	kind := strings.ToLower(strings.TrimSpace(os.Getenv(EnvTransport)))
+	switch kind {
+	case "", TransportHTTP:
+		return TransportHTTP, nil
+	case TransportStdio:
+		return TransportStdio, nil
+	default:
+		return "", fmt.Errorf("🧠 unknown transport %q (want %s or %s)", kind, TransportHTTP, TransportStdio)
+	}
+}
+
+// ErrSessionExpired is returned when the server no longer recognizes the
+// client's session, typically because it restarted. BrainClient recovers by
+// initializing a new session and resending the request.
+var ErrSessionExpired = errors.New("🧠 MCP session expired")
+
+// StatusError reports an HTTP error status from the /mcp endpoint that did not
+// carry a JSON-RPC error.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("🧠 server returned HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("🧠 server returned HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Transport carries JSON-RPC messages between a BrainClient and the server.
+// Implementations must be safe for concurrent use.
+type Transport interface {
+	// RoundTrip sends req and returns the response carrying the same ID.
+	// Progress notifications that arrive first are passed to onProgress,
+	// which may be nil.
+	RoundTrip(ctx context.Context, req *RPCRequest, onProgress ProgressFunc) (*RPCResponse, error)
+	// Notify sends a notification. No response is expected.
+	Notify(ctx context.Context, n *RPCNotification) error
+	// Close releases the transport's resources.
+	Close() error
+}
+
+// healthChecker is implemented by transports that can report server health.
+type healthChecker interface {
+	Health(ctx context.Context) (*HealthStatus, error)
+}
+
+// ─── Streamable HTTP ────────────────────────────────────────────────────────
+
+// HTTPTransport speaks MCP streamable HTTP to a long-lived server: every message
+// is POSTed to /mcp and the response body is either JSON or an SSE stream.
+// The server assigns a session via the Mcp-Session-Id header.
+type HTTPTransport struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewHTTPTransport creates a transport for the server at baseURL
+// (e.g., "http://127.0.0.1:8765").
+func NewHTTPTransport(baseURL string) *HTTPTransport {
+	return &HTTPTransport{
+		baseURL:    baseURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// BaseURL returns the server address.
+func (t *HTTPTransport) BaseURL() string {
+	return t.baseURL
+}
+
+// SessionID returns the MCP session ID sent with each request.
+func (t *HTTPTransport) SessionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+// SetSessionID replaces the MCP session ID sent with each request.
+func (t *HTTPTransport) SetSessionID(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessionID = id
+}
+
+// Health queries the server's /health endpoint.
+func (t *HTTPTransport) Health(ctx context.Context) (*HealthStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+"/health", nil)
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to create request: %w", err)
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("🧠 health check failed: status %d", resp.StatusCode)
+	}
+
+	var status HealthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("🧠 failed to decode health response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// RoundTrip posts req to /mcp and reads the matching response from the body.
+// A session ID assigned by the server is remembered for later requests.
+//
+// Streamable HTTP servers answer 404 for an unknown session and 400 for a
+// missing one; both are reported as ErrSessionExpired and the stale ID is
+// dropped. A 400 whose body holds a JSON-RPC error is a session failure only
+// if the request sent a session ID and the error is about the session.
+// Other error statuses become a *StatusError unless a 4xx body holds a
+// JSON-RPC error, which is returned as the response.
+func (t *HTTPTransport) RoundTrip(ctx context.Context, req *RPCRequest, onProgress ProgressFunc) (*RPCResponse, error) {
+	sent := t.SessionID()
+	resp, err := t.post(ctx, req, sent)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return t.errorResponse(req, resp, sent)
+	}
+
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		t.SetSessionID(sid)
+	}
+
+	// The body is either a single JSON response or an SSE stream of
+	// "event: message\ndata: {...}\n\n" frames ending with the response.
+	rpcResp, err := readResponse(resp, req.ID, onProgress)
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to parse RPC response: %w", err)
+	}
+	return rpcResp, nil
+}
+
+// errorResponse interprets an HTTP error status for req, which was sent with
+// session ID sent.
+func (t *HTTPTransport) errorResponse(req *RPCRequest, resp *http.Response, sent string) (*RPCResponse, error) {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	text := strings.TrimSpace(string(body))
+	rpcResp, err := parseResponse([]byte(text))
+	if err != nil || rpcResp.Error == nil {
+		rpcResp = nil
+	}
+
+	expired := false
+	switch {
+	case req.Method == "initialize":
+		// There is no session to expire while initializing.
+	case resp.StatusCode == http.StatusNotFound:
+		expired = true
+	case resp.StatusCode == http.StatusBadRequest:
+		// A 400 may also reject the request itself, such as invalid params.
+		expired = rpcResp == nil || (sent != "" && isSessionError(rpcResp.Error))
+	}
+	if expired {
+		// Only drop the session this request used: a concurrent call may
+		// already have replaced it with a fresh one.
+		t.mu.Lock()
+		if t.sessionID == sent {
+			t.sessionID = ""
+		}
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w (HTTP %d)", ErrSessionExpired, resp.StatusCode)
+	}
+
+	// A server that refuses a repeated initialize may still name its session.
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" && req.Method == "initialize" {
+		t.SetSessionID(sid)
+	}
+
+	if resp.StatusCode < 500 && rpcResp != nil {
+		return rpcResp, nil
+	}
+	return nil, &StatusError{StatusCode: resp.StatusCode, Body: text}
+}
+
+// Notify posts a notification to /mcp. Notifications have no response, so
+// the body is discarded.
+func (t *HTTPTransport) Notify(ctx context.Context, n *RPCNotification) error {
+	resp, err := t.post(ctx, n, t.SessionID())
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// Close is a no-op: the HTTP server outlives the client.
+func (t *HTTPTransport) Close() error {
+	return nil
+}
+
+// post marshals a JSON-RPC message and posts it to the /mcp endpoint with the
+// given session ID. The caller owns the response body.
+func (t *HTTPTransport) post(ctx context.Context, msg interface{}, sessionID string) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.baseURL+"/mcp", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("🧠 failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		httpReq.Header.Set("Mcp-Session-Id", sessionID)
+	}
+
+	return t.httpClient.Do(httpReq)
+}
@@ -39,11 +39,12 @@ func init() {
 
 func runBootstrap(cmd *cobra.Command, args []string) error {
 	// Create client and ensure server is running
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	// Build args for bootstrap_context tool
 	toolArgs := map[string]interface{}{
@@ -58,7 +59,7 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	}
 
 	// Call the tool
-	result, err := brainClient.CallTool("bootstrap_context", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "bootstrap_context", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -137,13 +137,14 @@ func init() {
 
 // runConfigRoot handles 'brain config' - displays full configuration
 func runConfigRoot(cmd *cobra.Command, args []string) error {
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
-	result, err := brainClient.CallTool("config_get", map[string]any{})
+	result, err := brainClient.CallToolContext(cmd.Context(), "config_get", map[string]any{})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -170,13 +171,14 @@ func runConfigRoot(cmd *cobra.Command, args []string) error {
 func runConfigGet(cmd *cobra.Command, args []string) error {
 	key := normalizeConfigKey(args[0])
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
-	result, err := brainClient.CallTool("config_get", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "config_get", map[string]any{
 		"key": key,
 	})
 	if err != nil {
@@ -201,13 +203,14 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := normalizeConfigKey(args[0])
 	value := parseConfigValue(args[1])
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
-	result, err := brainClient.CallTool("config_set", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "config_set", map[string]any{
 		"key":   key,
 		"value": value,
 	})
@@ -254,11 +257,12 @@ func runConfigReset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("missing key or --all flag")
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	toolArgs := map[string]any{}
 
@@ -268,7 +272,7 @@ func runConfigReset(cmd *cobra.Command, args []string) error {
 		toolArgs["key"] = normalizeConfigKey(args[0])
 	}
 
-	result, err := brainClient.CallTool("config_reset", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "config_reset", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
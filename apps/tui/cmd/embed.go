@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/peterkloss/brain-tui/client"
@@ -70,10 +71,11 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		return fmt.Errorf("failed to connect to Brain MCP: %w", err)
 	}
+	defer brainClient.Close()
 
 	fmt.Printf("Generating embeddings for project: %s\n", project)
 	if embedForce {
@@ -98,8 +100,10 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 		toolArgs["project"] = project
 	}
 
-	// Call the generate_embeddings tool
-	result, err := brainClient.CallTool("generate_embeddings", toolArgs)
+	// Call the generate_embeddings tool, rendering progress as it streams in
+	progress := newProgressPrinter(os.Stderr)
+	result, err := brainClient.CallToolWithProgressContext(cmd.Context(), "generate_embeddings", toolArgs, progress.Update)
+	progress.Done()
 	if err != nil {
 		return fmt.Errorf("embedding generation failed: %w", err)
 	}
@@ -143,3 +147,35 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// progressPrinter renders MCP progress notifications on a single, continuously
+// rewritten terminal line.
+type progressPrinter struct {
+	w       io.Writer
+	printed bool
+}
+
+func newProgressPrinter(w io.Writer) *progressPrinter {
+	return &progressPrinter{w: w}
+}
+
+// Update redraws the progress line. It satisfies client.ProgressFunc.
+func (p *progressPrinter) Update(update client.Progress) {
+	line := fmt.Sprintf("   Progress: %.0f", update.Progress)
+	if update.Total > 0 {
+		line = fmt.Sprintf("   Progress: %.0f/%.0f (%.0f%%)", update.Progress, update.Total, update.Percent())
+	}
+	if update.Message != "" {
+		line += " - " + update.Message
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s", line)
+	p.printed = true
+}
+
+// Done terminates the progress line so subsequent output starts on a fresh line.
+func (p *progressPrinter) Done() {
+	if p.printed {
+		fmt.Fprintln(p.w)
+		p.printed = false
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/internal/installer"
+	"github.com/peterkloss/brain/packages/validation"
+	"github.com/spf13/cobra"
+)
+
+// Environment exported to launched tools, alongside BRAIN_PROJECT.
+const (
+	envProject = "BRAIN_PROJECT"
+	envTool    = "BRAIN_TOOL"  // installer slug of the launched tool
+	envScope   = "BRAIN_SCOPE" // install scope the tool was verified against
+)
+
+// launchTarget describes a tool that 'brain <command>' launches.
+type launchTarget struct {
+	command string // cobra command name
+	tool    string // installer slug in tools.config.yaml
+	binary  string // executable looked up on PATH
+}
+
+var launchTargets = []launchTarget{
+	{command: "claude", tool: "claude-code", binary: "claude"},
+	{command: "cursor", tool: "cursor", binary: "cursor"},
+}
+
+var (
+	launchProject string
+	launchScope   string
+)
+
+// ExecFunc replaces the current process with binary. It only returns on
+// failure.
+type ExecFunc func(binary string, args []string, env []string) error
+
+var (
+	execMu   sync.RWMutex
+	execTool ExecFunc = syscall.Exec
+)
+
+// SetExecFunc replaces how launch commands exec the tool binary, for tests
+// that must not replace the test process. The returned func restores the
+// previous behavior.
+func SetExecFunc(fn ExecFunc) (restore func()) {
+	execMu.Lock()
+	prev := execTool
+	execTool = fn
+	execMu.Unlock()
+	return func() {
+		execMu.Lock()
+		execTool = prev
+		execMu.Unlock()
+	}
+}
+
+func init() {
+	for _, target := range launchTargets {
+		rootCmd.AddCommand(newLaunchCmd(target))
+	}
+}
+
+func newLaunchCmd(target launchTarget) *cobra.Command {
+	c := &cobra.Command{
+		Use:   target.command + " [flags] [-- tool args...]",
+		Short: fmt.Sprintf("Launch %s with Brain loaded", target.binary),
+		Long: fmt.Sprintf(`Launch %[1]s with Brain loaded.
+
+Before starting %[1]s, this makes sure the Brain MCP server is running,
+resolves the project (--project, BRAIN_PROJECT, or the current directory),
+and checks that 'brain install' has placed Brain content for the selected
+scope. BRAIN_PROJECT, BRAIN_TOOL and BRAIN_SCOPE are exported to the tool.
+
+Arguments after the flags (or after --) are passed to %[1]s unchanged.
+
+Examples:
+  brain %[2]s
+  brain %[2]s --scope project
+  brain %[2]s -p brain -- --help`, target.binary, target.command),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLaunch(cmd, target, args)
+		},
+	}
+	// Stop at the first tool argument so its flags are not parsed as ours.
+	c.Flags().SetInterspersed(false)
+	c.Flags().StringVarP(&launchProject, "project", "p", "", "Project name (default: BRAIN_PROJECT or the current directory)")
+	c.Flags().StringVar(&launchScope, "scope", "", "Install scope to verify (e.g. global, project; default: the tool's default scope)")
+	return c
+}
+
+// runLaunch handles 'brain claude' and 'brain cursor'
+func runLaunch(cmd *cobra.Command, target launchTarget, args []string) error {
+	binary, err := exec.LookPath(target.binary)
+	if err != nil {
+		return fmt.Errorf("🧠 %s not found on PATH: %w", target.binary, err)
+	}
+
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("🧠 failed to start Brain MCP server: %w", err)
+	}
+	brainClient.Close()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("🧠 resolve working directory: %w", err)
+	}
+	project := validation.ResolveProject(launchProject, cwd)
+	if project == "" {
+		return fmt.Errorf("🧠 no project specified and none could be resolved from %s; use --project or run from a configured project directory", cwd)
+	}
+
+	tool, err := launchInstaller(target.tool)
+	if err != nil {
+		return err
+	}
+	if launchScope != "" {
+		if err := tool.SetScope(launchScope); err != nil {
+			return fmt.Errorf("🧠 %w", err)
+		}
+	}
+	if err := tool.VerifyManifest(); err != nil {
+		return fmt.Errorf("🧠 Brain install for %s (%s scope) is not current: %w; run 'brain install' first", tool.DisplayName(), tool.Scope(), err)
+	}
+
+	env := os.Environ()
+	env = setEnv(env, envProject, project)
+	env = setEnv(env, envTool, target.tool)
+	env = setEnv(env, envScope, tool.Scope())
+
+	execMu.RLock()
+	run := execTool
+	execMu.RUnlock()
+	argv := append([]string{target.binary}, args...)
+	if err := run(binary, argv, env); err != nil {
+		return fmt.Errorf("🧠 exec %s: %w", binary, err)
+	}
+	return nil
+}
+
+// launchInstaller builds a fresh ToolInstaller for slug from tools.config.yaml.
+// A fresh installer keeps --scope from leaking into the shared registry.
+func launchInstaller(slug string) (*installer.ToolInstaller, error) {
+	path := resolveToolConfigPath(resolveTemplateSource())
+	cfg, err := installer.LoadToolConfigs(path)
+	if err != nil {
+		return nil, fmt.Errorf("🧠 load tool configs: %w", err)
+	}
+	tc, ok := cfg.Tools[slug]
+	if !ok {
+		return nil, fmt.Errorf("🧠 tool %q is not defined in %s", slug, path)
+	}
+	return installer.NewToolInstaller(tc), nil
+}
+
+// setEnv sets key=value in env, replacing any existing entry.
+func setEnv(env []string, key, value string) []string {
+	prefix := key + "="
+	out := env[:0:0]
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, prefix) {
+			out = append(out, kv)
+		}
+	}
+	return append(out, prefix+value)
+}
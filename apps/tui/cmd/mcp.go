@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/peterkloss/brain-tui/client"
 	"github.com/spf13/cobra"
@@ -12,7 +11,18 @@ import (
 var mcpCmd = &cobra.Command{
 	Use:   "mcp",
 	Short: "Manage the Brain MCP server",
-	Long:  `Manage the Brain MCP server - start, stop, restart, or check status.`,
+	Long: `Manage the Brain MCP server - start, stop, restart, or check status -
+and inspect what it exposes (tools, tool, call, resources, resource, prompts).
+
+The server launcher is resolved from (highest precedence first):
+  1. Environment: BRAIN_MCP_RUNTIME, BRAIN_MCP_ENTRY, BRAIN_HTTP_HOST, BRAIN_HTTP_PORT
+  2. Launcher config: ~/.config/brain/mcp-launcher.json (or $BRAIN_MCP_LAUNCHER_CONFIG)
+     {"runtime": "bun", "entry": "...", "args": [], "port": 8765, "env": {}}
+  3. Defaults: bun run <brain checkout or ~/.local/share/brain>/apps/mcp/src/index.ts
+
+Other commands talk to this shared server over HTTP. With --transport stdio
+(or BRAIN_MCP_TRANSPORT=stdio) they instead run the same launcher as a child
+process speaking MCP over stdin/stdout, which needs no background daemon.`,
 }
 
 var mcpStartCmd = &cobra.Command{
@@ -41,9 +51,7 @@ var mcpRestartCmd = &cobra.Command{
 	Use:   "restart",
 	Short: "Restart the MCP server",
 	Run: func(cmd *cobra.Command, args []string) {
-		_ = client.StopServer() // Ignore error if not running
-		time.Sleep(500 * time.Millisecond)
-		if err := client.StartServer(); err != nil {
+		if err := client.RestartServer(); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
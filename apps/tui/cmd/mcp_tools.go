@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mcpListJSON bool
+	mcpCallArgs []string
+	mcpArgsJSON string
+)
+
+var mcpToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "List the tools the running server exposes",
+	Long: `List the tools the running Brain MCP server exposes (tools/list).
+
+Examples:
+  brain mcp tools
+  brain mcp tools --json | jq '.[].name'`,
+	Args: cobra.NoArgs,
+	RunE: runMcpTools,
+}
+
+var mcpToolCmd = &cobra.Command{
+	Use:   "tool <name>",
+	Short: "Show a tool's description and input schema",
+	Long: `Show a tool's description and JSON input schema, as reported by the server.
+
+Examples:
+  brain mcp tool search
+  brain mcp tool search --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMcpTool,
+}
+
+var mcpCallCmd = &cobra.Command{
+	Use:   "call <name>",
+	Short: "Call a tool with arguments from the command line",
+	Long: `Call any server tool directly, for debugging hooks or scripting against
+tools that have no dedicated command yet.
+
+Arguments are given as repeated --arg key=value flags or as one JSON object
+with --args-json ("-" reads it from stdin). --arg values are typed by the
+tool's input schema: string properties are passed as-is, anything else is
+parsed as JSON (numbers, booleans, arrays, objects) and falls back to a string.
+
+Text content is printed as returned; --json prints the full tool result.
+The command fails if the tool reports an error.
+
+Examples:
+  brain mcp call search --arg query="session protocol" --arg limit=5
+  brain mcp call read_note --args-json '{"identifier": "ADR-001"}'
+  echo '{"project": "brain"}' | brain mcp call get_project_details --args-json -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMcpCall,
+}
+
+var mcpResourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "List the resources the running server exposes",
+	Args:  cobra.NoArgs,
+	RunE:  runMcpResources,
+}
+
+var mcpResourceCmd = &cobra.Command{
+	Use:   "resource <uri>",
+	Short: "Read a resource by URI",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMcpResource,
+}
+
+var mcpPromptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "List the prompts the running server exposes",
+	Args:  cobra.NoArgs,
+	RunE:  runMcpPrompts,
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpToolsCmd)
+	mcpCmd.AddCommand(mcpToolCmd)
+	mcpCmd.AddCommand(mcpCallCmd)
+	mcpCmd.AddCommand(mcpResourcesCmd)
+	mcpCmd.AddCommand(mcpResourceCmd)
+	mcpCmd.AddCommand(mcpPromptsCmd)
+
+	for _, c := range []*cobra.Command{mcpToolsCmd, mcpToolCmd, mcpCallCmd, mcpResourcesCmd, mcpResourceCmd, mcpPromptsCmd} {
+		c.Flags().BoolVar(&mcpListJSON, "json", false, "Output as JSON")
+	}
+	mcpCallCmd.Flags().StringArrayVar(&mcpCallArgs, "arg", nil, "Tool argument as key=value (repeatable)")
+	mcpCallCmd.Flags().StringVar(&mcpArgsJSON, "args-json", "", `Tool arguments as a JSON object ("-" reads stdin)`)
+	mcpCallCmd.MarkFlagsMutuallyExclusive("arg", "args-json")
+}
+
+// runMcpTools handles 'brain mcp tools'
+func runMcpTools(cmd *cobra.Command, args []string) error {
+	return withMcpClient(cmd, func(brainClient *client.BrainClient) error {
+		tools, err := brainClient.ListTools(cmd.Context())
+		if err != nil {
+			return err
+		}
+		sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+		if mcpListJSON {
+			return printJSON(tools)
+		}
+
+		width := 0
+		for _, t := range tools {
+			width = max(width, len(t.Name))
+		}
+		for _, t := range tools {
+			fmt.Printf("  %-*s  %s\n", width, t.Name, firstLine(t.Description))
+		}
+		fmt.Printf("\n%d tools\n", len(tools))
+		return nil
+	})
+}
+
+// runMcpTool handles 'brain mcp tool <name>'
+func runMcpTool(cmd *cobra.Command, args []string) error {
+	return withMcpClient(cmd, func(brainClient *client.BrainClient) error {
+		tool, err := brainClient.GetTool(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		if mcpListJSON {
+			return printJSON(tool)
+		}
+
+		fmt.Println(tool.Name)
+		fmt.Println(strings.Repeat("=", len(tool.Name)))
+		if tool.Description != "" {
+			fmt.Println(tool.Description)
+		}
+		fmt.Println()
+		fmt.Println("Input schema:")
+		return printJSON(tool.InputSchema)
+	})
+}
+
+// runMcpCall handles 'brain mcp call <name>'
+func runMcpCall(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	return withMcpClient(cmd, func(brainClient *client.BrainClient) error {
+		toolArgs, err := resolveToolArgs(cmd, brainClient, name)
+		if err != nil {
+			return err
+		}
+
+		result, err := brainClient.CallToolContext(cmd.Context(), name, toolArgs)
+		if err != nil {
+			return err
+		}
+		if mcpListJSON {
+			if err := printJSON(result); err != nil {
+				return err
+			}
+		} else {
+			for _, item := range result.Content {
+				if item.Type == "text" {
+					fmt.Println(item.Text)
+				} else {
+					fmt.Printf("[%s content]\n", item.Type)
+				}
+			}
+		}
+		if result.IsError {
+			return fmt.Errorf("tool %s reported an error", name)
+		}
+		return nil
+	})
+}
+
+// runMcpResources handles 'brain mcp resources'
+func runMcpResources(cmd *cobra.Command, args []string) error {
+	return withMcpClient(cmd, func(brainClient *client.BrainClient) error {
+		resources, err := brainClient.ListResources(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if mcpListJSON {
+			return printJSON(resources)
+		}
+		if len(resources) == 0 {
+			fmt.Println("  (no resources)")
+			return nil
+		}
+		for _, r := range resources {
+			fmt.Printf("  %s  %s\n", r.URI, firstLine(r.Description))
+		}
+		return nil
+	})
+}
+
+// runMcpResource handles 'brain mcp resource <uri>'
+func runMcpResource(cmd *cobra.Command, args []string) error {
+	return withMcpClient(cmd, func(brainClient *client.BrainClient) error {
+		contents, err := brainClient.ReadResource(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		if mcpListJSON {
+			return printJSON(contents)
+		}
+		for _, c := range contents {
+			if c.Text != "" || c.Blob == "" {
+				fmt.Println(c.Text)
+			} else {
+				fmt.Printf("[binary %s, %d bytes base64]\n", c.MimeType, len(c.Blob))
+			}
+		}
+		return nil
+	})
+}
+
+// runMcpPrompts handles 'brain mcp prompts'
+func runMcpPrompts(cmd *cobra.Command, args []string) error {
+	return withMcpClient(cmd, func(brainClient *client.BrainClient) error {
+		prompts, err := brainClient.ListPrompts(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if mcpListJSON {
+			return printJSON(prompts)
+		}
+		if len(prompts) == 0 {
+			fmt.Println("  (no prompts)")
+			return nil
+		}
+		for _, p := range prompts {
+			fmt.Printf("  %s  %s\n", p.Name, firstLine(p.Description))
+			for _, a := range p.Arguments {
+				required := ""
+				if a.Required {
+					required = " (required)"
+				}
+				fmt.Printf("      %s%s  %s\n", a.Name, required, a.Description)
+			}
+		}
+		return nil
+	})
+}
+
+// withMcpClient connects to the server, runs fn, and reports any error on stderr.
+func withMcpClient(cmd *cobra.Command, fn func(*client.BrainClient) error) error {
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	defer brainClient.Close()
+
+	if err := fn(brainClient); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// resolveToolArgs builds the arguments for 'brain mcp call' from --args-json
+// or --arg. For --arg the tool's schema is fetched to type the values; if the
+// server cannot list tools, values are typed by guessing instead.
+func resolveToolArgs(cmd *cobra.Command, brainClient *client.BrainClient, name string) (map[string]any, error) {
+	if mcpArgsJSON != "" {
+		return readArgsJSON(mcpArgsJSON, cmd.InOrStdin())
+	}
+
+	var schema json.RawMessage
+	tool, err := brainClient.GetTool(cmd.Context(), name)
+	switch {
+	case err == nil:
+		schema = tool.InputSchema
+	case errors.Is(err, client.ErrUnknownTool):
+		return nil, err
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch schema for %s: %v\n", name, err)
+	}
+	return parseToolArgs(mcpCallArgs, schema)
+}
+
+// parseToolArgs converts key=value pairs into tool arguments, typing each
+// value by the property's type in schema (a JSON Schema object, may be nil).
+func parseToolArgs(pairs []string, schema json.RawMessage) (map[string]any, error) {
+	var s struct {
+		Properties map[string]struct {
+			Type any `json:"type"`
+		} `json:"properties"`
+	}
+	if len(schema) > 0 {
+		json.Unmarshal(schema, &s)
+	}
+
+	args := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --arg %q (want key=value)", pair)
+		}
+		if prop, ok := s.Properties[key]; ok && prop.Type == "string" {
+			args[key] = value
+			continue
+		}
+		var typed any
+		if err := json.Unmarshal([]byte(value), &typed); err == nil {
+			args[key] = typed
+		} else {
+			args[key] = value
+		}
+	}
+	return args, nil
+}
+
+// readArgsJSON parses --args-json, reading from stdin when the value is "-".
+func readArgsJSON(value string, stdin io.Reader) (map[string]any, error) {
+	data := []byte(value)
+	if value == "-" {
+		var err error
+		if data, err = io.ReadAll(stdin); err != nil {
+			return nil, fmt.Errorf("read --args-json from stdin: %w", err)
+		}
+	}
+	var args map[string]any
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, fmt.Errorf("--args-json must be a JSON object: %w", err)
+	}
+	return args, nil
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// firstLine returns the first line of s, for one-line listings.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/peterkloss/brain/packages/validation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	memoryReindexDomain      string
+	memoryReindexDryRun      bool
+	memoryReindexMinKeywords int
+	memoryReindexFormat      string
+)
+
+var memoryDomainPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+var memoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Maintain the memory indexes",
+	Long: `Maintain the skills-{domain}-index.md lookup tables and memory-index.md
+of a memories directory (default: .serena/memories).
+
+Examples:
+  brain memory reindex --dry-run
+  brain memory reindex --domain git`,
+}
+
+var memoryReindexCmd = &cobra.Command{
+	Use:   "reindex [memories-path]",
+	Short: "Rebuild the domain index tables",
+	Long: `Rebuilds each skills-{domain}-index.md as a pure keyword table:
+
+  - orphaned {domain}-* memories are added, with keywords taken from their
+    frontmatter (keywords, tags, title), name and headings
+  - references to missing files and duplicate entries are removed
+  - entries with another domain's prefix move to that domain's index;
+    entries without a domain prefix (e.g. skill-*) are dropped with a warning
+  - keywords are trimmed until at least 40% are unique within the index and
+    topped up to --min-keywords from the memory's content
+  - memory-index.md drops missing files and lists every domain index
+
+The rebuilt indexes pass 'brain validate memory-index'. --domain rebuilds one
+domain, creating its index if needed. --dry-run prints the diff instead of
+writing it.
+
+Exit codes: 0 done, 2 usage error.
+
+Examples:
+  brain memory reindex --dry-run
+  brain memory reindex --domain git
+  brain memory reindex ~/memories --format json`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runMemoryReindex,
+}
+
+func init() {
+	rootCmd.AddCommand(memoryCmd)
+	memoryCmd.AddCommand(memoryReindexCmd)
+
+	memoryReindexCmd.Flags().StringVar(&memoryReindexDomain, "domain", "", "Only rebuild this domain's index")
+	memoryReindexCmd.Flags().BoolVar(&memoryReindexDryRun, "dry-run", false, "Print the changes without writing them")
+	memoryReindexCmd.Flags().IntVar(&memoryReindexMinKeywords, "min-keywords", validation.DefaultMinKeywords, "Keywords each entry should have")
+	memoryReindexCmd.Flags().StringVar(&memoryReindexFormat, "format", formatConsole, "Output format: console or json")
+}
+
+// runMemoryReindex handles 'brain memory reindex'
+func runMemoryReindex(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".serena/memories")
+	if err != nil {
+		return err
+	}
+	if memoryReindexFormat != formatConsole && memoryReindexFormat != formatJSON {
+		return usageError("unknown --format %q (want console, json)", memoryReindexFormat)
+	}
+	if memoryReindexDomain != "" && !memoryDomainPattern.MatchString(memoryReindexDomain) {
+		return usageError("invalid --domain %q (want lowercase letters, digits and hyphens)", memoryReindexDomain)
+	}
+	if memoryReindexMinKeywords < 1 {
+		return usageError("--min-keywords must be at least 1")
+	}
+
+	result, err := validation.ReindexMemories(path, validation.MemoryReindexOptions{
+		Domain:      memoryReindexDomain,
+		MinKeywords: memoryReindexMinKeywords,
+		DryRun:      memoryReindexDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("🧠 reindex memories: %w", err)
+	}
+
+	if memoryReindexFormat == formatJSON {
+		return printJSON(result)
+	}
+	printMemoryReindex(result)
+	return nil
+}
+
+func printMemoryReindex(result validation.MemoryReindexResult) {
+	changed := result.Changed()
+	for _, c := range changed {
+		action := "updated"
+		switch {
+		case c.Created && result.DryRun:
+			action = "would create"
+		case c.Created:
+			action = "created"
+		case result.DryRun:
+			action = "would update"
+		}
+		fmt.Printf("%s: %s\n", c.Path, action)
+		for _, line := range []struct {
+			label string
+			names []string
+		}{
+			{"added", c.Added},
+			{"removed", c.Removed},
+			{"deduplicated", c.Deduplicated},
+			{"moved", c.Moved},
+			{"dropped", c.Dropped},
+			{"rekeyed", c.Rekeyed},
+		} {
+			if len(line.names) > 0 {
+				fmt.Printf("  %s: %s\n", line.label, strings.Join(line.names, ", "))
+			}
+		}
+		if result.DryRun {
+			fmt.Print(c.Diff)
+		}
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+
+	switch {
+	case len(changed) == 0:
+		fmt.Printf("%s: indexes are up to date\n", result.MemoryPath)
+	case result.DryRun:
+		fmt.Printf("%d index files would change (dry run)\n", len(changed))
+	default:
+		fmt.Printf("%d index files rebuilt\n", len(changed))
+	}
+}
@@ -12,6 +12,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -173,18 +174,19 @@ func init() {
 
 // runMigrate handles 'brain migrate'
 func runMigrate(cmd *cobra.Command, args []string) error {
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	toolArgs := map[string]any{
 		"dry_run": migrateDryRun,
 		"cleanup": migrateCleanup,
 	}
 
-	result, err := brainClient.CallTool("migrate_config", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "migrate_config", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -203,15 +205,16 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 
 // runMigrateAgents handles 'brain migrate-agents'
 func runMigrateAgents(cmd *cobra.Command, args []string) error {
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	// Determine which tool to call based on flags
 	if migrateVerifyOnly {
-		return runVerifyAgentsIndexing(brainClient)
+		return runVerifyAgentsIndexing(cmd.Context(), brainClient)
 	}
 
 	toolArgs := map[string]any{
@@ -222,7 +225,7 @@ func runMigrateAgents(cmd *cobra.Command, args []string) error {
 		toolArgs["project"] = migrateAgentsProject
 	}
 
-	result, err := brainClient.CallTool("migrate_agents_content", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "migrate_agents_content", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -240,14 +243,14 @@ func runMigrateAgents(cmd *cobra.Command, args []string) error {
 }
 
 // runVerifyAgentsIndexing handles 'brain migrate-agents --verify-only'
-func runVerifyAgentsIndexing(brainClient *client.BrainClient) error {
+func runVerifyAgentsIndexing(ctx context.Context, brainClient *client.BrainClient) error {
 	toolArgs := map[string]any{}
 
 	if migrateAgentsProject != "" {
 		toolArgs["project"] = migrateAgentsProject
 	}
 
-	result, err := brainClient.CallTool("verify_agents_indexing", toolArgs)
+	result, err := brainClient.CallToolContext(ctx, "verify_agents_indexing", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -307,13 +310,14 @@ func runRollback(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid rollback target")
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
-	result, err := brainClient.CallTool("config_rollback", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "config_rollback", map[string]any{
 		"target": rollbackTarget,
 	})
 	if err != nil {
@@ -14,6 +14,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -200,11 +201,11 @@ func runProjectsRoot(cmd *cobra.Command, args []string) error {
 
 		// If editing flags provided, edit the project
 		if projectsCodePath != "" || getEffectiveMemoriesPath() != "" {
-			return editProject(project)
+			return editProject(cmd.Context(), project)
 		}
 
 		// Otherwise get project details
-		return getProjectDetails(project)
+		return getProjectDetails(cmd.Context(), project)
 	}
 
 	return cmd.Help()
@@ -212,13 +213,14 @@ func runProjectsRoot(cmd *cobra.Command, args []string) error {
 
 // runProjectsList handles 'brain projects list'
 func runProjectsList(cmd *cobra.Command, args []string) error {
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
-	result, err := brainClient.CallTool("list_projects", map[string]any{})
+	result, err := brainClient.CallToolContext(cmd.Context(), "list_projects", map[string]any{})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -318,15 +320,16 @@ func getEffectiveDeleteMemories() bool {
 
 // runProjectsActive handles 'brain projects active' and 'brain projects active -p NAME'
 func runProjectsActive(cmd *cobra.Command, args []string) error {
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	// If project specified, set it
 	if projectsActiveProject != "" {
-		result, err := brainClient.CallTool("active_project", map[string]any{
+		result, err := brainClient.CallToolContext(cmd.Context(), "active_project", map[string]any{
 			"operation": "set",
 			"project":   projectsActiveProject,
 		})
@@ -339,7 +342,7 @@ func runProjectsActive(cmd *cobra.Command, args []string) error {
 	}
 
 	// Otherwise get current active project
-	result, err := brainClient.CallTool("active_project", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "active_project", map[string]any{
 		"operation": "get",
 	})
 	if err != nil {
@@ -372,14 +375,15 @@ func runProjectsActive(cmd *cobra.Command, args []string) error {
 }
 
 // getProjectDetails retrieves and displays project details
-func getProjectDetails(project string) error {
-	brainClient, err := client.EnsureServerRunning()
+func getProjectDetails(ctx context.Context, project string) error {
+	brainClient, err := client.EnsureServerRunningContext(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
-	result, err := brainClient.CallTool("get_project_details", map[string]any{
+	result, err := brainClient.CallToolContext(ctx, "get_project_details", map[string]any{
 		"project": project,
 	})
 	if err != nil {
@@ -445,7 +449,7 @@ func getProjectDetails(project string) error {
 
 // editProject updates project configuration via config_update_project.
 // code_path is optional (memory-only projects are valid).
-func editProject(project string) error {
+func editProject(ctx context.Context, project string) error {
 	// Get effective memories path (handles deprecated flag)
 	effectiveMemoriesPath := getEffectiveMemoriesPath()
 
@@ -454,11 +458,12 @@ func editProject(project string) error {
 		return fmt.Errorf("invalid memories-path value: %s", effectiveMemoriesPath)
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	args := map[string]any{
 		"project": project,
@@ -474,7 +479,7 @@ func editProject(project string) error {
 		args["memories_path"] = effectiveMemoriesPath
 	}
 
-	result, err := brainClient.CallTool("config_update_project", args)
+	result, err := brainClient.CallToolContext(ctx, "config_update_project", args)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -511,11 +516,12 @@ func runProjectsCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid memories-path value: %s", effectiveMemoriesPath)
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	toolArgs := map[string]any{
 		"name":      projectsName,
@@ -527,7 +533,7 @@ func runProjectsCreate(cmd *cobra.Command, args []string) error {
 		toolArgs["memories_path"] = effectiveMemoriesPath
 	}
 
-	result, err := brainClient.CallTool("create_project", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "create_project", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
@@ -577,14 +583,15 @@ func runProjectsDelete(cmd *cobra.Command, args []string) error {
 	deleteMemories := getEffectiveDeleteMemories()
 
 	// First, get project details to show the memories path in confirmation
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	// Get project details to display in confirmation prompt
-	detailsResult, err := brainClient.CallTool("get_project_details", map[string]any{
+	detailsResult, err := brainClient.CallToolContext(cmd.Context(), "get_project_details", map[string]any{
 		"project": project,
 	})
 	if err != nil {
@@ -689,7 +696,7 @@ func runProjectsDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Execute deletion (MCP tool accepts both old and new field names)
-	result, err := brainClient.CallTool("delete_project", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "delete_project", map[string]any{
 		"project":         project,
 		"delete_memories": deleteMemories,
 	})
@@ -2,12 +2,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/peterkloss/brain-tui/client"
 	"github.com/peterkloss/brain-tui/internal/tui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Version is set at build time or defaults to development version.
@@ -26,34 +31,133 @@ Launch without arguments to start the interactive TUI.`,
 		if len(args) > 0 {
 			project = args[0]
 		}
-		launchTUI(project)
+		launchTUI(cmd.Context(), project)
 	},
 	Version: Version,
+	// PersistentPreRunE applies --transport and --trace before any command connects.
+	PersistentPreRunE: applyGlobalFlags,
 }
 
+// transportFlag selects how commands reach the MCP server (see client.TransportKind).
+var transportFlag string
+
+// traceFlag selects where wire traces go (see client.TracerFromEnv).
+var traceFlag string
+
 func init() {
 	// Set custom version template
 	rootCmd.SetVersionTemplate("Brain v{{.Version}}\n")
+
+	rootCmd.PersistentFlags().StringVar(&transportFlag, "transport", "",
+		"MCP transport: http (shared background server) or stdio (child process per command); overrides "+client.EnvTransport)
+	rootCmd.PersistentFlags().StringVar(&traceFlag, "trace", "",
+		"Record MCP requests and responses to the trace file (--trace) or stderr (--trace=stderr); overrides "+client.EnvTrace)
+	rootCmd.PersistentFlags().Lookup("trace").NoOptDefVal = client.TraceFile
+}
+
+// applyGlobalFlags applies the persistent flags every command shares.
+func applyGlobalFlags(cmd *cobra.Command, args []string) error {
+	if err := applyTransportFlag(cmd, args); err != nil {
+		return err
+	}
+	return applyTraceFlag(cmd, args)
 }
 
-// Execute runs the root command.
+// applyTransportFlag exports --transport as BRAIN_MCP_TRANSPORT so that
+// client.EnsureServerRunning picks it up, and rejects unknown values early.
+func applyTransportFlag(cmd *cobra.Command, args []string) error {
+	if transportFlag != "" {
+		os.Setenv(client.EnvTransport, transportFlag)
+	}
+	_, err := client.TransportKind()
+	return err
+}
+
+// applyTraceFlag exports --trace as BRAIN_TRACE so that every client the
+// command creates records its traffic, and rejects unknown values early.
+func applyTraceFlag(cmd *cobra.Command, args []string) error {
+	if traceFlag != "" {
+		os.Setenv(client.EnvTrace, traceFlag)
+	}
+	_, err := client.TracerFromEnv()
+	return err
+}
+
+// Execute runs the root command. Commands receive a context that is cancelled
+// on SIGINT/SIGTERM so in-flight MCP calls are aborted instead of left hanging.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Err != nil {
+			fmt.Fprintln(os.Stderr, exitErr.Err)
+		}
+		os.Exit(exitErr.Code)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
-func launchTUI(project string) {
+// ExitError makes Execute exit with Code. Err, if set, is printed to stderr;
+// a nil Err means the command already reported the outcome itself.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// ExecuteArgs runs the root command with args and returns its error instead
+// of exiting. Every flag is reset to its default first, so tests can run
+// several commands in one process (see client/clienttest for a fake server).
+func ExecuteArgs(ctx context.Context, args ...string) error {
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// resetFlags restores the flags of cmd and its subcommands to their defaults.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			s.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func launchTUI(ctx context.Context, project string) {
 	// Initialize Brain MCP client (ensures server is running)
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(ctx)
 	if err != nil {
 		fmt.Printf("Failed to connect to Brain MCP: %v\n", err)
 		fmt.Println("Make sure Brain MCP server can be started.")
 		os.Exit(1)
 	}
+	defer brainClient.Close()
 
-	if err := tui.LaunchTUI(project, brainClient); err != nil {
+	if err := tui.LaunchTUI(ctx, project, brainClient); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/tools"
 	"github.com/peterkloss/brain/packages/validation"
 	"github.com/spf13/cobra"
 )
@@ -20,32 +20,6 @@ var (
 	searchFullContent bool
 )
 
-// SearchResult represents a single search result.
-type SearchResult struct {
-	ID              string  `json:"id"`
-	Permalink       string  `json:"permalink"`
-	Title           string  `json:"title"`
-	URL             string  `json:"url"`
-	SimilarityScore float64 `json:"similarity_score,omitempty"`
-	Snippet         string  `json:"snippet,omitempty"`
-	FullContent     string  `json:"fullContent,omitempty"`
-	Source          string  `json:"source,omitempty"`
-	Depth           int     `json:"depth,omitempty"` // 0 = direct match, 1+ = related via wikilinks
-}
-
-// SearchResponse represents the search response from Brain MCP.
-type SearchResponse struct {
-	Results    []SearchResult `json:"results,omitempty"`
-	TotalCount int            `json:"total_count,omitempty"`
-	Query      string         `json:"query,omitempty"`
-}
-
-// MCPContent represents the MCP content wrapper format.
-type MCPContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
-}
-
 var searchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search the knowledge base",
@@ -106,73 +80,36 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
-
-	// Build tool arguments
-	toolArgs := map[string]any{
-		"query":   query,
-		"project": project,
-	}
-
-	if searchLimit != 10 {
-		toolArgs["limit"] = searchLimit
-	}
-	if searchThreshold != 0.7 {
-		toolArgs["threshold"] = searchThreshold
-	}
-	if searchMode != "auto" {
-		toolArgs["mode"] = searchMode
-	}
-	if searchDepth > 0 {
-		toolArgs["depth"] = searchDepth
-	}
-	if searchFullContent {
-		toolArgs["fullContent"] = true
-	}
-
-	// Call the search tool
-	result, err := brainClient.CallTool("search", toolArgs)
+	defer brainClient.Close()
+
+	response, err := tools.New(brainClient).Search(cmd.Context(), tools.SearchArgs{
+		Query:       query,
+		Project:     project,
+		Limit:       tools.Ptr(searchLimit),
+		Threshold:   tools.Ptr(searchThreshold),
+		Mode:        tools.SearchMode(searchMode),
+		Depth:       tools.Ptr(searchDepth),
+		FullContext: tools.Ptr(searchFullContent),
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
 
-	text := result.GetText()
-
-	// Debug: show raw response
-	if os.Getenv("DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG raw response: %s\n", text)
-	}
-
-	// Unwrap MCP content format if present: [{"type":"text","text":"..."}]
-	var mcpContent []MCPContent
-	if err := json.Unmarshal([]byte(text), &mcpContent); err == nil && len(mcpContent) > 0 {
-		text = mcpContent[0].Text
+	if len(response.Results) == 0 {
+		fmt.Println("No results found.")
+		return nil
 	}
-
-	// Try to parse as SearchResponse
-	var response SearchResponse
-	if err := json.Unmarshal([]byte(text), &response); err == nil && len(response.Results) > 0 {
-		printSearchResults(response.Results)
-	} else {
-		// Try parsing as array directly
-		var results []SearchResult
-		if err := json.Unmarshal([]byte(text), &results); err == nil && len(results) > 0 {
-			printSearchResults(results)
-		} else {
-			// Output raw text if not JSON
-			fmt.Println(text)
-		}
-	}
-
+	printSearchResults(response.Results)
 	return nil
 }
 
-func printSearchResults(results []SearchResult) {
+func printSearchResults(results []tools.SearchResult) {
 	for i, r := range results {
 		// Show depth indicator for related notes
 		depthPrefix := ""
@@ -181,16 +118,8 @@ func printSearchResults(results []SearchResult) {
 		}
 		fmt.Printf("%d. %s%s\n", i+1, depthPrefix, r.Title)
 
-		// Use URL if permalink is empty
-		path := r.Permalink
-		if path == "" {
-			path = r.URL
-		}
-		if path == "" {
-			path = r.ID
-		}
-		if path != "" {
-			fmt.Printf("   %s\n", path)
+		if r.Permalink != "" {
+			fmt.Printf("   %s\n", r.Permalink)
 		}
 		if r.SimilarityScore > 0 || r.Source != "" {
 			fmt.Print("   ")
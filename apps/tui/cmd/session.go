@@ -213,15 +213,16 @@ func runSessionRoot(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to connect to Brain MCP: %v\n", err)
 		os.Exit(1)
 	}
+	defer brainClient.Close()
 
 	// Call the session tool with "get" operation
 	// The project parameter is used by Brain MCP to scope the session
-	result, err := brainClient.CallTool("session", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "session", map[string]any{
 		"operation": "get",
 		"project":   sessionProject,
 	})
@@ -244,14 +245,15 @@ func runSessionRoot(cmd *cobra.Command, args []string) error {
 
 // runGetState implements the get-state subcommand.
 func runGetState(cmd *cobra.Command, args []string) error {
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to connect to Brain MCP: %v\n", err)
 		os.Exit(1)
 	}
+	defer brainClient.Close()
 
 	// Call the session tool with "get" operation
-	result, err := brainClient.CallTool("session", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "session", map[string]any{
 		"operation": "get",
 	})
 	if err != nil {
@@ -295,14 +297,15 @@ func runSetState(cmd *cobra.Command, args []string) error {
 	}
 
 	// Connect to Brain MCP
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to connect to Brain MCP: %v\n", err)
 		os.Exit(1)
 	}
+	defer brainClient.Close()
 
 	// Call the session tool with "set" operation
-	result, err := brainClient.CallTool("session", map[string]any{
+	result, err := brainClient.CallToolContext(cmd.Context(), "session", map[string]any{
 		"operation": "set",
 		"updates":   updates,
 	})
@@ -67,11 +67,12 @@ func runCreateSession(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to connect to Brain MCP: %v\n", err)
 		os.Exit(1)
 	}
+	defer brainClient.Close()
 
 	// Build tool arguments
 	toolArgs := map[string]any{
@@ -82,7 +83,7 @@ func runCreateSession(cmd *cobra.Command, args []string) error {
 		toolArgs["project"] = createSessionProject
 	}
 
-	result, err := brainClient.CallTool("session", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "session", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to create session: %v\n", err)
 		os.Exit(1)
@@ -64,11 +64,12 @@ func init() {
 func runPauseSession(cmd *cobra.Command, args []string) error {
 	sessionID := args[0]
 
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to connect to Brain MCP: %v\n", err)
 		os.Exit(1)
 	}
+	defer brainClient.Close()
 
 	// Build tool arguments
 	toolArgs := map[string]any{
@@ -79,7 +80,7 @@ func runPauseSession(cmd *cobra.Command, args []string) error {
 		toolArgs["project"] = pauseSessionProject
 	}
 
-	result, err := brainClient.CallTool("session", toolArgs)
+	result, err := brainClient.CallToolContext(cmd.Context(), "session", toolArgs)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to pause session: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,249 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/clienttest"
+	"github.com/peterkloss/brain-tui/cmd"
+)
+
+// runBrain runs the CLI in-process and returns what it printed to stdout.
+func runBrain(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	err = cmd.ExecuteArgs(context.Background(), args...)
+	w.Close()
+	os.Stdout = stdout
+	return <-out, err
+}
+
+// fakeServer installs a fake MCP server that answers each tool with a fixed
+// JSON result.
+func fakeServer(t *testing.T, results map[string]any) *clienttest.Server {
+	t.Helper()
+	srv := clienttest.NewServer(t)
+	for tool, result := range results {
+		result := result
+		srv.Handle(tool, func(map[string]any) (*client.ToolResult, error) {
+			return clienttest.JSON(result), nil
+		})
+	}
+	srv.Install()
+	return srv
+}
+
+// onlyCall returns the single call made to tool, failing otherwise.
+func onlyCall(t *testing.T, srv *clienttest.Server, tool string) map[string]any {
+	t.Helper()
+	calls := srv.CallsTo(tool)
+	if len(calls) != 1 {
+		t.Fatalf("%s called %d times, want 1 (all calls: %+v)", tool, len(calls), srv.Calls())
+	}
+	return calls[0].Args
+}
+
+func assertContains(t *testing.T, out string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("output missing %q:\n%s", w, out)
+		}
+	}
+}
+
+func TestE2E_Search(t *testing.T) {
+	srv := fakeServer(t, map[string]any{
+		"search": map[string]any{
+			"results": []map[string]any{{
+				"permalink":        "decisions/adr-001",
+				"title":            "ADR-001 Session Protocol",
+				"similarity_score": 0.87,
+				"snippet":          "Sessions start with...",
+				"source":           "semantic",
+			}},
+			"total": 1, "query": "session protocol", "mode": "auto", "depth": 0,
+		},
+	})
+
+	out, err := runBrain(t, "search", "session", "protocol", "--project", "brain", "--full-content")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	assertContains(t, out, "1. ADR-001 Session Protocol", "decisions/adr-001", "Score: 0.87 (semantic)")
+
+	args := onlyCall(t, srv, "search")
+	if args["query"] != "session protocol" || args["project"] != "brain" || args["full_context"] != true {
+		t.Errorf("search args = %v", args)
+	}
+}
+
+func TestE2E_SessionGetStateAndCreate(t *testing.T) {
+	srv := clienttest.NewServer(t)
+	srv.Handle("session", func(args map[string]any) (*client.ToolResult, error) {
+		if args["operation"] == "create" {
+			return clienttest.JSON(map[string]any{
+				"success":   true,
+				"sessionId": "SESSION-2026-02-04_01-e2e",
+				"path":      "sessions/SESSION-2026-02-04_01-e2e.md",
+			}), nil
+		}
+		return clienttest.Text(`{"currentMode":"coding","version":3}`), nil
+	})
+	srv.Install()
+
+	out, err := runBrain(t, "session", "get-state")
+	if err != nil {
+		t.Fatalf("get-state: %v", err)
+	}
+	if strings.TrimSpace(out) != `{"currentMode":"coding","version":3}` {
+		t.Errorf("get-state output = %q", out)
+	}
+
+	out, err = runBrain(t, "session", "create", "--topic", "e2e tests", "-p", "brain")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	assertContains(t, out, "Session created: SESSION-2026-02-04_01-e2e", "Path: sessions/SESSION-2026-02-04_01-e2e.md")
+
+	calls := srv.CallsTo("session")
+	if len(calls) != 2 || calls[0].Args["operation"] != "get" {
+		t.Fatalf("session calls = %+v", calls)
+	}
+	if a := calls[1].Args; a["operation"] != "create" || a["topic"] != "e2e tests" || a["project"] != "brain" {
+		t.Errorf("create args = %v", a)
+	}
+}
+
+func TestE2E_ProjectsListAndActive(t *testing.T) {
+	srv := fakeServer(t, map[string]any{
+		"list_projects":  []string{"brain", "website"},
+		"active_project": map[string]any{"active_project": "brain"},
+	})
+
+	out, err := runBrain(t, "projects", "list")
+	if err != nil {
+		t.Fatalf("projects list: %v", err)
+	}
+	assertContains(t, out, "Brain Memory Projects", "  brain\n", "  website\n")
+
+	out, err = runBrain(t, "projects", "active")
+	if err != nil {
+		t.Fatalf("projects active: %v", err)
+	}
+	assertContains(t, out, "Active: brain")
+	if args := onlyCall(t, srv, "active_project"); args["operation"] != "get" {
+		t.Errorf("active_project args = %v", args)
+	}
+}
+
+func TestE2E_ConfigGetAndSet(t *testing.T) {
+	srv := fakeServer(t, map[string]any{
+		"config_get": "info",
+		"config_set": map[string]any{"success": true, "key": "logging.level", "value": "debug", "reconfiguration_triggered": true},
+	})
+
+	out, err := runBrain(t, "config", "get", "logging.level")
+	if err != nil {
+		t.Fatalf("config get: %v", err)
+	}
+	if strings.TrimSpace(out) != "info" {
+		t.Errorf("config get output = %q, want info", out)
+	}
+
+	out, err = runBrain(t, "config", "set", "logging.level", "debug")
+	if err != nil {
+		t.Fatalf("config set: %v", err)
+	}
+	assertContains(t, out, "[PASS] Set logging.level = debug", "Reconfiguration triggered")
+
+	if args := onlyCall(t, srv, "config_set"); args["key"] != "logging.level" || args["value"] != "debug" {
+		t.Errorf("config_set args = %v", args)
+	}
+}
+
+func TestE2E_MigrateDryRun(t *testing.T) {
+	srv := fakeServer(t, map[string]any{
+		"migrate_config": map[string]any{"success": true, "dry_run": true, "old_config_found": false},
+	})
+
+	out, err := runBrain(t, "migrate", "--dry-run")
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	assertContains(t, out, "Config Migration (Dry Run)", "No old config found")
+
+	args := onlyCall(t, srv, "migrate_config")
+	if fmt.Sprint(args) != fmt.Sprint(map[string]any{"dry_run": true, "cleanup": false}) {
+		t.Errorf("migrate_config args = %v", args)
+	}
+}
+
+func TestE2E_FlagsResetBetweenRuns(t *testing.T) {
+	srv := fakeServer(t, map[string]any{
+		"migrate_config": map[string]any{"success": true, "old_config_found": false},
+	})
+
+	if _, err := runBrain(t, "migrate", "--dry-run"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runBrain(t, "migrate"); err != nil {
+		t.Fatal(err)
+	}
+	calls := srv.CallsTo("migrate_config")
+	if len(calls) != 2 || calls[1].Args["dry_run"] != false {
+		t.Errorf("second run inherited --dry-run: %+v", calls)
+	}
+}
+
+func TestE2E_TraceRecordsAndShows(t *testing.T) {
+	t.Cleanup(xdg.Reload) // runs after the env is restored
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv(client.EnvTrace, "")
+	xdg.Reload()
+	fakeServer(t, map[string]any{
+		"list_projects": []string{"brain"},
+		"config_get":    "info",
+	})
+
+	if _, err := runBrain(t, "projects", "list", "--trace"); err != nil {
+		t.Fatalf("projects list: %v", err)
+	}
+	if _, err := runBrain(t, "config", "get", "logging.level", "--trace"); err != nil {
+		t.Fatalf("config get: %v", err)
+	}
+
+	out, err := runBrain(t, "trace", "show", "--tool", "list_projects")
+	if err != nil {
+		t.Fatalf("trace show: %v", err)
+	}
+	assertContains(t, out, "tools/call list_projects", "  ok  ", `<- {"content"`)
+	if strings.Contains(out, "config_get") {
+		t.Errorf("--tool list_projects showed other tools:\n%s", out)
+	}
+
+	out, err = runBrain(t, "trace", "show", "--last", "1")
+	if err != nil {
+		t.Fatalf("trace show --last: %v", err)
+	}
+	if n := strings.Count(out, "tools/call"); n != 1 || !strings.Contains(out, "config_get") {
+		t.Errorf("--last 1 output:\n%s", out)
+	}
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/peterkloss/brain-tui/cmd"
+	"github.com/peterkloss/brain-tui/internal/installer"
+)
+
+type execCall struct {
+	binary string
+	args   []string
+	env    []string
+}
+
+// launchEnv isolates HOME and the cache dir, puts a fake claude on PATH,
+// starts a fake MCP server and records exec calls instead of performing them.
+func launchEnv(t *testing.T) (home string, calls *[]execCall) {
+	t.Helper()
+	// Registered first so it runs after the env is restored.
+	t.Cleanup(xdg.Reload)
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("BRAIN_PROJECT", "")
+	xdg.Reload()
+
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	fakeServer(t, nil)
+	calls = &[]execCall{}
+	t.Cleanup(cmd.SetExecFunc(func(binary string, args, env []string) error {
+		*calls = append(*calls, execCall{binary, args, env})
+		return nil
+	}))
+	return home, calls
+}
+
+// installFiles writes files under dir and a claude-code manifest listing them.
+func installFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	var paths []string
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	if err := installer.WriteManifest("claude-code", paths); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLaunch_ClaudeExecsWithEnvAndArgs(t *testing.T) {
+	home, calls := launchEnv(t)
+	installFiles(t, filepath.Join(home, ".claude"), "agents/architect.md", "hooks/hooks.json")
+
+	if _, err := runBrain(t, "claude", "--scope", "global", "-p", "brain", "--", "--resume", "abc"); err != nil {
+		t.Fatalf("brain claude: %v", err)
+	}
+	if len(*calls) != 1 {
+		t.Fatalf("exec called %d times, want 1", len(*calls))
+	}
+	c := (*calls)[0]
+	if filepath.Base(c.binary) != "claude" {
+		t.Errorf("binary = %q", c.binary)
+	}
+	if !slices.Equal(c.args, []string{"claude", "--resume", "abc"}) {
+		t.Errorf("args = %q", c.args)
+	}
+	for _, want := range []string{"BRAIN_PROJECT=brain", "BRAIN_TOOL=claude-code", "BRAIN_SCOPE=global"} {
+		if !slices.Contains(c.env, want) {
+			t.Errorf("env missing %s", want)
+		}
+	}
+	if n := strings.Count(strings.Join(c.env, "\n"), "BRAIN_PROJECT="); n != 1 {
+		t.Errorf("BRAIN_PROJECT set %d times", n)
+	}
+}
+
+func TestLaunch_PositionalArgsPassThrough(t *testing.T) {
+	home, calls := launchEnv(t)
+	installFiles(t, filepath.Join(home, ".claude"), "agents/architect.md")
+
+	if _, err := runBrain(t, "claude", "--scope", "global", "-p", "brain", "chat", "--verbose"); err != nil {
+		t.Fatalf("brain claude: %v", err)
+	}
+	if len(*calls) != 1 || !slices.Equal((*calls)[0].args, []string{"claude", "chat", "--verbose"}) {
+		t.Errorf("exec calls = %+v", *calls)
+	}
+}
+
+func TestLaunch_RequiresCurrentManifest(t *testing.T) {
+	home, calls := launchEnv(t)
+
+	_, err := runBrain(t, "claude", "--scope", "global", "-p", "brain")
+	if err == nil || !strings.Contains(err.Error(), "no install manifest") || !strings.Contains(err.Error(), "brain install") {
+		t.Errorf("missing manifest: err = %v", err)
+	}
+
+	installFiles(t, filepath.Join(home, ".claude"), "agents/architect.md")
+	os.Remove(filepath.Join(home, ".claude", "agents", "architect.md"))
+	_, err = runBrain(t, "claude", "--scope", "global", "-p", "brain")
+	if err == nil || !strings.Contains(err.Error(), "installed file missing") {
+		t.Errorf("missing file: err = %v", err)
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("exec called despite stale install: %+v", *calls)
+	}
+}
+
+func TestLaunch_ScopeProject(t *testing.T) {
+	home, calls := launchEnv(t)
+	installFiles(t, filepath.Join(home, ".claude"), "agents/architect.md")
+
+	// Installed globally, so the project scope is not current.
+	_, err := runBrain(t, "claude", "--scope", "project", "-p", "brain")
+	if err == nil || !strings.Contains(err.Error(), "outside the project scope") {
+		t.Errorf("err = %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installFiles(t, filepath.Join(cwd, ".claude"), "agents/architect.md")
+	t.Cleanup(func() { os.RemoveAll(filepath.Join(cwd, ".claude")) })
+	if _, err := runBrain(t, "claude", "--scope", "project", "-p", "brain"); err != nil {
+		t.Fatalf("brain claude --scope project: %v", err)
+	}
+	if len(*calls) != 1 || !slices.Contains((*calls)[0].env, "BRAIN_SCOPE=project") {
+		t.Errorf("exec calls = %+v", *calls)
+	}
+
+	_, err = runBrain(t, "claude", "--scope", "nope", "-p", "brain")
+	if err == nil || !strings.Contains(err.Error(), "available scopes") {
+		t.Errorf("unknown scope: err = %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func memoriesDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "skills-git-index.md"), "# Git\n\n| Keywords | File |\n|---|---|\n| commit | git-gone |\n")
+	writeFile(t, filepath.Join(dir, "memory-index.md"), "| Keywords | File |\n|----------|------|\n| git | skills-git-index |\n")
+	writeFile(t, filepath.Join(dir, "git-branch-naming.md"), "---\ntags: [branches, kebab]\n---\n# Branch naming\n\n## Prefixes\n")
+	return dir
+}
+
+func TestMemoryReindex(t *testing.T) {
+	dir := memoriesDir(t)
+	index := filepath.Join(dir, "skills-git-index.md")
+	before, _ := os.ReadFile(index)
+
+	out, err := runBrain(t, "memory", "reindex", dir, "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run: %v\n%s", err, out)
+	}
+	assertContains(t, out, "would update", "added: git-branch-naming", "removed: git-gone",
+		"-# Git", "+| branches kebab branch naming prefixes | git-branch-naming |", "(dry run)")
+	if after, _ := os.ReadFile(index); string(after) != string(before) {
+		t.Fatal("--dry-run wrote the index")
+	}
+
+	out, err = runBrain(t, "memory", "reindex", dir)
+	if err != nil {
+		t.Fatalf("reindex: %v\n%s", err, out)
+	}
+	assertContains(t, out, "1 index files rebuilt")
+	if out, err := runBrain(t, "validate", "memory-index", dir); err != nil {
+		t.Fatalf("reindexed memories fail validation: %v\n%s", err, out)
+	}
+
+	out, err = runBrain(t, "memory", "reindex", dir)
+	if err != nil || !strings.Contains(out, "indexes are up to date") {
+		t.Errorf("second reindex: %v\n%s", err, out)
+	}
+}
+
+func TestMemoryReindex_Domain(t *testing.T) {
+	dir := memoriesDir(t)
+	writeFile(t, filepath.Join(dir, "ci-runners.md"), "# Self-hosted runners\n")
+
+	out, err := runBrain(t, "memory", "reindex", dir, "--domain", "ci", "--format", "json")
+	if err != nil {
+		t.Fatalf("reindex --domain: %v\n%s", err, out)
+	}
+	assertContains(t, out, `"created": true`, `"ci-runners"`)
+	if _, err := os.Stat(filepath.Join(dir, "skills-ci-index.md")); err != nil {
+		t.Error("skills-ci-index.md was not created")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "skills-git-index.md")); !strings.Contains(string(data), "git-gone") {
+		t.Error("--domain ci rebuilt the git index")
+	}
+}
+
+func TestMemoryReindex_UsageErrors(t *testing.T) {
+	dir := memoriesDir(t)
+	for _, args := range [][]string{
+		{"memory", "reindex", filepath.Join(dir, "missing")},
+		{"memory", "reindex", dir, "--domain", "Bad Domain"},
+		{"memory", "reindex", dir, "--format", "xml"},
+		{"memory", "reindex", dir, "--min-keywords", "0"},
+	} {
+		if _, err := runBrain(t, args...); exitCode(err) != 2 {
+			t.Errorf("%v: exit code = %d (err %v), want 2", args, exitCode(err), err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// allRepo creates a repository (not a git repo) with one valid and one
+// broken skill and a memories directory outside it.
+func allRepo(t *testing.T) (repo, memories string) {
+	t.Helper()
+	repo = t.TempDir()
+	writeFile(t, filepath.Join(repo, ".claude", "skills", "memory", "SKILL.md"), "---\nname: memory\ndescription: Search and write memories\n---\n\n# Memory\n")
+	writeFile(t, filepath.Join(repo, ".claude", "skills", "broken", "SKILL.md"), "# No frontmatter\n")
+	memories = t.TempDir()
+	writeFile(t, filepath.Join(memories, "memory-index.md"), "| Keywords | File |\n|----------|------|\n")
+	return repo, memories
+}
+
+func TestValidateAll_ConsoleSkipsInapplicable(t *testing.T) {
+	repo, memories := allRepo(t)
+
+	out, err := runBrain(t, "validate", "all", repo, "--memories", memories)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out,
+		"Validation Report",
+		"[PASS] skills "+filepath.Join(".claude", "skills", "memory", "SKILL.md"),
+		"[FAIL] skills "+filepath.Join(".claude", "skills", "broken", "SKILL.md"),
+		"[SKIP] traceability: no specs directory",
+		"[SKIP] skill-violations: not a git repository",
+		"memory-index",
+		"Summary:")
+}
+
+func TestValidateAll_SARIF(t *testing.T) {
+	repo, memories := allRepo(t)
+
+	out, err := runBrain(t, "validate", "all", repo, "--memories", memories, "--format", "sarif")
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1", code, err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("decode SARIF: %v\n%s", err, out)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF log = %+v", log)
+	}
+	found := false
+	for _, r := range log.Runs[0].Results {
+		if r.RuleID == "skills/frontmatter_present" {
+			found = true
+			if len(r.Locations) != 1 || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != ".claude/skills/broken/SKILL.md" {
+				t.Errorf("frontmatter result locations = %+v", r.Locations)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no skills/frontmatter_present result:\n%s", out)
+	}
+}
+
+func TestValidateAll_JUnitToFile(t *testing.T) {
+	repo, memories := allRepo(t)
+	output := filepath.Join(t.TempDir(), "junit.xml")
+
+	out, err := runBrain(t, "validate", "all", repo, "--memories", memories, "--format", "junit", "--output", output)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1", code, err)
+	}
+	if out != "" {
+		t.Errorf("--output still printed to stdout:\n%s", out)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites struct {
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("decode JUnit: %v\n%s", err, data)
+	}
+	if suites.Failures == 0 || len(suites.Suites) == 0 {
+		t.Errorf("JUnit report = %+v\n%s", suites, data)
+	}
+}
+
+func TestValidateAll_UnknownFormatIsUsageError(t *testing.T) {
+	_, err := runBrain(t, "validate", "all", t.TempDir(), "--format", "html")
+	if code := exitCode(err); code != 2 {
+		t.Errorf("exit code = %d (err %v), want 2", code, err)
+	}
+}
+
+func TestValidateAll_OnlyAndList(t *testing.T) {
+	repo, memories := allRepo(t)
+
+	out, err := runBrain(t, "validate", "all", repo, "--memories", memories, "--only", "memory-index,traceability")
+	if err != nil && exitCode(err) != 1 {
+		t.Fatalf("--only: %v", err)
+	}
+	assertContains(t, out, "memory-index", "[SKIP] traceability")
+	if strings.Contains(out, "skills") {
+		t.Errorf("--only ran other validators:\n%s", out)
+	}
+
+	_, err = runBrain(t, "validate", "all", repo, "--only", "nope")
+	if code := exitCode(err); code != 2 {
+		t.Errorf("unknown validator exit code = %d, want 2", code)
+	}
+
+	out, err = runBrain(t, "validate", "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	assertContains(t, out, "pre-pr", "traceability", "skill-violations")
+}
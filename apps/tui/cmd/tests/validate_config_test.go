@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_IgnoreAndSeverity(t *testing.T) {
+	repo, _ := allRepo(t)
+	skills := filepath.Join(repo, ".claude", "skills")
+
+	writeFile(t, filepath.Join(repo, ".brain", "validation.yaml"), "version: 1\nignore:\n  - .claude/skills/broken/**\n")
+	out, err := runBrain(t, "validate", "skills", skills)
+	if err != nil {
+		t.Fatalf("ignored skill still failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "broken") {
+		t.Errorf("ignored skill was validated:\n%s", out)
+	}
+	out, err = runBrain(t, "validate", "all", repo, "--only", "skills")
+	if err != nil {
+		t.Fatalf("validate all with ignore: %v\n%s", err, out)
+	}
+
+	writeFile(t, filepath.Join(repo, ".brain", "validation.yaml"), "version: 1\nseverity:\n  skills/frontmatter_present: warn\n")
+	out, err = runBrain(t, "validate", "skills", skills)
+	if err != nil {
+		t.Fatalf("warned check still failed: %v\n%s", err, out)
+	}
+	assertContains(t, out, "[PASS] frontmatter_present", "(warning)", "skills: 2 of 2 passed")
+}
+
+func TestValidateConfig_InvalidIsUsageError(t *testing.T) {
+	repo, _ := allRepo(t)
+	writeFile(t, filepath.Join(repo, ".brain", "validation.yaml"), "severity:\n  skills: loud\n")
+
+	_, err := runBrain(t, "validate", "skills", filepath.Join(repo, ".claude", "skills"))
+	if code := exitCode(err); code != 2 {
+		t.Errorf("skills exit code = %d (err %v), want 2", code, err)
+	}
+	_, err = runBrain(t, "validate", "all", repo)
+	if code := exitCode(err); code != 2 {
+		t.Errorf("all exit code = %d (err %v), want 2", code, err)
+	}
+}
+
+func TestValidateConfig_PrePRIgnoreAndSuppression(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	writeFile(t, filepath.Join(repo, ".github", "workflows", "ci.yml"), "jobs:\n  test:\n    steps:\n      - run: make build test\n")
+	writeFile(t, filepath.Join(repo, "src", "a.go"), "package src\n\nconst password = \"hunter2\"\n\n// TODO: rotate\n")
+	config := filepath.Join(repo, ".brain", "validation.yaml")
+
+	out, err := runBrain(t, "validate", "pre-pr", repo)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out, `src/a.go:3 - Hardcoded value: password = "hunter2"`, "src/a.go:5 - TODO comment")
+
+	writeFile(t, config, "version: 1\nignore: [\"src/**\"]\n")
+	for _, args := range [][]string{{"pre-pr", repo}, {"all", repo, "--only", "pre-pr"}} {
+		if out, err := runBrain(t, append([]string{"validate"}, args...)...); err != nil {
+			t.Errorf("validate %v with src/** ignored: %v\n%s", args, err, out)
+		}
+	}
+
+	writeFile(t, config, "version: 1\n")
+	writeFile(t, filepath.Join(repo, "src", "a.go"), "package src\n\n// brain-ignore cross_cutting_concerns: test fixture\nconst password = \"hunter2\"\n")
+	for _, args := range [][]string{{"pre-pr", repo}, {"all", repo, "--only", "pre-pr"}} {
+		if out, err := runBrain(t, append([]string{"validate"}, args...)...); err != nil {
+			t.Errorf("validate %v with the value suppressed: %v\n%s", args, err, out)
+		}
+	}
+
+	// Only the configured source directories are checked.
+	writeFile(t, filepath.Join(repo, "src", "a.go"), "package src\n\nconst password = \"hunter2\"\n")
+	writeFile(t, config, "version: 1\nprePR:\n  sourceDirs: [app]\n")
+	if out, err := runBrain(t, "validate", "pre-pr", repo); err != nil {
+		t.Errorf("src checked despite sourceDirs: %v\n%s", err, out)
+	}
+}
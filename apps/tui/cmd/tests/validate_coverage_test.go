@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateTestCoverage_Profile(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	writeFile(t, filepath.Join(repo, "src", "calc.ts"),
+		"export function add(a: number, b: number) {\n  return a + b;\n}\n\nexport function sub(a: number, b: number) {\n  return a - b;\n}\n")
+	// A test file exists, so the heuristic alone passes.
+	writeFile(t, filepath.Join(repo, "src", "calc.test.ts"), "import { add } from './calc';\n")
+	profile := filepath.Join(repo, "coverage", "lcov.info")
+	writeFile(t, profile, "SF:src/calc.ts\nFN:1,add\nFN:5,sub\nDA:2,4\nDA:6,0\nend_of_record\n")
+
+	if out, err := runBrain(t, "validate", "test-coverage", repo, "--language", "typescript", "--threshold", "80"); err != nil {
+		t.Fatalf("heuristic: %v\n%s", err, out)
+	}
+
+	out, err := runBrain(t, "validate", "test-coverage", repo, "--language", "typescript", "--threshold", "80",
+		"--coverage-profile", profile)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out, "Line coverage 50% below threshold 80%", "src/calc.ts: 50% (uncovered lines 6)")
+
+	out, err = runBrain(t, "validate", "test-coverage", repo, "--language", "typescript", "--threshold", "80",
+		"--coverage-profile", profile, "--format", "json")
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("json exit code = %d (err %v), want 1", code, err)
+	}
+	assertContains(t, out, `"profileFormat": "lcov"`, `"name": "sub"`, `"belowThreshold": true`)
+}
+
+func TestValidateTestCoverage_ConfiguredLanguagePolyglot(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	writeFile(t, filepath.Join(repo, ".brain", "validation.yaml"), `version: 1
+languages:
+  elixir:
+    extensions: [".ex"]
+    testNames: ["{name}_test.exs"]
+    testPattern: "_test\\.exs$"
+    testLayouts: [{source: lib, test: test}]
+`)
+	writeFile(t, filepath.Join(repo, "lib", "billing", "invoice.ex"), "defmodule Invoice do end\n")
+	writeFile(t, filepath.Join(repo, "lib", "billing", "refund.ex"), "defmodule Refund do end\n")
+	writeFile(t, filepath.Join(repo, "test", "billing", "invoice_test.exs"), "")
+	writeFile(t, filepath.Join(repo, "src", "main", "java", "Order.java"), "class Order {}\n")
+
+	out, err := runBrain(t, "validate", "test-coverage", repo, "--threshold", "50")
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out, "elixir: 50% of 2 files", "java: 0% of 1 files", "test/billing/refund_test.exs")
+
+	if out, err := runBrain(t, "validate", "test-coverage", repo, "--language", "elixir", "--threshold", "50"); err != nil {
+		t.Fatalf("elixir only: %v\n%s", err, out)
+	}
+}
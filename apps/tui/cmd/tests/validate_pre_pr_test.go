@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidatePrePR_Base(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "src", "legacy.go"), "package src\n\n// TODO: old debt\n")
+	writeFile(t, filepath.Join(repo, "src", "app.go"), "package src\n")
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"add", "-A"}, {"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-qm", "base"}} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// The committed TODO is outside the diff.
+	if out, err := runBrain(t, "validate", "pre-pr", repo, "--base", "main", "--quick"); err != nil {
+		t.Fatalf("no changes: %v\n%s", err, out)
+	}
+
+	writeFile(t, filepath.Join(repo, "src", "app.go"), "package src\n\n// FIXME: later\n")
+	out, err := runBrain(t, "validate", "pre-pr", repo, "--base", "main", "--quick")
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out, "src/app.go:3 - FIXME comment")
+
+	out, err = runBrain(t, "validate", "pre-pr", repo, "--base", "origin/nope")
+	if code := exitCode(err); code != 2 {
+		t.Errorf("unknown base: exit code = %d (err %v), want 2\n%s", code, err, out)
+	}
+}
+
+func TestValidatePrePR_Diff(t *testing.T) {
+	dir := t.TempDir()
+	patch := filepath.Join(dir, "staged.patch")
+	writeFile(t, patch, "--- a/src/client.go\n+++ b/src/client.go\n@@ -4,0 +5 @@\n+\tcfg := &tls.Config{InsecureSkipVerify: true}\n")
+
+	out, err := runBrain(t, "validate", "pre-pr", "--diff", patch, "--format", "json")
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out, `"file": "src/client.go"`, `"line": 5`, `"message": "Insecure default: InsecureSkipVerify: true"`)
+
+	out, err = runBrain(t, "validate", "pre-pr", "--diff", patch, "--base", "main")
+	if code := exitCode(err); code != 2 {
+		t.Errorf("--diff with --base: exit code = %d (err %v), want 2\n%s", code, err, out)
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// repoProtocol is the SESSION-PROTOCOL.md shipped in templates/protocols.
+func repoProtocol(t *testing.T) string {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "..", "templates", "protocols", "SESSION-PROTOCOL.md")
+}
+
+func TestValidateSession_Fix(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "SESSION-2026-01-20_01-fix.md")
+	original := "# Session 01\n\n## Session Info\n\n- **Branch**: feat/x\n\n## Protocol Compliance\n\n" +
+		"### Session Start\n\n| Req | Step | Status | Evidence |\n|---|---|---|---|\n" +
+		"| MUST | Initialize Brain memory tools | [x] | Brain MCP initialized |\n"
+	writeFile(t, logPath, original)
+
+	out, err := runBrain(t, "validate", "session", logPath, "--fix", "--dry-run", "--protocol", repoProtocol(t))
+	if err != nil {
+		t.Fatalf("dry run: %v\n%s", err, out)
+	}
+	assertContains(t, out,
+		"--- a/"+strings.TrimPrefix(filepath.ToSlash(logPath), "/"),
+		"+## Session End (COMPLETE ALL before closing)",
+		"| Initialize Brain memory tools              | [x]    | Brain MCP initialized ",
+		"Added missing section 'Session End'")
+	if data, _ := os.ReadFile(logPath); string(data) != original {
+		t.Fatal("--dry-run wrote the session log")
+	}
+
+	out, err = runBrain(t, "validate", "session", logPath, "--fix", "--protocol", repoProtocol(t))
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1 for the unchecked checklist", code, err)
+	}
+	if strings.Contains(out, "+++") || !strings.Contains(out, `"section_session_end"`) {
+		t.Errorf("stdout should hold only the validation result:\n%s", out)
+	}
+	data, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(data), "## Session End") {
+		t.Error("--fix did not write the session log")
+	}
+}
+
+func TestValidateSession_FixUsageErrors(t *testing.T) {
+	if _, err := runBrain(t, "validate", "session", "--fix"); exitCode(err) != 2 {
+		t.Errorf("--fix without a log: exit code %d, want 2", exitCode(err))
+	}
+	if _, err := runBrain(t, "validate", "session", "--dry-run"); exitCode(err) != 2 {
+		t.Errorf("--dry-run without --fix: exit code %d, want 2", exitCode(err))
+	}
+	log := filepath.Join(t.TempDir(), "SESSION-2026-01-20_01-x.md")
+	writeFile(t, log, "# Session\n")
+	if _, err := runBrain(t, "validate", "session", log, "--fix", "--protocol", filepath.Join(t.TempDir(), "missing.md")); exitCode(err) != 2 {
+		t.Errorf("missing protocol: exit code %d, want 2", exitCode(err))
+	}
+}
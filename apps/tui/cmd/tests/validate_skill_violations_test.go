@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateSkillViolations_RuleFile(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	writeFile(t, filepath.Join(repo, ".claude", "skills", "release", "SKILL.md"), "---\nname: release\n---\n")
+	writeFile(t, filepath.Join(repo, "RELEASING.md"), "Never run npm publish by hand.\n\n```bash\nnpm publish\n```\n")
+	rules := filepath.Join(repo, "ci", "skill-rules.yaml")
+	writeFile(t, rules, "version: 1\nrules:\n  - {id: npm-publish, pattern: 'npm\\s+publish', skill: release, severity: error}\n")
+
+	// Without the rule file only the built-in gh rules apply.
+	if out, err := runBrain(t, "validate", "skill-violations", repo); err != nil {
+		t.Fatalf("built-in rules: %v\n%s", err, out)
+	}
+
+	out, err := runBrain(t, "validate", "skill-violations", repo, "--rules", rules)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1\n%s", code, err, out)
+	}
+	assertContains(t, out, "RELEASING.md:4:1 - matches 'npm\\s+publish' (use the release skill)")
+
+	out, err = runBrain(t, "validate", "skill-violations", repo, "--rules", rules, "--format", "json")
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("json exit code = %d (err %v), want 1", code, err)
+	}
+	assertContains(t, out, `"rule": "npm-publish"`, `"language": "shell"`, `"column": 1`)
+
+	writeFile(t, filepath.Join(repo, ".brain", "validation.yaml"), "version: 1\nignore: [RELEASING.md]\n")
+	if out, err := runBrain(t, "validate", "skill-violations", repo, "--rules", rules); err != nil {
+		t.Errorf("ignored file still failed: %v\n%s", err, out)
+	}
+}
+
+func TestValidateSkillViolations_InvalidRulesAreUsageErrors(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	writeFile(t, filepath.Join(repo, ".brain", "skill-rules.yaml"), "rules:\n  - {id: a, pattern: x}\n")
+
+	for _, args := range [][]string{
+		{"validate", "skill-violations", repo},
+		{"validate", "skill-violations", repo, "--rules", filepath.Join(repo, "missing.yaml")},
+	} {
+		out, err := runBrain(t, args...)
+		if code := exitCode(err); code != 2 {
+			t.Errorf("%v: exit code = %d (err %v), want 2\n%s", args, code, err, out)
+		}
+	}
+}
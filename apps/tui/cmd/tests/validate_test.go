@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/peterkloss/brain-tui/cmd"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// exitCode returns the code a command error makes brain exit with.
+func exitCode(err error) int {
+	var exitErr *cmd.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	default:
+		return 1
+	}
+}
+
+func TestValidate_SkillsConsoleAndExitCode(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "memory", "SKILL.md"), "---\nname: memory\ndescription: Search and write memories\n---\n\n# Memory\n")
+	writeFile(t, filepath.Join(dir, "broken", "SKILL.md"), "# No frontmatter\n")
+
+	out, err := runBrain(t, "validate", "skills", dir)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("exit code = %d (err %v), want 1", code, err)
+	}
+	assertContains(t, out,
+		"[PASS] skills "+filepath.Join(dir, "memory", "SKILL.md"),
+		"[FAIL] skills "+filepath.Join(dir, "broken", "SKILL.md"),
+		"[FAIL] frontmatter_present",
+		"skills: 1 of 2 passed")
+
+	out, err = runBrain(t, "validate", "skills", filepath.Join(dir, "memory", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("valid skill: %v\n%s", err, out)
+	}
+}
+
+func TestValidate_CommandsJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "analyze.md"), "---\ndescription: Use when you need to analyze code patterns\n---\n\n# Analyze\n")
+	writeFile(t, filepath.Join(dir, "nested", "review.md"), "# No frontmatter\n")
+
+	out, err := runBrain(t, "validate", "commands", dir, "--format", "json")
+	if err != nil {
+		t.Fatalf("commands: %v\n%s", err, out)
+	}
+	var results []struct {
+		Valid    bool   `json:"valid"`
+		FilePath string `json:"filePath"`
+	}
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(results) != 1 || !results[0].Valid {
+		t.Errorf("results = %+v, want one valid command", results)
+	}
+
+	_, err = runBrain(t, "validate", "commands", dir, "--recursive", "--format", "json")
+	if code := exitCode(err); code != 1 {
+		t.Errorf("--recursive exit code = %d, want 1 for the nested command", code)
+	}
+}
+
+func TestValidate_PRDescription(t *testing.T) {
+	dir := t.TempDir()
+	body := filepath.Join(dir, "pr.md")
+	writeFile(t, body, "## Summary\n\nUpdates `src/app.ts`.\n")
+
+	out, err := runBrain(t, "validate", "pr-description", "--body-file", body, "--files", "src/app.ts", "--format", "json")
+	var result struct {
+		CriticalCount int `json:"criticalCount"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("decode %q: %v", out, jerr)
+	}
+	if result.CriticalCount != 0 {
+		t.Errorf("criticalCount = %d for a matching description (err %v)", result.CriticalCount, err)
+	}
+
+	files := filepath.Join(dir, "files.txt")
+	writeFile(t, files, "src/other.ts\n")
+	out, err = runBrain(t, "validate", "pr-description", "--body-file", body, "--files-from", files)
+	if code := exitCode(err); code != 1 {
+		t.Errorf("exit code = %d, want 1 when a mentioned file is not in the diff", code)
+	}
+	assertContains(t, out, "[FAIL] pr-description")
+}
+
+func TestValidate_TraceabilityEmptySpecs(t *testing.T) {
+	out, err := runBrain(t, "validate", "traceability", t.TempDir(), "--strict")
+	if err != nil {
+		t.Fatalf("traceability: %v\n%s", err, out)
+	}
+	assertContains(t, out, "Traceability Validation Report", "Requirements: 0")
+}
+
+func TestValidate_TraceabilityGraph(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	specs := filepath.Join(repo, ".agents", "specs")
+	writeFile(t, filepath.Join(specs, "requirements", "REQ-012.md"), "---\ntype: requirement\nid: REQ-012\nstatus: approved\n---\n")
+	writeFile(t, filepath.Join(specs, "design", "DESIGN-004.md"), "---\ntype: design\nid: DESIGN-004\nstatus: approved\nrelated:\n  - REQ-012\n---\n")
+	writeFile(t, filepath.Join(specs, "tasks", "TASK-020.md"), "---\ntype: task\nid: TASK-020\nstatus: done\nrelated:\n  - DESIGN-004\ncode:\n  - src/export.go\ntests:\n  - src/export_test.go\n---\n")
+	writeFile(t, filepath.Join(repo, "src", "export.go"), "package src\n")
+	writeFile(t, filepath.Join(repo, "src", "export_test.go"), "package src\n\n// TASK-020\n")
+
+	out, err := runBrain(t, "validate", "traceability", specs, "--graph", "matrix", "--focus", "REQ-012")
+	if err != nil {
+		t.Fatalf("graph: %v\n%s", err, out)
+	}
+	assertContains(t, out, "| REQ-012 | DESIGN-004 | TASK-020 | src/export.go | src/export_test.go |")
+
+	out, err = runBrain(t, "validate", "traceability", specs, "--graph", "dot")
+	if err != nil {
+		t.Fatalf("graph: %v\n%s", err, out)
+	}
+	assertContains(t, out, `"TASK-020" -> "src/export_test.go";`)
+
+	for _, args := range [][]string{
+		{"--graph", "svg"},
+		{"--focus", "REQ-012"},
+		{"--graph", "csv", "--focus", "REQ-999"},
+		{"--graph", "csv", "--format", "json"},
+	} {
+		_, err := runBrain(t, append([]string{"validate", "traceability", specs}, args...)...)
+		if code := exitCode(err); code != 2 {
+			t.Errorf("%s: exit code = %d (err %v), want 2", strings.Join(args, " "), code, err)
+		}
+	}
+
+	// --focus must not hide the global --trace flag.
+	out, err = runBrain(t, "validate", "traceability", "--help")
+	if err != nil {
+		t.Fatalf("help: %v\n%s", err, out)
+	}
+	assertContains(t, out, "--focus string", "--trace string[=\"")
+}
+
+func TestValidate_TraceabilityStatusRules(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	specs := filepath.Join(repo, ".agents", "specs")
+	writeFile(t, filepath.Join(specs, "requirements", "REQ-001.md"), "---\ntype: requirement\nid: REQ-001\nstatus: draft\n---\n")
+	writeFile(t, filepath.Join(specs, "design", "DESIGN-001.md"), "---\ntype: design\nid: DESIGN-001\nstatus: approved\nrelated:\n  - REQ-001\n---\n")
+	writeFile(t, filepath.Join(specs, "tasks", "TASK-001.md"), "---\ntype: task\nid: TASK-001\nstatus: pending\nrelated:\n  - DESIGN-001\n---\n")
+
+	out, err := runBrain(t, "validate", "traceability", specs)
+	if err != nil {
+		t.Fatalf("non-strict: %v\n%s", err, out)
+	}
+	assertContains(t, out, "[Status Rule: design-approved] DESIGN 'DESIGN-001' is 'approved' but REQ 'REQ-001' is 'draft'",
+		"Suggested: Set DESIGN 'DESIGN-001' to 'review' until REQ 'REQ-001' is no longer 'draft'")
+
+	if _, err := runBrain(t, "validate", "traceability", specs, "--strict"); exitCode(err) != 1 {
+		t.Errorf("strict: exit code = %d (err %v), want 1", exitCode(err), err)
+	}
+
+	writeFile(t, filepath.Join(repo, ".brain", "validation.yaml"), "traceability:\n  statusRules:\n    - name: design-approved\n      severity: \"off\"\n")
+	if out, err := runBrain(t, "validate", "traceability", specs, "--strict"); err != nil {
+		t.Errorf("rule off: %v\n%s", err, out)
+	}
+}
+
+func TestValidate_ConsistencyCache(t *testing.T) {
+	t.Cleanup(xdg.Reload) // runs after the env is restored
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	xdg.Reload()
+
+	repo := t.TempDir()
+	for _, f := range []string{"auth", "payment"} {
+		writeFile(t, filepath.Join(repo, ".agents", "planning", "prd-"+f+".md"), "# PRD\n\n## Requirements\n\n- [ ] Req 1\n")
+		writeFile(t, filepath.Join(repo, ".agents", "planning", "tasks-"+f+".md"), "# Tasks\n\n### Task 1\n")
+	}
+	cached := func(args ...string) string {
+		t.Helper()
+		out, err := runBrain(t, append([]string{"validate", "consistency", repo, "--format", "json"}, args...)...)
+		if err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return out
+	}
+
+	if out := cached(); strings.Contains(out, `"cached"`) {
+		t.Errorf("first run reused cached results:\n%s", out)
+	}
+	if out := cached(); strings.Count(out, `"cached": true`) != 2 {
+		t.Errorf("second run did not reuse cached results:\n%s", out)
+	}
+	if out := cached("--no-cache"); strings.Contains(out, `"cached"`) {
+		t.Errorf("--no-cache reused cached results:\n%s", out)
+	}
+
+	// validate all shares the cache.
+	out, err := runBrain(t, "validate", "all", repo, "--only", "consistency", "--format", "json")
+	if err != nil {
+		t.Fatalf("all: %v\n%s", err, out)
+	}
+	if strings.Count(out, `"cached": true`) != 2 {
+		t.Errorf("validate all did not reuse cached results:\n%s", out)
+	}
+	if out, _ := runBrain(t, "validate", "all", repo, "--only", "consistency", "--format", "json", "--no-cache"); strings.Contains(out, `"cached"`) {
+		t.Errorf("validate all --no-cache reused cached results:\n%s", out)
+	}
+}
+
+func TestValidate_UsageErrorsExit2(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"validate", "consistency", dir, "--checkpoint", "3"},
+		{"validate", "memory-index", filepath.Join(dir, "missing")},
+		{"validate", "test-coverage", dir, "--language", "cobol"},
+		{"validate", "test-coverage", dir, "--threshold", "120"},
+		{"validate", "test-coverage", dir, "--coverage-profile", filepath.Join(dir, "missing.out")},
+		{"validate", "test-coverage", dir, "--profile-format", "lcov"},
+		{"validate", "pre-pr", dir, "--format", "xml"},
+		{"validate", "pre-pr", dir, "--no-such-flag"},
+		{"validate", "pr-description", "--files", "a.go"},
+	} {
+		_, err := runBrain(t, args...)
+		if code := exitCode(err); code != 2 {
+			t.Errorf("%s: exit code = %d (err %v), want 2", strings.Join(args, " "), code, err)
+		}
+	}
+}
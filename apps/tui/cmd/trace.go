@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/peterkloss/brain-tui/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceLast int
+	traceTool string
+	traceJSON bool
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Inspect recorded MCP wire traces",
+	Long: `Inspect MCP requests and responses recorded with --trace.
+
+Run any command with --trace (or BRAIN_TRACE=file) to append one JSON record
+per request to the trace file under the cache dir; --trace=stderr
+(BRAIN_TRACE=stderr) writes the records to stderr instead. Records hold the
+method, tool, request ID, session, latency, status and truncated bodies.
+
+Examples:
+  brain search "session protocol" --trace
+  BRAIN_TRACE=file brain session get-state   # from a hook
+  brain trace show --last 5
+  brain trace show --tool session`,
+}
+
+var traceShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Pretty-print recorded traces",
+	Args:  cobra.NoArgs,
+	RunE:  runTraceShow,
+}
+
+func init() {
+	rootCmd.AddCommand(traceCmd)
+	traceCmd.AddCommand(traceShowCmd)
+
+	traceShowCmd.Flags().IntVarP(&traceLast, "last", "n", 20, "Show the last N records (0 for all)")
+	traceShowCmd.Flags().StringVar(&traceTool, "tool", "", "Only show tools/call records for this tool")
+	traceShowCmd.Flags().BoolVar(&traceJSON, "json", false, "Output records as JSON lines")
+}
+
+// runTraceShow handles 'brain trace show'
+func runTraceShow(cmd *cobra.Command, args []string) error {
+	path := client.TracePath()
+	records, err := client.ReadTraceRecords(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+
+	if traceTool != "" {
+		filtered := records[:0]
+		for _, rec := range records {
+			if rec.Tool == traceTool {
+				filtered = append(filtered, rec)
+			}
+		}
+		records = filtered
+	}
+	if traceLast > 0 && len(records) > traceLast {
+		records = records[len(records)-traceLast:]
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("No trace records in %s\n", path)
+		fmt.Println("Run a command with --trace to record some.")
+		return nil
+	}
+
+	if traceJSON {
+		tracer := client.NewWriterTracer(os.Stdout)
+		for _, rec := range records {
+			tracer.Trace(rec)
+		}
+		return nil
+	}
+	for _, rec := range records {
+		printTraceRecord(rec)
+	}
+	return nil
+}
+
+// printTraceRecord prints one record as a header line followed by its bodies.
+func printTraceRecord(rec client.TraceRecord) {
+	name := rec.Method
+	if rec.Tool != "" {
+		name += " " + rec.Tool
+	}
+	fmt.Printf("%s  %s", rec.Time.Local().Format("2006-01-02 15:04:05.000"), name)
+	if rec.ID != 0 {
+		fmt.Printf("  #%d", rec.ID)
+	}
+	fmt.Printf("  %s  %.1fms", rec.Status, rec.LatencyMS)
+	if rec.HTTPStatus != 0 {
+		fmt.Printf("  HTTP %d", rec.HTTPStatus)
+	}
+	if rec.Session != "" {
+		fmt.Printf("  session %s", rec.Session)
+	}
+	fmt.Println()
+
+	if rec.Request != "" {
+		fmt.Printf("  -> %s\n", rec.Request)
+	}
+	if rec.Response != "" {
+		fmt.Printf("  <- %s\n", rec.Response)
+	}
+	if rec.Error != "" {
+		fmt.Printf("  !! %s\n", rec.Error)
+	}
+	fmt.Println()
+}
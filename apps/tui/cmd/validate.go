@@ -10,12 +10,52 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var sessionLogPath string
+var (
+	sessionLogPath      string
+	sessionFix          bool
+	sessionFixDryRun    bool
+	sessionProtocolPath string
+)
 
 var validateCmd = &cobra.Command{
 	Use:   "validate",
-	Short: "Session validation commands",
-	Long:  `Commands for validating session state before exit.`,
+	Short: "Validation commands",
+	Long: `Commands for validating session state and running the offline validators
+against a repository.
+
+Every subcommand exits 0 when validation passes, 1 when it fails and 2 for
+invalid flags or arguments. Offline validators print a console report, or
+the full result with --format json. 'brain validate all' runs every
+applicable validator and can also emit SARIF and JUnit XML.
+
+Validators read .brain/validation.yaml from the repository root when it
+exists. It sets check severities (error, warn or off), path ignores, extra
+test coverage languages, pre-PR directories and session protocol and PR
+description patterns:
+
+  version: 1
+  severity:
+    ci_environment: warn          # a check in any validator
+    pre-pr/fail_safe_design: off  # a check in one validator
+    memory-index: off             # a whole validator
+  ignore:
+    - vendor/**
+  languages:
+    elixir: {extensions: [".ex"], testSuffix: "_test.exs", testPattern: "_test\\.exs$"}
+    scala:
+      extensions: [".scala"]
+      testNames: ["{name}Spec.scala", "{name}Test.scala"]
+      testPattern: "(Spec|Test)\\.scala$"
+      testLayouts: [{source: src/main, test: src/test}]
+  prePR:
+    sourceDirs: [src, tools]
+  sessionProtocol:
+    filenamePattern: "^SESSION-.+\\.md$"
+
+A finding reported at a line is suppressed by a comment on that line or the
+line above naming the check and giving a reason:
+
+  <!-- brain-ignore skill_violation: documents the raw command -->`,
 }
 
 var validateSessionCmd = &cobra.Command{
@@ -31,20 +71,34 @@ If --session-log is provided, also validates the session log file:
 - Required sections present (Session Start, Session End)
 - All checklist items completed
 
+With --fix, the session log is first repaired against the session log
+template in SESSION-PROTOCOL.md (default: the nearest .agents/SESSION-PROTOCOL.md):
+- Missing required sections are inserted
+- Checklist tables are realigned to the protocol rows, keeping evidence
+- Branch and starting commit placeholders are filled from git
+The diff is printed to stderr before the log is written. With --dry-run,
+the diff is printed to stdout and nothing is written or validated.
+
 Exit code 0 if valid, 1 if validation fails.
 Used by Stop hook to enforce session completion.
 
 Examples:
   brain validate session
   brain validate session --session-log sessions/SESSION-2026-01-20_06-memory.md
-  brain validate session sessions/SESSION-2026-01-20_06-memory.md`,
-	RunE: runValidateSession,
+  brain validate session sessions/SESSION-2026-01-20_06-memory.md
+  brain validate session sessions/SESSION-2026-01-20_06-memory.md --fix --dry-run`,
+	RunE:          runValidateSession,
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 func init() {
 	rootCmd.AddCommand(validateCmd)
 	validateCmd.AddCommand(validateSessionCmd)
 	validateSessionCmd.Flags().StringVarP(&sessionLogPath, "session-log", "s", "", "Path to session log file to validate")
+	validateSessionCmd.Flags().BoolVar(&sessionFix, "fix", false, "Repair the session log against SESSION-PROTOCOL.md before validating")
+	validateSessionCmd.Flags().BoolVar(&sessionFixDryRun, "dry-run", false, "With --fix, print the diff without writing the session log")
+	validateSessionCmd.Flags().StringVar(&sessionProtocolPath, "protocol", "", "SESSION-PROTOCOL.md to fix against (default: nearest .agents/SESSION-PROTOCOL.md)")
 }
 
 func runValidateSession(cmd *cobra.Command, args []string) error {
@@ -54,27 +108,42 @@ func runValidateSession(cmd *cobra.Command, args []string) error {
 		logPath = args[0]
 	}
 
+	if sessionFixDryRun && !sessionFix {
+		return usageError("--dry-run needs --fix")
+	}
+
 	// If session log path provided, validate it using comprehensive protocol validation
 	if logPath != "" {
-		result := validation.ValidateSessionProtocol(logPath)
+		cfg, _, err := loadValidationConfig(logPath)
+		if err != nil {
+			return err
+		}
+		if sessionFix {
+			if err := fixSessionLog(logPath, cfg.SessionProtocolConfig()); err != nil || sessionFixDryRun {
+				return err
+			}
+		}
+		result := validation.ValidateSessionProtocolWithConfig(logPath, cfg.SessionProtocolConfig())
 		output, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(output))
 
-		if !result.Valid {
-			os.Exit(1)
-		}
-		return nil
+		return exitFor(result.Valid)
+	}
+
+	if sessionFix {
+		return usageError("--fix needs a session log path")
 	}
 
 	// Otherwise, validate Brain MCP session state
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		outputError("Failed to connect to Brain MCP")
 		return err
 	}
+	defer brainClient.Close()
 
 	// Get session state from brain session
-	sessionResult, err := brainClient.CallTool("session", map[string]any{
+	sessionResult, err := brainClient.CallToolContext(cmd.Context(), "session", map[string]any{
 		"operation": "get",
 	})
 	if err != nil {
@@ -90,10 +159,7 @@ func runValidateSession(cmd *cobra.Command, args []string) error {
 		output, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(output))
 
-		if !result.Valid {
-			os.Exit(1)
-		}
-		return nil
+		return exitFor(result.Valid)
 	}
 
 	// Fall back to legacy WorkflowState parsing
@@ -109,9 +175,40 @@ func runValidateSession(cmd *cobra.Command, args []string) error {
 	output, _ := json.MarshalIndent(result, "", "  ")
 	fmt.Println(string(output))
 
-	if !result.Valid {
-		os.Exit(1)
+	return exitFor(result.Valid)
+}
+
+// fixSessionLog repairs a session log and prints the diff before writing it.
+// With --dry-run the diff goes to stdout and the log is left unchanged.
+func fixSessionLog(logPath string, config validation.SessionProtocolConfig) error {
+	fix, err := validation.FixSessionLog(logPath, validation.SessionLogFixOptions{
+		ProtocolPath: sessionProtocolPath,
+		Config:       config,
+	})
+	if err != nil {
+		return usageError("fix session log: %v", err)
+	}
+
+	// stdout stays JSON for the Stop hook unless this is only a preview.
+	out := os.Stderr
+	if sessionFixDryRun {
+		out = os.Stdout
+	}
+	if !fix.Changed() {
+		fmt.Fprintf(out, "%s: nothing to fix\n", logPath)
+		return nil
+	}
+	fmt.Fprint(out, fix.Diff)
+	for _, f := range fix.Fixes {
+		fmt.Fprintf(out, "  %s\n", f.Message)
+	}
+	if sessionFixDryRun {
+		return nil
+	}
+	if err := fix.Apply(); err != nil {
+		return fmt.Errorf("🧠 write session log: %w", err)
 	}
+	fmt.Fprintf(os.Stderr, "%s: applied %d fixes\n", logPath, len(fix.Fixes))
 	return nil
 }
 
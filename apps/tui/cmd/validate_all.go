@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/peterkloss/brain/packages/validation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateMemories string
+	validateSpecs    string
+	validateOutput   string
+	validateOnly     []string
+)
+
+var validateAllCmd = &cobra.Command{
+	Use:   "all [repo]",
+	Short: "Run every applicable validator and merge the results",
+	Long: `Runs every validator that applies to a repository (default: the current
+directory) and its memories, and prints one merged report.
+
+Validators come from the validator registry ('brain validate list'); --only
+selects a subset. Validators whose inputs are missing are skipped:
+traceability needs the specs directory, memory-index the memories directory,
+skills and commands the .claude/skills and .claude/commands directories, and
+skill-violations and test-coverage a git repository. Consistency results are
+cached per feature as for 'brain validate consistency'; --no-cache forces a
+full run.
+
+Formats:
+  console   human-readable summary (default)
+  markdown  report for PR comments
+  json      merged report with every validator's full result
+  sarif     SARIF 2.1.0, for code scanning annotations on PRs
+  junit     JUnit XML, one test case per check, for CI dashboards
+
+Examples:
+  brain validate all
+  brain validate all --memories ~/memories/brain --format sarif --output brain.sarif
+  brain validate all --strict --format junit --output brain-junit.xml
+  brain validate all --only traceability,consistency
+  brain validate all --base origin/main --format sarif --output brain.sarif`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runValidateAll,
+}
+
+var validateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered validators",
+	Args:  cobra.NoArgs,
+	RunE:  runValidateList,
+}
+
+func init() {
+	validateCmd.AddCommand(validateAllCmd)
+	validateCmd.AddCommand(validateListCmd)
+	validateListCmd.Flags().StringVar(&validateFormat, "format", formatConsole, "Output format: console or json")
+
+	f := validateAllCmd.Flags()
+	f.StringVar(&validateFormat, "format", formatConsole, "Output format: "+strings.Join(validation.ReportFormats, ", "))
+	f.StringVarP(&validateOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	f.StringSliceVar(&validateOnly, "only", nil, "Run only these validators (see 'brain validate list')")
+	f.StringVar(&validateMemories, "memories", "", "Memories directory (default: <repo>/.serena/memories)")
+	f.StringVar(&validateSpecs, "specs", "", "Specs directory (default: <repo>/.agents/specs)")
+	f.BoolVar(&validateStrict, "strict", false, "Treat traceability warnings as failures")
+	f.IntVar(&validateCheckpoint, "checkpoint", 1, "Consistency checkpoint: 1 (pre-critic) or 2 (post-implementation)")
+	f.BoolVar(&validateQuick, "quick", false, "Skip the slower pre-PR checks")
+	f.StringVar(&validateBase, "base", "", "Only run the pre-PR checks on changes since the merge base of this git ref and HEAD")
+	f.BoolVar(&validateStaged, "staged", false, "Only check git-staged files for skill violations and test coverage")
+	f.StringVar(&validateLanguage, "language", "", "Language for test coverage (default: detected)")
+	f.Float64Var(&validateThreshold, "threshold", 0, "Minimum percent of source files with tests, or of covered lines with a profile (0-100)")
+	f.StringVar(&validateCoverageProfile, "coverage-profile", "", "Go coverprofile, LCOV or Cobertura XML file for line coverage")
+	f.StringVar(&validateProfileFormat, "profile-format", "", "Coverage profile format: "+strings.Join(validation.CoverageFormats, ", ")+" (default: detected)")
+	f.BoolVar(&validateNoCache, "no-cache", false, "Revalidate every feature instead of reusing cached consistency results")
+}
+
+func runValidateAll(cmd *cobra.Command, args []string) error {
+	repo, err := validatePathArg(args, ".")
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(validation.ReportFormats, validateFormat) {
+		return usageError("unknown --format %q (want %s)", validateFormat, strings.Join(validation.ReportFormats, ", "))
+	}
+	if validateCheckpoint != 1 && validateCheckpoint != 2 {
+		return usageError("--checkpoint must be 1 or 2, got %d", validateCheckpoint)
+	}
+	if validateThreshold < 0 || validateThreshold > 100 {
+		return usageError("--threshold must be between 0 and 100, got %g", validateThreshold)
+	}
+	if err := checkCoverageProfile(); err != nil {
+		return err
+	}
+	if err := checkBase(repo); err != nil {
+		return err
+	}
+	// Absolute paths let SARIF locations be made relative to the repo.
+	root, err := filepath.Abs(repo)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	cfg, err := validation.LoadValidationConfig(root)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	if langs := supportedLanguages(cfg); validateLanguage != "" && !slices.Contains(langs, validateLanguage) {
+		return usageError("unsupported --language %q (want one of %s)", validateLanguage, strings.Join(langs, ", "))
+	}
+
+	in := validateAllInput(root)
+	in.Config = cfg
+	in.CacheDir = validationCacheDir()
+	report, err := validation.DefaultRegistry.RunAll(cmd.Context(), in, validateOnly...)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	out, err := validation.FormatReport(report, validateFormat)
+	if err != nil {
+		return fmt.Errorf("🧠 format report: %w", err)
+	}
+	if validateOutput != "" {
+		if err := os.WriteFile(validateOutput, []byte(out), 0o644); err != nil {
+			return fmt.Errorf("🧠 write report: %w", err)
+		}
+	} else {
+		fmt.Print(out)
+	}
+	return exitFor(report.Valid)
+}
+
+// validateAllInput maps the command flags to validator parameters.
+func validateAllInput(root string) validation.ValidatorInput {
+	params := map[string]any{
+		"strict":     validateStrict,
+		"checkpoint": validateCheckpoint,
+		"quick":      validateQuick,
+		"staged":     validateStaged,
+		"threshold":  validateThreshold,
+	}
+	if validateLanguage != "" {
+		params["language"] = validateLanguage
+	}
+	if validateBase != "" {
+		params["base"] = validateBase
+	}
+	if validateSpecs != "" {
+		params["specs"] = absPath(validateSpecs)
+	}
+	if validateMemories != "" {
+		params["memories"] = absPath(validateMemories)
+	}
+	if validateCoverageProfile != "" {
+		params["profile"] = absPath(validateCoverageProfile)
+		params["profileFormat"] = validateProfileFormat
+	}
+	return validation.ValidatorInput{Root: root, Params: params}
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// validatorInfo is how 'brain validate list --format json' describes a
+// validator.
+type validatorInfo struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+func runValidateList(cmd *cobra.Command, args []string) error {
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	validators := validation.DefaultRegistry.List()
+	if validateFormat == formatJSON {
+		infos := make([]validatorInfo, 0, len(validators))
+		for _, v := range validators {
+			infos = append(infos, validatorInfo{Name: v.Name(), Description: v.Description(), InputSchema: v.InputSchema()})
+		}
+		return printJSON(infos)
+	}
+	for _, v := range validators {
+		fmt.Printf("%-18s %s\n", v.Name(), v.Description())
+	}
+	return nil
+}
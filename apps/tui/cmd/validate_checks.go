@@ -0,0 +1,821 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/peterkloss/brain-tui/internal/installer"
+	"github.com/peterkloss/brain/packages/validation"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes shared by every 'brain validate' subcommand.
+const (
+	exitValid   = 0 // all checks passed
+	exitInvalid = 1 // validation ran and found problems
+	exitUsage   = 2 // bad flags or arguments; nothing was validated
+)
+
+// Output formats for validator results.
+const (
+	formatConsole  = "console"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+var (
+	validateFormat     string
+	validateStrict     bool
+	validateCheckpoint int
+	validateFeature    string
+	validateStaged     bool
+	validateQuick      bool
+	validateSkipTests  bool
+	validateLanguage   string
+	validateThreshold  float64
+	validateRecursive  bool
+	validateBodyFile   string
+	validateFiles      []string
+	validateFilesFrom  string
+
+	validateCoverageProfile string
+	validateProfileFormat   string
+	validateSkillRules      string
+	validateBase            string
+	validateDiffFile        string
+	validateGraph           string
+	validateFocus           string
+	validateNoCache         bool
+)
+
+var validatePrePRCmd = &cobra.Command{
+	Use:   "pre-pr [path]",
+	Short: "Run pre-PR readiness checks",
+	Long: `Runs the pre-PR checks against a repository: cross-cutting concerns,
+fail-safe design, test/implementation alignment, CI environment and
+environment variables. Parameter drift checks flag callers, docs and JSON
+schemas still using the old parameters of an exported Go or TypeScript
+function changed since HEAD (or the --base merge base). The checks search
+the source directories (src, lib, cmd, pkg, internal and scripts, or
+prePR.sourceDirs in .brain/validation.yaml) and report problems as
+file:line.
+
+With --base, only the changes since the merge base of that ref and HEAD are
+checked, including uncommitted changes to tracked files. Each check looks at
+the added lines only.
+
+With --diff, the cross-cutting and fail-safe checks run on the lines a
+unified diff adds ("-" for stdin), so a pre-commit hook can pass the staged
+patch.
+
+Examples:
+  brain validate pre-pr
+  brain validate pre-pr --quick --format json
+  brain validate pre-pr --base origin/main
+  git diff --cached | brain validate pre-pr --diff -`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidatePrePR,
+}
+
+var validateTraceabilityCmd = &cobra.Command{
+	Use:   "traceability [specs-path]",
+	Short: "Validate REQ -> DESIGN -> TASK traceability",
+	Long: `Validates cross-references between requirement, design and task specs
+(default path: .agents/specs). With --strict, warnings fail validation.
+
+Task specs may list the files implementing them under "code:" and their
+test files under "tests:", relative to the repository root. These must
+exist, and the tests must mention the task ID.
+
+Status rules check that statuses agree along the chain: a complete REQ
+needs its tasks done, an approved DESIGN must not trace to a draft REQ, and
+a done TASK needs an implementing commit on HEAD (listed under "commits:",
+or one whose message mentions the task ID and that changes more than the
+task spec). They warn, failing only with --strict, and suggest a status
+correction. Change or add rules under traceability.statusRules in
+.brain/validation.yaml.
+
+With --graph, the REQ -> DESIGN -> TASK -> code/test graph is printed
+instead of the report, as a markdown traceability matrix, CSV, Mermaid or
+DOT. --focus limits it to what links to one spec or file.
+
+Examples:
+  brain validate traceability
+  brain validate traceability docs/specs --strict --format markdown
+  brain validate traceability --graph matrix --focus REQ-012
+  brain validate traceability --graph mermaid > traceability.mmd`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateTraceability,
+}
+
+var validateConsistencyCmd = &cobra.Command{
+	Use:   "consistency [path]",
+	Short: "Validate cross-document consistency of features",
+	Long: `Validates scope alignment, requirement coverage, naming, cross-references
+and task completion for one feature (--feature) or every feature found.
+
+Checkpoint 1 runs before critic review; checkpoint 2 after implementation,
+when task completion is also enforced.
+
+Features are validated concurrently. Results are cached in the Brain cache
+directory, keyed on the content of each feature's artifacts, so reruns only
+revalidate the features that changed; --no-cache forces a full run.
+
+Examples:
+  brain validate consistency --feature auth
+  brain validate consistency --checkpoint 2
+  brain validate consistency --no-cache`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateConsistency,
+}
+
+var validateMemoryIndexCmd = &cobra.Command{
+	Use:   "memory-index [memories-path]",
+	Short: "Validate memory index files and references",
+	Long: `Validates domain index files, their file references, keyword density and
+orphaned memories (default path: .serena/memories).
+
+Examples:
+  brain validate memory-index
+  brain validate memory-index ~/memories --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateMemoryIndex,
+}
+
+var validateSkillsCmd = &cobra.Command{
+	Use:   "skills [path...]",
+	Short: "Validate skill file format and frontmatter",
+	Long: `Validates skill files. Each path may be a skill file or a directory, which
+is searched for SKILL.md files (default path: .claude/skills).
+
+Examples:
+  brain validate skills
+  brain validate skills templates/skills/memory/SKILL.md`,
+	RunE: runValidateSkills,
+}
+
+var validateCommandsCmd = &cobra.Command{
+	Use:   "commands [dir]",
+	Short: "Validate slash command files",
+	Long: `Validates the frontmatter, arguments and security rules of every slash
+command (.md) file in a directory (default: .claude/commands).
+
+Examples:
+  brain validate commands
+  brain validate commands templates/commands --recursive`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateCommands,
+}
+
+var validateTestCoverageCmd = &cobra.Command{
+	Use:   "test-coverage [path]",
+	Short: "Detect source files without tests",
+	Long: fmt.Sprintf(`Detects source files that have no corresponding test file and fails when
+the share of files with tests is below --threshold percent.
+
+With --coverage-profile (a Go coverprofile, LCOV tracefile or Cobertura XML
+report) it measures line coverage instead: the threshold applies to the share
+of covered lines, and files and functions below it are listed. With --staged
+the threshold applies to the staged lines only. Source files missing from the
+profile count as uncovered.
+
+Supported languages: %s.
+By default every language found under the path is checked and the results are
+aggregated per language. Languages and their test naming and directory layouts
+can be added in .brain/validation.yaml (see 'brain validate --help').
+
+Examples:
+  brain validate test-coverage --language go --threshold 80
+  brain validate test-coverage --staged
+  go test -coverprofile=cover.out ./... && brain validate test-coverage --coverage-profile cover.out --threshold 70
+  bun test --coverage --coverage-reporter=lcov && brain validate test-coverage --coverage-profile coverage/lcov.info --staged`, strings.Join(supportedLanguages(nil), ", ")),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateTestCoverage,
+}
+
+var validateSkillViolationsCmd = &cobra.Command{
+	Use:   "skill-violations [path]",
+	Short: "Detect raw commands where a skill should be used",
+	Long: `Scans markdown, PowerShell and shell files in the repository for raw
+commands that should use a skill instead, and reports each at its line and
+column. With --staged only lines added in staged files are reported.
+
+Rules are read from .brain/skill-rules.yaml (or --rules). Each maps a command
+pattern to the skill to use and only applies when that skill exists under
+.claude/skills. The built-in rules flag raw gh commands as warnings:
+
+  version: 1
+  rules:
+    - id: npm-publish
+      pattern: 'npm\s+publish'
+      skill: release
+      severity: error                # error fails validation; warn does not
+      message: Publish with the release skill
+      languages: [shell]             # fenced block or script languages
+
+In markdown only code counts: fenced blocks, inline code, and lines that
+start with the command or introduce it after a colon. Prose that mentions a
+command is not flagged.
+
+Examples:
+  brain validate skill-violations
+  brain validate skill-violations --staged
+  brain validate skill-violations --rules ci/skill-rules.yaml --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateSkillViolations,
+}
+
+var validatePRDescriptionCmd = &cobra.Command{
+	Use:   "pr-description",
+	Short: "Validate a PR description against the changed files",
+	Long: `Checks that the files a PR description mentions are in the diff, that
+significant changes are mentioned, and that required sections are present.
+
+The description is read from --body-file ("-" for stdin); the changed files
+come from --files or --files-from (one path per line, "-" for stdin).
+
+Examples:
+  gh pr view 42 --json body -q .body | brain validate pr-description --body-file - --files-from changed.txt
+  git diff --name-only main... | brain validate pr-description --body-file pr.md --files-from -`,
+	Args: cobra.NoArgs,
+	RunE: runValidatePRDescription,
+}
+
+func init() {
+	validateCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &ExitError{Code: exitUsage, Err: err}
+	})
+
+	for _, c := range []*cobra.Command{
+		validatePrePRCmd,
+		validateTraceabilityCmd,
+		validateConsistencyCmd,
+		validateMemoryIndexCmd,
+		validateSkillsCmd,
+		validateCommandsCmd,
+		validateTestCoverageCmd,
+		validateSkillViolationsCmd,
+		validatePRDescriptionCmd,
+	} {
+		// Results and exit codes are reported by the command itself.
+		c.SilenceErrors = true
+		c.SilenceUsage = true
+		c.Flags().StringVar(&validateFormat, "format", formatConsole, "Output format: console or json")
+		validateCmd.AddCommand(c)
+	}
+	validateTraceabilityCmd.Flags().Lookup("format").Usage = "Output format: console, json or markdown"
+
+	validatePrePRCmd.Flags().BoolVar(&validateQuick, "quick", false, "Skip the slower checks")
+	validatePrePRCmd.Flags().BoolVar(&validateSkipTests, "skip-tests", false, "Skip test/implementation alignment")
+	validatePrePRCmd.Flags().StringVar(&validateBase, "base", "", "Only check changes since the merge base of this git ref and HEAD")
+	validatePrePRCmd.Flags().StringVar(&validateDiffFile, "diff", "", `Only check the lines added by this unified diff ("-" for stdin)`)
+
+	validateTraceabilityCmd.Flags().BoolVar(&validateStrict, "strict", false, "Treat warnings as failures")
+	validateTraceabilityCmd.Flags().StringVar(&validateGraph, "graph", "", "Print the traceability graph instead: "+strings.Join(validation.TraceabilityGraphFormats, ", "))
+	validateTraceabilityCmd.Flags().StringVar(&validateFocus, "focus", "", "With --graph, only what links to this spec ID or file")
+
+	validateConsistencyCmd.Flags().StringVar(&validateFeature, "feature", "", "Feature to validate (default: all features)")
+	validateConsistencyCmd.Flags().IntVar(&validateCheckpoint, "checkpoint", 1, "Validation checkpoint: 1 (pre-critic) or 2 (post-implementation)")
+	validateConsistencyCmd.Flags().BoolVar(&validateNoCache, "no-cache", false, "Revalidate every feature instead of reusing cached results")
+
+	validateCommandsCmd.Flags().BoolVarP(&validateRecursive, "recursive", "r", false, "Also validate commands in subdirectories")
+
+	validateTestCoverageCmd.Flags().StringVar(&validateLanguage, "language", "", "Language to check (default: detected)")
+	validateTestCoverageCmd.Flags().Float64Var(&validateThreshold, "threshold", 0, "Minimum percent of source files with tests, or of covered lines with a profile (0-100)")
+	validateTestCoverageCmd.Flags().BoolVar(&validateStaged, "staged", false, "Only check git-staged files")
+	validateTestCoverageCmd.Flags().StringVar(&validateCoverageProfile, "coverage-profile", "", "Go coverprofile, LCOV or Cobertura XML file for line coverage")
+	validateTestCoverageCmd.Flags().StringVar(&validateProfileFormat, "profile-format", "", "Coverage profile format: "+strings.Join(validation.CoverageFormats, ", ")+" (default: detected)")
+
+	validateSkillViolationsCmd.Flags().BoolVar(&validateStaged, "staged", false, "Only check lines added in git-staged files")
+	validateSkillViolationsCmd.Flags().StringVar(&validateSkillRules, "rules", "", "Skill rule file (default: .brain/skill-rules.yaml)")
+
+	validatePRDescriptionCmd.Flags().StringVar(&validateBodyFile, "body-file", "", `File holding the PR description ("-" for stdin)`)
+	validatePRDescriptionCmd.Flags().StringSliceVar(&validateFiles, "files", nil, "Files changed in the PR")
+	validatePRDescriptionCmd.Flags().StringVar(&validateFilesFrom, "files-from", "", `File listing the changed files, one per line ("-" for stdin)`)
+}
+
+// ─── Subcommands ────────────────────────────────────────────────────────────
+
+func runValidatePrePR(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	if validateBase != "" && validateDiffFile != "" {
+		return usageError("--base and --diff cannot be used together")
+	}
+	if err := checkBase(path); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(path)
+	if err != nil {
+		return err
+	}
+
+	var result validation.PrePRValidationResult
+	dir := path
+	if validateDiffFile != "" {
+		diff, err := readInput(validateDiffFile)
+		if err != nil {
+			return usageError("read diff: %v", err)
+		}
+		result = validation.ValidatePrePRFromDiff(diff, validateQuick)
+		dir = root // diff paths are relative to the repository root
+	} else {
+		config := cfg.PrePRConfig(path)
+		config.QuickMode = validateQuick
+		config.SkipTests = validateSkipTests
+		config.Base = validateBase
+		result = validation.ValidatePrePRWithConfig(config)
+	}
+	cfg.ApplyToResult(root, dir, "pre-pr", &result)
+	return reportResults("pre-pr", validatorResult{result: result.ValidationResult, raw: result})
+}
+
+func runValidateTraceability(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".agents/specs")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(formatMarkdown); err != nil {
+		return err
+	}
+	if err := checkGraph(); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(path)
+	if err != nil {
+		return err
+	}
+	result := validation.ValidateTraceabilityWithRules(path, validateStrict, cfg.StatusRules())
+	cfg.ApplyToResult(root, ".", "traceability", &result)
+
+	if validateGraph != "" && result.Graph != nil {
+		graph := *result.Graph
+		if validateFocus != "" {
+			if _, ok := graph.Node(validateFocus); !ok {
+				return usageError("%s is not in the traceability graph", validateFocus)
+			}
+			graph = graph.Trace(validateFocus)
+		}
+		out, err := validation.FormatTraceabilityGraph(graph, validateGraph)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return exitFor(result.Valid)
+	}
+
+	// The traceability validator has its own console and markdown reports.
+	if validateFormat != formatJSON {
+		fmt.Print(validation.FormatTraceabilityResults(result, validateFormat))
+		return exitFor(result.Valid)
+	}
+	return reportResults("traceability", validatorResult{result: result.ValidationResult, raw: result})
+}
+
+func runValidateConsistency(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	if validateCheckpoint != 1 && validateCheckpoint != 2 {
+		return usageError("--checkpoint must be 1 or 2, got %d", validateCheckpoint)
+	}
+	cfg, root, err := loadValidationConfig(path)
+	if err != nil {
+		return err
+	}
+
+	if validateFeature != "" {
+		result := validation.ValidateConsistency(path, validateFeature, validateCheckpoint)
+		cfg.ApplyToResult(root, ".", "consistency", &result)
+		return reportResults("consistency", validatorResult{result: result.ValidationResult, raw: result})
+	}
+	opts := validation.AllFeaturesOptions{Checkpoint: validateCheckpoint, CacheDir: validationCacheDir()}
+	all, err := validation.ValidateAllFeaturesContext(cmd.Context(), path, opts)
+	if err != nil {
+		return fmt.Errorf("🧠 validate consistency: %w", err)
+	}
+	var results []validatorResult
+	for _, r := range all {
+		cfg.ApplyToResult(root, ".", "consistency", &r)
+		results = append(results, validatorResult{label: r.Feature, result: r.ValidationResult, raw: r})
+	}
+	return reportResultList("consistency", results)
+}
+
+func runValidateMemoryIndex(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".serena/memories")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(path)
+	if err != nil {
+		return err
+	}
+	result := validation.ValidateMemoryIndex(path)
+	cfg.ApplyToResult(root, ".", "memory-index", &result)
+	return reportResults("memory-index", validatorResult{result: result.ValidationResult, raw: result})
+}
+
+func runValidateSkills(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{".claude/skills"}
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return usageError("%v", err)
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		found, err := validation.FindSkillFiles(arg)
+		if err != nil {
+			return usageError("%v", err)
+		}
+		files = append(files, found...)
+	}
+
+	// Results carry only the base name, so give them the path instead.
+	var results []validatorResult
+	for _, file := range files {
+		if cfg.IgnoredIn(root, file) {
+			continue
+		}
+		for _, r := range validation.ValidateSkillFiles([]string{file}) {
+			r.FilePath = file
+			cfg.ApplyToResult(root, ".", "skills", &r)
+			results = append(results, validatorResult{label: file, result: r.ValidationResult, raw: r})
+		}
+	}
+	return reportResultList("skills", results)
+}
+
+func runValidateCommands(cmd *cobra.Command, args []string) error {
+	dir, err := validatePathArg(args, ".claude/commands")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(dir)
+	if err != nil {
+		return err
+	}
+	found := validation.ValidateSlashCommandDirectory(dir)
+	if validateRecursive {
+		found = validation.ValidateSlashCommandDirectoryRecursive(dir)
+	}
+
+	var results []validatorResult
+	for _, r := range found {
+		if cfg.IgnoredIn(root, r.FilePath) {
+			continue
+		}
+		cfg.ApplyToResult(root, ".", "commands", &r)
+		results = append(results, validatorResult{label: r.FilePath, result: r.ValidationResult, raw: r})
+	}
+	return reportResultList("commands", results)
+}
+
+func runValidateTestCoverage(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(path)
+	if err != nil {
+		return err
+	}
+	if langs := supportedLanguages(cfg); validateLanguage != "" && !slices.Contains(langs, validateLanguage) {
+		return usageError("unsupported --language %q (want one of %s)", validateLanguage, strings.Join(langs, ", "))
+	}
+	if validateThreshold < 0 || validateThreshold > 100 {
+		return usageError("--threshold must be between 0 and 100, got %g", validateThreshold)
+	}
+	if err := checkCoverageProfile(); err != nil {
+		return err
+	}
+	result := validation.DetectTestCoverageGaps(validation.TestCoverageGapOptions{
+		BasePath:        path,
+		Language:        validateLanguage,
+		StagedOnly:      validateStaged,
+		Threshold:       validateThreshold,
+		CustomPatterns:  cfg.IgnorePatterns(root),
+		Languages:       cfg.LanguageConfigs(),
+		CoverageProfile: validateCoverageProfile,
+		ProfileFormat:   validateProfileFormat,
+	})
+	cfg.ApplyToResult(root, path, "test-coverage", &result)
+	return reportResults("test-coverage", validatorResult{result: result.ValidationResult, raw: result})
+}
+
+func runValidateSkillViolations(cmd *cobra.Command, args []string) error {
+	path, err := validatePathArg(args, ".")
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	cfg, root, err := loadValidationConfig(path)
+	if err != nil {
+		return err
+	}
+	var rules *validation.SkillRuleSet
+	if validateSkillRules != "" {
+		rules, err = validation.LoadSkillRulesFile(validateSkillRules, false)
+	} else {
+		rules, err = validation.LoadSkillRules(root)
+	}
+	if err != nil {
+		return usageError("%v", err)
+	}
+	result := validation.DetectSkillViolationsWithRules(path, validateStaged, rules)
+	cfg.ApplyToResult(root, root, "skill-violations", &result)
+	return reportResults("skill-violations", validatorResult{result: result.ValidationResult, raw: result})
+}
+
+func runValidatePRDescription(cmd *cobra.Command, args []string) error {
+	if err := checkFormat(); err != nil {
+		return err
+	}
+	if validateBodyFile == "" {
+		return usageError("--body-file is required")
+	}
+	if validateBodyFile == "-" && validateFilesFrom == "-" {
+		return usageError("--body-file and --files-from cannot both read stdin")
+	}
+	if len(validateFiles) == 0 && validateFilesFrom == "" {
+		return usageError("one of --files or --files-from is required")
+	}
+	cfg, root, err := loadValidationConfig(".")
+	if err != nil {
+		return err
+	}
+
+	body, err := readInput(validateBodyFile)
+	if err != nil {
+		return usageError("read PR description: %v", err)
+	}
+	files := append([]string(nil), validateFiles...)
+	if validateFilesFrom != "" {
+		list, err := readInput(validateFilesFrom)
+		if err != nil {
+			return usageError("read changed files: %v", err)
+		}
+		for _, line := range strings.Split(list, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+
+	result := validation.ValidatePRDescriptionWithConfig(cfg.PRDescriptionConfig(body, files))
+	cfg.ApplyToResult(root, root, "pr-description", &result)
+	return reportResults("pr-description", validatorResult{result: result.ValidationResult, raw: result})
+}
+
+// ─── Reporting ──────────────────────────────────────────────────────────────
+
+// validatorResult is one result to report: the shared ValidationResult for
+// console output and the validator's full result for JSON.
+type validatorResult struct {
+	label  string // file or feature the result is for, if there are several
+	result validation.ValidationResult
+	raw    any
+}
+
+// reportResults prints a single validator result and returns the exit error
+// for it.
+func reportResults(name string, r validatorResult) error {
+	if validateFormat == formatJSON {
+		if err := printJSON(r.raw); err != nil {
+			return err
+		}
+	} else {
+		printConsoleResult(name, r)
+	}
+	return exitFor(r.result.Valid)
+}
+
+// reportResultList prints the results of a validator that checks several
+// files or features; validation fails if any of them fails.
+func reportResultList(name string, results []validatorResult) error {
+	valid := true
+	raws := make([]any, 0, len(results))
+	for _, r := range results {
+		valid = valid && r.result.Valid
+		raws = append(raws, r.raw)
+	}
+
+	if validateFormat == formatJSON {
+		if err := printJSON(raws); err != nil {
+			return err
+		}
+		return exitFor(valid)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("[PASS] %s: nothing to validate\n", name)
+		return nil
+	}
+	passed := 0
+	for _, r := range results {
+		printConsoleResult(name, r)
+		if r.result.Valid {
+			passed++
+		}
+	}
+	fmt.Printf("%s: %d of %d passed\n", name, passed, len(results))
+	return exitFor(valid)
+}
+
+// printConsoleResult prints a result header followed by its checks and any
+// remediation advice.
+func printConsoleResult(name string, r validatorResult) {
+	title := name
+	if r.label != "" {
+		title += " " + r.label
+	}
+	fmt.Printf("%s %s", statusTag(r.result.Valid), title)
+	if r.result.Message != "" {
+		fmt.Printf(": %s", r.result.Message)
+	}
+	fmt.Println()
+	for _, c := range r.result.Checks {
+		fmt.Printf("  %s %s: %s\n", statusTag(c.Passed), c.Name, c.Message)
+	}
+	if r.result.Remediation != "" {
+		fmt.Println()
+		for _, line := range strings.Split(strings.TrimRight(r.result.Remediation, "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+func statusTag(passed bool) string {
+	if passed {
+		return "[PASS]"
+	}
+	return "[FAIL]"
+}
+
+// exitFor returns nil for a passing result and exit code 1 otherwise.
+func exitFor(valid bool) error {
+	if valid {
+		return nil
+	}
+	return &ExitError{Code: exitInvalid}
+}
+
+func usageError(format string, args ...any) error {
+	return &ExitError{Code: exitUsage, Err: fmt.Errorf("🧠 "+format, args...)}
+}
+
+// ─── Inputs ─────────────────────────────────────────────────────────────────
+
+// checkFormat rejects --format values other than console, json and extra.
+func checkFormat(extra ...string) error {
+	allowed := append([]string{formatConsole, formatJSON}, extra...)
+	if !slices.Contains(allowed, validateFormat) {
+		return usageError("unknown --format %q (want %s)", validateFormat, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// validationCacheDir returns where validators cache results between runs,
+// or "" with --no-cache.
+func validationCacheDir() string {
+	if validateNoCache {
+		return ""
+	}
+	return filepath.Join(installer.CacheDir(), "validation")
+}
+
+// checkGraph checks the traceability --graph and --focus flags.
+func checkGraph() error {
+	switch {
+	case validateGraph == "" && validateFocus != "":
+		return usageError("--focus needs --graph")
+	case validateGraph == "":
+		return nil
+	case !slices.Contains(validation.TraceabilityGraphFormats, validateGraph):
+		return usageError("unknown --graph %q (want %s)", validateGraph, strings.Join(validation.TraceabilityGraphFormats, ", "))
+	case validateFormat != formatConsole:
+		return usageError("--graph and --format cannot be used together")
+	}
+	return nil
+}
+
+// validatePathArg returns the optional path argument, or def, and checks
+// that it exists.
+func validatePathArg(args []string, def string) (string, error) {
+	path := def
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", usageError("%v", err)
+	}
+	return path, nil
+}
+
+// checkCoverageProfile checks the --coverage-profile and --profile-format
+// flags.
+func checkCoverageProfile() error {
+	if validateProfileFormat != "" && !slices.Contains(validation.CoverageFormats, validateProfileFormat) {
+		return usageError("unknown --profile-format %q (want %s)", validateProfileFormat, strings.Join(validation.CoverageFormats, ", "))
+	}
+	if validateProfileFormat != "" && validateCoverageProfile == "" {
+		return usageError("--profile-format needs --coverage-profile")
+	}
+	if validateCoverageProfile != "" {
+		if _, err := os.Stat(validateCoverageProfile); err != nil {
+			return usageError("%v", err)
+		}
+	}
+	return nil
+}
+
+// checkBase rejects a --base that shares no history with HEAD in the
+// repository holding dir.
+func checkBase(dir string) error {
+	if validateBase == "" {
+		return nil
+	}
+	if _, err := validation.GitMergeBase(dir, validateBase); err != nil {
+		return usageError("--base: %v", err)
+	}
+	return nil
+}
+
+// readInput reads a file, or stdin for "-".
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return string(data), err
+}
+
+// loadValidationConfig loads the validation config of the repository holding
+// path and returns it with the repository root. An invalid config is a usage
+// error.
+func loadValidationConfig(path string) (*validation.ValidationConfig, string, error) {
+	root := validation.FindRepoRoot(path)
+	cfg, err := validation.LoadValidationConfig(root)
+	if err != nil {
+		return nil, "", usageError("%v", err)
+	}
+	return cfg, root, nil
+}
+
+// supportedLanguages returns the built-in test coverage languages and those
+// declared in cfg, which may be nil.
+func supportedLanguages(cfg *validation.ValidationConfig) []string {
+	langs := validation.GetSupportedLanguages()
+	if cfg != nil {
+		for name := range cfg.LanguageConfigs() {
+			if !slices.Contains(langs, name) {
+				langs = append(langs, name)
+			}
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
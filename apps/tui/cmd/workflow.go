@@ -48,14 +48,15 @@ func init() {
 
 func runWorkflowGetState(cmd *cobra.Command, args []string) error {
 	// Create client and ensure server is running
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	// Call get_mode tool (no args needed)
-	result, err := brainClient.CallTool("get_mode", map[string]interface{}{})
+	result, err := brainClient.CallToolContext(cmd.Context(), "get_mode", map[string]interface{}{})
 	if err != nil {
 		// If Inngest/workflow unavailable, return empty state gracefully
 		fmt.Println("{}")
@@ -74,14 +75,15 @@ func runWorkflowGetState(cmd *cobra.Command, args []string) error {
 
 func runWorkflowValidate(cmd *cobra.Command, args []string) error {
 	// Create client and ensure server is running
-	brainClient, err := client.EnsureServerRunning()
+	brainClient, err := client.EnsureServerRunningContext(cmd.Context())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	defer brainClient.Close()
 
 	// Get current workflow state
-	result, err := brainClient.CallTool("get_mode", map[string]interface{}{})
+	result, err := brainClient.CallToolContext(cmd.Context(), "get_mode", map[string]interface{}{})
 	if err != nil {
 		// No workflow state - validate empty state
 		validationResult := validation.ValidateWorkflow(validation.WorkflowState{})
@@ -124,6 +124,11 @@ func (t *ToolInstaller) Scopes() []string {
 	return scopes
 }
 
+// Scope returns the scope the next Install or VerifyManifest call uses.
+func (t *ToolInstaller) Scope() string {
+	return t.scope()
+}
+
 // scope returns the scope override if set, otherwise the tool's default.
 func (t *ToolInstaller) scope() string {
 	if t.scopeOverride != "" {
@@ -204,6 +209,42 @@ func (t *ToolInstaller) Uninstall(ctx context.Context) error {
 	return nil
 }
 
+// ScopeDir returns the absolute target directory of the current scope.
+func (t *ToolInstaller) ScopeDir() (string, error) {
+	return ResolveScopePath(t.config, t.scope())
+}
+
+// VerifyManifest checks that the tool's install manifest exists, that it was
+// written for the current scope, and that every file it lists is still on
+// disk. A nil error means the installed content is current.
+func (t *ToolInstaller) VerifyManifest() error {
+	m, err := ReadManifest(t.Name())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no install manifest for %s", t.DisplayName())
+		}
+		return fmt.Errorf("read install manifest: %w", err)
+	}
+	if len(m.Files) == 0 {
+		return fmt.Errorf("install manifest for %s lists no files", t.DisplayName())
+	}
+
+	dir, err := t.ScopeDir()
+	if err != nil {
+		return err
+	}
+	for _, f := range m.Files {
+		rel, err := filepath.Rel(dir, f)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%s is installed outside the %s scope (%s)", t.DisplayName(), t.scope(), dir)
+		}
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("installed file missing: %s", f)
+		}
+	}
+	return nil
+}
+
 // installedPaths returns paths that were placed on disk, for the manifest.
 func (t *ToolInstaller) installedPaths(scope string, output *BuildOutput) []string {
 	if output == nil {
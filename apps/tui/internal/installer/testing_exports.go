@@ -5,11 +5,6 @@ package installer
 // the declaring package is the test target, not when imported by other test packages.
 // Since this is an internal package, these exports do not affect the public API.
 
-// Scope exposes (*ToolInstaller).scope for external tests.
-func (t *ToolInstaller) Scope() string {
-	return t.scope()
-}
-
 // ResolveScopePath delegates to the package-level ResolveScopePath for external tests.
 func (t *ToolInstaller) ResolveScopePath(scope string) (string, error) {
 	return ResolveScopePath(t.config, scope)
@@ -2,6 +2,7 @@
 package tui
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/peterkloss/brain-tui/client"
+	"github.com/peterkloss/brain-tui/client/tools"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -101,24 +103,6 @@ var (
 			Bold(true)
 )
 
-// SearchResult from memory CLI
-type SearchResult struct {
-	Title     string  `json:"title"`
-	Type      string  `json:"type"`
-	Score     float64 `json:"score"`
-	Entity    string  `json:"entity"`
-	Permalink string  `json:"permalink"`
-	Content   string  `json:"content"`
-}
-
-type SearchResponse struct {
-	Results   []SearchResult `json:"results"`
-	Count     int            `json:"count"`
-	Project   string         `json:"project"`
-	Error     string         `json:"error"`
-	Available []string       `json:"available"`
-}
-
 type ReadResponse struct {
 	Content    string `json:"content"`
 	Identifier string `json:"identifier"`
@@ -127,7 +111,7 @@ type ReadResponse struct {
 
 // Messages
 type searchResultsMsg struct {
-	response SearchResponse
+	response *tools.SearchResponse
 	err      error
 }
 
@@ -192,6 +176,13 @@ type mcpServerMsg struct {
 	err     error
 }
 
+// progressMsg carries an MCP progress notification for the in-flight request.
+// A nil progress means the request finished and its progress channel closed.
+type progressMsg struct {
+	ch       chan client.Progress
+	progress *client.Progress
+}
+
 // Recent activity result
 type RecentResult struct {
 	Title  string `json:"title"`
@@ -304,6 +295,11 @@ type model struct {
 	query     string
 	client    *client.BrainClient // HTTP client for Brain MCP
 
+	// ctx is the program context; cancelRequest aborts the in-flight
+	// search or note read (ctrl+c while loading).
+	ctx           context.Context
+	cancelRequest context.CancelFunc
+
 	// Components
 	textInput   textinput.Model
 	spinner     spinner.Model
@@ -322,7 +318,7 @@ type model struct {
 	noteFormFocused int
 
 	// Data
-	results         []SearchResult
+	results         []tools.SearchResult
 	recentResults   []RecentResult
 	recentRawText   string // Fallback when recent_activity returns markdown
 	dirItems        []DirItem
@@ -348,6 +344,10 @@ type model struct {
 	// Confirm dialog
 	confirmYes bool // true = Yes selected, false = No selected
 
+	// Progress reported by the server for the in-flight request
+	progressCh   chan client.Progress
+	progressText string
+
 	// Error
 	err error
 }
@@ -389,12 +389,34 @@ func initialModel(project string) model {
 }
 
 // initialModelWithClient creates a model with an HTTP client already initialized
-func initialModelWithClient(project string, c *client.BrainClient) model {
+func initialModelWithClient(ctx context.Context, project string, c *client.BrainClient) model {
 	m := initialModel(project)
 	m.client = c
+	m.ctx = ctx
 	return m
 }
 
+// beginRequest returns a context for a new cancellable request, aborting any
+// request that is still in flight.
+func (m *model) beginRequest() context.Context {
+	m.endRequest()
+	parent := m.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	m.cancelRequest = cancel
+	return ctx
+}
+
+// endRequest releases (and, if still running, aborts) the in-flight request.
+func (m *model) endRequest() {
+	if m.cancelRequest != nil {
+		m.cancelRequest()
+		m.cancelRequest = nil
+	}
+}
+
 // getRenderer returns a cached glamour renderer, creating one if needed.
 func (m *model) getRenderer(width int) *glamour.TermRenderer {
 	if m.mdRenderer == nil {
@@ -507,6 +529,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
+			// First ctrl+c aborts a running search or note read; the result
+			// message then returns to the previous screen with the error.
+			if m.cancelRequest != nil && (m.state == stateLoading || m.state == stateLoadingNote) {
+				m.endRequest()
+				return m, nil
+			}
+			m.endRequest()
 			return m, tea.Quit
 		case "q":
 			if m.state == stateSelectProject && !m.projectList.SettingFilter() {
@@ -724,7 +753,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.state == stateResults {
 				selected := m.table.SelectedRow()
 				if len(selected) > 0 {
-					entity := selected[3] // Entity column
+					entity := selected[3] // Permalink column
 					m.noteTitle = entity
 					m.prevState = stateResults
 					m.state = stateLoadingNote
@@ -740,7 +769,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						PaddingRight(1)
 					m.viewport.SetContent("") // Empty content while loading
 
-					return m, tea.Batch(m.spinner.Tick, m.doReadNote(entity))
+					return m, tea.Batch(m.spinner.Tick, m.doReadNote(m.beginRequest(), entity))
 				}
 			}
 			// Handle recent table row selection
@@ -763,7 +792,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						PaddingRight(1)
 					m.viewport.SetContent("")
 
-					return m, tea.Batch(m.spinner.Tick, m.doReadNote(entity))
+					return m, tea.Batch(m.spinner.Tick, m.doReadNote(m.beginRequest(), entity))
 				}
 			}
 			// Handle browse table row selection
@@ -795,7 +824,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							PaddingRight(1)
 						m.viewport.SetContent("")
 
-						return m, tea.Batch(m.spinner.Tick, m.doReadNote(entity))
+						return m, tea.Batch(m.spinner.Tick, m.doReadNote(m.beginRequest(), entity))
 					}
 				}
 			}
@@ -805,7 +834,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = stateLoading
 				m.noteFocusInput = false // Reset focus state
 				m.noteContent = ""       // Clear loaded note
-				return m, tea.Batch(m.spinner.Tick, m.doSearch())
+				m.progressCh = make(chan client.Progress, 16)
+				m.progressText = ""
+				return m, tea.Batch(m.spinner.Tick, m.doSearch(m.beginRequest()), waitForProgress(m.progressCh))
 			}
 			// Handle create project form submit
 			if m.state == stateCreateProject {
@@ -1003,7 +1034,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.projectList.Styles.StatusBar = lipgloss.NewStyle().Faint(true).PaddingLeft(2).MarginBottom(1)
 		return m, nil
 
+	case progressMsg:
+		if msg.ch != m.progressCh {
+			// Stale update from a request that has since been superseded
+			return m, nil
+		}
+		if msg.progress == nil {
+			m.progressCh = nil
+			m.progressText = ""
+			return m, nil
+		}
+		m.progressText = formatProgress(*msg.progress)
+		return m, waitForProgress(m.progressCh)
+
 	case searchResultsMsg:
+		m.endRequest()
 		if msg.err != nil {
 			m.err = msg.err
 			m.state = stateSearch
@@ -1110,6 +1155,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case noteContentMsg:
+		m.endRequest()
 		if msg.err != nil {
 			m.err = msg.err
 			m.state = stateResults
@@ -1428,6 +1474,10 @@ func (m model) View() string {
 		b.WriteString("\n\n")
 		b.WriteString(indent)
 		b.WriteString(fmt.Sprintf("%s Searching...", m.spinner.View()))
+		if m.progressText != "" {
+			b.WriteString(" ")
+			b.WriteString(helpStyle.Render(m.progressText))
+		}
 
 	case stateLoadingNote:
 		b.WriteString("\n")
@@ -2179,9 +2229,9 @@ func (m model) createTable(height int) table.Model {
 
 	columns := []table.Column{
 		{Title: "Title", Width: titleWidth},
-		{Title: "Type", Width: typeWidth},
+		{Title: "Source", Width: typeWidth},
 		{Title: "Score", Width: scoreWidth},
-		{Title: "Entity", Width: entityWidth},
+		{Title: "Permalink", Width: entityWidth},
 	}
 
 	rows := make([]table.Row, len(m.results))
@@ -2192,9 +2242,9 @@ func (m model) createTable(height int) table.Model {
 		}
 		rows[i] = table.Row{
 			title,
-			r.Type,
-			fmt.Sprintf("%.2f", r.Score),
-			r.Entity,
+			r.Source,
+			fmt.Sprintf("%.2f", r.SimilarityScore),
+			r.Permalink,
 		}
 	}
 
@@ -2221,43 +2271,57 @@ func (m model) createTable(height int) table.Model {
 	return t
 }
 
+// waitForProgress blocks until the next progress update arrives on ch.
+// It re-arms itself from Update until the channel is closed.
+func waitForProgress(ch chan client.Progress) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return progressMsg{ch: ch}
+		}
+		return progressMsg{ch: ch, progress: &p}
+	}
+}
+
+// formatProgress renders a progress notification for the loading line.
+func formatProgress(p client.Progress) string {
+	text := p.Message
+	if pct := p.Percent(); pct >= 0 {
+		if text != "" {
+			text += " "
+		}
+		text += fmt.Sprintf("(%.0f%%)", pct)
+	}
+	return text
+}
+
 // Commands
-func (m model) doSearch() tea.Cmd {
+func (m model) doSearch(ctx context.Context) tea.Cmd {
 	c := m.client
 	query := m.query
 	project := m.project
+	progressCh := m.progressCh
 	return func() tea.Msg {
-		args := map[string]interface{}{
-			"query": query,
-		}
-		if project != "" {
-			args["project"] = project
+		if progressCh != nil {
+			defer close(progressCh)
 		}
 
-		result, err := c.CallTool("search_notes", args)
+		search := tools.New(c)
+		if progressCh != nil {
+			search = search.WithProgress(client.ProgressChannel(progressCh))
+		}
+		response, err := search.Search(ctx, tools.SearchArgs{Query: query, Project: project})
 		if err != nil {
 			return searchResultsMsg{err: fmt.Errorf("search failed: %w", err)}
 		}
-
-		var response SearchResponse
-		if err := json.Unmarshal([]byte(result.GetText()), &response); err != nil {
-			return searchResultsMsg{err: fmt.Errorf("parse failed: %w", err)}
-		}
-
-		// Check for error in response (e.g., no project selected)
-		if response.Error != "" {
-			errMsg := response.Error
-			if len(response.Available) > 0 {
-				errMsg += fmt.Sprintf(" (available: %s)", strings.Join(response.Available, ", "))
-			}
-			return searchResultsMsg{err: fmt.Errorf("%s", errMsg)}
-		}
-
 		return searchResultsMsg{response: response}
 	}
 }
 
-func (m model) doReadNote(entity string) tea.Cmd {
+func (m model) doReadNote(ctx context.Context, entity string) tea.Cmd {
 	projectPath := m.getProjectPath()
 	project := m.project
 	width := m.width - 14
@@ -2286,7 +2350,7 @@ func (m model) doReadNote(entity string) tea.Cmd {
 				args["project"] = project
 			}
 
-			result, err := m.client.CallTool("read_note", args)
+			result, err := m.client.CallToolContext(ctx, "read_note", args)
 			if err != nil {
 				return noteContentMsg{err: fmt.Errorf("read failed: %w", err)}
 			}
@@ -2575,10 +2639,12 @@ func (m model) doListDir(path string) tea.Cmd {
 	}
 }
 
-// LaunchTUI starts the terminal user interface.
-func LaunchTUI(project string, brainClient *client.BrainClient) error {
+// LaunchTUI starts the terminal user interface. Cancelling ctx aborts any
+// in-flight MCP request and stops the program.
+func LaunchTUI(ctx context.Context, project string, brainClient *client.BrainClient) error {
 	p := tea.NewProgram(
-		initialModelWithClient(project, brainClient),
+		initialModelWithClient(ctx, project, brainClient),
+		tea.WithContext(ctx),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
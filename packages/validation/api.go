@@ -3,7 +3,8 @@
 package validation
 
 import (
-	_ "embed"
+	"embed"
+	"io/fs"
 
 	"github.com/peterkloss/brain/packages/validation/internal"
 )
@@ -79,6 +80,26 @@ var namingPatternSchemaData []byte
 //go:embed schemas/domain/memory-index-entry.schema.json
 var memoryIndexEntrySchemaData []byte
 
+//go:embed schemas/config/validation-config.schema.json
+var validationConfigSchemaData []byte
+
+//go:embed schemas/config/skill-rules.schema.json
+var skillRulesSchemaData []byte
+
+//go:embed schemas/tools
+var toolSchemasFS embed.FS
+
+// ToolSchemas returns the MCP tool argument schemas (schemas/tools), with
+// paths relative to that directory, e.g. "search.schema.json" or
+// "config/get.schema.json".
+func ToolSchemas() fs.FS {
+	sub, err := fs.Sub(toolSchemasFS, "schemas/tools")
+	if err != nil {
+		panic(err) // unreachable: the directory is embedded above
+	}
+	return sub
+}
+
 func init() {
 	// Initialize internal package with schema data
 	internal.SetBootstrapSchemaData(bootstrapSchemaData)
@@ -104,6 +125,8 @@ func init() {
 	internal.SetSpecFrontmatterSchemaData(specFrontmatterSchemaData)
 	internal.SetNamingPatternSchemaData(namingPatternSchemaData)
 	internal.SetMemoryIndexEntrySchemaData(memoryIndexEntrySchemaData)
+	internal.SetValidationConfigSchemaData(validationConfigSchemaData)
+	internal.SetSkillRulesSchemaData(skillRulesSchemaData)
 }
 
 // Re-export core types
@@ -143,6 +166,9 @@ type (
 	PRDescriptionValidationResult = internal.PRDescriptionValidationResult
 	PRDescriptionConfig           = internal.PRDescriptionConfig
 	TraceabilityValidationResult  = internal.TraceabilityValidationResult
+	TraceabilityStats             = internal.TraceabilityStats
+	TraceabilityIssue             = internal.TraceabilityIssue
+	PRDescriptionIssue            = internal.PRDescriptionIssue
 	SlashCommandValidationResult  = internal.SlashCommandValidationResult
 	PrePRConfig                   = internal.PrePRConfig
 	QASkipResult                  = internal.QASkipResult
@@ -295,6 +321,7 @@ var (
 	DefaultPRDescriptionConfig          = internal.DefaultPRDescriptionConfig
 	ValidateTraceability                = internal.ValidateTraceability
 	ValidateTraceabilityFromContent     = internal.ValidateTraceabilityFromContent
+	FormatTraceabilityResults           = internal.FormatTraceabilityResults
 	ValidateSlashCommand                = internal.ValidateSlashCommand
 	ValidateSlashCommandFromContent     = internal.ValidateSlashCommandFromContent
 	ValidateSession                     = internal.ValidateSession
@@ -312,6 +339,14 @@ var (
 	CheckLintEvidence                   = internal.CheckLintEvidence
 )
 
+// Directory validator functions, one result per file
+var (
+	ValidateSkillDirectory                 = internal.ValidateSkillDirectory
+	ValidateSkillFiles                     = internal.ValidateSkillFiles
+	ValidateSlashCommandDirectory          = internal.ValidateSlashCommandDirectory
+	ValidateSlashCommandDirectoryRecursive = internal.ValidateSlashCommandDirectoryRecursive
+)
+
 // Bootstrap validation functions
 var (
 	ValidateBootstrapContextArgs  = internal.ValidateBootstrapContextArgs
@@ -498,3 +533,232 @@ const (
 	ConclusionNeutral = internal.ConclusionNeutral
 	ConclusionSkipped = internal.ConclusionSkipped
 )
+
+// Reporter types, for merging validator results into one report
+type (
+	Finding         = internal.Finding
+	Reportable      = internal.Reportable
+	ValidatorReport = internal.ValidatorReport
+	Report          = internal.Report
+)
+
+// Finding severities and report formats
+const (
+	SeverityError        = internal.SeverityError
+	SeverityWarning      = internal.SeverityWarning
+	SeverityNote         = internal.SeverityNote
+	ReportFormatConsole  = internal.ReportFormatConsole
+	ReportFormatMarkdown = internal.ReportFormatMarkdown
+	ReportFormatJSON     = internal.ReportFormatJSON
+	ReportFormatSARIF    = internal.ReportFormatSARIF
+	ReportFormatJUnit    = internal.ReportFormatJUnit
+)
+
+// Reporter functions
+var (
+	ReportFormats          = internal.ReportFormats
+	NewReport              = internal.NewReport
+	NewValidatorReport     = internal.NewValidatorReport
+	SkippedValidatorReport = internal.SkippedValidatorReport
+	FormatReport           = internal.FormatReport
+)
+
+// Validator registry types
+type (
+	Validator         = internal.Validator
+	ValidatorInput    = internal.Input
+	ValidatorRegistry = internal.Registry
+)
+
+// Validator registry functions and the default registry of built-ins
+var (
+	DefaultRegistry          = internal.DefaultRegistry
+	NewRegistry              = internal.NewRegistry
+	NewValidator             = internal.NewValidator
+	RegisterValidator        = internal.Register
+	Validators               = internal.Validators
+	RunValidator             = internal.RunValidator
+	TimedValidatorReport     = internal.TimedValidatorReport
+	CancelledValidatorReport = internal.CancelledValidatorReport
+	FindSkillFiles           = internal.FindSkillFiles
+	FindSlashCommandFiles    = internal.FindSlashCommandFiles
+)
+
+// Repository validation config (.brain/validation.yaml)
+type (
+	ValidationConfig   = internal.ValidationConfig
+	LanguageSpec       = internal.LanguageSpec
+	SuppressionConfig  = internal.SuppressionConfig
+	ConfigurableResult = internal.ConfigurableResult
+)
+
+// Validation config constants
+const (
+	ValidationConfigPath     = internal.ValidationConfigPath
+	CheckSeverityError       = internal.CheckSeverityError
+	CheckSeverityWarn        = internal.CheckSeverityWarn
+	CheckSeverityOff         = internal.CheckSeverityOff
+	DefaultSuppressionMarker = internal.DefaultSuppressionMarker
+)
+
+// Validation config functions
+var (
+	DefaultValidationConfig = internal.DefaultValidationConfig
+	LoadValidationConfig    = internal.LoadValidationConfig
+	ParseValidationConfig   = internal.ParseValidationConfig
+	FindRepoRoot            = internal.FindRepoRoot
+)
+
+// Session log autofix
+type (
+	SessionLogFixOptions = internal.SessionLogFixOptions
+	SessionLogFix        = internal.SessionLogFix
+	SessionLogFixResult  = internal.SessionLogFixResult
+)
+
+// Session log fix kinds
+const (
+	SessionLogFixSection     = internal.SessionLogFixSection
+	SessionLogFixChecklist   = internal.SessionLogFixChecklist
+	SessionLogFixPlaceholder = internal.SessionLogFixPlaceholder
+)
+
+// Session log autofix functions
+var (
+	FixSessionLog        = internal.FixSessionLog
+	FixSessionLogContent = internal.FixSessionLogContent
+)
+
+// Memory reindex
+type (
+	MemoryReindexOptions = internal.MemoryReindexOptions
+	MemoryReindexResult  = internal.MemoryReindexResult
+	MemoryIndexChange    = internal.MemoryIndexChange
+)
+
+// Memory reindex functions
+var (
+	ReindexMemories = internal.ReindexMemories
+)
+
+// DefaultMinKeywords is the keyword count each memory index entry should have.
+const DefaultMinKeywords = internal.DefaultMinKeywords
+
+// Coverage profiles
+type (
+	CoverageProfile      = internal.CoverageProfile
+	FileProfile          = internal.FileProfile
+	ProfileFunction      = internal.ProfileFunction
+	FileLineCoverage     = internal.FileLineCoverage
+	FunctionLineCoverage = internal.FunctionLineCoverage
+)
+
+// Coverage profile formats
+const (
+	CoverageFormatGo        = internal.CoverageFormatGo
+	CoverageFormatLCOV      = internal.CoverageFormatLCOV
+	CoverageFormatCobertura = internal.CoverageFormatCobertura
+)
+
+// Coverage profile functions
+var (
+	CoverageFormats             = internal.CoverageFormats
+	ParseCoverageProfile        = internal.ParseCoverageProfile
+	ParseCoverageProfileContent = internal.ParseCoverageProfileContent
+)
+
+// Test coverage languages
+type (
+	TestLayout       = internal.TestLayout
+	LanguageCoverage = internal.LanguageCoverage
+)
+
+// DefaultTestLayouts keeps tests next to the source or in a tests/ directory.
+var DefaultTestLayouts = internal.DefaultTestLayouts
+
+// Skill violation rules (.brain/skill-rules.yaml)
+type (
+	SkillRule    = internal.SkillRule
+	SkillRuleSet = internal.SkillRuleSet
+)
+
+// Skill rule constants
+const (
+	SkillRulesPath   = internal.SkillRulesPath
+	DefaultSkillsDir = internal.DefaultSkillsDir
+)
+
+// Skill rule functions
+var (
+	BuiltinSkillRules              = internal.BuiltinSkillRules
+	DefaultSkillRules              = internal.DefaultSkillRules
+	LoadSkillRules                 = internal.LoadSkillRules
+	LoadSkillRulesFile             = internal.LoadSkillRulesFile
+	ParseSkillRules                = internal.ParseSkillRules
+	DetectSkillViolationsWithRules = internal.DetectSkillViolationsWithRules
+)
+
+// Diff-scoped pre-PR validation
+type (
+	DiffFile         = internal.DiffFile
+	DiffLine         = internal.DiffLine
+	PrePRFinding     = internal.PrePRFinding
+	ParameterDrift   = internal.ParameterDrift
+)
+
+// Diff-scoped pre-PR functions
+var (
+	ParseUnifiedDiff         = internal.ParseUnifiedDiff
+	GitMergeBase             = internal.GitMergeBase
+	ValidatePrePRAgainstBase = internal.ValidatePrePRAgainstBase
+	ValidatePrePRFromDiff    = internal.ValidatePrePRFromDiff
+	DetectParameterDrift     = internal.DetectParameterDrift
+)
+
+// Traceability graph
+type (
+	TraceabilityGraph = internal.TraceabilityGraph
+	TraceNode         = internal.TraceNode
+	TraceEdge         = internal.TraceEdge
+	TraceabilityRow   = internal.TraceabilityRow
+)
+
+// Traceability graph node kinds and formats
+const (
+	TraceKindRequirement     = internal.TraceKindRequirement
+	TraceKindDesign          = internal.TraceKindDesign
+	TraceKindTask            = internal.TraceKindTask
+	TraceKindCode            = internal.TraceKindCode
+	TraceKindTest            = internal.TraceKindTest
+	TraceabilityGraphMatrix  = internal.TraceabilityGraphMatrix
+	TraceabilityGraphCSV     = internal.TraceabilityGraphCSV
+	TraceabilityGraphMermaid = internal.TraceabilityGraphMermaid
+	TraceabilityGraphDOT     = internal.TraceabilityGraphDOT
+)
+
+// Traceability graph functions
+var (
+	TraceabilityGraphFormats = internal.TraceabilityGraphFormats
+	BuildTraceabilityGraph   = internal.BuildTraceabilityGraph
+	FormatTraceabilityGraph  = internal.FormatTraceabilityGraph
+)
+
+// Traceability status rules
+type (
+	StatusRule         = internal.StatusRule
+	TraceabilityConfig = internal.TraceabilityConfig
+)
+
+// Traceability status rule functions
+var (
+	DefaultStatusRules            = internal.DefaultStatusRules
+	MergeStatusRules              = internal.MergeStatusRules
+	CheckStatusRules              = internal.CheckStatusRules
+	ValidateTraceabilityWithRules = internal.ValidateTraceabilityWithRules
+)
+
+// Parallel consistency validation
+type AllFeaturesOptions = internal.AllFeaturesOptions
+
+// Parallel consistency validation functions
+var ValidateAllFeaturesContext = internal.ValidateAllFeaturesContext
@@ -158,6 +158,224 @@ export interface McpServerConfig {
   searchGuardEnforce?: boolean;
 }
 
+// Source: schemas/config/skill-rules.schema.json
+/**
+ * Skill violation rules, read from .brain/skill-rules.yaml at the repository root. Each rule maps a raw command pattern to the skill that should be used instead.
+ */
+export interface SkillRules {
+  /**
+   * Rule file version. Must be 1.
+   */
+  version?: 1;
+  /**
+   * Directory, relative to the repository root, holding one directory per skill. A rule only applies when its skill exists there.
+   */
+  skillsDir?: string;
+  /**
+   * Keep the built-in rules for raw gh commands. Rules in this file with the same id replace them.
+   */
+  defaults?: boolean;
+  /**
+   * Rules, checked in order. The first rule matching a line reports it.
+   */
+  rules?: SkillRule[];
+}
+export interface SkillRule {
+  /**
+   * Unique rule identifier, e.g. "npm-publish".
+   */
+  id: string;
+  /**
+   * Regular expression (RE2) matching the raw command.
+   */
+  pattern: string;
+  /**
+   * Skill to use instead, named by its directory under skillsDir.
+   */
+  skill: string;
+  /**
+   * error fails validation; warn reports the violation without failing.
+   */
+  severity?: "error" | "warn";
+  /**
+   * Guidance shown with each violation.
+   */
+  message?: string;
+  /**
+   * Code languages the rule applies to, e.g. [shell, powershell]: fenced block languages in markdown or the language of a script. Inline code and commands in markdown text have no language and are only checked by rules without languages.
+   */
+  languages?: string[];
+}
+
+// Source: schemas/config/validation-config.schema.json
+/**
+ * error fails validation, warn reports without failing, off drops the check (or disables the validator)
+ */
+export type Severity = "error" | "warn" | "off";
+export type StringList = [string, ...string[]];
+export type SpecType = "requirement" | "design" | "task";
+
+/**
+ * Repository validation configuration, read from .brain/validation.yaml at the repository root. Sets check severities, path ignores, extra coverage languages, pattern overrides and inline suppressions.
+ */
+export interface ValidationConfig {
+  /**
+   * Configuration version. Must be 1.
+   */
+  version?: 1;
+  /**
+   * Severity per validator ("skills"), per check ("ci_environment") or per validator check ("pre-pr/ci_environment"). The most specific key wins.
+   */
+  severity?: {
+    [k: string]: Severity | undefined;
+  };
+  /**
+   * Glob patterns, relative to the repository root, of paths whose findings are dropped. ** matches any number of directories.
+   */
+  ignore?: string[];
+  /**
+   * Additional test coverage languages, keyed by language name. A built-in language of the same name is replaced.
+   */
+  languages?: {
+    [k: string]: LanguageSpec | undefined;
+  };
+  sessionProtocol?: SessionProtocolOverrides;
+  prePR?: PrePROverrides;
+  prDescription?: PRDescriptionOverrides;
+  suppressions?: SuppressionConfig;
+  traceability?: TraceabilityConfig;
+}
+/**
+ * Source and test file conventions of a language
+ */
+export interface LanguageSpec {
+  /**
+   * Source file extensions, e.g. [".ex", ".exs"]
+   *
+   * @minItems 1
+   */
+  extensions: [string, ...string[]];
+  /**
+   * Suffix replacing the extension to name a test file, e.g. "_test.exs"
+   */
+  testSuffix?: string;
+  /**
+   * Test file name templates; {name} is the source name without its extension and {ext} the extension, e.g. ["test_{name}.py", "{name}_test.py"]. The first names the expected test. Default: {name} followed by testSuffix.
+   *
+   * @minItems 1
+   */
+  testNames?: [string, ...string[]];
+  /**
+   * Regular expression identifying test files
+   */
+  testPattern: string;
+  /**
+   * Where tests live relative to sources, checked in order. Default: next to the source, then a tests/ directory.
+   *
+   * @minItems 1
+   */
+  testLayouts?: [TestLayout, ...TestLayout[]];
+  /**
+   * Regular expression of source content that marks a file as holding its own tests, e.g. "#\\[cfg\\(test\\)\\]"
+   */
+  inlineTestPattern?: string;
+  /**
+   * Regular expressions of source files that need no tests
+   */
+  ignore?: string[];
+}
+/**
+ * Maps source directories to test directories. With source, its path segments are replaced by test (src/main -> src/test). With only test, tests sit in a test directory of the source directory or any parent, flat or mirroring the source tree. {} is the source directory itself.
+ */
+export interface TestLayout {
+  /**
+   * Source directory segments, e.g. "src/main"
+   */
+  source?: string;
+  /**
+   * Test directory segments, e.g. "src/test" or "tests"
+   */
+  test?: string;
+}
+/**
+ * Overrides for the session protocol patterns. Unset fields keep their defaults.
+ */
+export interface SessionProtocolOverrides {
+  /**
+   * Regular expression session log filenames must match
+   */
+  filenamePattern?: string;
+  requiredSections?: StringList;
+  brainInitializationPatterns?: StringList;
+  brainUpdatePatterns?: StringList;
+  branchPatterns?: StringList;
+  branchPlaceholders?: StringList;
+  commitShaPatterns?: StringList;
+  lintEvidencePatterns?: StringList;
+}
+/**
+ * Overrides for whole-tree pre-PR validation. Unset fields keep their defaults.
+ */
+export interface PrePROverrides {
+  sourceDirs?: StringList;
+  testDirs?: StringList;
+  envFiles?: StringList;
+}
+/**
+ * Overrides for PR description validation. Unset fields keep their defaults.
+ */
+export interface PRDescriptionOverrides {
+  significantExtensions?: string[];
+  significantPaths?: StringList;
+  requiredSections?: StringList;
+  validateChecklist?: boolean;
+}
+/**
+ * Inline suppression comments. A comment such as '<!-- brain-ignore skill_violation: reason -->' suppresses the named checks on its own line and the line below.
+ */
+export interface SuppressionConfig {
+  /**
+   * Word that starts a suppression comment
+   */
+  marker?: string;
+  /**
+   * Whether a suppression must give a reason after the check names
+   */
+  requireJustification?: boolean;
+}
+/**
+ * Traceability validation settings
+ */
+export interface TraceabilityConfig {
+  /**
+   * Status propagation rules. A rule named like a built-in one (requirement-complete, design-approved, task-commit) changes the fields it sets; others are added.
+   */
+  statusRules?: StatusRule[];
+}
+/**
+ * Constrains the status of a spec by the statuses of the specs traced to or from it, or by its implementing commit
+ */
+export interface StatusRule {
+  /**
+   * Rule name, e.g. requirement-complete
+   */
+  name: string;
+  type?: SpecType;
+  status?: StringList;
+  linked?: SpecType;
+  require?: StringList;
+  forbid?: StringList;
+  /**
+   * The spec needs an implementing commit: one on HEAD listed under 'commits:' in its frontmatter, or one on HEAD whose message mentions its ID and that changes more than the spec file
+   */
+  commit?: boolean;
+  /**
+   * Status to suggest for the spec when the rule fails
+   */
+  suggest?: string;
+  severity?: Severity;
+}
+
 // Source: schemas/domain/memory-index-entry.schema.json
 /**
  * Schema for memory index entry validation. Validates entries in domain index files (skills-*-index.md).
@@ -285,7 +503,7 @@ export interface SkillFrontmatter {
  */
 export interface SkillViolationResult {
   /**
-   * Whether validation passed (violations are warnings, not failures)
+   * Whether validation passed (only error-severity violations fail it)
    */
   valid: boolean;
   /**
@@ -305,7 +523,7 @@ export interface SkillViolationResult {
    */
   remediation?: string;
   /**
-   * Path to skills directory
+   * Path to the skills directory the rules' skills are looked up in
    */
   skillsDir?: string;
   /**
@@ -330,14 +548,42 @@ export interface SkillViolation {
    * Line number of violation
    */
   line: number;
+  /**
+   * Column (1-based byte offset) where the matched command starts
+   */
+  column?: number;
   /**
    * Regex pattern that matched
    */
   pattern: string;
   /**
-   * Extracted gh subcommand
+   * Extracted subcommand, e.g. "pr" of "gh pr create"
    */
   command?: string;
+  /**
+   * Text the pattern matched
+   */
+  match?: string;
+  /**
+   * Id of the skill rule that matched
+   */
+  rule?: string;
+  /**
+   * Skill that should be used instead
+   */
+  skill?: string;
+  /**
+   * Rule severity; error violations fail validation
+   */
+  severity?: "error" | "warn";
+  /**
+   * Code language of the match: the fenced block language in markdown or the script language. Empty for inline code and commands in markdown text.
+   */
+  language?: string;
+  /**
+   * Rule guidance
+   */
+  message?: string;
 }
 
 // Source: schemas/domain/slash-command-frontmatter.schema.json
@@ -393,6 +639,18 @@ export type SpecFrontmatter = {
    * Related specification IDs for traceability
    */
   related?: string[];
+  /**
+   * TASK only: implementing files or globs, relative to the repository root
+   */
+  code?: string[];
+  /**
+   * TASK only: test files or globs, relative to the repository root, that reference the task ID
+   */
+  tests?: string[];
+  /**
+   * TASK only: implementing commits. A done TASK without them needs a commit on HEAD whose message mentions its ID and that changes more than the spec file
+   */
+  commits?: string[];
   /**
    * File path (runtime metadata, not from YAML)
    */
@@ -489,6 +747,10 @@ export interface PrePRConfig {
    * Environment variable files to parse for documented variables
    */
   envFiles?: string[];
+  /**
+   * Git ref, e.g. origin/main. When set, only lines added since its merge base with HEAD are checked, and findings are reported at their line
+   */
+  base?: string;
 }
 
 // Source: schemas/session/session-protocol.schema.json
@@ -1597,7 +1859,11 @@ export interface RateLimitInfo {
 /**
  * Programming language supported for test coverage detection
  */
-export type SupportedLanguage = "go" | "powershell" | "typescript" | "javascript" | "python" | "csharp";
+export type SupportedLanguage = "go" | "powershell" | "typescript" | "javascript" | "python" | "csharp" | "rust" | "java" | "kotlin" | "shell";
+/**
+ * Coverage profile format
+ */
+export type CoverageFormat = "go" | "lcov" | "cobertura";
 
 /**
  * Schema for test coverage gap detection options and results.
@@ -1617,7 +1883,7 @@ export interface TestCoverageGapOptions {
   /**
    * Language to check. Empty triggers auto-detection.
    */
-  language?: "go" | "powershell" | "typescript" | "javascript" | "python" | "csharp";
+  language?: "go" | "powershell" | "typescript" | "javascript" | "python" | "csharp" | "rust" | "java" | "kotlin" | "shell";
   /**
    * Only check git-staged files
    */
@@ -1634,6 +1900,14 @@ export interface TestCoverageGapOptions {
    * Additional regex ignore patterns
    */
   customPatterns?: string[];
+  /**
+   * Go coverprofile, LCOV tracefile or Cobertura XML report. When set, coverage is measured per line.
+   */
+  coverageProfile?: string;
+  /**
+   * Coverage profile format. Empty triggers detection.
+   */
+  profileFormat?: "go" | "lcov" | "cobertura";
 }
 /**
  * Result of test coverage gap detection
@@ -1668,6 +1942,21 @@ export interface TestCoverageGapResult {
   threshold?: number;
   missingTests?: MissingTestFile[];
   ignorePatterns?: string[];
+  /**
+   * Coverage profile the line coverage was read from
+   */
+  coverageProfile?: string;
+  profileFormat?: CoverageFormat;
+  linesCovered?: number;
+  linesTotal?: number;
+  changedLinesCovered?: number;
+  changedLinesTotal?: number;
+  changedCoveragePercent?: number;
+  files?: FileLineCoverage[];
+  /**
+   * Per-language breakdown of a polyglot result
+   */
+  languages?: LanguageCoverage[];
 }
 /**
  * A single validation check result
@@ -1700,3 +1989,56 @@ export interface MissingTestFile {
   expectedTest: string;
   language?: SupportedLanguage;
 }
+/**
+ * One language's share of a polyglot result
+ */
+export interface LanguageCoverage {
+  language: string;
+  totalSourceFiles: number;
+  filesWithTests: number;
+  coveragePercent: number;
+}
+/**
+ * Line coverage of one source file
+ */
+export interface FileLineCoverage {
+  /**
+   * Relative path to the source file
+   */
+  file: string;
+  coveredLines: number;
+  totalLines: number;
+  percent: number;
+  belowThreshold?: boolean;
+  /**
+   * The profile does not include the file; its executable lines count as uncovered
+   */
+  notInProfile?: boolean;
+  /**
+   * Uncovered line ranges, e.g. "3-7, 12"
+   */
+  uncovered?: string;
+  functions?: FunctionLineCoverage[];
+  /**
+   * Covered staged lines
+   */
+  changedCovered?: number;
+  /**
+   * Instrumented staged lines
+   */
+  changedTotal?: number;
+}
+/**
+ * Line coverage of one function
+ */
+export interface FunctionLineCoverage {
+  name: string;
+  /**
+   * Line the function starts on
+   */
+  line: number;
+  coveredLines: number;
+  totalLines: number;
+  percent: number;
+  belowThreshold?: boolean;
+}
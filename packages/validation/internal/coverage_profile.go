@@ -0,0 +1,638 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Coverage profile formats.
+const (
+	CoverageFormatGo        = "go"        // go test -coverprofile
+	CoverageFormatLCOV      = "lcov"      // LCOV tracefile (bun, c8, istanbul)
+	CoverageFormatCobertura = "cobertura" // Cobertura XML (coverage.py, coverlet)
+)
+
+// CoverageFormats lists the supported coverage profile formats.
+var CoverageFormats = []string{CoverageFormatGo, CoverageFormatLCOV, CoverageFormatCobertura}
+
+// CoverageProfile is line coverage read from a coverage profile.
+type CoverageProfile struct {
+	Format  string
+	Files   map[string]*FileProfile // keyed by the path the profile uses
+	Sources []string                // Cobertura source roots
+}
+
+// FileProfile is the coverage of one file in a profile.
+type FileProfile struct {
+	Path      string
+	Lines     map[int]int // instrumented line -> hit count
+	Functions []ProfileFunction
+}
+
+// ProfileFunction is a function's line range in a profile. End is 0 when
+// the function runs up to the next one.
+type ProfileFunction struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// FileLineCoverage is the line coverage of one source file.
+type FileLineCoverage struct {
+	File           string                 `json:"file"`
+	CoveredLines   int                    `json:"coveredLines"`
+	TotalLines     int                    `json:"totalLines"`
+	Percent        float64                `json:"percent"`
+	BelowThreshold bool                   `json:"belowThreshold,omitempty"`
+	NotInProfile   bool                   `json:"notInProfile,omitempty"`
+	Uncovered      string                 `json:"uncovered,omitempty"` // line ranges, e.g. "3-7, 12"
+	Functions      []FunctionLineCoverage `json:"functions,omitempty"`
+	ChangedCovered int                    `json:"changedCovered,omitempty"`
+	ChangedTotal   int                    `json:"changedTotal,omitempty"`
+}
+
+// FunctionLineCoverage is the line coverage of one function.
+type FunctionLineCoverage struct {
+	Name           string  `json:"name"`
+	Line           int     `json:"line"`
+	CoveredLines   int     `json:"coveredLines"`
+	TotalLines     int     `json:"totalLines"`
+	Percent        float64 `json:"percent"`
+	BelowThreshold bool    `json:"belowThreshold,omitempty"`
+}
+
+// ParseCoverageProfile reads a coverage profile. An empty format is detected
+// from the content.
+func ParseCoverageProfile(path, format string) (*CoverageProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCoverageProfileContent(data, format)
+}
+
+// ParseCoverageProfileContent parses coverage profile content. An empty
+// format is detected from the content.
+func ParseCoverageProfileContent(data []byte, format string) (*CoverageProfile, error) {
+	if format == "" {
+		format = detectCoverageFormat(data)
+	}
+	switch format {
+	case CoverageFormatGo:
+		return parseGoCoverProfile(data)
+	case CoverageFormatLCOV:
+		return parseLCOV(data)
+	case CoverageFormatCobertura:
+		return parseCobertura(data)
+	case "":
+		return nil, fmt.Errorf("unrecognized coverage profile format (want %s)", strings.Join(CoverageFormats, ", "))
+	}
+	return nil, fmt.Errorf("unsupported coverage profile format %q (want %s)", format, strings.Join(CoverageFormats, ", "))
+}
+
+func detectCoverageFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return CoverageFormatGo
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return CoverageFormatCobertura
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return CoverageFormatLCOV
+	}
+	return ""
+}
+
+func (p *CoverageProfile) file(path string) *FileProfile {
+	fp, ok := p.Files[path]
+	if !ok {
+		fp = &FileProfile{Path: path, Lines: map[int]int{}}
+		p.Files[path] = fp
+	}
+	return fp
+}
+
+// hit records count hits on a line; a line is covered if any block covering
+// it ran.
+func (fp *FileProfile) hit(line, count int) {
+	if prev, ok := fp.Lines[line]; !ok || count > prev {
+		fp.Lines[line] = count
+	}
+}
+
+var goCoverBlockPattern = regexp.MustCompile(`^(.+):(\d+)\.\d+,(\d+)\.\d+ \d+ (\d+)$`)
+
+// parseGoCoverProfile parses "file.go:line.col,line.col statements count"
+// blocks. Files are keyed by import path.
+func parseGoCoverProfile(data []byte) (*CoverageProfile, error) {
+	p := &CoverageProfile{Format: CoverageFormatGo, Files: map[string]*FileProfile{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		match := goCoverBlockPattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("line %d: malformed coverprofile block %q", n, line)
+		}
+		start, _ := strconv.Atoi(match[2])
+		end, _ := strconv.Atoi(match[3])
+		count, _ := strconv.Atoi(match[4])
+		fp := p.file(match[1])
+		for l := start; l <= end; l++ {
+			fp.hit(l, count)
+		}
+	}
+	return p, scanner.Err()
+}
+
+// parseLCOV parses the SF, FN and DA records of an LCOV tracefile.
+func parseLCOV(data []byte) (*CoverageProfile, error) {
+	p := &CoverageProfile{Format: CoverageFormatLCOV, Files: map[string]*FileProfile{}}
+	var fp *FileProfile
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, _ := strings.Cut(line, ":")
+		switch key {
+		case "SF":
+			fp = p.file(value)
+		case "end_of_record":
+			fp = nil
+		case "DA", "FN":
+			if fp == nil {
+				return nil, fmt.Errorf("line %d: %s record outside SF", n, key)
+			}
+			fields := strings.Split(value, ",")
+			num, err := strconv.Atoi(fields[0])
+			if err != nil || len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed %s record %q", n, key, line)
+			}
+			if key == "DA" {
+				hits, err := strconv.Atoi(fields[1])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: malformed DA record %q", n, line)
+				}
+				fp.hit(num, hits)
+				continue
+			}
+			fn := ProfileFunction{Name: fields[len(fields)-1], Start: num}
+			if len(fields) == 3 { // LCOV 2: FN:start,end,name
+				fn.End, _ = strconv.Atoi(fields[1])
+			}
+			fp.Functions = append(fp.Functions, fn)
+		}
+	}
+	return p, scanner.Err()
+}
+
+type coberturaReport struct {
+	Sources []string         `xml:"sources>source"`
+	Classes []coberturaClass `xml:"packages>package>classes>class"`
+}
+
+type coberturaClass struct {
+	Filename string            `xml:"filename,attr"`
+	Methods  []coberturaMethod `xml:"methods>method"`
+	Lines    []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name  string          `xml:"name,attr"`
+	Lines []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// parseCobertura parses the classes of a Cobertura XML report, merging
+// classes that share a file.
+func parseCobertura(data []byte) (*CoverageProfile, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parse Cobertura XML: %w", err)
+	}
+	p := &CoverageProfile{Format: CoverageFormatCobertura, Files: map[string]*FileProfile{}}
+	for _, source := range report.Sources {
+		if source = strings.TrimSpace(source); source != "" {
+			p.Sources = append(p.Sources, source)
+		}
+	}
+	for _, class := range report.Classes {
+		fp := p.file(class.Filename)
+		for _, l := range class.Lines {
+			fp.hit(l.Number, l.Hits)
+		}
+		for _, m := range class.Methods {
+			if len(m.Lines) == 0 {
+				continue
+			}
+			fn := ProfileFunction{Name: m.Name, Start: m.Lines[0].Number, End: m.Lines[0].Number}
+			for _, l := range m.Lines {
+				fn.Start = min(fn.Start, l.Number)
+				fn.End = max(fn.End, l.Number)
+				fp.hit(l.Number, l.Hits)
+			}
+			fp.Functions = append(fp.Functions, fn)
+		}
+	}
+	return p, nil
+}
+
+// profileResolver maps source files to the profile entries that cover them.
+type profileResolver struct {
+	byPath     map[string]*FileProfile
+	unresolved []*FileProfile
+}
+
+// resolver resolves profile paths, which may be absolute, relative to the
+// scanned path, the repo root or a Cobertura source, or Go import paths.
+// Anything else is matched to source files by its longest path suffix.
+func (p *CoverageProfile) resolver(basePath, repoRoot string) *profileResolver {
+	r := &profileResolver{byPath: map[string]*FileProfile{}}
+	roots := append([]string{basePath, repoRoot}, p.Sources...)
+	var modulePath, moduleDir string
+	if p.Format == CoverageFormatGo {
+		modulePath, moduleDir = findGoModule(basePath)
+	}
+	for key, fp := range p.Files {
+		path := filepath.FromSlash(key)
+		var candidates []string
+		if filepath.IsAbs(path) {
+			candidates = append(candidates, path)
+		}
+		if modulePath != "" && strings.HasPrefix(key, modulePath+"/") {
+			candidates = append(candidates, filepath.Join(moduleDir, filepath.FromSlash(strings.TrimPrefix(key, modulePath+"/"))))
+		}
+		for _, root := range roots {
+			if !filepath.IsAbs(path) {
+				candidates = append(candidates, filepath.Join(root, path))
+			}
+		}
+		resolved := false
+		for _, c := range candidates {
+			if FileExists(c) {
+				r.byPath[filepath.Clean(c)] = fp
+				resolved = true
+				break
+			}
+		}
+		if !resolved {
+			r.unresolved = append(r.unresolved, fp)
+		}
+	}
+	return r
+}
+
+// lookup returns the coverage of a source file, or nil if the profile does
+// not include it. Suffix matches need at least two path components (or the
+// whole profile path) and must be unambiguous.
+func (r *profileResolver) lookup(file string) *FileProfile {
+	if fp := r.byPath[filepath.Clean(file)]; fp != nil {
+		return fp
+	}
+	fileParts := strings.Split(filepath.ToSlash(file), "/")
+	var best *FileProfile
+	bestLen, tie := 0, false
+	for _, fp := range r.unresolved {
+		keyParts := strings.Split(strings.TrimPrefix(filepath.ToSlash(fp.Path), "./"), "/")
+		n := commonSuffix(fileParts, keyParts)
+		if n < 2 && n < len(keyParts) {
+			continue
+		}
+		switch {
+		case n > bestLen:
+			best, bestLen, tie = fp, n, false
+		case n == bestLen:
+			tie = true
+		}
+	}
+	if tie {
+		return nil
+	}
+	return best
+}
+
+func commonSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+var goModulePattern = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// findGoModule returns the module path and directory of the go.mod at or
+// above dir.
+func findGoModule(dir string) (modulePath, moduleDir string) {
+	for {
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			if match := goModulePattern.FindSubmatch(data); match != nil {
+				return strings.Trim(string(match[1]), `"`), dir
+			}
+			return "", ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// fileLineCoverage computes a source file's line and function coverage.
+// Files missing from the profile count their executable lines as uncovered.
+// changed limits the changed-line counts to those lines (nil skips them).
+func fileLineCoverage(file, rel string, fp *FileProfile, changed map[int]bool, threshold float64) FileLineCoverage {
+	fc := FileLineCoverage{File: rel}
+	lines := map[int]int{}
+	var functions []ProfileFunction
+	if fp != nil {
+		lines = fp.Lines
+		functions = fp.Functions
+	} else {
+		fc.NotInProfile = true
+		for _, l := range executableLines(file) {
+			lines[l] = 0
+		}
+	}
+	if len(functions) == 0 && strings.HasSuffix(file, ".go") {
+		functions = goFunctions(file)
+	}
+
+	var uncovered []int
+	for line, hits := range lines {
+		fc.TotalLines++
+		if hits > 0 {
+			fc.CoveredLines++
+		} else {
+			uncovered = append(uncovered, line)
+		}
+		if changed[line] {
+			fc.ChangedTotal++
+			if hits > 0 {
+				fc.ChangedCovered++
+			}
+		}
+	}
+	fc.Percent = linePercent(fc.CoveredLines, fc.TotalLines)
+	fc.BelowThreshold = threshold > 0 && fc.TotalLines > 0 && fc.Percent < threshold
+	if fp != nil {
+		sort.Ints(uncovered)
+		fc.Uncovered = lineRanges(uncovered)
+	}
+
+	sort.Slice(functions, func(i, j int) bool { return functions[i].Start < functions[j].Start })
+	for i, fn := range functions {
+		end := fn.End
+		if end == 0 {
+			end = int(^uint(0) >> 1)
+			if i+1 < len(functions) {
+				end = functions[i+1].Start - 1
+			}
+		}
+		f := FunctionLineCoverage{Name: fn.Name, Line: fn.Start}
+		for line, hits := range lines {
+			if line >= fn.Start && line <= end {
+				f.TotalLines++
+				if hits > 0 {
+					f.CoveredLines++
+				}
+			}
+		}
+		if f.TotalLines == 0 {
+			continue
+		}
+		f.Percent = linePercent(f.CoveredLines, f.TotalLines)
+		f.BelowThreshold = threshold > 0 && f.Percent < threshold
+		fc.Functions = append(fc.Functions, f)
+	}
+	return fc
+}
+
+func linePercent(covered, total int) float64 {
+	if total == 0 {
+		return 100.0
+	}
+	return float64(covered) / float64(total) * 100.0
+}
+
+// lineRanges formats sorted line numbers as "3-7, 12".
+func lineRanges(lines []int) string {
+	var parts []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, Itoa(lines[i]))
+		} else {
+			parts = append(parts, Itoa(lines[i])+"-"+Itoa(lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// goFunctions returns the line ranges of a Go file's functions and methods,
+// named as go tool cover -func names them.
+func goFunctions(file string) []ProfileFunction {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	var functions []ProfileFunction
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			recv := fn.Recv.List[0].Type
+			if index, ok := recv.(*ast.IndexExpr); ok {
+				recv = index.X
+			}
+			switch t := recv.(type) {
+			case *ast.StarExpr:
+				if ident, ok := t.X.(*ast.Ident); ok {
+					name = "(*" + ident.Name + ")." + name
+				}
+			case *ast.Ident:
+				name = t.Name + "." + name
+			}
+		}
+		functions = append(functions, ProfileFunction{
+			Name:  name,
+			Start: fset.Position(fn.Pos()).Line,
+			End:   fset.Position(fn.End()).Line,
+		})
+	}
+	return functions
+}
+
+// executableLines estimates the lines a coverage tool would instrument in a
+// file missing from the profile: non-blank lines inside Go function bodies,
+// or non-blank, non-comment lines for other languages.
+func executableLines(file string) []int {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	code := func(n int) bool {
+		text := strings.TrimSpace(lines[n-1])
+		return text != "" && text != "{" && text != "}" &&
+			!strings.HasPrefix(text, "//") && !strings.HasPrefix(text, "#") &&
+			!strings.HasPrefix(text, "/*") && !strings.HasPrefix(text, "*")
+	}
+
+	var out []int
+	if strings.HasSuffix(file, ".go") {
+		for _, fn := range goFunctions(file) {
+			for n := fn.Start + 1; n < fn.End; n++ {
+				if code(n) {
+					out = append(out, n)
+				}
+			}
+		}
+		return out
+	}
+	for n := 1; n <= len(lines); n++ {
+		if code(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+var diffHunkPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// stagedChangedLines returns the lines of a file added or changed in the
+// git index.
+func stagedChangedLines(repoRoot, file string) map[int]bool {
+	changed := map[int]bool{}
+	cmd := exec.Command("git", "-C", repoRoot, "diff", "--cached", "--unified=0", "--no-color", "--", file)
+	output, err := cmd.Output()
+	if err != nil {
+		return changed
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		match := diffHunkPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(match[1])
+		count := 1
+		if match[2] != "" {
+			count, _ = strconv.Atoi(match[2])
+		}
+		for l := start; l < start+count; l++ {
+			changed[l] = true
+		}
+	}
+	return changed
+}
+
+// detectLineCoverage measures the files to check against a coverage profile.
+// The threshold applies to overall line coverage, or to the coverage of the
+// changed lines in staged mode; files and functions below it are reported
+// without failing the check.
+func detectLineCoverage(result TestCoverageGapResult, opts TestCoverageGapOptions, files []string, repoRoot string, langConfig LanguageConfig) TestCoverageGapResult {
+	profile, err := ParseCoverageProfile(opts.CoverageProfile, opts.ProfileFormat)
+	if err != nil {
+		result.Valid = false
+		result.Message = "Failed to read coverage profile: " + err.Error()
+		return result
+	}
+	result.CoverageProfile = opts.CoverageProfile
+	result.ProfileFormat = profile.Format
+	resolver := profile.resolver(result.BasePath, repoRoot)
+
+	for _, file := range files {
+		var changed map[int]bool
+		if opts.StagedOnly {
+			changed = stagedChangedLines(repoRoot, file)
+		}
+		rel := makeRelativePath(file, repoRoot)
+		fc := fileLineCoverage(file, rel, resolver.lookup(file), changed, opts.Threshold)
+		if fc.TotalLines == 0 {
+			continue // nothing executable
+		}
+		result.Files = append(result.Files, fc)
+		result.LinesCovered += fc.CoveredLines
+		result.LinesTotal += fc.TotalLines
+		result.ChangedLinesCovered += fc.ChangedCovered
+		result.ChangedLinesTotal += fc.ChangedTotal
+		if fc.CoveredLines == 0 {
+			result.MissingTests = append(result.MissingTests, MissingTestFile{
+				SourceFile:   rel,
+				ExpectedTest: makeRelativePath(findExpectedTestPath(file, langConfig, repoRoot), repoRoot),
+				Language:     opts.Language,
+			})
+		}
+	}
+
+	result.TotalSourceFiles = len(result.Files)
+	result.FilesWithoutTests = len(result.MissingTests)
+	result.FilesWithTests = result.TotalSourceFiles - result.FilesWithoutTests
+	result.CoveragePercent = linePercent(result.LinesCovered, result.LinesTotal)
+
+	measured, percent := "Line coverage", result.CoveragePercent
+	if opts.StagedOnly && result.ChangedLinesTotal > 0 {
+		result.ChangedCoveragePercent = linePercent(result.ChangedLinesCovered, result.ChangedLinesTotal)
+		measured, percent = "Changed-line coverage", result.ChangedCoveragePercent
+	}
+
+	finishCoverageResult(&result, opts, measured, percent)
+	return result
+}
+
+// buildLineCoverageRemediation lists the least covered files with their
+// uncovered lines.
+func buildLineCoverageRemediation(files []FileLineCoverage) string {
+	var below []FileLineCoverage
+	for _, f := range files {
+		if f.BelowThreshold {
+			below = append(below, f)
+		}
+	}
+	if len(below) == 0 {
+		return "Add tests that exercise the changed lines"
+	}
+	sort.SliceStable(below, func(i, j int) bool { return below[i].Percent < below[j].Percent })
+
+	var sb strings.Builder
+	sb.WriteString("Add tests covering these lines:\n")
+	for _, f := range below[:min(10, len(below))] {
+		sb.WriteString("  - " + f.File + ": " + formatCoveragePercent(f.Percent))
+		if f.NotInProfile {
+			sb.WriteString(" (not in profile)")
+		} else if f.Uncovered != "" {
+			sb.WriteString(" (uncovered lines " + f.Uncovered + ")")
+		}
+		sb.WriteString("\n")
+	}
+	if len(below) > 10 {
+		sb.WriteString("  ... and " + Itoa(len(below)-10) + " more files\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,244 @@
+package internal_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+const calcSource = `package calc
+
+// Add adds.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Div divides.
+func Div(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+`
+
+// calcRepo is a git repo with a Go module holding calc.go (tested) and
+// other.go (missing from the profiles).
+func calcRepo(t *testing.T) string {
+	t.Helper()
+	dir := createTempGitRepo(t)
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/calc\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "calc.go"), calcSource)
+	writeTestFile(t, filepath.Join(dir, "other.go"), "package calc\n\nfunc Other() int {\n\treturn 1\n}\n")
+	return dir
+}
+
+// calcGoProfile covers Add and the first branch of Div.
+const calcGoProfile = `mode: set
+example.com/calc/calc.go:4.24,6.2 1 1
+example.com/calc/calc.go:9.24,10.12 1 1
+example.com/calc/calc.go:10.12,12.3 1 0
+example.com/calc/calc.go:13.2,13.14 1 1
+`
+
+func TestParseCoverageProfileContent_Formats(t *testing.T) {
+	lcov := "TN:\nSF:src/calc.ts\nFN:1,add\nFN:5,div\nDA:2,3\nDA:6,0\nDA:7,1\nend_of_record\n"
+	cobertura := `<?xml version="1.0"?>
+<coverage><sources><source>/repo</source></sources><packages><package><classes>
+<class filename="calc.py"><methods><method name="div"><lines><line number="6" hits="0"/><line number="7" hits="2"/></lines></method></methods>
+<lines><line number="2" hits="1"/><line number="6" hits="0"/><line number="7" hits="2"/></lines></class>
+</classes></package></packages></coverage>`
+
+	tests := []struct {
+		name, data, format, file string
+		lines                    int
+		functions                int
+	}{
+		{"go", calcGoProfile, internal.CoverageFormatGo, "example.com/calc/calc.go", 8, 0},
+		{"lcov", lcov, internal.CoverageFormatLCOV, "src/calc.ts", 3, 2},
+		{"cobertura", cobertura, internal.CoverageFormatCobertura, "calc.py", 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := internal.ParseCoverageProfileContent([]byte(tt.data), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Format != tt.format {
+				t.Errorf("detected format %q, want %q", p.Format, tt.format)
+			}
+			fp := p.Files[tt.file]
+			if fp == nil || len(fp.Lines) != tt.lines || len(fp.Functions) != tt.functions {
+				t.Errorf("file %s = %+v", tt.file, fp)
+			}
+		})
+	}
+
+	if _, err := internal.ParseCoverageProfileContent([]byte("not a profile"), ""); err == nil {
+		t.Error("expected an error for an unrecognized profile")
+	}
+	if _, err := internal.ParseCoverageProfileContent([]byte("mode: set\ngarbage\n"), ""); err == nil {
+		t.Error("expected an error for a malformed coverprofile")
+	}
+}
+
+func TestDetectTestCoverageGaps_GoProfile(t *testing.T) {
+	dir := calcRepo(t)
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	writeTestFile(t, profile, calcGoProfile)
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:        dir,
+		Language:        "go",
+		Threshold:       80,
+		CoverageProfile: profile,
+	})
+	if result.ProfileFormat != internal.CoverageFormatGo || len(result.Files) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	calc := result.Files[0]
+	if calc.File != "calc.go" || calc.CoveredLines != 6 || calc.TotalLines != 8 || calc.Uncovered != "11-12" {
+		t.Errorf("calc.go = %+v", calc)
+	}
+	if len(calc.Functions) != 2 || calc.Functions[0].Name != "Add" || calc.Functions[0].Percent != 100 ||
+		!calc.Functions[1].BelowThreshold {
+		t.Errorf("functions = %+v", calc.Functions)
+	}
+	other := result.Files[1]
+	if !other.NotInProfile || other.TotalLines != 1 || other.CoveredLines != 0 {
+		t.Errorf("other.go = %+v", other)
+	}
+	if len(result.MissingTests) != 1 || result.MissingTests[0].SourceFile != "other.go" {
+		t.Errorf("missing tests = %+v", result.MissingTests)
+	}
+
+	// 6 of 9 lines are covered.
+	if result.Valid || result.LinesCovered != 6 || result.LinesTotal != 9 {
+		t.Errorf("valid=%v covered=%d total=%d", result.Valid, result.LinesCovered, result.LinesTotal)
+	}
+	if !strings.Contains(result.Remediation, "other.go: 0% (not in profile)") ||
+		!strings.Contains(result.Remediation, "calc.go: 75% (uncovered lines 11-12)") {
+		t.Errorf("remediation:\n%s", result.Remediation)
+	}
+
+	var lineFindings int
+	for _, f := range result.Findings() {
+		if f.Check == "line_coverage" {
+			lineFindings++
+			if f.File == "calc.go" && f.Line != 11 {
+				t.Errorf("finding line = %d, want 11", f.Line)
+			}
+		}
+	}
+	if lineFindings != 2 {
+		t.Errorf("got %d line_coverage findings, want 2", lineFindings)
+	}
+}
+
+func TestDetectTestCoverageGaps_LCOVSuffixMatch(t *testing.T) {
+	dir := createTempGitRepo(t)
+	writeTestFile(t, filepath.Join(dir, "src", "calc.ts"), "export function add(a: number, b: number) {\n  return a + b;\n}\n")
+	profile := filepath.Join(t.TempDir(), "lcov.info")
+	// Paths from another checkout match by suffix.
+	writeTestFile(t, profile, "SF:/ci/a/src/calc.ts\nDA:1,1\nDA:2,1\nend_of_record\nSF:/ci/b/src/calc.ts\nDA:1,0\nend_of_record\n")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:        dir,
+		Language:        "typescript",
+		Threshold:       100,
+		CoverageProfile: profile,
+	})
+	// Two profile entries end in src/calc.ts, so the match is ambiguous.
+	if len(result.Files) != 1 || !result.Files[0].NotInProfile {
+		t.Fatalf("files = %+v", result.Files)
+	}
+
+	writeTestFile(t, profile, "SF:/ci/a/src/calc.ts\nFN:1,add\nDA:1,1\nDA:2,1\nend_of_record\n")
+	result = internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:        dir,
+		Language:        "typescript",
+		Threshold:       100,
+		CoverageProfile: profile,
+	})
+	if !result.Valid || result.CoveragePercent != 100 || len(result.Files[0].Functions) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestDetectTestCoverageGaps_StagedChangedLines(t *testing.T) {
+	dir := calcRepo(t)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", "calc"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	// Stage a change to Div's uncovered branch only.
+	writeTestFile(t, filepath.Join(dir, "calc.go"), strings.Replace(calcSource, "return 0\n", "return -1\n", 1))
+	if out, err := exec.Command("git", "-C", dir, "add", "calc.go").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	writeTestFile(t, profile, calcGoProfile)
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:        dir,
+		Language:        "go",
+		StagedOnly:      true,
+		Threshold:       50,
+		CoverageProfile: profile,
+	})
+	if len(result.Files) != 1 || result.ChangedLinesTotal != 1 || result.ChangedLinesCovered != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	// calc.go is 75% covered overall, but the changed line is not.
+	if result.Valid || !strings.Contains(result.Checks[0].Message, "Changed-line coverage 0%") {
+		t.Errorf("checks = %+v", result.Checks)
+	}
+}
+
+func TestDetectTestCoverageGaps_BadProfile(t *testing.T) {
+	dir := calcRepo(t)
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:        dir,
+		Language:        "go",
+		CoverageProfile: filepath.Join(dir, "missing.out"),
+	})
+	if result.Valid || !strings.Contains(result.Message, "Failed to read coverage profile") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestDetectTestCoverageGaps_PolyglotProfile(t *testing.T) {
+	dir := createTempGitRepo(t)
+	writeTestFile(t, filepath.Join(dir, "server", "calc.go"), "package server\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	writeTestFile(t, filepath.Join(dir, "web", "app.ts"), "export function run() {\n  return 1;\n}\n")
+	profile := filepath.Join(t.TempDir(), "lcov.info")
+	writeTestFile(t, profile, "SF:server/calc.go\nDA:3,1\nDA:4,0\nend_of_record\nSF:web/app.ts\nDA:1,1\nDA:2,1\nend_of_record\n")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:        dir,
+		Threshold:       90,
+		CoverageProfile: profile,
+	})
+	if len(result.Languages) != 2 || result.LinesCovered != 3 || result.LinesTotal != 4 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	checks := map[string]string{}
+	for _, c := range result.Checks {
+		checks[c.Name] = c.Message
+	}
+	if result.Valid || checks["coverage_threshold"] != "Line coverage 75% below threshold 90%" {
+		t.Errorf("valid = %v, checks = %+v", result.Valid, result.Checks)
+	}
+	if checks["files_below_threshold"] != "1 of 2 files below 90% line coverage" || checks["coverage_go"] == "" {
+		t.Errorf("checks = %+v", result.Checks)
+	}
+	if !strings.Contains(result.Remediation, "server/calc.go: 50% (uncovered lines 4)") {
+		t.Errorf("remediation:\n%s", result.Remediation)
+	}
+}
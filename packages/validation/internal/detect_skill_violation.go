@@ -1,13 +1,13 @@
 package internal
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
@@ -16,10 +16,17 @@ import (
 
 // SkillViolation represents a detected violation of skill usage policy.
 type SkillViolation struct {
-	File    string `json:"file"`
-	Line    int    `json:"line"`
-	Pattern string `json:"pattern"`
-	Command string `json:"command,omitempty"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	Pattern  string `json:"pattern"`
+	Command  string `json:"command,omitempty"`  // subcommand, e.g. "pr" of "gh pr create"
+	Match    string `json:"match,omitempty"`    // matched text
+	Rule     string `json:"rule,omitempty"`     // id of the rule that matched
+	Skill    string `json:"skill,omitempty"`    // skill to use instead
+	Severity string `json:"severity,omitempty"` // error or warn
+	Language string `json:"language,omitempty"` // code language of the match
+	Message  string `json:"message,omitempty"`  // rule guidance
 }
 
 // SkillViolationResult represents the result of skill violation detection.
@@ -136,10 +143,18 @@ var GhCommandPatterns = []*regexp.Regexp{
 // DefaultSkillsPath is the default path to GitHub skills directory.
 const DefaultSkillsPath = ".claude/skills/github/scripts"
 
-// DetectSkillViolations scans files for raw gh command usage when GitHub skills exist.
+// DetectSkillViolations scans files for raw commands that have a skill, using
+// the repository rules (.brain/skill-rules.yaml, or the built-in gh rules).
 // basePath is the root directory to scan from.
-// stagedOnly when true, only checks git-staged files.
+// stagedOnly when true, only checks lines added in git-staged files.
 func DetectSkillViolations(basePath string, stagedOnly bool) SkillViolationResult {
+	return DetectSkillViolationsWithRules(basePath, stagedOnly, nil)
+}
+
+// DetectSkillViolationsWithRules scans files for raw commands matching rules.
+// Nil rules load the repository rules. A rule only applies when its skill
+// exists in the skills directory.
+func DetectSkillViolationsWithRules(basePath string, stagedOnly bool, rules *SkillRuleSet) SkillViolationResult {
 	result := SkillViolationResult{
 		ValidationResult: ValidationResult{
 			Valid: true,
@@ -162,11 +177,19 @@ func DetectSkillViolations(basePath string, stagedOnly bool) SkillViolationResul
 		return result
 	}
 
-	// Check if skills directory exists
-	skillsDir := filepath.Join(repoRoot, DefaultSkillsPath)
-	result.SkillsDir = skillsDir
-	if !DirExists(skillsDir) {
-		result.Message = "GitHub skills directory not found: " + skillsDir
+	if rules == nil {
+		if rules, err = LoadSkillRules(repoRoot); err != nil {
+			result.Valid = false
+			result.Message = "Invalid skill rules: " + err.Error()
+			return result
+		}
+	}
+
+	// Only rules whose skill exists are enforced
+	result.SkillsDir = rules.skillsDir(repoRoot)
+	active := rules.activeRules(repoRoot)
+	if len(active) == 0 {
+		result.Message = "No skills for the skill rules found in: " + result.SkillsDir
 		return result
 	}
 
@@ -180,39 +203,24 @@ func DetectSkillViolations(basePath string, stagedOnly bool) SkillViolationResul
 	result.FilesChecked = len(filesToCheck)
 
 	// Scan files for violations
-	violations, capabilityGaps := scanFilesForViolations(repoRoot, filesToCheck)
-	result.Violations = violations
-	result.CapabilityGaps = capabilityGaps
-
-	// Build checks
-	var checks []Check
-	if len(violations) > 0 {
-		for _, v := range violations {
-			checks = append(checks, Check{
-				Name:    "skill_violation",
-				Passed:  false,
-				Message: v.File + ":" + Itoa(v.Line) + " - matches '" + v.Pattern + "'",
-			})
+	for _, file := range filesToCheck {
+		content, err := os.ReadFile(filepath.Join(repoRoot, file))
+		if err != nil {
+			continue
 		}
-		result.Message = "Detected raw 'gh' command usage (skill violations)"
-		result.Remediation = buildSkillViolationRemediation(capabilityGaps)
-	} else {
-		checks = append(checks, Check{
-			Name:    "skill_violation",
-			Passed:  true,
-			Message: "No skill violations detected",
-		})
-		result.Message = "No skill violations detected"
+		var added map[int]bool
+		if stagedOnly {
+			added = stagedChangedLines(repoRoot, file)
+		}
+		result.Violations = append(result.Violations, scanContentForViolations(file, string(content), active, added)...)
 	}
 
-	result.Checks = checks
-	// Note: violations are non-blocking warnings per the original PowerShell script
-	// result.Valid remains true even with violations
-
+	buildSkillViolationChecks(&result)
 	return result
 }
 
-// DetectSkillViolationsFromContent scans content strings for skill violations.
+// DetectSkillViolationsFromContent scans content strings for skill violations
+// of the built-in gh rules.
 // Useful for testing or when content is already loaded.
 func DetectSkillViolationsFromContent(contents map[string]string) SkillViolationResult {
 	result := SkillViolationResult{
@@ -222,51 +230,59 @@ func DetectSkillViolationsFromContent(contents map[string]string) SkillViolation
 		FilesChecked: len(contents),
 	}
 
-	var violations []SkillViolation
-	capabilityGaps := make(map[string]bool)
-
-	for filename, content := range contents {
-		fileViolations := scanContentForViolations(filename, content)
-		violations = append(violations, fileViolations...)
+	files := make([]string, 0, len(contents))
+	for filename := range contents {
+		files = append(files, filename)
+	}
+	sort.Strings(files)
 
-		for _, v := range fileViolations {
-			if v.Command != "" {
-				capabilityGaps[v.Command] = true
-			}
-		}
+	rules := BuiltinSkillRules()
+	for _, filename := range files {
+		result.Violations = append(result.Violations, scanContentForViolations(filename, contents[filename], rules, nil)...)
 	}
 
-	result.Violations = violations
+	buildSkillViolationChecks(&result)
+	return result
+}
 
-	// Convert capability gaps map to slice
-	for gap := range capabilityGaps {
-		result.CapabilityGaps = append(result.CapabilityGaps, gap)
+// buildSkillViolationChecks sets the checks, capability gaps and message of
+// a result from its violations. Only error-severity violations fail it.
+func buildSkillViolationChecks(result *SkillViolationResult) {
+	if len(result.Violations) == 0 {
+		result.Checks = []Check{{
+			Name:    "skill_violation",
+			Passed:  true,
+			Message: "No skill violations detected",
+		}}
+		result.Message = "No skill violations detected"
+		return
 	}
 
-	// Build checks
+	gaps := make(map[string]bool)
 	var checks []Check
-	if len(violations) > 0 {
-		for _, v := range violations {
-			checks = append(checks, Check{
-				Name:    "skill_violation",
-				Passed:  false,
-				Message: v.File + ":" + Itoa(v.Line) + " - matches '" + v.Pattern + "'",
-			})
+	for _, v := range result.Violations {
+		if v.Command != "" && !gaps[v.Command] {
+			gaps[v.Command] = true
+			result.CapabilityGaps = append(result.CapabilityGaps, v.Command)
+		}
+		if v.Severity == CheckSeverityError {
+			result.Valid = false
+		}
+		message := v.File + ":" + Itoa(v.Line) + ":" + Itoa(v.Column) + " - matches '" + v.Pattern + "'"
+		if v.Skill != "" {
+			message += " (use the " + v.Skill + " skill)"
 		}
-		result.Message = "Detected raw 'gh' command usage (skill violations)"
-		result.Remediation = buildSkillViolationRemediation(result.CapabilityGaps)
-	} else {
 		checks = append(checks, Check{
 			Name:    "skill_violation",
-			Passed:  true,
-			Message: "No skill violations detected",
+			Passed:  false,
+			Message: message,
 		})
-		result.Message = "No skill violations detected"
 	}
+	sort.Strings(result.CapabilityGaps)
 
 	result.Checks = checks
-
-	return result
+	result.Message = "Detected raw command usage where skills exist (skill violations)"
+	result.Remediation = buildSkillViolationRemediation(result.Violations)
 }
 
 // findGitRoot finds the git repository root from the given starting directory.
@@ -284,16 +300,16 @@ func getFilesToCheck(repoRoot string, stagedOnly bool) []string {
 	var files []string
 
 	if stagedOnly {
-		files = getGitStagedMarkdownAndPowerShell(repoRoot)
+		files = getGitStagedTargetFiles(repoRoot)
 	} else {
-		files = getAllMarkdownAndPowerShellFiles(repoRoot)
+		files = getAllTargetFiles(repoRoot)
 	}
 
 	return files
 }
 
-// getGitStagedMarkdownAndPowerShell returns git-staged .md, .ps1, .psm1 files.
-func getGitStagedMarkdownAndPowerShell(repoRoot string) []string {
+// getGitStagedTargetFiles returns git-staged markdown, PowerShell and shell files.
+func getGitStagedTargetFiles(repoRoot string) []string {
 	cmd := exec.Command("git", "-C", repoRoot, "diff", "--cached", "--name-only", "--diff-filter=ACMR")
 	output, err := cmd.Output()
 	if err != nil {
@@ -315,8 +331,8 @@ func getGitStagedMarkdownAndPowerShell(repoRoot string) []string {
 	return files
 }
 
-// getAllMarkdownAndPowerShellFiles returns all .md, .ps1, .psm1 files in the repo.
-func getAllMarkdownAndPowerShellFiles(repoRoot string) []string {
+// getAllTargetFiles returns all markdown, PowerShell and shell files in the repo.
+func getAllTargetFiles(repoRoot string) []string {
 	var files []string
 
 	err := filepath.Walk(repoRoot, func(path string, info os.FileInfo, err error) error {
@@ -351,135 +367,250 @@ func getAllMarkdownAndPowerShellFiles(repoRoot string) []string {
 	return files
 }
 
+// targetFileLanguages maps the extensions checked for violations to the code
+// language of the file; markdown is split into text and code blocks.
+var targetFileLanguages = map[string]string{
+	".md":   "markdown",
+	".ps1":  "powershell",
+	".psm1": "powershell",
+	".sh":   "shell",
+	".bash": "shell",
+}
+
 // isTargetFile returns true if the file should be checked for violations.
 func isTargetFile(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	return ext == ".md" || ext == ".ps1" || ext == ".psm1"
+	_, ok := targetFileLanguages[strings.ToLower(filepath.Ext(path))]
+	return ok
 }
 
-// scanFilesForViolations scans files and returns violations and capability gaps.
-func scanFilesForViolations(repoRoot string, files []string) ([]SkillViolation, []string) {
-	var violations []SkillViolation
-	capabilityGaps := make(map[string]bool)
+// codeSpan is text on one line that rules are matched against.
+type codeSpan struct {
+	line     int // 1-indexed
+	column   int // 1-indexed byte offset of text in the line
+	text     string
+	language string // code language; "" for inline code and markdown text
+	prose    bool   // markdown text: only commands, not mentions, count
+}
 
-	for _, file := range files {
-		fullPath := filepath.Join(repoRoot, file)
-		content, err := os.ReadFile(fullPath)
-		if err != nil {
-			continue
+var (
+	markdownFencePattern = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+	// commandPrefixPattern matches what may precede a command in markdown
+	// text: indentation, a list marker, a blockquote or a shell prompt.
+	commandPrefixPattern = regexp.MustCompile(`^\s*(?:[-*+]\s+|\d+[.)]\s+)?(?:>\s*)*(?:\$|PS>)?\s*$`)
+)
+
+// codeSpans splits content into the spans rules are matched against: each
+// line of a script, and the fenced code, inline code and text lines of a
+// markdown file.
+func codeSpans(filename, content string) []codeSpan {
+	lines := strings.Split(content, "\n")
+	language := targetFileLanguages[strings.ToLower(filepath.Ext(filename))]
+	if language == "" {
+		language = "markdown" // content scanned without a known extension
+	}
+
+	var spans []codeSpan
+	if language != "markdown" {
+		for i, line := range lines {
+			spans = append(spans, codeSpan{line: i + 1, column: 1, text: line, language: language})
 		}
+		return spans
+	}
 
-		fileViolations := scanContentForViolations(file, string(content))
-		if len(fileViolations) > 0 {
-			// Only report first violation per file
-			violations = append(violations, fileViolations[0])
+	for i := 0; i < len(lines); i++ {
+		open := markdownFencePattern.FindStringSubmatch(lines[i])
+		if open == nil || (open[1][0] == '`' && strings.Contains(open[2], "`")) {
+			spans = append(spans, codeSpan{line: i + 1, column: 1, text: lines[i], language: "", prose: true})
+			spans = append(spans, inlineCodeSpans(i+1, lines[i])...)
+			continue
+		}
 
-			if fileViolations[0].Command != "" {
-				capabilityGaps[fileViolations[0].Command] = true
-			}
+		// Collect the block up to its closing fence or the end of the file
+		start := i + 1
+		end := start
+		for end < len(lines) && !isClosingFence(lines[end], open[1]) {
+			end++
+		}
+		blockLanguage := fenceLanguage(open[2], lines[start:end])
+		for j := start; j < end; j++ {
+			spans = append(spans, codeSpan{line: j + 1, column: 1, text: lines[j], language: blockLanguage})
 		}
+		i = end
 	}
+	return spans
+}
 
-	// Convert map to slice
-	var gaps []string
-	for gap := range capabilityGaps {
-		gaps = append(gaps, gap)
+// isClosingFence reports whether line closes a block opened by fence.
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 || len(trimmed) < len(fence) {
+		return false
 	}
+	return strings.Trim(trimmed, fence[:1]) == ""
+}
 
-	return violations, gaps
+// inlineCodeSpans returns the `code` spans of a markdown text line.
+func inlineCodeSpans(lineNum int, line string) []codeSpan {
+	var spans []codeSpan
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		run := i
+		for i < len(line) && line[i] == '`' {
+			i++
+		}
+		fence := line[run:i]
+		closing := strings.Index(line[i:], fence)
+		if closing < 0 {
+			break
+		}
+		spans = append(spans, codeSpan{line: lineNum, column: i + 1, text: line[i : i+closing]})
+		i += closing + len(fence)
+	}
+	return spans
 }
 
-// scanContentForViolations scans content string for gh command violations.
-func scanContentForViolations(filename, content string) []SkillViolation {
+// scanContentForViolations scans content for commands matching rules and
+// returns them with their line and column. In markdown, fenced and inline
+// code is checked, and text lines only where a command starts the line or
+// follows a colon, so prose that mentions a command is not flagged. When
+// lines is not nil, only violations on those lines are reported. Only the
+// first violation on a line is reported.
+func scanContentForViolations(filename, content string, rules []SkillRule, lines map[int]bool) []SkillViolation {
 	var violations []SkillViolation
 
-	lines := strings.Split(content, "\n")
-	for lineNum, line := range lines {
-		for _, pattern := range GhCommandPatterns {
-			if pattern.MatchString(line) {
-				violation := SkillViolation{
-					File:    filename,
-					Line:    lineNum + 1, // 1-indexed
-					Pattern: pattern.String(),
-					Command: extractGhCommand(line),
-				}
-				violations = append(violations, violation)
-				break // Only report first match per line
+	spans := codeSpans(filename, content)
+	for i := 0; i < len(spans); {
+		// Group the spans of one line
+		j := i
+		for j < len(spans) && spans[j].line == spans[i].line {
+			j++
+		}
+		if lines == nil || lines[spans[i].line] {
+			if v, ok := firstViolation(filename, spans[i:j], rules); ok {
+				violations = append(violations, v)
 			}
 		}
+		i = j
 	}
 
 	return violations
 }
 
-// extractGhCommand extracts the gh subcommand from a line.
-func extractGhCommand(line string) string {
-	pattern := regexp.MustCompile(`gh\s+(\w+)`)
-	matches := pattern.FindStringSubmatch(line)
+// firstViolation returns the match of the first rule, in rule order, that
+// matches one of the spans of a line.
+func firstViolation(filename string, spans []codeSpan, rules []SkillRule) (SkillViolation, bool) {
+	for _, rule := range rules {
+		re := rule.compiled()
+		if re == nil {
+			continue
+		}
+		for _, span := range spans {
+			if !rule.appliesTo(span.language) {
+				continue
+			}
+			for _, loc := range re.FindAllStringIndex(span.text, -1) {
+				if span.prose && !isCommandPosition(span.text[:loc[0]]) {
+					continue
+				}
+				match := span.text[loc[0]:loc[1]]
+				return SkillViolation{
+					File:     filename,
+					Line:     span.line,
+					Column:   span.column + loc[0],
+					Pattern:  rule.Pattern,
+					Command:  extractSubcommand(match),
+					Match:    match,
+					Rule:     rule.ID,
+					Skill:    rule.Skill,
+					Severity: skillRuleSeverity(rule),
+					Language: span.language,
+					Message:  rule.Message,
+				}, true
+			}
+		}
+	}
+	return SkillViolation{}, false
+}
+
+// isCommandPosition reports whether text before a match in a markdown text
+// line makes it a command rather than a mention in prose.
+func isCommandPosition(prefix string) bool {
+	return commandPrefixPattern.MatchString(prefix) || strings.HasSuffix(strings.TrimSpace(prefix), ":")
+}
+
+// skillRuleSeverity returns the rule severity, warn when unset.
+func skillRuleSeverity(rule SkillRule) string {
+	if rule.Severity == "" {
+		return CheckSeverityWarn
+	}
+	return rule.Severity
+}
+
+var subcommandPattern = regexp.MustCompile(`^\S+\s+(\w[\w-]*)`)
+
+// extractSubcommand extracts the subcommand, such as "pr" of "gh pr create",
+// from a matched command.
+func extractSubcommand(match string) string {
+	matches := subcommandPattern.FindStringSubmatch(strings.TrimSpace(match))
 	if len(matches) > 1 {
 		return matches[1]
 	}
 	return ""
 }
 
-// buildSkillViolationRemediation builds remediation text for skill violations.
-func buildSkillViolationRemediation(capabilityGaps []string) string {
+// buildSkillViolationRemediation builds remediation text for skill violations,
+// listing each raw command with the skill it should be added to.
+func buildSkillViolationRemediation(violations []SkillViolation) string {
 	var sb strings.Builder
 
-	sb.WriteString("These commands indicate missing GitHub skill capabilities.\n")
-	sb.WriteString("Use .claude/skills/github/ scripts instead, or file an issue to add the capability.\n\n")
+	sb.WriteString("These commands indicate missing skill capabilities.\n")
+	sb.WriteString("Use the skill scripts instead, or file an issue to add the capability.\n\n")
 
-	if len(capabilityGaps) > 0 {
-		sb.WriteString("Missing skill capabilities detected:\n")
-		for _, gap := range capabilityGaps {
-			sb.WriteString("  - gh " + gap + " (consider adding to .claude/skills/github/)\n")
+	seen := make(map[string]bool)
+	var gaps []string
+	for _, v := range violations {
+		fields := strings.Fields(v.Match)
+		if len(fields) == 0 {
+			continue
+		}
+		gap := "  - " + fields[0]
+		if v.Command != "" {
+			gap += " " + v.Command
+		}
+		if v.Skill != "" {
+			gap += " (consider adding to " + DefaultSkillsDir + "/" + v.Skill + "/)"
 		}
-		sb.WriteString("\n")
+		if !seen[gap] {
+			seen[gap] = true
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) > 0 {
+		sort.Strings(gaps)
+		sb.WriteString("Missing skill capabilities detected:\n")
+		sb.WriteString(strings.Join(gaps, "\n"))
+		sb.WriteString("\n\n")
 	}
 
-	sb.WriteString("REMINDER: Use GitHub skills for better error handling, consistency, and auditability.\n")
-	sb.WriteString("Before using raw 'gh' commands, check: Get-ChildItem .claude/skills/github/scripts -Recurse\n")
+	sb.WriteString("REMINDER: Use skills for better error handling, consistency, and auditability.\n")
+	sb.WriteString("Before using raw commands, check the skill's scripts, e.g.: Get-ChildItem .claude/skills/github/scripts -Recurse\n")
 	sb.WriteString("If the capability you need doesn't exist, create a skill script or file an issue.")
 
 	return sb.String()
 }
 
-// ScanFileForViolations scans a single file for skill violations.
+// ScanFileForViolations scans a single file for violations of the built-in gh
+// rules, with the same code awareness as DetectSkillViolations.
 // Exported for direct file scanning without full validation setup.
 func ScanFileForViolations(filePath string) ([]SkillViolation, error) {
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	var violations []SkillViolation
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-
-		for _, pattern := range GhCommandPatterns {
-			if pattern.MatchString(line) {
-				violation := SkillViolation{
-					File:    filePath,
-					Line:    lineNum,
-					Pattern: pattern.String(),
-					Command: extractGhCommand(line),
-				}
-				violations = append(violations, violation)
-				break // Only report first match per line
-			}
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return violations, err
-	}
-
-	return violations, nil
+	return scanContentForViolations(filePath, string(content), BuiltinSkillRules(), nil), nil
 }
 
 // HasSkillViolations returns true if any violations were detected.
@@ -690,3 +690,75 @@ func TestDetectSkillViolationsFromContent_NoViolationsChecks(t *testing.T) {
 		t.Error("Expected passing skill_violation check")
 	}
 }
+
+// Tests for code awareness and locations
+
+func TestDetectSkillViolations_ProseMentionsNotFlagged(t *testing.T) {
+	contents := map[string]string{
+		"docs.md": `# GitHub
+
+Never call gh pr create directly; the skill handles retries.
+Run: gh pr view 42
+- gh issue list
+$ gh api repos/owner/repo
+`,
+	}
+
+	result := internal.DetectSkillViolationsFromContent(contents)
+
+	var lines []int
+	for _, v := range result.Violations {
+		lines = append(lines, v.Line)
+	}
+	if len(lines) != 3 || lines[0] != 4 || lines[1] != 5 || lines[2] != 6 {
+		t.Errorf("violation lines = %v, want 4, 5 and 6 (not the prose on line 3)", lines)
+	}
+	if v := result.Violations[0]; v.Column != 6 || v.Language != "" {
+		t.Errorf("violation = %+v, want column 6 with no language", v)
+	}
+}
+
+func TestDetectSkillViolations_FencedBlockLanguage(t *testing.T) {
+	contents := map[string]string{
+		"docs.md": "Use `gh pr merge` to merge.\n\n" +
+			"```bash\ngh pr list\n```\n\n" +
+			"```\n$prs = gh pr list --json number\n```\n\n" +
+			"~~~\ngh issue view 1 && echo done\n~~~\n",
+	}
+
+	result := internal.DetectSkillViolationsFromContent(contents)
+
+	want := []struct {
+		line, column int
+		language     string
+	}{
+		{1, 6, ""},
+		{4, 1, "shell"},
+		{8, 8, "powershell"},
+		{12, 1, "shell"},
+	}
+	if len(result.Violations) != len(want) {
+		t.Fatalf("violations = %+v", result.Violations)
+	}
+	for i, w := range want {
+		v := result.Violations[i]
+		if v.Line != w.line || v.Column != w.column || v.Language != w.language {
+			t.Errorf("violation %d = line %d column %d language %q, want %d %d %q", i, v.Line, v.Column, v.Language, w.line, w.column, w.language)
+		}
+	}
+}
+
+func TestScanFileForViolations_Columns(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "Sync-PR.ps1")
+	if err := os.WriteFile(testFile, []byte("# Sync\n    $pr = gh pr view $Number\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	violations, err := internal.ScanFileForViolations(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Line != 2 || violations[0].Column != 11 || violations[0].Language != "powershell" {
+		t.Errorf("violations = %+v", violations)
+	}
+}
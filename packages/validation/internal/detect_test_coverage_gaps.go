@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -22,6 +23,28 @@ type TestCoverageGapResult struct {
 	Threshold         float64           `json:"threshold"`
 	MissingTests      []MissingTestFile `json:"missingTests,omitempty"`
 	IgnorePatterns    []string          `json:"ignorePatterns,omitempty"`
+
+	// Line coverage, set when a coverage profile is given
+	CoverageProfile        string             `json:"coverageProfile,omitempty"`
+	ProfileFormat          string             `json:"profileFormat,omitempty"`
+	LinesCovered           int                `json:"linesCovered,omitempty"`
+	LinesTotal             int                `json:"linesTotal,omitempty"`
+	ChangedLinesCovered    int                `json:"changedLinesCovered,omitempty"`
+	ChangedLinesTotal      int                `json:"changedLinesTotal,omitempty"`
+	ChangedCoveragePercent float64            `json:"changedCoveragePercent,omitempty"`
+	Files                  []FileLineCoverage `json:"files,omitempty"`
+
+	// Languages breaks down a polyglot result; Language is then the most
+	// common one
+	Languages []LanguageCoverage `json:"languages,omitempty"`
+}
+
+// LanguageCoverage is one language's share of a polyglot result.
+type LanguageCoverage struct {
+	Language         string  `json:"language"`
+	TotalSourceFiles int     `json:"totalSourceFiles"`
+	FilesWithTests   int     `json:"filesWithTests"`
+	CoveragePercent  float64 `json:"coveragePercent"`
 }
 
 // MissingTestFile represents a source file without a corresponding test file.
@@ -37,14 +60,37 @@ type LanguageConfig struct {
 	TestSuffix    string         // Test file suffix (e.g., "_test.go", ".Tests.ps1")
 	TestPattern   *regexp.Regexp // Pattern to identify test files
 	DefaultIgnore []string       // Default patterns to ignore
+	// TestNames are test file name templates; {name} is the source file name
+	// without its extension and {ext} the extension (default: "{name}" + TestSuffix)
+	TestNames []string
+	// TestLayouts say where tests live (default: DefaultTestLayouts)
+	TestLayouts []TestLayout
+	// InlineTests marks source files that hold their own tests (e.g. Rust's #[cfg(test)])
+	InlineTests *regexp.Regexp
+}
+
+// TestLayout maps source directories to the directories holding their tests.
+// With Source set, the Source path segments are replaced by Test, e.g.
+// src/main/java/x -> src/test/java/x. With only Test set, tests sit in a Test
+// directory of the source directory or any parent up to the repo root,
+// either flat (tests/test_x.py) or mirroring the source tree
+// (tests/pkg/x_test.go). An empty layout is the source directory itself.
+// Both the mirrored and the flat location are checked.
+type TestLayout struct {
+	Source string `json:"source,omitempty"`
+	Test   string `json:"test,omitempty"`
 }
 
+// DefaultTestLayouts keeps tests next to the source or in a tests/ directory.
+var DefaultTestLayouts = []TestLayout{{}, {Test: "tests"}}
+
 // LanguageConfigs maps language names to their configurations.
 var LanguageConfigs = map[string]LanguageConfig{
 	"go": {
 		Extensions:  []string{".go"},
 		TestSuffix:  "_test.go",
 		TestPattern: regexp.MustCompile(`_test\.go$`),
+		TestNames:   []string{"{name}_test.go"},
 		DefaultIgnore: []string{
 			`_test\.go$`, // Test files themselves
 			`/testdata/`, // Test data directories
@@ -60,6 +106,7 @@ var LanguageConfigs = map[string]LanguageConfig{
 		Extensions:  []string{".ps1", ".psm1"},
 		TestSuffix:  ".Tests.ps1",
 		TestPattern: regexp.MustCompile(`\.Tests\.ps1$`),
+		TestNames:   []string{"{name}.Tests.ps1"},
 		DefaultIgnore: []string{
 			`\.Tests\.ps1$`,   // Test files themselves
 			`tests?[\\/]`,     // Test directories
@@ -73,6 +120,8 @@ var LanguageConfigs = map[string]LanguageConfig{
 		Extensions:  []string{".ts", ".tsx"},
 		TestSuffix:  ".test.ts",
 		TestPattern: regexp.MustCompile(`\.(test|spec)\.(ts|tsx)$`),
+		TestNames:   []string{"{name}.test.ts", "{name}.spec.ts", "{name}.test{ext}", "{name}.spec{ext}"},
+		TestLayouts: []TestLayout{{}, {Test: "tests"}, {Test: "__tests__"}},
 		DefaultIgnore: []string{
 			`\.(test|spec)\.(ts|tsx)$`, // Test files
 			`/node_modules/`,           // Dependencies
@@ -86,6 +135,8 @@ var LanguageConfigs = map[string]LanguageConfig{
 		Extensions:  []string{".js", ".jsx"},
 		TestSuffix:  ".test.js",
 		TestPattern: regexp.MustCompile(`\.(test|spec)\.(js|jsx)$`),
+		TestNames:   []string{"{name}.test.js", "{name}.spec.js", "{name}.test{ext}", "{name}.spec{ext}"},
+		TestLayouts: []TestLayout{{}, {Test: "tests"}, {Test: "__tests__"}},
 		DefaultIgnore: []string{
 			`\.(test|spec)\.(js|jsx)$`, // Test files
 			`/node_modules/`,           // Dependencies
@@ -97,6 +148,8 @@ var LanguageConfigs = map[string]LanguageConfig{
 		Extensions:  []string{".py"},
 		TestSuffix:  "_test.py",
 		TestPattern: regexp.MustCompile(`([\\/]test_[^\\/]+\.py$|_test\.py$)`),
+		TestNames:   []string{"test_{name}.py", "{name}_test.py"},
+		TestLayouts: []TestLayout{{}, {Test: "tests"}, {Test: "test"}},
 		DefaultIgnore: []string{
 			`[\\/]test_[^\\/]+\.py$`, // Test files (test_*.py)
 			`_test\.py$`,             // Test files (*_test.py)
@@ -110,6 +163,7 @@ var LanguageConfigs = map[string]LanguageConfig{
 		Extensions:  []string{".cs"},
 		TestSuffix:  "Tests.cs",
 		TestPattern: regexp.MustCompile(`Tests?\.cs$`),
+		TestNames:   []string{"{name}Tests.cs", "{name}Test.cs"},
 		DefaultIgnore: []string{
 			`Tests?\.cs$`,       // Test files
 			`/obj/`,             // Build output
@@ -119,6 +173,65 @@ var LanguageConfigs = map[string]LanguageConfig{
 			`GlobalUsings\.cs$`, // Global usings
 		},
 	},
+	"rust": {
+		Extensions:  []string{".rs"},
+		TestSuffix:  ".rs",
+		TestPattern: regexp.MustCompile(`[\\/]tests[\\/].+\.rs$`),
+		TestNames:   []string{"{name}.rs", "{name}_test.rs", "test_{name}.rs"},
+		TestLayouts: []TestLayout{{Source: "src", Test: "tests"}, {Test: "tests"}},
+		InlineTests: regexp.MustCompile(`#\[cfg\(test\)\]`),
+		DefaultIgnore: []string{
+			`/tests/`,    // Integration tests
+			`/target/`,   // Build output
+			`/benches/`,  // Benchmarks
+			`/examples/`, // Examples
+			`main\.rs$`,  // Binary entry points
+			`build\.rs$`, // Build scripts
+		},
+	},
+	"java": {
+		Extensions:  []string{".java"},
+		TestSuffix:  "Test.java",
+		TestPattern: regexp.MustCompile(`(Test|Tests|IT)\.java$`),
+		TestNames:   []string{"{name}Test.java", "{name}Tests.java", "{name}IT.java"},
+		TestLayouts: []TestLayout{{Source: "src/main", Test: "src/test"}, {}},
+		DefaultIgnore: []string{
+			`(Test|Tests|IT)\.java$`, // Test files
+			`/src/test/`,             // Test sources
+			`/target/`,               // Maven output
+			`/build/`,                // Gradle output
+			`package-info\.java$`,    // Package docs
+			`module-info\.java$`,     // Module descriptors
+		},
+	},
+	"kotlin": {
+		Extensions:  []string{".kt"},
+		TestSuffix:  "Test.kt",
+		TestPattern: regexp.MustCompile(`(Test|Tests|IT)\.kt$`),
+		TestNames:   []string{"{name}Test.kt", "{name}Tests.kt", "{name}IT.kt"},
+		TestLayouts: []TestLayout{{Source: "src/main", Test: "src/test"}, {}},
+		DefaultIgnore: []string{
+			`(Test|Tests|IT)\.kt$`, // Test files
+			`/src/test/`,           // Test sources
+			`/build/`,              // Gradle output
+			`/target/`,             // Maven output
+		},
+	},
+	"shell": {
+		Extensions:  []string{".sh", ".bash"},
+		TestSuffix:  ".bats",
+		TestPattern: regexp.MustCompile(`(_test|_spec)\.(sh|bash)$|[\\/]test_[^\\/]+\.(sh|bash)$`),
+		TestNames:   []string{"{name}.bats", "{name}_test{ext}", "{name}_spec{ext}", "test_{name}{ext}"},
+		TestLayouts: []TestLayout{{}, {Test: "tests"}, {Test: "test"}, {Test: "spec"}},
+		DefaultIgnore: []string{
+			`(_test|_spec)\.(sh|bash)$`,     // Test files
+			`[\\/]test_[^\\/]+\.(sh|bash)$`, // Test files (test_*.sh)
+			`/tests?/`,                      // Test directories
+			`/node_modules/`,                // Dependencies
+			`\.github[\\/]`,                 // GitHub workflows
+			`install.*\.sh$`,                // Installation scripts
+		},
+	},
 }
 
 // TestCoverageGapOptions configures the test coverage gap detection.
@@ -129,9 +242,17 @@ type TestCoverageGapOptions struct {
 	IgnoreFile     string   // Path to file containing patterns to ignore
 	Threshold      float64  // Coverage threshold (0-100)
 	CustomPatterns []string // Additional ignore patterns
+	// Languages adds to or replaces LanguageConfigs for this run
+	Languages map[string]LanguageConfig
+	// CoverageProfile is a Go coverprofile, LCOV tracefile or Cobertura XML
+	// report; when set, coverage is measured per line instead of per file
+	CoverageProfile string
+	ProfileFormat   string // "go", "lcov" or "cobertura" (empty = detect)
 }
 
-// DetectTestCoverageGaps identifies source files without corresponding test files.
+// DetectTestCoverageGaps identifies source files without corresponding test
+// files. With a coverage profile it measures line coverage per file and
+// function instead, and in staged mode the coverage of the changed lines.
 func DetectTestCoverageGaps(opts TestCoverageGapOptions) TestCoverageGapResult {
 	result := TestCoverageGapResult{
 		BasePath:   opts.BasePath,
@@ -153,6 +274,17 @@ func DetectTestCoverageGaps(opts TestCoverageGapOptions) TestCoverageGapResult {
 	}
 	result.BasePath = absPath
 
+	languages := LanguageConfigs
+	if len(opts.Languages) > 0 {
+		languages = make(map[string]LanguageConfig, len(LanguageConfigs)+len(opts.Languages))
+		for name, config := range LanguageConfigs {
+			languages[name] = config
+		}
+		for name, config := range opts.Languages {
+			languages[name] = config
+		}
+	}
+
 	// Find git repo root
 	repoRoot := findGitRepoRoot(absPath)
 	if repoRoot == "" {
@@ -161,14 +293,18 @@ func DetectTestCoverageGaps(opts TestCoverageGapOptions) TestCoverageGapResult {
 		return result
 	}
 
-	// Auto-detect language if not specified
+	// Auto-detect languages if not specified; polyglot repos check each
 	if opts.Language == "" {
-		opts.Language = detectLanguage(absPath)
+		detected := detectLanguages(absPath, languages)
+		if len(detected) > 1 {
+			return detectPolyglotCoverageGaps(result, opts, detected)
+		}
+		opts.Language = detected[0]
 	}
 	result.Language = opts.Language
 
 	// Get language config
-	langConfig, ok := LanguageConfigs[opts.Language]
+	langConfig, ok := languages[opts.Language]
 	if !ok {
 		result.Valid = false
 		result.Message = "Unsupported language: " + opts.Language
@@ -197,11 +333,14 @@ func DetectTestCoverageGaps(opts TestCoverageGapOptions) TestCoverageGapResult {
 		return result
 	}
 
+	if opts.CoverageProfile != "" {
+		return detectLineCoverage(result, opts, filesToCheck, repoRoot, langConfig)
+	}
+
 	result.TotalSourceFiles = len(filesToCheck)
 
 	// Check each file for corresponding test
 	var missingTests []MissingTestFile
-	var checks []Check
 
 	for _, file := range filesToCheck {
 		testPath := findExpectedTestPath(file, langConfig, repoRoot)
@@ -227,51 +366,7 @@ func DetectTestCoverageGaps(opts TestCoverageGapOptions) TestCoverageGapResult {
 		result.CoveragePercent = 100.0
 	}
 
-	// Determine if valid based on threshold
-	if opts.Threshold > 0 && result.CoveragePercent < opts.Threshold {
-		result.Valid = false
-		checks = append(checks, Check{
-			Name:    "coverage_threshold",
-			Passed:  false,
-			Message: "Test coverage " + formatCoveragePercent(result.CoveragePercent) + " below threshold " + formatCoveragePercent(opts.Threshold),
-		})
-	} else {
-		result.Valid = true
-		checks = append(checks, Check{
-			Name:    "coverage_threshold",
-			Passed:  true,
-			Message: "Test coverage " + formatCoveragePercent(result.CoveragePercent) + " meets threshold",
-		})
-	}
-
-	// Add check for missing tests
-	if len(missingTests) > 0 {
-		checks = append(checks, Check{
-			Name:    "missing_tests",
-			Passed:  true, // Non-blocking warning
-			Message: Itoa(len(missingTests)) + " files without test coverage",
-		})
-	} else {
-		checks = append(checks, Check{
-			Name:    "missing_tests",
-			Passed:  true,
-			Message: "All source files have test coverage",
-		})
-	}
-
-	result.Checks = checks
-
-	if result.Valid {
-		if len(missingTests) > 0 {
-			result.Message = "Test coverage gaps detected (non-blocking)"
-		} else {
-			result.Message = "All source files have test coverage"
-		}
-	} else {
-		result.Message = "Test coverage below threshold"
-		result.Remediation = buildCoverageRemediation(missingTests, opts.Language)
-	}
-
+	finishCoverageResult(&result, opts, "Test coverage", result.CoveragePercent)
 	return result
 }
 
@@ -303,8 +398,9 @@ func findGitRepoRoot(startDir string) string {
 	return strings.TrimSpace(string(output))
 }
 
-// detectLanguage auto-detects the primary language based on file extensions.
-func detectLanguage(basePath string) string {
+// detectLanguages returns the languages with source files under basePath,
+// most files first, or go if there are none.
+func detectLanguages(basePath string, languages map[string]LanguageConfig) []string {
 	counts := make(map[string]int)
 
 	filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
@@ -315,12 +411,14 @@ func detectLanguage(basePath string) string {
 		// Skip common non-source directories
 		if strings.Contains(path, "/node_modules/") ||
 			strings.Contains(path, "/vendor/") ||
+			strings.Contains(path, "/target/") ||
+			strings.Contains(path, "/.venv/") ||
 			strings.Contains(path, "/.git/") {
 			return nil
 		}
 
 		ext := strings.ToLower(filepath.Ext(path))
-		for lang, config := range LanguageConfigs {
+		for lang, config := range languages {
 			for _, langExt := range config.Extensions {
 				if ext == langExt && !config.TestPattern.MatchString(path) {
 					counts[lang]++
@@ -330,19 +428,178 @@ func detectLanguage(basePath string) string {
 		return nil
 	})
 
-	// Find language with most files
-	maxCount := 0
-	detected := "go" // Default to Go
-	for lang, count := range counts {
-		if count > maxCount {
-			maxCount = count
-			detected = lang
+	detected := make([]string, 0, len(counts))
+	for lang := range counts {
+		detected = append(detected, lang)
+	}
+	sort.Slice(detected, func(i, j int) bool {
+		if counts[detected[i]] != counts[detected[j]] {
+			return counts[detected[i]] > counts[detected[j]]
 		}
+		return detected[i] < detected[j]
+	})
+	if len(detected) == 0 {
+		return []string{"go"} // Default to Go
 	}
-
 	return detected
 }
 
+// detectPolyglotCoverageGaps checks each detected language and aggregates
+// the results. Languages without source files to check, or that a coverage
+// profile does not cover, are left out.
+func detectPolyglotCoverageGaps(result TestCoverageGapResult, opts TestCoverageGapOptions, detected []string) TestCoverageGapResult {
+	var parts []TestCoverageGapResult
+	for _, lang := range detected {
+		langOpts := opts
+		langOpts.Language = lang
+		part := DetectTestCoverageGaps(langOpts)
+		if part.TotalSourceFiles == 0 || (opts.CoverageProfile != "" && !coversAnyFile(part)) {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	switch len(parts) {
+	case 0:
+		opts.Language = detected[0]
+		return DetectTestCoverageGaps(opts)
+	case 1:
+		return parts[0]
+	}
+
+	result.Language = parts[0].Language
+	for _, part := range parts {
+		if part.CoverageProfile == "" && opts.CoverageProfile != "" {
+			return part // the profile could not be read
+		}
+		result.TotalSourceFiles += part.TotalSourceFiles
+		result.FilesWithTests += part.FilesWithTests
+		result.FilesWithoutTests += part.FilesWithoutTests
+		result.MissingTests = append(result.MissingTests, part.MissingTests...)
+		result.IgnorePatterns = append(result.IgnorePatterns, part.IgnorePatterns...)
+		result.CoverageProfile = part.CoverageProfile
+		result.ProfileFormat = part.ProfileFormat
+		result.LinesCovered += part.LinesCovered
+		result.LinesTotal += part.LinesTotal
+		result.ChangedLinesCovered += part.ChangedLinesCovered
+		result.ChangedLinesTotal += part.ChangedLinesTotal
+		result.Files = append(result.Files, part.Files...)
+		result.Languages = append(result.Languages, LanguageCoverage{
+			Language:         part.Language,
+			TotalSourceFiles: part.TotalSourceFiles,
+			FilesWithTests:   part.FilesWithTests,
+			CoveragePercent:  part.CoveragePercent,
+		})
+	}
+
+	measured := "Test coverage"
+	if opts.CoverageProfile != "" {
+		measured = "Line coverage"
+		result.CoveragePercent = linePercent(result.LinesCovered, result.LinesTotal)
+	} else {
+		result.CoveragePercent = float64(result.FilesWithTests) / float64(result.TotalSourceFiles) * 100.0
+	}
+	percent := result.CoveragePercent
+	if opts.CoverageProfile != "" && opts.StagedOnly && result.ChangedLinesTotal > 0 {
+		result.ChangedCoveragePercent = linePercent(result.ChangedLinesCovered, result.ChangedLinesTotal)
+		measured, percent = "Changed-line coverage", result.ChangedCoveragePercent
+	}
+
+	finishCoverageResult(&result, opts, measured, percent)
+	return result
+}
+
+// finishCoverageResult sets the validity, checks, message and remediation of
+// a coverage result from the measured percentage: file coverage, or line
+// coverage when opts has a coverage profile.
+func finishCoverageResult(result *TestCoverageGapResult, opts TestCoverageGapOptions, measured string, percent float64) {
+	lineCoverage := opts.CoverageProfile != ""
+
+	result.Valid = opts.Threshold <= 0 || percent >= opts.Threshold
+	check := Check{Name: "coverage_threshold", Passed: result.Valid}
+	if result.Valid {
+		check.Message = measured + " " + formatCoveragePercent(percent) + " meets threshold"
+	} else {
+		check.Message = measured + " " + formatCoveragePercent(percent) + " below threshold " + formatCoveragePercent(opts.Threshold)
+	}
+	checks := []Check{check}
+
+	for _, lang := range result.Languages {
+		checks = append(checks, Check{
+			Name:    "coverage_" + lang.Language,
+			Passed:  true, // Non-blocking breakdown
+			Message: lang.Language + ": " + formatCoveragePercent(lang.CoveragePercent) + " of " + Itoa(lang.TotalSourceFiles) + " files",
+		})
+	}
+
+	var belowFiles, belowFunctions int
+	for _, f := range result.Files {
+		if f.BelowThreshold {
+			belowFiles++
+		}
+		for _, fn := range f.Functions {
+			if fn.BelowThreshold {
+				belowFunctions++
+			}
+		}
+	}
+	if lineCoverage && opts.Threshold > 0 {
+		checks = append(checks,
+			Check{Name: "files_below_threshold", Passed: true, // Non-blocking warning
+				Message: Itoa(belowFiles) + " of " + Itoa(len(result.Files)) + " files below " + formatCoveragePercent(opts.Threshold) + " line coverage"},
+			Check{Name: "functions_below_threshold", Passed: true,
+				Message: Itoa(belowFunctions) + " functions below " + formatCoveragePercent(opts.Threshold) + " line coverage"})
+	}
+
+	covered := "test coverage"
+	if lineCoverage {
+		covered = "covered lines"
+	}
+	if len(result.MissingTests) > 0 {
+		checks = append(checks, Check{
+			Name:    "missing_tests",
+			Passed:  true, // Non-blocking warning
+			Message: Itoa(len(result.MissingTests)) + " files without " + covered,
+		})
+	} else {
+		checks = append(checks, Check{
+			Name:    "missing_tests",
+			Passed:  true,
+			Message: "All source files have " + covered,
+		})
+	}
+	result.Checks = checks
+
+	switch {
+	case !result.Valid:
+		result.Message = "Test coverage below threshold"
+		if lineCoverage {
+			result.Remediation = buildLineCoverageRemediation(result.Files)
+		} else {
+			result.Remediation = buildCoverageRemediation(result.MissingTests, opts.Language)
+		}
+	case belowFiles > 0 || len(result.MissingTests) > 0:
+		result.Message = "Test coverage gaps detected (non-blocking)"
+	case lineCoverage:
+		result.Message = "Line coverage " + formatCoveragePercent(result.CoveragePercent) + " from " + result.ProfileFormat + " profile"
+	default:
+		result.Message = "All source files have test coverage"
+	}
+}
+
+// coversAnyFile reports whether a line coverage result found any of its
+// files in the profile.
+func coversAnyFile(r TestCoverageGapResult) bool {
+	if r.CoverageProfile == "" {
+		return true // keep profile errors
+	}
+	for _, f := range r.Files {
+		if !f.NotInProfile {
+			return true
+		}
+	}
+	return false
+}
+
 // buildIgnorePatterns builds the complete list of ignore patterns.
 func buildIgnorePatterns(langConfig LanguageConfig, opts TestCoverageGapOptions) []string {
 	patterns := make([]string, 0)
@@ -476,92 +733,105 @@ func filterIgnoredFiles(files []string, patterns []string) []string {
 	return filtered
 }
 
-// findExpectedTestPath calculates the expected test file path.
-func findExpectedTestPath(sourcePath string, langConfig LanguageConfig, _ string) string {
-	dir := filepath.Dir(sourcePath)
-	base := filepath.Base(sourcePath)
-	ext := filepath.Ext(base)
-	nameWithoutExt := strings.TrimSuffix(base, ext)
-
-	var testFileName string
-	switch langConfig.TestSuffix {
-	case "_test.go":
-		testFileName = nameWithoutExt + "_test.go"
-	case ".Tests.ps1":
-		testFileName = nameWithoutExt + ".Tests.ps1"
-	case ".test.ts":
-		testFileName = nameWithoutExt + ".test.ts"
-	case ".test.js":
-		testFileName = nameWithoutExt + ".test.js"
-	case "_test.py":
-		testFileName = "test_" + nameWithoutExt + ".py"
-	case "Tests.cs":
-		testFileName = nameWithoutExt + "Tests.cs"
-	default:
-		testFileName = nameWithoutExt + langConfig.TestSuffix
+// findExpectedTestPath calculates the expected test file path: the first
+// test name in the first test directory.
+func findExpectedTestPath(sourcePath string, langConfig LanguageConfig, repoRoot string) string {
+	candidates := candidateTestPaths(sourcePath, langConfig, repoRoot)
+	if len(candidates) == 0 {
+		return sourcePath
 	}
-
-	return filepath.Join(dir, testFileName)
+	return candidates[0]
 }
 
-// testFileExists checks if a test file exists for the source file.
+// testFileExists checks if a test file exists for the source file, or the
+// source file holds its own tests.
 func testFileExists(sourcePath string, langConfig LanguageConfig, repoRoot string) bool {
-	dir := filepath.Dir(sourcePath)
-	base := filepath.Base(sourcePath)
-	ext := filepath.Ext(base)
-	nameWithoutExt := strings.TrimSuffix(base, ext)
-
-	// Check primary location (same directory)
-	primaryTest := findExpectedTestPath(sourcePath, langConfig, repoRoot)
-	if FileExists(primaryTest) {
-		return true
+	if langConfig.InlineTests != nil {
+		if content, err := os.ReadFile(sourcePath); err == nil && langConfig.InlineTests.Match(content) {
+			return true
+		}
 	}
-
-	// Check tests/ subdirectory
-	testsSubdir := filepath.Join(dir, "tests")
-	testInSubdir := filepath.Join(testsSubdir, filepath.Base(primaryTest))
-	if FileExists(testInSubdir) {
-		return true
+	for _, candidate := range candidateTestPaths(sourcePath, langConfig, repoRoot) {
+		if FileExists(candidate) {
+			return true
+		}
 	}
+	return false
+}
 
-	// For Go, also check in a 'tests' directory relative to repo root
-	if langConfig.TestSuffix == "_test.go" {
-		// Calculate relative path from repo root
-		relPath, err := filepath.Rel(repoRoot, dir)
-		if err == nil {
-			testsDir := filepath.Join(repoRoot, "tests", relPath)
-			testInTestsDir := filepath.Join(testsDir, nameWithoutExt+"_test.go")
-			if FileExists(testInTestsDir) {
-				return true
+// candidateTestPaths lists where a source file's tests may be, in order:
+// each test directory of the language's layouts with each test name.
+func candidateTestPaths(sourcePath string, langConfig LanguageConfig, repoRoot string) []string {
+	base := filepath.Base(sourcePath)
+	ext := filepath.Ext(base)
+	replacer := strings.NewReplacer("{name}", strings.TrimSuffix(base, ext), "{ext}", ext)
+	templates := langConfig.TestNames
+	if len(templates) == 0 {
+		templates = []string{"{name}" + langConfig.TestSuffix}
+	}
+
+	var candidates []string
+	seen := map[string]bool{sourcePath: true}
+	for _, dir := range testDirs(filepath.Dir(sourcePath), langConfig, repoRoot) {
+		for _, template := range templates {
+			candidate := filepath.Join(dir, replacer.Replace(template))
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
 			}
 		}
 	}
+	return candidates
+}
 
-	// For Python, check test_*.py naming convention
-	if langConfig.TestSuffix == "_test.py" {
-		altTestName := "test_" + nameWithoutExt + ".py"
-		altTestPath := filepath.Join(dir, altTestName)
-		if FileExists(altTestPath) {
-			return true
-		}
-
-		// Check in tests/ subdirectory
-		altTestInSubdir := filepath.Join(testsSubdir, altTestName)
-		if FileExists(altTestInSubdir) {
-			return true
+// testDirs lists the directories the language's test layouts put the tests
+// of sources in dir.
+func testDirs(dir string, langConfig LanguageConfig, repoRoot string) []string {
+	layouts := langConfig.TestLayouts
+	if len(layouts) == 0 {
+		layouts = DefaultTestLayouts
+	}
+	rel, err := filepath.Rel(repoRoot, dir)
+	inRepo := err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	if !inRepo || rel == "." {
+		rel = ""
+	}
+
+	var dirs []string
+	seen := map[string]bool{}
+	add := func(d string) {
+		if d = filepath.Clean(d); !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
 		}
 	}
-
-	// For TypeScript/JavaScript, check .spec.* variant
-	if strings.HasSuffix(langConfig.TestSuffix, ".test.ts") || strings.HasSuffix(langConfig.TestSuffix, ".test.js") {
-		specSuffix := strings.Replace(langConfig.TestSuffix, ".test.", ".spec.", 1)
-		specTestPath := filepath.Join(dir, nameWithoutExt+specSuffix)
-		if FileExists(specTestPath) {
-			return true
+	for _, layout := range layouts {
+		switch {
+		case layout.Source == "" && layout.Test == "":
+			add(dir)
+		case layout.Source != "":
+			// Replace the Source segments of the repo-relative path
+			padded := "/" + filepath.ToSlash(rel) + "/"
+			i := strings.Index(padded, "/"+strings.Trim(layout.Source, "/")+"/")
+			if !inRepo || i < 0 {
+				continue
+			}
+			testRoot := filepath.Join(repoRoot, filepath.FromSlash(padded[1:i+1]), filepath.FromSlash(layout.Test))
+			add(filepath.Join(testRoot, filepath.FromSlash(padded[i+len(strings.Trim(layout.Source, "/"))+2:])))
+			add(testRoot)
+		default:
+			// A Test directory of dir or any parent up to the repo root
+			for ancestor := dir; ; ancestor = filepath.Dir(ancestor) {
+				below, _ := filepath.Rel(ancestor, dir)
+				add(filepath.Join(ancestor, layout.Test, below))
+				add(filepath.Join(ancestor, layout.Test))
+				if !inRepo || ancestor == repoRoot || filepath.Dir(ancestor) == ancestor {
+					break
+				}
+			}
 		}
 	}
-
-	return false
+	return dirs
 }
 
 // makeRelativePath converts an absolute path to relative path from repo root.
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -642,30 +643,30 @@ func TestGetSupportedLanguages(t *testing.T) {
 
 func TestAddLanguageConfig(t *testing.T) {
 	// Add custom language config
-	internal.AddLanguageConfig("rust", internal.LanguageConfig{
-		Extensions:  []string{".rs"},
-		TestSuffix:  "_test.rs",
+	internal.AddLanguageConfig("zig", internal.LanguageConfig{
+		Extensions:  []string{".zig"},
+		TestSuffix:  "_test.zig",
 		TestPattern: internal.LanguageConfigs["go"].TestPattern, // Reuse pattern for testing
 		DefaultIgnore: []string{
-			`_test\.rs$`,
-			`/target/`,
+			`_test\.zig$`,
+			`/zig-cache/`,
 		},
 	})
 
 	languages := internal.GetSupportedLanguages()
 	found := false
 	for _, lang := range languages {
-		if lang == "rust" {
+		if lang == "zig" {
 			found = true
 			break
 		}
 	}
 	if !found {
-		t.Error("Expected 'rust' to be in supported languages after AddLanguageConfig")
+		t.Error("Expected 'zig' to be in supported languages after AddLanguageConfig")
 	}
 
 	// Clean up
-	delete(internal.LanguageConfigs, "rust")
+	delete(internal.LanguageConfigs, "zig")
 }
 
 // Tests for edge cases
@@ -764,3 +765,130 @@ func findTestGitRoot(startDir string) string {
 	}
 	return strings.TrimSpace(string(output))
 }
+
+// Tests for test layouts and polyglot detection
+
+func TestDetectTestCoverageGaps_JavaMirroredLayout(t *testing.T) {
+	tmpDir := createTempGitRepo(t)
+
+	main := filepath.Join(tmpDir, "src", "main", "java", "com", "acme")
+	writeTestFile(t, filepath.Join(main, "Order.java"), "class Order {}")
+	writeTestFile(t, filepath.Join(main, "Invoice.java"), "class Invoice {}")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "test", "java", "com", "acme", "OrderTest.java"), "class OrderTest {}")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath: tmpDir,
+		Language: "java",
+	})
+
+	if result.TotalSourceFiles != 2 || result.FilesWithTests != 1 {
+		t.Fatalf("files = %d with tests %d, want 2 and 1: %+v", result.TotalSourceFiles, result.FilesWithTests, result.MissingTests)
+	}
+	want := filepath.Join("src", "test", "java", "com", "acme", "InvoiceTest.java")
+	if got := result.MissingTests[0].ExpectedTest; got != want {
+		t.Errorf("ExpectedTest = %q, want %q", got, want)
+	}
+}
+
+func TestDetectTestCoverageGaps_PythonTestsDirectory(t *testing.T) {
+	tmpDir := createTempGitRepo(t)
+
+	writeTestFile(t, filepath.Join(tmpDir, "app", "models", "user.py"), "class User: pass")
+	writeTestFile(t, filepath.Join(tmpDir, "app", "models", "order.py"), "class Order: pass")
+	writeTestFile(t, filepath.Join(tmpDir, "tests", "test_user.py"), "def test_user(): pass")
+	writeTestFile(t, filepath.Join(tmpDir, "tests", "app", "models", "test_order.py"), "def test_order(): pass")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath: tmpDir,
+		Language: "python",
+	})
+
+	if result.FilesWithTests != 2 || len(result.MissingTests) != 0 {
+		t.Errorf("expected both files to find tests under tests/, missing: %+v", result.MissingTests)
+	}
+}
+
+func TestDetectTestCoverageGaps_RustInlineAndIntegrationTests(t *testing.T) {
+	tmpDir := createTempGitRepo(t)
+
+	writeTestFile(t, filepath.Join(tmpDir, "src", "lib.rs"), "pub fn add() {}\n\n#[cfg(test)]\nmod tests {}\n")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "parser.rs"), "pub fn parse() {}\n")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "lexer.rs"), "pub fn lex() {}\n")
+	writeTestFile(t, filepath.Join(tmpDir, "tests", "parser.rs"), "#[test]\nfn parses() {}\n")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath: tmpDir,
+		Language: "rust",
+	})
+
+	if result.TotalSourceFiles != 3 || result.FilesWithTests != 2 {
+		t.Fatalf("files = %d with tests %d, want 3 and 2: %+v", result.TotalSourceFiles, result.FilesWithTests, result.MissingTests)
+	}
+	if got := result.MissingTests[0].SourceFile; got != filepath.Join("src", "lexer.rs") {
+		t.Errorf("missing test for %q, want src/lexer.rs", got)
+	}
+}
+
+func TestDetectTestCoverageGaps_CustomLanguageNamesAndLayouts(t *testing.T) {
+	tmpDir := createTempGitRepo(t)
+
+	writeTestFile(t, filepath.Join(tmpDir, "lib", "billing", "invoice.ex"), "defmodule Invoice do end")
+	writeTestFile(t, filepath.Join(tmpDir, "test", "billing", "invoice_test.exs"), "")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath: tmpDir,
+		Language: "elixir",
+		Languages: map[string]internal.LanguageConfig{
+			"elixir": {
+				Extensions:  []string{".ex"},
+				TestPattern: regexp.MustCompile(`_test\.exs$`),
+				TestNames:   []string{"{name}_test.exs"},
+				TestLayouts: []internal.TestLayout{{Source: "lib", Test: "test"}},
+			},
+		},
+	})
+
+	if result.FilesWithTests != 1 {
+		t.Errorf("expected lib/billing/invoice.ex to find test/billing/invoice_test.exs, missing: %+v", result.MissingTests)
+	}
+}
+
+func TestDetectTestCoverageGaps_Polyglot(t *testing.T) {
+	tmpDir := createTempGitRepo(t)
+
+	writeTestFile(t, filepath.Join(tmpDir, "server", "handler.go"), "package server")
+	writeTestFile(t, filepath.Join(tmpDir, "server", "handler_test.go"), "package server")
+	writeTestFile(t, filepath.Join(tmpDir, "server", "router.go"), "package server")
+	writeTestFile(t, filepath.Join(tmpDir, "web", "app.ts"), "export {}")
+	writeTestFile(t, filepath.Join(tmpDir, "web", "app.test.ts"), "export {}")
+	writeTestFile(t, filepath.Join(tmpDir, "scripts", "build.py"), "pass")
+
+	result := internal.DetectTestCoverageGaps(internal.TestCoverageGapOptions{
+		BasePath:  tmpDir,
+		Threshold: 50,
+	})
+
+	if result.Language != "go" {
+		t.Errorf("Language = %q, want the most common language go", result.Language)
+	}
+	if result.TotalSourceFiles != 4 || result.FilesWithTests != 2 {
+		t.Errorf("files = %d with tests %d, want 4 and 2", result.TotalSourceFiles, result.FilesWithTests)
+	}
+	if !result.Valid {
+		t.Errorf("50%% overall coverage should meet the threshold: %s", result.Message)
+	}
+
+	byLanguage := map[string]internal.LanguageCoverage{}
+	for _, lang := range result.Languages {
+		byLanguage[lang.Language] = lang
+	}
+	if len(byLanguage) != 3 || byLanguage["go"].CoveragePercent != 50 ||
+		byLanguage["typescript"].CoveragePercent != 100 || byLanguage["python"].CoveragePercent != 0 {
+		t.Errorf("Languages = %+v", result.Languages)
+	}
+	for _, m := range result.MissingTests {
+		if m.Language == "" {
+			t.Errorf("missing test %s has no language", m.SourceFile)
+		}
+	}
+}
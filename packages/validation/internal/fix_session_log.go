@@ -0,0 +1,642 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SessionLogFixOptions configures FixSessionLog.
+type SessionLogFixOptions struct {
+	// ProtocolPath is the SESSION-PROTOCOL.md whose session log template the
+	// log is repaired against. Empty searches upward from the log for
+	// .agents/SESSION-PROTOCOL.md.
+	ProtocolPath string
+	// Config supplies the required sections and branch placeholders. The
+	// zero value uses DefaultSessionProtocolConfig.
+	Config SessionProtocolConfig
+	// Branch and StartingCommit replace the session info placeholders.
+	// FixSessionLog reads empty values from git.
+	Branch         string
+	StartingCommit string
+}
+
+// Kinds of session log fix.
+const (
+	SessionLogFixSection     = "section"
+	SessionLogFixChecklist   = "checklist"
+	SessionLogFixPlaceholder = "placeholder"
+)
+
+// SessionLogFix describes one repair made to a session log.
+type SessionLogFix struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// SessionLogFixResult is a repaired session log. Nothing is written until
+// Apply is called.
+type SessionLogFixResult struct {
+	SessionLogPath string          `json:"sessionLogPath"`
+	ProtocolPath   string          `json:"protocolPath"`
+	Fixes          []SessionLogFix `json:"fixes"`
+	Diff           string          `json:"diff,omitempty"` // unified diff from the original to the fixed log
+	Original       string          `json:"-"`
+	Fixed          string          `json:"-"`
+}
+
+// Changed reports whether any fix was made.
+func (r SessionLogFixResult) Changed() bool {
+	return r.Fixed != r.Original
+}
+
+// Apply writes the fixed log over the original.
+func (r SessionLogFixResult) Apply() error {
+	if !r.Changed() {
+		return nil
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(r.SessionLogPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(r.SessionLogPath, []byte(r.Fixed), mode)
+}
+
+// sessionProtocolLocations are where SESSION-PROTOCOL.md is looked for,
+// relative to each directory above a session log.
+var sessionProtocolLocations = []string{
+	filepath.Join(".agents", "SESSION-PROTOCOL.md"),
+	filepath.Join("templates", "protocols", "SESSION-PROTOCOL.md"),
+	"SESSION-PROTOCOL.md",
+}
+
+// FixSessionLog repairs a session log against the protocol template:
+// missing required sections are inserted, drifted checklist tables are
+// realigned to the protocol rows keeping their status and evidence, and
+// branch and starting commit placeholders are filled from git. The log is
+// not modified; preview the result's Diff and call Apply to write it.
+func FixSessionLog(sessionLogPath string, opts SessionLogFixOptions) (SessionLogFixResult, error) {
+	result := SessionLogFixResult{SessionLogPath: sessionLogPath}
+
+	content, err := os.ReadFile(sessionLogPath)
+	if err != nil {
+		return result, err
+	}
+	result.Original = string(content)
+
+	protocolPath := opts.ProtocolPath
+	if protocolPath == "" {
+		protocolPath = findSessionProtocol(filepath.Dir(sessionLogPath))
+		if protocolPath == "" {
+			return result, fmt.Errorf("SESSION-PROTOCOL.md not found above %s", sessionLogPath)
+		}
+	}
+	protocol, err := os.ReadFile(protocolPath)
+	if err != nil {
+		return result, err
+	}
+	result.ProtocolPath = protocolPath
+
+	if opts.Branch == "" || opts.StartingCommit == "" {
+		branch, commit := sessionGitState(sessionLogPath)
+		if opts.Branch == "" {
+			opts.Branch = branch
+		}
+		if opts.StartingCommit == "" {
+			opts.StartingCommit = commit
+		}
+	}
+
+	fixed, fixes, err := FixSessionLogContent(result.Original, string(protocol), opts)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", protocolPath, err)
+	}
+	result.Fixed = fixed
+	result.Fixes = fixes
+	if result.Changed() {
+		name := strings.TrimPrefix(filepath.ToSlash(sessionLogPath), "/")
+		result.Diff = unifiedDiff("a/"+name, "b/"+name, result.Original, fixed)
+	}
+	return result, nil
+}
+
+// FixSessionLogContent repairs session log content against the session log
+// template in protocol, the content of SESSION-PROTOCOL.md. Placeholders are
+// filled only from opts.Branch and opts.StartingCommit.
+func FixSessionLogContent(content, protocol string, opts SessionLogFixOptions) (string, []SessionLogFix, error) {
+	config := opts.Config
+	if config.FilenamePattern == "" && len(config.RequiredSections) == 0 {
+		config = DefaultSessionProtocolConfig
+	}
+	template := extractSessionLogTemplate(protocol)
+	if template == "" {
+		return content, nil, fmt.Errorf("no session log template found")
+	}
+	tmpl := parseTemplateSections(template)
+
+	var fixes []SessionLogFix
+	content, fixes = insertMissingSections(content, tmpl, config.RequiredSections, fixes)
+	content, fixes = realignChecklists(content, tmpl, fixes)
+	content, fixes = fillSessionPlaceholders(content, opts.Branch, opts.StartingCommit, config.BranchPlaceholders, fixes)
+	return content, fixes, nil
+}
+
+// templateSection is a heading of the session log template and the lines
+// up to the next heading.
+type templateSection struct {
+	level int
+	name  string // heading text without any parenthetical, e.g. "Session End"
+	lines []string
+}
+
+// extractSessionLogTemplate returns the fenced markdown block following the
+// "Session Log Template" heading of SESSION-PROTOCOL.md.
+func extractSessionLogTemplate(protocol string) string {
+	lines := strings.Split(protocol, "\n")
+	start := -1
+	for i, line := range lines {
+		if h, ok := parseHeading(line); ok && strings.HasPrefix(h.name, "Session Log Template") {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	for i := start; i < len(lines); i++ {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "```" {
+				return strings.Join(lines[i+1:j], "\n") + "\n"
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// parseTemplateSections splits the template at its ## and ### headings.
+func parseTemplateSections(template string) []templateSection {
+	var sections []templateSection
+	for _, line := range strings.Split(strings.TrimRight(template, "\n"), "\n") {
+		if h, ok := parseHeading(line); ok && h.level >= 2 {
+			sections = append(sections, templateSection{level: h.level, name: h.name})
+		}
+		if len(sections) > 0 {
+			last := &sections[len(sections)-1]
+			last.lines = append(last.lines, line)
+		}
+	}
+	return sections
+}
+
+// heading is a parsed markdown heading line.
+type heading struct {
+	level int
+	name  string
+}
+
+var headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*$`)
+
+func parseHeading(line string) (heading, bool) {
+	m := headingPattern.FindStringSubmatch(line)
+	if m == nil {
+		return heading{}, false
+	}
+	name := m[2]
+	if i := strings.Index(name, " ("); i > 0 {
+		name = name[:i]
+	}
+	return heading{level: len(m[1]), name: strings.TrimSpace(name)}, true
+}
+
+// findHeading returns the index of the first line of lines that is a
+// heading named name, outside fenced code blocks.
+func findHeading(lines []string, name string) int {
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if h, ok := parseHeading(line); ok && !fenced && h.level >= 2 && h.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextHeading returns the index of the first heading after line from, or
+// len(lines).
+func nextHeading(lines []string, from int) int {
+	for i := from + 1; i < len(lines); i++ {
+		if _, ok := parseHeading(lines[i]); ok {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// insertMissingSections adds each required section missing from content,
+// with any template subsections content also lacks, before the next
+// template section content has.
+func insertMissingSections(content string, tmpl []templateSection, required []string, fixes []SessionLogFix) (string, []SessionLogFix) {
+	for _, name := range required {
+		if containsSection(content, name) {
+			continue
+		}
+		i := -1
+		for k, s := range tmpl {
+			if s.name == name {
+				i = k
+				break
+			}
+		}
+		if i < 0 {
+			continue
+		}
+
+		lines := strings.Split(content, "\n")
+		block := append([]string(nil), tmpl[i].lines...)
+		j := i + 1
+		for ; j < len(tmpl) && tmpl[j].level > tmpl[i].level && findHeading(lines, tmpl[j].name) < 0; j++ {
+			block = append(block, tmpl[j].lines...)
+		}
+
+		at := -1
+		for k := j; k < len(tmpl) && at < 0; k++ {
+			at = findHeading(lines, tmpl[k].name)
+		}
+		if at < 0 {
+			// Appended at the end: drop the trailing rule before the next
+			// template section.
+			for len(block) > 0 && (strings.TrimSpace(block[len(block)-1]) == "" || strings.TrimSpace(block[len(block)-1]) == "---") {
+				block = block[:len(block)-1]
+			}
+			body := strings.TrimRight(content, "\n")
+			content = body + "\n\n" + strings.Join(block, "\n") + "\n"
+		} else {
+			if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+				block = append([]string{""}, block...)
+			}
+			if strings.TrimSpace(block[len(block)-1]) != "" {
+				block = append(block, "")
+			}
+			lines = append(lines[:at], append(block, lines[at:]...)...)
+			content = strings.Join(lines, "\n")
+		}
+		fixes = append(fixes, SessionLogFix{
+			Kind:    SessionLogFixSection,
+			Message: fmt.Sprintf("Added missing section '%s' from the protocol template", name),
+		})
+	}
+	return content, fixes
+}
+
+// realignChecklists rebuilds every checklist table that has drifted from
+// the protocol rows. Rows are matched by step; matched rows keep their
+// status and evidence.
+func realignChecklists(content string, tmpl []templateSection, fixes []SessionLogFix) (string, []SessionLogFix) {
+	for _, s := range tmpl {
+		protoTable := tableLines(s.lines, 1)
+		protoRows := checklistRows(protoTable)
+		if len(protoRows) == 0 {
+			continue
+		}
+
+		lines := strings.Split(content, "\n")
+		at := findHeading(lines, s.name)
+		if at < 0 {
+			continue
+		}
+		end := nextHeading(lines, at)
+		start := -1
+		for i := at + 1; i < end; i++ {
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				start = i
+				break
+			}
+		}
+
+		var sessionRows []ChecklistRow
+		stop := start
+		if start >= 0 {
+			for stop < end && strings.HasPrefix(strings.TrimSpace(lines[stop]), "|") {
+				stop++
+			}
+			sessionRows = checklistRows(lines[start:stop])
+		}
+		if start >= 0 && !DetectTemplateDrift(sessionRows, protoRows).HasDrift {
+			continue
+		}
+
+		rows, kept := alignChecklistRows(protoRows, sessionRows)
+		table := renderChecklistTable(splitTableRow(protoTable[0]), rows)
+		if start < 0 {
+			// No table yet: add one below the heading.
+			table = append([]string{""}, table...)
+			start, stop = at+1, at+1
+		}
+		lines = append(lines[:start], append(table, lines[stop:]...)...)
+		content = strings.Join(lines, "\n")
+
+		msg := fmt.Sprintf("Realigned the '%s' checklist to the %d protocol rows", s.name, len(protoRows))
+		if kept > 0 {
+			msg += fmt.Sprintf(", keeping the status and evidence of %d", kept)
+		}
+		if dropped := len(sessionRows) - kept; dropped > 0 {
+			msg += fmt.Sprintf(" and dropping %d rows not in the protocol", dropped)
+		}
+		fixes = append(fixes, SessionLogFix{Kind: SessionLogFixChecklist, Message: msg})
+	}
+	return content, fixes
+}
+
+// tableLines returns the first run of table lines in lines, skipping the
+// first skip lines.
+func tableLines(lines []string, skip int) []string {
+	start := -1
+	for i := skip; i < len(lines); i++ {
+		isRow := strings.HasPrefix(strings.TrimSpace(lines[i]), "|")
+		if isRow && start < 0 {
+			start = i
+		}
+		if !isRow && start >= 0 {
+			return lines[start:i]
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	return lines[start:]
+}
+
+// checklistRows parses a checklist table, skipping its header row however it
+// is padded.
+func checklistRows(table []string) []ChecklistRow {
+	var rows []ChecklistRow
+	for _, row := range ParseChecklistTable(table) {
+		if row.Requirement != "REQ" || !strings.EqualFold(row.Step, "Step") {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// alignChecklistRows returns the protocol rows with the status and evidence
+// of the session row for the same step, and how many session rows matched.
+func alignChecklistRows(protoRows, sessionRows []ChecklistRow) ([][]string, int) {
+	used := make([]bool, len(sessionRows))
+	kept := 0
+	rows := make([][]string, 0, len(protoRows))
+	for _, p := range protoRows {
+		row := []string{p.Requirement, p.Step, p.Status, p.Evidence}
+		key := strings.ToLower(NormalizeStep(p.Step))
+		for i, s := range sessionRows {
+			if !used[i] && strings.ToLower(NormalizeStep(s.Step)) == key {
+				used[i] = true
+				kept++
+				row[2], row[3] = s.Status, s.Evidence
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, kept
+}
+
+func splitTableRow(line string) []string {
+	cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// renderChecklistTable renders a markdown table with padded columns.
+func renderChecklistTable(header []string, rows [][]string) []string {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i := range widths {
+			if i < len(row) && utf8.RuneCountInString(row[i]) > widths[i] {
+				widths[i] = utf8.RuneCountInString(row[i])
+			}
+		}
+	}
+	render := func(cells []string, pad string) string {
+		var b strings.Builder
+		b.WriteString("|")
+		for i, w := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			b.WriteString(" " + cell + strings.Repeat(pad, w-utf8.RuneCountInString(cell)) + " |")
+		}
+		return b.String()
+	}
+
+	separator := make([]string, len(widths))
+	for i, w := range widths {
+		separator[i] = strings.Repeat("-", w)
+	}
+	out := []string{render(header, " "), render(separator, "-")}
+	for _, row := range rows {
+		out = append(out, render(row, " "))
+	}
+	return out
+}
+
+var startingCommitPlaceholder = regexp.MustCompile(`(?m)^(\s*-\s*\*\*Starting Commit\*\*\s*:\s*)\[SHA\][ \t]*$`)
+
+// fillSessionPlaceholders replaces branch and starting commit placeholders.
+func fillSessionPlaceholders(content, branch, commit string, branchPlaceholders []string, fixes []SessionLogFix) (string, []SessionLogFix) {
+	if branch != "" {
+		placeholders := append([]string{"[output of `git branch --show-current`]"}, branchPlaceholders...)
+		filled := false
+		for _, p := range placeholders {
+			filled = filled || strings.Contains(content, p)
+			content = strings.ReplaceAll(content, p, branch)
+		}
+		if filled {
+			fixes = append(fixes, SessionLogFix{
+				Kind:    SessionLogFixPlaceholder,
+				Message: fmt.Sprintf("Filled the branch placeholders with '%s'", branch),
+			})
+		}
+	}
+	if commit != "" {
+		if startingCommitPlaceholder.MatchString(content) {
+			content = startingCommitPlaceholder.ReplaceAllString(content, "${1}`"+commit+"`")
+			fixes = append(fixes, SessionLogFix{
+				Kind:    SessionLogFixPlaceholder,
+				Message: fmt.Sprintf("Filled the starting commit placeholders with %s", commit),
+			})
+		}
+	}
+	return content, fixes
+}
+
+// findSessionProtocol walks up from dir to the nearest SESSION-PROTOCOL.md.
+func findSessionProtocol(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		for _, loc := range sessionProtocolLocations {
+			if path := filepath.Join(abs, loc); FileExists(path) {
+				return path
+			}
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return ""
+		}
+		abs = parent
+	}
+}
+
+// sessionGitState returns the current branch and the session's starting
+// commit: the parent of the commit that added the log, or HEAD when the log
+// is not committed yet. Values git cannot provide are empty.
+func sessionGitState(sessionLogPath string) (branch, commit string) {
+	dir := filepath.Dir(sessionLogPath)
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	branch = git("branch", "--show-current")
+	added := strings.Fields(git("log", "--diff-filter=A", "--format=%H", "--", filepath.Base(sessionLogPath)))
+	if len(added) > 0 {
+		commit = git("rev-parse", "--short", added[len(added)-1]+"^")
+	}
+	if commit == "" && len(added) == 0 {
+		commit = git("rev-parse", "--short", "HEAD")
+	}
+	return branch, commit
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	a, b int  // line indexes in the old and new text
+	line string
+}
+
+// unifiedDiff returns a unified diff of two texts, or "" if they are equal.
+func unifiedDiff(fromName, toName, from, to string) string {
+	ops := diffLines(splitDiffLines(from), splitDiffLines(to))
+
+	var b strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are within two contexts of each other.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			} else if j-end > 2*diffContext {
+				break
+			}
+		}
+		stop := end + 1 + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:stop] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		oldStart, newStart := ops[start].a+1, ops[start].b+1
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, op := range ops[start:stop] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			b.WriteByte('\n')
+		}
+		i = stop
+	}
+	return b.String()
+}
+
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edit script from a to b along a longest common
+// subsequence, deletions before insertions.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', a: i, b: j, line: a[i]})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{kind: '+', a: i, b: j, line: b[j]})
+			j++
+		default:
+			ops = append(ops, diffOp{kind: '-', a: i, b: j, line: a[i]})
+			i++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,176 @@
+package internal_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+// sessionProtocolPath is the protocol shipped in templates/protocols.
+func sessionProtocolPath(t *testing.T) string {
+	t.Helper()
+	_, currentFile, _, _ := runtime.Caller(0)
+	repoRoot := filepath.Join(filepath.Dir(currentFile), "..", "..", "..")
+	return filepath.Join(repoRoot, "templates", "protocols", "SESSION-PROTOCOL.md")
+}
+
+func readProtocol(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(sessionProtocolPath(t))
+	if err != nil {
+		t.Fatalf("read protocol: %v", err)
+	}
+	return string(data)
+}
+
+// driftedSessionLog lacks Session End, has a reordered Session Start
+// checklist missing most rows, and keeps the template placeholders.
+const driftedSessionLog = `# Session 01 - 2026-01-20
+
+## Session Info
+
+- **Date**: 2026-01-20
+- **Branch**: [branch name]
+- **Starting Commit**: [SHA]
+- **Objective**: Fix the thing
+
+## Protocol Compliance
+
+### Session Start (COMPLETE ALL before work)
+
+| Req | Step | Status | Evidence |
+|-----|------|--------|----------|
+| MUST | Read handoff context | [x] | Read HANDOFF.md |
+| MUST | Initialize Brain memory tools | [x] | Brain MCP initialized |
+| MAY | Make coffee | [x] | Espresso |
+
+### Branch Verification
+
+**Current Branch**: [output of ` + "`git branch --show-current`" + `]
+
+## Work Log
+
+Did things.
+`
+
+func TestFixSessionLogContent(t *testing.T) {
+	fixed, fixes, err := internal.FixSessionLogContent(driftedSessionLog, readProtocol(t), internal.SessionLogFixOptions{
+		Branch:         "feat/fix",
+		StartingCommit: "abc1234",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := map[string]int{}
+	for _, f := range fixes {
+		kinds[f.Kind]++
+	}
+	if kinds[internal.SessionLogFixSection] != 1 || kinds[internal.SessionLogFixChecklist] != 1 || kinds[internal.SessionLogFixPlaceholder] != 2 {
+		t.Errorf("fixes = %+v", fixes)
+	}
+
+	if !strings.Contains(fixed, "## Session End (COMPLETE ALL before closing)") {
+		t.Error("Session End was not inserted")
+	}
+	if strings.Index(fixed, "## Session End") < strings.Index(fixed, "## Work Log") {
+		t.Error("Session End should follow the work log")
+	}
+	for _, want := range []string{"- **Branch**: feat/fix", "- **Starting Commit**: `abc1234`", "**Current Branch**: feat/fix"} {
+		if !strings.Contains(fixed, want) {
+			t.Errorf("fixed log lacks %q", want)
+		}
+	}
+	if strings.Contains(fixed, "Make coffee") {
+		t.Error("rows not in the protocol should be dropped")
+	}
+
+	start := strings.Split(internal.ExtractSection(fixed, "Session Start"), "\n")
+	rows := internal.ParseChecklistTable(start)[1:] // the padded header is parsed as a row
+	if len(rows) != 12 {
+		t.Fatalf("Session Start has %d rows, want 12", len(rows))
+	}
+	if rows[0].Step != "Initialize Brain memory tools" || rows[0].Status != "[x]" || rows[0].Evidence != "Brain MCP initialized" {
+		t.Errorf("first row lost its evidence: %+v", rows[0])
+	}
+	if rows[2].Evidence != "Read HANDOFF.md" || rows[3].Status != "[ ]" {
+		t.Errorf("rows = %+v", rows[2:4])
+	}
+
+	// A fixed log needs no further fixes.
+	again, fixes, err := internal.FixSessionLogContent(fixed, readProtocol(t), internal.SessionLogFixOptions{Branch: "feat/fix"})
+	if err != nil || again != fixed || len(fixes) != 0 {
+		t.Errorf("second pass changed the log: %+v", fixes)
+	}
+}
+
+func TestFixSessionLogContent_MissingParentSection(t *testing.T) {
+	log := "# Session\n\n## Session Info\n\n- **Branch**: main\n\n### Session Start\n\n" +
+		"| Req | Step | Status | Evidence |\n|---|---|---|---|\n"
+	fixed, _, err := internal.FixSessionLogContent(log, readProtocol(t), internal.SessionLogFixOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compliance := strings.Index(fixed, "## Protocol Compliance")
+	if compliance < 0 || compliance > strings.Index(fixed, "### Session Start") {
+		t.Errorf("Protocol Compliance should be inserted before Session Start:\n%s", fixed)
+	}
+	if strings.Count(fixed, "### Session Start") != 1 {
+		t.Error("Session Start was duplicated")
+	}
+}
+
+func TestFixSessionLogContent_NoTemplate(t *testing.T) {
+	if _, _, err := internal.FixSessionLogContent(driftedSessionLog, "# Protocol\n", internal.SessionLogFixOptions{}); err == nil {
+		t.Error("expected an error for a protocol without a session log template")
+	}
+}
+
+func TestFixSessionLog_GitAndApply(t *testing.T) {
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	for _, args := range [][]string{
+		{"checkout", "-q", "-b", "feat/session-fix"},
+		{"commit", "-q", "--allow-empty", "-m", "start"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	head, _ := exec.Command("git", "-C", dir, "rev-parse", "--short", "HEAD").Output()
+
+	protocol := filepath.Join(dir, ".agents", "SESSION-PROTOCOL.md")
+	writeTestFile(t, protocol, readProtocol(t))
+	logPath := filepath.Join(dir, ".agents", "sessions", "SESSION-2026-01-20_01-fix.md")
+	writeTestFile(t, logPath, driftedSessionLog)
+
+	result, err := internal.FixSessionLog(logPath, internal.SessionLogFixOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ProtocolPath != protocol {
+		t.Errorf("ProtocolPath = %q, want %q", result.ProtocolPath, protocol)
+	}
+	if !strings.Contains(result.Diff, "+- **Branch**: feat/session-fix") ||
+		!strings.Contains(result.Diff, "+- **Starting Commit**: `"+strings.TrimSpace(string(head))+"`") ||
+		!strings.Contains(result.Diff, "-- **Branch**: [branch name]") {
+		t.Errorf("diff:\n%s", result.Diff)
+	}
+
+	data, _ := os.ReadFile(logPath)
+	if string(data) != driftedSessionLog {
+		t.Fatal("FixSessionLog wrote the log before Apply")
+	}
+	if err := result.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(logPath)
+	if string(data) != result.Fixed {
+		t.Error("Apply did not write the fixed log")
+	}
+}
@@ -0,0 +1,730 @@
+package internal
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParameterDrift is a use of an exported Go or TypeScript function that
+// still has the function's old shape after its parameters were added,
+// removed or renamed.
+type ParameterDrift struct {
+	Function   string   `json:"function"`
+	Definition string   `json:"definition"` // file:line of the new signature
+	OldParams  []string `json:"oldParams"`
+	NewParams  []string `json:"newParams"`
+	Kind       string   `json:"kind"` // caller, test, doc or schema
+	File       string   `json:"file"`
+	Line       int      `json:"line"`
+	Message    string   `json:"message"`
+}
+
+// issue is the drift as a check message.
+func (p ParameterDrift) issue() string {
+	return p.File + ":" + Itoa(p.Line) + " - " + p.Message
+}
+
+// funcSignature is an exported function and its parameters.
+type funcSignature struct {
+	Name    string
+	Lang    string // "go" or "typescript"
+	Pkg     string // Go package name
+	File    string // slash path relative to the repository
+	Line    int
+	Params  []string
+	MinArgs int
+	MaxArgs int // -1 when variadic
+}
+
+func (s funcSignature) accepts(args int) bool {
+	return args >= s.MinArgs && (s.MaxArgs < 0 || args <= s.MaxArgs)
+}
+
+func (s funcSignature) String() string {
+	return s.Name + "(" + strings.Join(s.Params, ", ") + ")"
+}
+
+// signatureChange is an exported function whose parameters changed.
+type signatureChange struct {
+	old, new funcSignature
+	call     *regexp.Regexp // a call by name in text
+}
+
+// removed returns the old parameter names the function no longer has.
+func (c signatureChange) removed() []string {
+	return paramNamesMissing(c.old.Params, c.new.Params)
+}
+
+// added returns the parameter names the function did not have before.
+func (c signatureChange) added() []string {
+	return paramNamesMissing(c.new.Params, c.old.Params)
+}
+
+func (c signatureChange) drift(kind, file string, line int, message string) ParameterDrift {
+	definition := c.new.File + ":" + Itoa(c.new.Line)
+	return ParameterDrift{
+		Function:   c.new.Name,
+		Definition: definition,
+		OldParams:  c.old.Params,
+		NewParams:  c.new.Params,
+		Kind:       kind,
+		File:       file,
+		Line:       line,
+		Message:    message + "; " + c.old.String() + " is now " + c.new.String() + " at " + definition,
+	}
+}
+
+// paramNamesMissing returns the names in params that are not in other,
+// ignoring blank and destructured parameters.
+func paramNamesMissing(params, other []string) []string {
+	has := make(map[string]bool, len(other))
+	for _, p := range other {
+		has[strings.TrimPrefix(p, "...")] = true
+	}
+	var missing []string
+	for _, p := range params {
+		name := strings.TrimPrefix(p, "...")
+		if name != "_" && !strings.HasPrefix(name, "{") && !strings.HasPrefix(name, "[") && !has[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// DetectParameterDrift finds uses of exported Go and TypeScript functions
+// whose parameters changed since base (HEAD when empty) but that still have
+// the old shape: Go, TypeScript and JavaScript callers passing the old number
+// of arguments, calls in markdown docs with the old arguments or parameter
+// names, and JSON schemas under a schemas/ directory whose properties
+// describe the old parameters. It finds nothing outside a git repository.
+func DetectParameterDrift(basePath, base string) []ParameterDrift {
+	changes := signatureChanges(basePath, base)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var drift []ParameterDrift
+	walkDriftFiles(basePath, func(rel string) bool { return driftFileType(rel) != "" }, func(rel string, data []byte) {
+		kind := "caller"
+		if isTestFile(rel) {
+			kind = "test"
+		}
+		switch driftFileType(rel) {
+		case "go":
+			drift = append(drift, goCallerDrift(rel, data, kind, changes)...)
+		case "script":
+			drift = append(drift, tsCallerDrift(rel, string(data), kind, changes)...)
+		case "doc":
+			drift = append(drift, docDrift(rel, string(data), changes)...)
+		case "schema":
+			drift = append(drift, schemaDrift(rel, data, changes)...)
+		}
+	})
+
+	sort.SliceStable(drift, func(i, j int) bool {
+		if drift[i].File != drift[j].File {
+			return drift[i].File < drift[j].File
+		}
+		return drift[i].Line < drift[j].Line
+	})
+	return drift
+}
+
+// scriptCallerExts are the files searched for TypeScript callers.
+var scriptCallerExts = map[string]bool{
+	".ts": true, ".tsx": true, ".mts": true, ".cts": true,
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+}
+
+// driftFileType returns how a file is searched for uses of changed
+// functions: "go", "script", "doc" or "schema", or "" when it is not.
+func driftFileType(rel string) string {
+	switch ext := strings.ToLower(path.Ext(rel)); {
+	case ext == ".go":
+		return "go"
+	case scriptCallerExts[ext] && !strings.HasSuffix(rel, ".d.ts"):
+		return "script"
+	case ext == ".md":
+		return "doc"
+	case ext == ".json" && (strings.HasPrefix(rel, "schemas/") || strings.Contains(rel, "/schemas/")):
+		return "schema"
+	}
+	return ""
+}
+
+// driftSkipDirs are not searched for uses of changed functions.
+var driftSkipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true, "testdata": true,
+}
+
+// maxDriftFileSize is the largest file searched for uses of changed
+// functions; bigger files are generated or bundled.
+const maxDriftFileSize = 1 << 20
+
+// walkDriftFiles calls fn with the slash path and content of every file
+// under root for which match is true, skipping hidden and generated directories
+// and files over maxDriftFileSize. Other files are never read.
+func walkDriftFiles(root string, match func(rel string) bool, fn func(rel string, data []byte)) {
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || driftSkipDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if !d.Type().IsRegular() || !match(rel) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxDriftFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		fn(rel, data)
+		return nil
+	})
+}
+
+// signatureChanges compares the exported signatures of the Go and
+// TypeScript files changed since the merge base of base and HEAD, or since
+// HEAD, with the working tree.
+func signatureChanges(basePath, base string) []signatureChange {
+	rev := "HEAD"
+	if base != "" {
+		mergeBase, err := GitMergeBase(basePath, base)
+		if err != nil {
+			return nil
+		}
+		rev = mergeBase
+	}
+	output, err := exec.Command("git", "-C", basePath, "diff", "--name-only", "--relative", "--no-renames", rev).Output()
+	if err != nil {
+		return nil
+	}
+
+	var changes []signatureChange
+	for _, rel := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		lang := signatureLanguage(rel)
+		if lang == "" {
+			continue
+		}
+		newSrc, err := os.ReadFile(filepath.Join(basePath, filepath.FromSlash(rel)))
+		if err != nil {
+			continue // deleted
+		}
+		oldSrc, err := exec.Command("git", "-C", basePath, "show", rev+":./"+rel).Output()
+		if err != nil {
+			continue // added
+		}
+
+		oldSigs := parseSignatures(lang, rel, oldSrc)
+		newSigs := parseSignatures(lang, rel, newSrc)
+		names := make([]string, 0, len(newSigs))
+		for name := range newSigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			old, ok := oldSigs[name]
+			if !ok || strings.Join(old.Params, ",") == strings.Join(newSigs[name].Params, ",") {
+				continue
+			}
+			changes = append(changes, signatureChange{
+				old:  old,
+				new:  newSigs[name],
+				call: regexp.MustCompile(`(?:^|[^\w$])` + regexp.QuoteMeta(name) + `\s*\(`),
+			})
+		}
+	}
+	return changes
+}
+
+// signatureLanguage returns the language whose exported signatures are
+// compared for a file, or "" for files that are not compared.
+func signatureLanguage(rel string) string {
+	if isTestFile(rel) {
+		return ""
+	}
+	switch strings.ToLower(path.Ext(rel)) {
+	case ".go":
+		return "go"
+	case ".ts", ".tsx", ".mts", ".cts":
+		if !strings.HasSuffix(rel, ".d.ts") {
+			return "typescript"
+		}
+	}
+	return ""
+}
+
+func parseSignatures(lang, file string, src []byte) map[string]funcSignature {
+	if lang == "go" {
+		return goSignatures(file, src)
+	}
+	return tsSignatures(file, string(src))
+}
+
+// goSignatures returns the exported package-level functions of a Go file.
+func goSignatures(file string, src []byte) map[string]funcSignature {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	sigs := make(map[string]funcSignature)
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+			continue
+		}
+		sig := funcSignature{
+			Name: fn.Name.Name,
+			Lang: "go",
+			Pkg:  f.Name.Name,
+			File: file,
+			Line: fset.Position(fn.Pos()).Line,
+		}
+		variadic := false
+		for _, field := range fn.Type.Params.List {
+			_, variadic = field.Type.(*ast.Ellipsis)
+			names := []string{"_"}
+			if len(field.Names) > 0 {
+				names = names[:0]
+				for _, name := range field.Names {
+					names = append(names, name.Name)
+				}
+			}
+			for _, name := range names {
+				if variadic {
+					name = "..." + name
+				}
+				sig.Params = append(sig.Params, name)
+			}
+		}
+		sig.MinArgs, sig.MaxArgs = len(sig.Params), len(sig.Params)
+		if variadic {
+			sig.MinArgs, sig.MaxArgs = len(sig.Params)-1, -1
+		}
+		sigs[sig.Name] = sig
+	}
+	return sigs
+}
+
+var (
+	tsFunctionPattern  = regexp.MustCompile(`(?m)^[ \t]*export\s+(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)\s*(?:<[^(]*?>)?\s*\(`)
+	tsArrowPattern     = regexp.MustCompile(`(?m)^[ \t]*export\s+const\s+([A-Za-z_$][\w$]*)\s*(?::[^=\n]+)?=\s*(?:async\s*)?(?:<[^(]*?>)?\s*\(`)
+	tsParamNamePattern = regexp.MustCompile(`^(?:(?:public|private|protected|readonly|override)\s+)*(\.\.\.)?\s*([A-Za-z_$][\w$]*|\{|\[)(\?)?`)
+	identifierPattern  = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+)
+
+// tsSignatures returns the exported functions and arrow functions of a
+// TypeScript file.
+func tsSignatures(file, content string) map[string]funcSignature {
+	sigs := make(map[string]funcSignature)
+	for _, pattern := range []*regexp.Regexp{tsFunctionPattern, tsArrowPattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			open := m[1] - 1
+			end := closingParen(content, open, true)
+			if end < 0 {
+				continue
+			}
+			if pattern == tsArrowPattern {
+				// The parenthesis must start an arrow function, not an expression.
+				rest := strings.TrimLeft(content[end+1:], " \t\r\n")
+				if !strings.HasPrefix(rest, "=>") && !strings.HasPrefix(rest, ":") {
+					continue
+				}
+			}
+
+			sig := funcSignature{
+				Name: content[m[2]:m[3]],
+				Lang: "typescript",
+				File: file,
+				Line: lineAt(content, m[2]),
+			}
+			required, variadic := 0, false
+			for _, param := range splitArgs(content[open+1:end], true) {
+				match := tsParamNamePattern.FindStringSubmatch(param)
+				if match == nil || match[2] == "this" {
+					continue
+				}
+				name := match[2]
+				switch name {
+				case "{":
+					name = "{...}"
+				case "[":
+					name = "[...]"
+				}
+				switch {
+				case match[1] != "":
+					variadic = true
+					name = "..." + name
+				case match[3] == "" && !hasDefault(param):
+					required = len(sig.Params) + 1 // optional parameters come last
+				}
+				sig.Params = append(sig.Params, name)
+			}
+			sig.MinArgs, sig.MaxArgs = required, len(sig.Params)
+			if variadic {
+				sig.MaxArgs = -1
+			}
+			sigs[sig.Name] = sig
+		}
+	}
+	return sigs
+}
+
+// hasDefault reports whether a TypeScript parameter has a default value.
+func hasDefault(param string) bool {
+	for _, part := range splitOutside(param, '=', true)[1:] {
+		if !strings.HasPrefix(part, ">") {
+			return true // not the => of a function type
+		}
+	}
+	return false
+}
+
+// goCallerDrift returns the calls in a Go file that pass the old number of
+// arguments to a changed Go function.
+func goCallerDrift(rel string, src []byte, kind string, changes []signatureChange) []ParameterDrift {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, rel, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	imports := make(map[string]string) // local name -> import path
+	for _, imp := range f.Imports {
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = importPath
+	}
+
+	var drift []ParameterDrift
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() {
+			return true
+		}
+		for _, c := range changes {
+			if c.new.Lang != "go" || !goCalls(call.Fun, c.new, f.Name.Name, path.Dir(rel), imports) {
+				continue
+			}
+			if args := len(call.Args); c.old.accepts(args) && !c.new.accepts(args) {
+				drift = append(drift, c.drift(kind, rel, fset.Position(call.Pos()).Line,
+					"call to "+c.new.Name+" passes "+countArguments(args)))
+			}
+		}
+		return true
+	})
+	return drift
+}
+
+// goCalls reports whether fun, in package pkg in dir, names the function sig.
+func goCalls(fun ast.Expr, sig funcSignature, pkg, dir string, imports map[string]string) bool {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name == sig.Name && pkg == sig.Pkg && dir == path.Dir(sig.File)
+	case *ast.SelectorExpr:
+		x, ok := fun.X.(*ast.Ident)
+		if !ok || fun.Sel.Name != sig.Name {
+			return false
+		}
+		importPath, ok := imports[x.Name]
+		if !ok {
+			return false
+		}
+		sigDir := path.Dir(sig.File)
+		if sigDir == "." {
+			return x.Name == sig.Pkg
+		}
+		return importPath == sigDir || strings.HasSuffix(importPath, "/"+sigDir)
+	}
+	return false
+}
+
+// tsCallerDrift returns the calls in a TypeScript or JavaScript file that
+// pass the old number of arguments to a changed TypeScript function.
+func tsCallerDrift(rel, content, kind string, changes []signatureChange) []ParameterDrift {
+	var drift []ParameterDrift
+	for _, c := range changes {
+		if c.new.Lang != "typescript" {
+			continue
+		}
+		for _, loc := range c.call.FindAllStringIndex(content, -1) {
+			open := loc[1] - 1
+			nameStart := loc[0] + strings.Index(content[loc[0]:loc[1]], c.new.Name)
+			line := lineAt(content, nameStart)
+			if rel == c.new.File && line == c.new.Line {
+				continue // the definition
+			}
+			if strings.HasSuffix(strings.TrimRight(content[:nameStart], " \t*"), "function") {
+				continue // another declaration of the name
+			}
+			end := closingParen(content, open, false)
+			if end < 0 || strings.HasPrefix(strings.TrimLeft(content[end+1:], " \t"), "{") {
+				continue // a method declaration
+			}
+			if args := len(splitArgs(content[open+1:end], false)); c.old.accepts(args) && !c.new.accepts(args) {
+				drift = append(drift, c.drift(kind, rel, line, "call to "+c.new.Name+" passes "+countArguments(args)))
+			}
+		}
+	}
+	return drift
+}
+
+// docDrift returns the calls in a markdown file that pass the old number
+// of arguments to a changed function or name a removed parameter.
+func docDrift(rel, content string, changes []signatureChange) []ParameterDrift {
+	var drift []ParameterDrift
+	for _, c := range changes {
+		removed := c.removed()
+		for _, loc := range c.call.FindAllStringIndex(content, -1) {
+			open := loc[1] - 1
+			end := closingParen(content, open, false)
+			if end < 0 {
+				continue
+			}
+			args := splitArgs(content[open+1:end], false)
+			if len(args) == 0 {
+				continue // a mention of the function, not a call
+			}
+			stale := c.old.accepts(len(args)) && !c.new.accepts(len(args))
+			for _, arg := range args {
+				stale = stale || (identifierPattern.MatchString(arg) && slices.Contains(removed, arg))
+			}
+			if stale {
+				drift = append(drift, c.drift("doc", rel, lineAt(content, open),
+					c.new.Name+"("+strings.Join(args, ", ")+") uses the old parameters"))
+			}
+		}
+	}
+	return drift
+}
+
+// schemaParamSuffixes are stripped from schema names before matching them
+// to function names, so FooParams describes the parameters of Foo.
+var schemaParamSuffixes = []string{"parameters", "params", "arguments", "args", "input", "options", "request"}
+
+// schemaDrift returns the objects in a JSON schema that describe the
+// parameters of a changed function and still have the old ones: a property
+// for a removed parameter, or all the old parameters but not an added one.
+func schemaDrift(rel string, data []byte, changes []signatureChange) []ParameterDrift {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	content := string(data)
+
+	var drift []ParameterDrift
+	var visit func(name string, v any)
+	visit = func(name string, v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				visit("", item)
+			}
+		case map[string]any:
+			if title, ok := v["title"].(string); ok {
+				name = title
+			}
+			if props, ok := v["properties"].(map[string]any); ok && name != "" {
+				for _, c := range changes {
+					if schemaDescribes(name, c.new.Name) {
+						drift = append(drift, schemaObjectDrift(rel, content, name, props, c)...)
+					}
+				}
+			}
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				children, named := v[key].(map[string]any)
+				if named && (key == "definitions" || key == "$defs" || key == "properties") {
+					childKeys := make([]string, 0, len(children))
+					for childKey := range children {
+						childKeys = append(childKeys, childKey)
+					}
+					sort.Strings(childKeys)
+					for _, childKey := range childKeys {
+						visit(childKey, children[childKey])
+					}
+					continue
+				}
+				visit("", v[key])
+			}
+		}
+	}
+	visit("", doc)
+	return drift
+}
+
+func schemaObjectDrift(rel, content, name string, props map[string]any, c signatureChange) []ParameterDrift {
+	var drift []ParameterDrift
+	for _, removed := range c.removed() {
+		if _, ok := props[removed]; ok {
+			drift = append(drift, c.drift("schema", rel, jsonKeyLine(content, removed),
+				"schema "+name+" has property "+removed+", which "+c.new.Name+" no longer takes"))
+		}
+	}
+
+	// An added parameter is only missing from a schema with the old shape.
+	for _, old := range paramNamesMissing(c.old.Params, nil) {
+		if _, ok := props[old]; !ok {
+			return drift // not the old shape
+		}
+	}
+	for _, added := range c.added() {
+		if _, ok := props[added]; !ok {
+			drift = append(drift, c.drift("schema", rel, jsonKeyLine(content, name),
+				"schema "+name+" lacks property "+added+", which "+c.new.Name+" now takes"))
+		}
+	}
+	return drift
+}
+
+// schemaDescribes reports whether a schema named name describes the
+// parameters of function fn.
+func schemaDescribes(name, fn string) bool {
+	normalize := func(s string) string {
+		var b strings.Builder
+		for _, r := range strings.ToLower(s) {
+			if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}
+	name = normalize(name)
+	for _, suffix := range schemaParamSuffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			name = trimmed
+			break
+		}
+	}
+	return name == normalize(fn)
+}
+
+// jsonKeyLine returns the line of the first "key" in JSON content, or 1.
+func jsonKeyLine(content, key string) int {
+	if i := strings.Index(content, strconv.Quote(key)); i >= 0 {
+		return lineAt(content, i)
+	}
+	return 1
+}
+
+func countArguments(n int) string {
+	if n == 1 {
+		return "1 argument"
+	}
+	return Itoa(n) + " arguments"
+}
+
+// lineAt returns the 1-based line of offset i in content.
+func lineAt(content string, i int) int {
+	return strings.Count(content[:i], "\n") + 1
+}
+
+// maxParenSpan bounds how far closingParen looks for the closing parenthesis.
+const maxParenSpan = 4096
+
+// closingParen returns the index of the parenthesis closing the one at
+// open, or -1. Brackets inside string literals are ignored; with angle set,
+// <> count as brackets, as in TypeScript types.
+func closingParen(s string, open int, angle bool) int {
+	depth := 0
+	for i := open; i < len(s) && i < open+maxParenSpan; i++ {
+		switch c := s[i]; c {
+		case '"', '\'', '`':
+			i = skipString(s, i)
+		case '(', '[', '{':
+			depth++
+		case '<':
+			if angle {
+				depth++
+			}
+		case '>':
+			if angle && (i == 0 || s[i-1] != '=') {
+				depth--
+			}
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				if c == ')' {
+					return i
+				}
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// splitArgs splits an argument or parameter list at its top-level commas.
+func splitArgs(s string, angle bool) []string {
+	var args []string
+	for _, arg := range splitOutside(s, ',', angle) {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// splitOutside splits s at each sep outside brackets and string literals.
+func splitOutside(s string, sep byte, angle bool) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			i = skipString(s, i)
+		case c == '(' || c == '[' || c == '{' || (angle && c == '<'):
+			depth++
+		case c == ')' || c == ']' || c == '}' || (angle && c == '>' && (i == 0 || s[i-1] != '=')):
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// skipString returns the index of the quote closing the string literal
+// that starts at i, or the end of s.
+func skipString(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			return j
+		}
+	}
+	return len(s) - 1
+}
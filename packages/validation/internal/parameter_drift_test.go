@@ -0,0 +1,168 @@
+package internal_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+// driftRepo commits files to a new repository on branch main and then
+// writes changes over them without committing.
+func driftRepo(t *testing.T, committed, changed map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+	for name, content := range committed {
+		writeTestFile(t, filepath.Join(tmpDir, name), content)
+	}
+	for _, args := range [][]string{{"checkout", "-qb", "main"}, {"add", "-A"}, {"commit", "-qm", "base"}} {
+		if out, err := exec.Command("git", append([]string{"-C", tmpDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	for name, content := range changed {
+		writeTestFile(t, filepath.Join(tmpDir, name), content)
+	}
+	return tmpDir
+}
+
+func driftSummary(drift []internal.ParameterDrift) string {
+	var lines []string
+	for _, d := range drift {
+		lines = append(lines, d.Kind+" "+d.File+":"+internal.Itoa(d.Line)+" "+d.Function)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDetectParameterDrift_Go(t *testing.T) {
+	committed := map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.21\n",
+		"lib/lib.go":      "package lib\n\nfunc Fetch(url string) error { return nil }\n\nfunc Keep(a int) {}\n",
+		"lib/local.go":    "package lib\n\nfunc fetchAll() { _ = Fetch(\"a\") }\n",
+		"cmd/main.go":     "package main\n\nimport \"example.com/app/lib\"\n\nfunc main() {\n\tlib.Keep(1)\n\t_ = lib.Fetch(\"https://example.com\")\n}\n",
+		"other/other.go":  "package other\n\nfunc Fetch(url string) {}\n\nfunc use() { Fetch(\"x\") }\n",
+		"lib/lib_test.go": "package lib_test\n\nimport (\n\t\"testing\"\n\n\tlib \"example.com/app/lib\"\n)\n\nfunc TestFetch(t *testing.T) { _ = lib.Fetch(\"u\") }\n",
+		"docs/usage.md":   "# Usage\n\nCall `lib.Fetch(url)` first, or `Fetch(url, retries)` to retry.\n",
+		"README.md":       "Use Fetch() for downloads.\n",
+	}
+	changed := map[string]string{
+		"lib/lib.go": "package lib\n\nfunc Fetch(url string, retries int) error { return nil }\n\nfunc Keep(a int) {}\n",
+	}
+	tmpDir := driftRepo(t, committed, changed)
+
+	drift := internal.DetectParameterDrift(tmpDir, "")
+
+	want := strings.Join([]string{
+		"caller cmd/main.go:7 Fetch",
+		"doc docs/usage.md:3 Fetch",
+		"test lib/lib_test.go:9 Fetch",
+		"caller lib/local.go:3 Fetch",
+	}, "\n")
+	if got := driftSummary(drift); got != want {
+		t.Errorf("drift =\n%s\nwant\n%s", got, want)
+	}
+	if len(drift) > 0 {
+		msg := drift[0].Message
+		if !strings.Contains(msg, "call to Fetch passes 1 argument") || !strings.Contains(msg, "Fetch(url) is now Fetch(url, retries) at lib/lib.go:3") {
+			t.Errorf("message = %q", msg)
+		}
+	}
+}
+
+func TestDetectParameterDrift_TypeScriptDocsAndSchemas(t *testing.T) {
+	committed := map[string]string{
+		"src/search.ts":  "export async function searchNotes(query: string, limit?: number): Promise<Note[]> {\n  return [];\n}\n",
+		"src/cli.ts":     "import { searchNotes } from \"./search\";\n\nawait searchNotes(q, 10);\nawait searchNotes(q);\n",
+		"docs/search.md": "Run `searchNotes(query, limit)`.\n",
+		"schemas/search-notes.schema.json": `{
+  "title": "SearchNotesParams",
+  "type": "object",
+  "properties": {
+    "query": {"type": "string"},
+    "limit": {"type": "number"}
+  }
+}
+`,
+	}
+	changed := map[string]string{
+		"src/search.ts": "export async function searchNotes(query: string, options: SearchOptions = {}): Promise<Note[]> {\n  return [];\n}\n",
+	}
+	tmpDir := driftRepo(t, committed, changed)
+
+	drift := internal.DetectParameterDrift(tmpDir, "main")
+
+	want := strings.Join([]string{
+		"doc docs/search.md:1 searchNotes",
+		"schema schemas/search-notes.schema.json:2 searchNotes", // lacks options
+		"schema schemas/search-notes.schema.json:6 searchNotes", // still has limit
+	}, "\n")
+	if got := driftSummary(drift); got != want {
+		t.Errorf("drift =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDetectParameterDrift_TypeScriptCallers(t *testing.T) {
+	committed := map[string]string{
+		"src/api.ts": "export const createNote = (title: string, body: string) => ({ title, body });\n",
+		"src/ui.tsx": "const n = createNote(\n  title,\n  \"body, with comma\",\n);\n",
+		// Not searched: bundles over the size cap, dependencies and other file types
+		"public/app.bundle.js":          "createNote(a, b);\n" + strings.Repeat("//\n", 1<<19),
+		"node_modules/notes/index.js":   "createNote(a, b);\n",
+		"notes/createNote.txt":          "createNote(a, b);\n",
+		"config/createNote.schema.json": `{"createNote": {"properties": {"title": {}, "body": {}}}}`,
+	}
+	changed := map[string]string{
+		"src/api.ts": "export const createNote = (title: string, body: string, tags: string[]) => ({ title, body, tags });\n",
+	}
+	tmpDir := driftRepo(t, committed, changed)
+
+	drift := internal.DetectParameterDrift(tmpDir, "")
+
+	if got := driftSummary(drift); got != "caller src/ui.tsx:1 createNote" {
+		t.Errorf("drift = %q", got)
+	}
+}
+
+func TestDetectParameterDrift_NotARepository(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "lib.go"), "package lib\n\nfunc A(x int) {}\n")
+
+	if drift := internal.DetectParameterDrift(tmpDir, ""); len(drift) != 0 {
+		t.Errorf("drift = %+v, want none", drift)
+	}
+}
+
+func TestValidatePrePR_ParameterDriftChecks(t *testing.T) {
+	committed := map[string]string{
+		"src/lib.go":  "package src\n\nfunc Sum(a int) int { return a }\n",
+		"src/use.go":  "package src\n\nvar total = Sum(1)\n",
+		"src/main.go": "package src\n",
+	}
+	changed := map[string]string{
+		"src/lib.go": "package src\n\nfunc Sum(a, b int) int { return a + b }\n",
+	}
+	tmpDir := driftRepo(t, committed, changed)
+
+	result := internal.ValidatePrePR(tmpDir, true)
+
+	if result.Valid {
+		t.Fatal("Expected parameter drift to fail validation")
+	}
+	if !hasCheck(result.Checks, "parameter_drift", "src/use.go:3 - call to Sum passes 1 argument; Sum(a) is now Sum(a, b) at src/lib.go:3") {
+		t.Errorf("checks = %+v", result.Checks)
+	}
+	if !hasCheck(result.Checks, "test_implementation", "Test-implementation alignment verified") {
+		t.Errorf("checks = %+v, want test alignment itself to pass", result.Checks)
+	}
+
+	var located bool
+	for _, f := range result.Findings() {
+		located = located || (f.Check == "parameter_drift" && f.File == "src/use.go" && f.Line == 3 && !f.Passed)
+	}
+	if !located {
+		t.Errorf("findings = %+v, want the drift located", result.Findings())
+	}
+}
@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Input is what a registered validator runs against.
+type Input struct {
+	Root   string         `json:"root"`             // repository root; relative paths in Params resolve against it
+	Params map[string]any `json:"params,omitempty"` // validator options, as described by its InputSchema
+
+	// Config is the repository validation config. Registry runs load it from
+	// Root when it is nil.
+	Config *ValidationConfig `json:"-"`
+
+	// CacheDir holds results that validators may reuse across runs, such as
+	// per-feature consistency results. Empty disables caching.
+	CacheDir string `json:"-"`
+}
+
+// config returns in.Config, or the defaults when it is unset.
+func (in Input) config() *ValidationConfig {
+	if in.Config != nil {
+		return in.Config
+	}
+	return DefaultValidationConfig()
+}
+
+// withConfig loads the validation config of in.Root unless in has one.
+func withConfig(in Input) (Input, error) {
+	if in.Config != nil || in.Root == "" {
+		return in, nil
+	}
+	cfg, err := LoadValidationConfig(in.Root)
+	if err != nil {
+		return in, err
+	}
+	in.Config = cfg
+	return in, nil
+}
+
+// String returns the string parameter key, or def if it is unset.
+func (in Input) String(key, def string) string {
+	if s, ok := in.Params[key].(string); ok && s != "" {
+		return s
+	}
+	return def
+}
+
+// Bool returns the boolean parameter key, or false if it is unset.
+func (in Input) Bool(key string) bool {
+	b, _ := in.Params[key].(bool)
+	return b
+}
+
+// Float returns the numeric parameter key, or def if it is unset.
+func (in Input) Float(key string, def float64) float64 {
+	switch n := in.Params[key].(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case json.Number:
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// Int returns the integer parameter key, or def if it is unset.
+func (in Input) Int(key string, def int) int {
+	return int(in.Float(key, float64(def)))
+}
+
+// Strings returns the string list parameter key.
+func (in Input) Strings(key string) []string {
+	switch v := in.Params[key].(type) {
+	case []string:
+		return v
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Path returns the path parameter key (or def when unset) resolved against
+// Root.
+func (in Input) Path(key, def string) string {
+	path := in.String(key, def)
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(in.Root, path)
+}
+
+// Validator is a check that can be discovered and run generically.
+type Validator interface {
+	// Name is the unique registry key, e.g. "traceability".
+	Name() string
+	// Description is a one-line summary for listings.
+	Description() string
+	// InputSchema is the JSON Schema of Input.Params.
+	InputSchema() json.RawMessage
+	// Run validates in and returns one report entry per validated item.
+	// Long-running validators should stop early when ctx is done.
+	Run(ctx context.Context, in Input) *Report
+}
+
+// funcValidator adapts a function to Validator.
+type funcValidator struct {
+	name        string
+	description string
+	schema      json.RawMessage
+	run         func(ctx context.Context, in Input) *Report
+}
+
+// NewValidator returns a Validator that calls run. A nil schema accepts any
+// parameters.
+func NewValidator(name, description string, schema json.RawMessage, run func(ctx context.Context, in Input) *Report) Validator {
+	if schema == nil {
+		schema = json.RawMessage(`{"type": "object"}`)
+	}
+	return &funcValidator{name: name, description: description, schema: schema, run: run}
+}
+
+func (v *funcValidator) Name() string                 { return v.name }
+func (v *funcValidator) Description() string          { return v.description }
+func (v *funcValidator) InputSchema() json.RawMessage { return v.schema }
+
+func (v *funcValidator) Run(ctx context.Context, in Input) *Report {
+	return v.run(ctx, in)
+}
+
+// Registry holds validators by name, in registration order.
+type Registry struct {
+	mu         sync.RWMutex
+	validators map[string]Validator
+	order      []string
+	schemas    map[string]*jsonschema.Schema
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		validators: make(map[string]Validator),
+		schemas:    make(map[string]*jsonschema.Schema),
+	}
+}
+
+// Register adds v. Names must be unique and input schemas must compile.
+func (r *Registry) Register(v Validator) error {
+	name := v.Name()
+	if name == "" {
+		return fmt.Errorf("validator name is required")
+	}
+	schema, err := compileInputSchema(name, v.InputSchema())
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.validators[name]; exists {
+		return fmt.Errorf("validator %q is already registered", name)
+	}
+	r.validators[name] = v
+	r.schemas[name] = schema
+	r.order = append(r.order, name)
+	return nil
+}
+
+// MustRegister is Register for validators known to be valid; it panics on
+// error.
+func (r *Registry) MustRegister(v Validator) {
+	if err := r.Register(v); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns the validator registered as name.
+func (r *Registry) Get(name string) (Validator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.validators[name]
+	return v, ok
+}
+
+// List returns the registered validators in registration order.
+func (r *Registry) List() []Validator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]Validator, 0, len(r.order))
+	for _, name := range r.order {
+		list = append(list, r.validators[name])
+	}
+	return list
+}
+
+// CheckInput validates in.Params against the input schema of validator name.
+func (r *Registry) CheckInput(name string, in Input) error {
+	r.mu.RLock()
+	schema, ok := r.schemas[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown validator %q", name)
+	}
+
+	params := in.Params
+	if params == nil {
+		params = map[string]any{}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("%s: encode params: %w", name, err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: decode params: %w", name, err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		return fmt.Errorf("%s: invalid params: %w", name, err)
+	}
+	return nil
+}
+
+// Run checks in against the schema of validator name, runs it and applies
+// the repository validation config to the report.
+func (r *Registry) Run(ctx context.Context, name string, in Input) (*Report, error) {
+	return r.RunAll(ctx, in, name)
+}
+
+// RunAll runs the named validators, or all of them when names is empty, in
+// order and merges their reports. Each validator is checked against its input
+// schema; validators not started before ctx is done are reported as cancelled.
+// The repository validation config is loaded from in.Root unless in.Config is
+// set, and applied to the merged report.
+func (r *Registry) RunAll(ctx context.Context, in Input, names ...string) (*Report, error) {
+	in, err := withConfig(in)
+	if err != nil {
+		return nil, err
+	}
+
+	validators := r.List()
+	if len(names) > 0 {
+		validators = validators[:0:0]
+		for _, name := range names {
+			v, ok := r.Get(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown validator %q", name)
+			}
+			validators = append(validators, v)
+		}
+	}
+	for _, v := range validators {
+		if err := r.CheckInput(v.Name(), in); err != nil {
+			return nil, err
+		}
+	}
+
+	report := NewReport(in.Root)
+	for _, v := range validators {
+		report.Merge(RunValidator(ctx, v, in))
+	}
+	in.config().ApplyToReport(report)
+	return report, nil
+}
+
+// RunValidator runs v with timing, cancellation and panic recovery. A report
+// entry without a duration gets the validator's total run time.
+func RunValidator(ctx context.Context, v Validator, in Input) (report *Report) {
+	if err := ctx.Err(); err != nil {
+		report = NewReport(in.Root)
+		report.Add(CancelledValidatorReport(v.Name(), err))
+		return report
+	}
+
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			report = NewReport(in.Root)
+			report.Add(ValidatorReport{
+				Validator: v.Name(),
+				Message:   fmt.Sprintf("validator panicked: %v", p),
+				Findings:  []Finding{{Check: "run", Severity: SeverityError, Message: fmt.Sprint(p)}},
+			})
+		}
+		elapsed := msSince(start)
+		for i := range report.Validators {
+			if report.Validators[i].DurationMS == 0 && report.Validators[i].Skipped == "" {
+				report.Validators[i].DurationMS = elapsed
+			}
+		}
+	}()
+
+	report = v.Run(ctx, in)
+	if report == nil {
+		report = NewReport(in.Root)
+	}
+	return report
+}
+
+// TimedValidatorReport runs one validation and records how long it took.
+func TimedValidatorReport(validator, target string, run func() Reportable) ValidatorReport {
+	start := time.Now()
+	v := NewValidatorReport(validator, target, run())
+	v.DurationMS = msSince(start)
+	return v
+}
+
+// CancelledValidatorReport records a validator stopped by ctx. A cancelled
+// run is not valid: nothing was checked.
+func CancelledValidatorReport(validator string, err error) ValidatorReport {
+	return ValidatorReport{Validator: validator, Skipped: "cancelled: " + err.Error()}
+}
+
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+func compileInputSchema(name string, schema json.RawMessage) (*jsonschema.Schema, error) {
+	var doc any
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, fmt.Errorf("validator %q: parse input schema: %w", name, err)
+	}
+	c := jsonschema.NewCompiler()
+	url := name + ".input.schema.json"
+	if err := c.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("validator %q: add input schema: %w", name, err)
+	}
+	compiled, err := c.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("validator %q: compile input schema: %w", name, err)
+	}
+	return compiled, nil
+}
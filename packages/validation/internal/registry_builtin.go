@@ -0,0 +1,305 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultRegistry holds the built-in validators and any registered with
+// Register.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a validator to DefaultRegistry.
+func Register(v Validator) error {
+	return DefaultRegistry.Register(v)
+}
+
+// Validators returns the validators in DefaultRegistry.
+func Validators() []Validator {
+	return DefaultRegistry.List()
+}
+
+func init() {
+	for _, v := range builtinValidators() {
+		DefaultRegistry.MustRegister(v)
+	}
+}
+
+// builtinValidators returns the repository validators in the order
+// 'brain validate all' reports them.
+func builtinValidators() []Validator {
+	return []Validator{
+		NewValidator("pre-pr",
+			"Pre-PR readiness: cross-cutting concerns, fail-safe design, test alignment, CI and environment",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"quick": {"type": "boolean", "description": "Skip the slower checks"},
+					"skipTests": {"type": "boolean", "description": "Skip test/implementation alignment"},
+					"base": {"type": "string", "description": "Only check changes since the merge base of this git ref and HEAD"}
+				}
+			}`),
+			runPrePRValidator),
+		NewValidator("traceability",
+			"REQ -> DESIGN -> TASK cross-references between specs",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"specs": {"type": "string", "description": "Specs directory (default: .agents/specs)"},
+					"strict": {"type": "boolean", "description": "Treat warnings as failures"}
+				}
+			}`),
+			runTraceabilityValidator),
+		NewValidator("consistency",
+			"Cross-document consistency of each feature under .agents/planning",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"feature": {"type": "string", "description": "Feature to validate (default: all features)"},
+					"checkpoint": {"type": "integer", "enum": [1, 2], "description": "1 (pre-critic) or 2 (post-implementation)"}
+				}
+			}`),
+			runConsistencyValidator),
+		NewValidator("memory-index",
+			"Memory domain indexes, their references, keyword density and orphans",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"memories": {"type": "string", "description": "Memories directory (default: .serena/memories)"}
+				}
+			}`),
+			runMemoryIndexValidator),
+		NewValidator("skills",
+			"Skill file format and frontmatter",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"skills": {"type": "string", "description": "Skills directory (default: .claude/skills)"}
+				}
+			}`),
+			runSkillsValidator),
+		NewValidator("commands",
+			"Slash command frontmatter, arguments and security rules",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"commands": {"type": "string", "description": "Commands directory (default: .claude/commands)"},
+					"recursive": {"type": "boolean", "description": "Also validate commands in subdirectories"}
+				}
+			}`),
+			runCommandsValidator),
+		NewValidator("skill-violations",
+			"Raw commands where a skill should be used instead",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"staged": {"type": "boolean", "description": "Only check lines added in git-staged files"},
+					"rules": {"type": "string", "description": "Skill rule file (default: .brain/skill-rules.yaml)"}
+				}
+			}`),
+			runSkillViolationsValidator),
+		NewValidator("test-coverage",
+			"Source files without a corresponding test file",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"language": {"type": "string", "description": "Language to check (default: detected)"},
+					"threshold": {"type": "number", "minimum": 0, "maximum": 100, "description": "Minimum percent of source files with tests, or of covered lines with a profile"},
+					"staged": {"type": "boolean", "description": "Only check git-staged files"},
+					"profile": {"type": "string", "description": "Go coverprofile, LCOV or Cobertura XML file for line coverage"},
+					"profileFormat": {"type": "string", "enum": ["go", "lcov", "cobertura"], "description": "Coverage profile format (default: detected)"}
+				}
+			}`),
+			runTestCoverageValidator),
+		NewValidator("pr-description",
+			"PR description against the files changed in the PR",
+			json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"body": {"type": "string", "description": "PR description"},
+					"files": {"type": "array", "items": {"type": "string"}, "description": "Files changed in the PR"}
+				}
+			}`),
+			runPRDescriptionValidator),
+	}
+}
+
+// single wraps one timed validation in a report.
+func single(in Input, validator string, run func() Reportable) *Report {
+	report := NewReport(in.Root)
+	report.Add(TimedValidatorReport(validator, "", run))
+	return report
+}
+
+// skipped returns a report recording that validator does not apply.
+func skipped(in Input, validator, reason string) *Report {
+	report := NewReport(in.Root)
+	report.Add(SkippedValidatorReport(validator, reason))
+	return report
+}
+
+// relTarget labels path relative to root when it is inside it.
+func relTarget(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func runPrePRValidator(ctx context.Context, in Input) *Report {
+	return single(in, "pre-pr", func() Reportable {
+		config := in.config().PrePRConfig(in.Root)
+		config.QuickMode = in.Bool("quick")
+		config.SkipTests = in.Bool("skipTests")
+		config.Base = in.String("base", "")
+		return ValidatePrePRWithConfig(config)
+	})
+}
+
+func runTraceabilityValidator(ctx context.Context, in Input) *Report {
+	specs := in.Path("specs", ".agents/specs")
+	if !DirExists(specs) {
+		return skipped(in, "traceability", "no specs directory at "+specs)
+	}
+	return single(in, "traceability", func() Reportable {
+		return ValidateTraceabilityWithRules(specs, in.Bool("strict"), in.config().StatusRules())
+	})
+}
+
+func runConsistencyValidator(ctx context.Context, in Input) *Report {
+	checkpoint := in.Int("checkpoint", 1)
+	if feature := in.String("feature", ""); feature != "" {
+		report := NewReport(in.Root)
+		report.Add(TimedValidatorReport("consistency", feature, func() Reportable {
+			return ValidateConsistency(in.Root, feature, checkpoint)
+		}))
+		return report
+	}
+	if len(GetAllFeatures(in.Root)) == 0 {
+		return skipped(in, "consistency", "no features under .agents/planning")
+	}
+
+	results, err := ValidateAllFeaturesContext(ctx, in.Root, AllFeaturesOptions{Checkpoint: checkpoint, CacheDir: in.CacheDir})
+	report := NewReport(in.Root)
+	for _, r := range results {
+		v := NewValidatorReport("consistency", r.Feature, r)
+		v.DurationMS = float64(r.elapsed.Microseconds()) / 1000
+		report.Add(v)
+	}
+	if err != nil {
+		report.Add(CancelledValidatorReport("consistency", err))
+	}
+	return report
+}
+
+func runMemoryIndexValidator(ctx context.Context, in Input) *Report {
+	memories := in.Path("memories", ".serena/memories")
+	if !DirExists(memories) {
+		return skipped(in, "memory-index", "no memories directory at "+memories)
+	}
+	return single(in, "memory-index", func() Reportable {
+		return ValidateMemoryIndex(memories)
+	})
+}
+
+func runSkillsValidator(ctx context.Context, in Input) *Report {
+	dir := in.Path("skills", ".claude/skills")
+	if !DirExists(dir) {
+		return skipped(in, "skills", "no skills directory at "+dir)
+	}
+	files, err := FindSkillFiles(dir)
+	if err != nil {
+		return skipped(in, "skills", err.Error())
+	}
+
+	report := NewReport(in.Root)
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			report.Add(CancelledValidatorReport("skills", err))
+			break
+		}
+		if in.config().IgnoredIn(in.Root, file) {
+			continue
+		}
+		report.Add(TimedValidatorReport("skills", relTarget(in.Root, file), func() Reportable {
+			// Results carry only the base name; locate findings by full path.
+			r := ValidateSkillFormat(file)
+			r.FilePath = file
+			return r
+		}))
+	}
+	return report
+}
+
+func runCommandsValidator(ctx context.Context, in Input) *Report {
+	dir := in.Path("commands", ".claude/commands")
+	if !DirExists(dir) {
+		return skipped(in, "commands", "no commands directory at "+dir)
+	}
+
+	report := NewReport(in.Root)
+	for _, file := range FindSlashCommandFiles(dir, in.Bool("recursive")) {
+		if err := ctx.Err(); err != nil {
+			report.Add(CancelledValidatorReport("commands", err))
+			break
+		}
+		if in.config().IgnoredIn(in.Root, file) {
+			continue
+		}
+		report.Add(TimedValidatorReport("commands", relTarget(in.Root, file), func() Reportable {
+			r := ValidateSlashCommand(file)
+			r.FilePath = file
+			return r
+		}))
+	}
+	return report
+}
+
+func runSkillViolationsValidator(ctx context.Context, in Input) *Report {
+	if findGitRoot(in.Root) == "" {
+		return skipped(in, "skill-violations", "not a git repository")
+	}
+	return single(in, "skill-violations", func() Reportable {
+		var rules *SkillRuleSet
+		if path := in.Path("rules", ""); path != "" {
+			var err error
+			if rules, err = LoadSkillRulesFile(path, false); err != nil {
+				return SkillViolationResult{ValidationResult: ValidationResult{
+					Message: "Invalid skill rules: " + err.Error(),
+				}}
+			}
+		}
+		return DetectSkillViolationsWithRules(in.Root, in.Bool("staged"), rules)
+	})
+}
+
+func runTestCoverageValidator(ctx context.Context, in Input) *Report {
+	if findGitRoot(in.Root) == "" {
+		return skipped(in, "test-coverage", "not a git repository")
+	}
+	cfg := in.config()
+	return single(in, "test-coverage", func() Reportable {
+		return DetectTestCoverageGaps(TestCoverageGapOptions{
+			BasePath:        in.Root,
+			Language:        in.String("language", ""),
+			StagedOnly:      in.Bool("staged"),
+			Threshold:       in.Float("threshold", 0),
+			CustomPatterns:  cfg.IgnorePatterns(in.Root),
+			Languages:       cfg.LanguageConfigs(),
+			CoverageProfile: in.Path("profile", ""),
+			ProfileFormat:   in.String("profileFormat", ""),
+		})
+	})
+}
+
+func runPRDescriptionValidator(ctx context.Context, in Input) *Report {
+	body := in.String("body", "")
+	if body == "" {
+		return skipped(in, "pr-description", "no PR description given")
+	}
+	return single(in, "pr-description", func() Reportable {
+		return ValidatePRDescriptionWithConfig(in.config().PRDescriptionConfig(body, in.Strings("files")))
+	})
+}
@@ -0,0 +1,193 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+func passing(name string) internal.Validator {
+	return internal.NewValidator(name, "always passes", nil, func(ctx context.Context, in internal.Input) *internal.Report {
+		report := internal.NewReport(in.Root)
+		report.Add(internal.NewValidatorReport(name, "", internal.ValidationResult{Valid: true}))
+		return report
+	})
+}
+
+func TestRegistry_RegisterAndList(t *testing.T) {
+	r := internal.NewRegistry()
+	r.MustRegister(passing("b"))
+	r.MustRegister(passing("a"))
+
+	var names []string
+	for _, v := range r.List() {
+		names = append(names, v.Name())
+	}
+	if strings.Join(names, ",") != "b,a" {
+		t.Errorf("List() = %v, want registration order b,a", names)
+	}
+	if err := r.Register(passing("a")); err == nil {
+		t.Error("duplicate registration should fail")
+	}
+	if _, ok := r.Get("missing"); ok {
+		t.Error("Get of an unregistered name succeeded")
+	}
+}
+
+func TestRegistry_RejectsBadSchema(t *testing.T) {
+	r := internal.NewRegistry()
+	v := internal.NewValidator("bad", "", json.RawMessage(`{"type": 12}`), nil)
+	if err := r.Register(v); err == nil {
+		t.Error("expected an error for an invalid input schema")
+	}
+}
+
+func TestRegistry_RunChecksInputSchema(t *testing.T) {
+	r := internal.NewRegistry()
+	r.MustRegister(internal.NewValidator("typed", "", json.RawMessage(`{
+		"type": "object",
+		"properties": {"threshold": {"type": "number", "maximum": 100}}
+	}`), func(ctx context.Context, in internal.Input) *internal.Report {
+		report := internal.NewReport(in.Root)
+		report.Add(internal.NewValidatorReport("typed", "", internal.ValidationResult{Valid: in.Float("threshold", 0) == 80}))
+		return report
+	}))
+
+	if _, err := r.Run(context.Background(), "typed", internal.Input{Params: map[string]any{"threshold": 150}}); err == nil {
+		t.Error("threshold 150 should fail the input schema")
+	}
+	report, err := r.Run(context.Background(), "typed", internal.Input{Params: map[string]any{"threshold": 80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid {
+		t.Errorf("report = %+v", report)
+	}
+	if _, err := r.Run(context.Background(), "nope", internal.Input{}); err == nil {
+		t.Error("running an unknown validator should fail")
+	}
+}
+
+func TestRunValidator_TimingAndPanics(t *testing.T) {
+	slow := internal.NewValidator("slow", "", nil, func(ctx context.Context, in internal.Input) *internal.Report {
+		time.Sleep(5 * time.Millisecond)
+		report := internal.NewReport(in.Root)
+		report.Add(internal.NewValidatorReport("slow", "", internal.ValidationResult{Valid: true}))
+		return report
+	})
+	report := internal.RunValidator(context.Background(), slow, internal.Input{})
+	if d := report.Validators[0].DurationMS; d < 5 {
+		t.Errorf("DurationMS = %v, want >= 5", d)
+	}
+
+	boom := internal.NewValidator("boom", "", nil, func(context.Context, internal.Input) *internal.Report {
+		panic("kaboom")
+	})
+	report = internal.RunValidator(context.Background(), boom, internal.Input{})
+	if report.Valid || !strings.Contains(report.Validators[0].Message, "kaboom") {
+		t.Errorf("panicking validator report = %+v", report)
+	}
+}
+
+func TestRegistry_RunAllCancelled(t *testing.T) {
+	r := internal.NewRegistry()
+	r.MustRegister(passing("a"))
+	r.MustRegister(passing("b"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := r.RunAll(ctx, internal.Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid || len(report.Validators) != 2 {
+		t.Fatalf("cancelled report = %+v", report)
+	}
+	for _, v := range report.Validators {
+		if !strings.HasPrefix(v.Skipped, "cancelled") {
+			t.Errorf("%s: Skipped = %q, want cancelled", v.Validator, v.Skipped)
+		}
+	}
+}
+
+func TestDefaultRegistry_BuiltinsSkipMissingInputs(t *testing.T) {
+	want := []string{"pre-pr", "traceability", "consistency", "memory-index", "skills", "commands", "skill-violations", "test-coverage", "pr-description"}
+	var names []string
+	for _, v := range internal.Validators() {
+		names = append(names, v.Name())
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("built-in validators = %v, want %v", names, want)
+	}
+
+	root := t.TempDir()
+	report, err := internal.DefaultRegistry.RunAll(context.Background(), internal.Input{Root: root},
+		"traceability", "consistency", "memory-index", "skills", "commands", "pr-description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid {
+		t.Errorf("an empty repository should only skip: %+v", report)
+	}
+	if _, _, skipped := report.Counts(); skipped != 6 {
+		t.Errorf("skipped = %d, want 6: %+v", skipped, report.Validators)
+	}
+}
+
+func TestDefaultRegistry_SkillsPerFile(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".claude", "skills", "good", "SKILL.md"), "---\nname: good\ndescription: A good skill\n---\n\n# Good\n")
+	writeTestFile(t, filepath.Join(root, ".claude", "skills", "bad", "SKILL.md"), "# No frontmatter\n")
+
+	report, err := internal.DefaultRegistry.Run(context.Background(), "skills", internal.Input{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid || len(report.Validators) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	bad := report.Validators[0]
+	if bad.Target != filepath.Join(".claude", "skills", "bad", "SKILL.md") || bad.Valid {
+		t.Errorf("bad skill entry = %+v", bad)
+	}
+	if f := bad.Findings[0]; f.File != filepath.Join(root, ".claude", "skills", "bad", "SKILL.md") {
+		t.Errorf("finding file = %q, want the full path", f.File)
+	}
+}
+
+func TestDefaultRegistry_ConsistencyCache(t *testing.T) {
+	root := t.TempDir()
+	writeFeatures(t, filepath.Join(root, ".agents", "planning"), "auth", "payment")
+	in := internal.Input{Root: root, CacheDir: filepath.Join(t.TempDir(), "cache")}
+
+	for run, wantCached := range []bool{false, true} {
+		report, err := internal.DefaultRegistry.Run(context.Background(), "consistency", in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Validators) != 2 || report.Validators[0].Target != "auth" || report.Validators[1].Target != "payment" {
+			t.Fatalf("run %d: report = %+v", run, report.Validators)
+		}
+		for _, v := range report.Validators {
+			if cached := v.Result.(internal.ConsistencyValidationResult).Cached; cached != wantCached {
+				t.Errorf("run %d: %s cached = %v, want %v", run, v.Target, cached, wantCached)
+			}
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
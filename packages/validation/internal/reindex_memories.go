@@ -0,0 +1,586 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMinKeywords is the keyword count ValidateMemoryIndex expects of
+// each index entry.
+const DefaultMinKeywords = 5
+
+// minKeywordDensity is the share of an entry's keywords that must be unique
+// within its domain index.
+const minKeywordDensity = 0.40
+
+// MemoryReindexOptions configures ReindexMemories.
+type MemoryReindexOptions struct {
+	Domain      string // rebuild only this domain's index, creating it if needed (default: all)
+	MinKeywords int    // keywords each entry should have (default: DefaultMinKeywords)
+	DryRun      bool   // compute the changes without writing them
+}
+
+// MemoryIndexChange describes one rebuilt index file.
+type MemoryIndexChange struct {
+	Path         string   `json:"path"`
+	Domain       string   `json:"domain,omitempty"` // empty for memory-index.md
+	Created      bool     `json:"created,omitempty"`
+	Added        []string `json:"added,omitempty"`        // orphaned files now indexed
+	Removed      []string `json:"removed,omitempty"`      // references to missing files
+	Deduplicated []string `json:"deduplicated,omitempty"` // entries merged into their first occurrence
+	Moved        []string `json:"moved,omitempty"`        // entries moved here from another domain's index
+	Dropped      []string `json:"dropped,omitempty"`      // entries without a domain prefix
+	Rekeyed      []string `json:"rekeyed,omitempty"`      // entries whose keywords changed
+	Diff         string   `json:"diff,omitempty"`
+	Original     string   `json:"-"`
+	Content      string   `json:"-"`
+}
+
+// Changed reports whether the file content changed.
+func (c MemoryIndexChange) Changed() bool {
+	return c.Content != c.Original
+}
+
+// MemoryReindexResult is the outcome of ReindexMemories.
+type MemoryReindexResult struct {
+	MemoryPath string              `json:"memoryPath"`
+	DryRun     bool                `json:"dryRun"`
+	Changes    []MemoryIndexChange `json:"changes"`
+	Warnings   []string            `json:"warnings,omitempty"`
+}
+
+// Changed returns the changes that modify a file.
+func (r MemoryReindexResult) Changed() []MemoryIndexChange {
+	var changed []MemoryIndexChange
+	for _, c := range r.Changes {
+		if c.Changed() {
+			changed = append(changed, c)
+		}
+	}
+	return changed
+}
+
+// reindexEntry is an index entry being rebuilt.
+type reindexEntry struct {
+	name     string
+	keywords []string
+	original string // keywords as read, to report rekeyed entries
+	pool     []string
+}
+
+// reindexPlan is a domain index being rebuilt.
+type reindexPlan struct {
+	index   DomainIndex
+	entries []*reindexEntry
+	change  MemoryIndexChange
+	rebuild bool // in scope: clean, add orphans and rebalance keywords
+	touched bool // out of scope but received moved entries
+}
+
+// ReindexMemories rebuilds the domain index tables (skills-{domain}-index.md)
+// of a memories directory from the files on disk and keeps memory-index.md in
+// sync. Orphaned files are added with keywords from their frontmatter,
+// headings and name; references to missing files are removed; duplicates are
+// merged; entries are moved to the index matching their domain prefix, or
+// dropped if no domain matches; and keywords are adjusted so each entry has
+// enough of them and at least 40% unique within its index. The rebuilt
+// indexes pass ValidateMemoryIndex. Nothing is written with opts.DryRun.
+func ReindexMemories(memoryPath string, opts MemoryReindexOptions) (MemoryReindexResult, error) {
+	if opts.MinKeywords <= 0 {
+		opts.MinKeywords = DefaultMinKeywords
+	}
+	resolved, err := filepath.Abs(memoryPath)
+	if err != nil {
+		return MemoryReindexResult{}, err
+	}
+	if !DirExists(resolved) {
+		return MemoryReindexResult{}, fmt.Errorf("memory path not found: %s", memoryPath)
+	}
+	result := MemoryReindexResult{MemoryPath: resolved, DryRun: opts.DryRun}
+
+	indices := getDomainIndices(resolved)
+	if opts.Domain != "" && !hasDomain(indices, opts.Domain) {
+		name := "skills-" + opts.Domain + "-index"
+		indices = append(indices, DomainIndex{Path: filepath.Join(resolved, name+".md"), Name: name, Domain: opts.Domain})
+	}
+	domains := make([]string, 0, len(indices))
+	for _, index := range indices {
+		domains = append(domains, index.Domain)
+	}
+
+	plans := make(map[string]*reindexPlan, len(indices))
+	for _, index := range indices {
+		plan := &reindexPlan{
+			index:   index,
+			rebuild: opts.Domain == "" || opts.Domain == index.Domain,
+			change:  MemoryIndexChange{Path: index.Path, Domain: index.Domain},
+		}
+		content, err := os.ReadFile(index.Path)
+		switch {
+		case os.IsNotExist(err):
+			plan.change.Created = true
+		case err != nil:
+			return result, err
+		}
+		plan.change.Original = string(content)
+		for _, e := range parseIndexEntries(string(content)) {
+			plan.entries = append(plan.entries, &reindexEntry{name: e.FileName, keywords: e.Keywords, original: e.RawKeywords})
+		}
+		plans[index.Domain] = plan
+	}
+
+	// Clean the rebuilt indexes, collecting entries that belong elsewhere.
+	type move struct {
+		from  string
+		entry *reindexEntry
+	}
+	moves := map[string][]move{}
+	for _, domain := range domains {
+		plan := plans[domain]
+		if !plan.rebuild {
+			continue
+		}
+		seen := map[string]*reindexEntry{}
+		var kept []*reindexEntry
+		for _, e := range plan.entries {
+			e.name = strings.TrimSuffix(e.name, ".md")
+			switch owner := memoryDomain(e.name, domains); {
+			case !FileExists(filepath.Join(resolved, e.name+".md")):
+				plan.change.Removed = append(plan.change.Removed, e.name)
+			case seen[e.name] != nil:
+				seen[e.name].keywords = append(seen[e.name].keywords, e.keywords...)
+				plan.change.Deduplicated = appendUnique(plan.change.Deduplicated, e.name)
+			case owner == "":
+				plan.change.Dropped = append(plan.change.Dropped, e.name)
+				result.Warnings = append(result.Warnings, fmt.Sprintf(
+					"%s: dropped %s, which lacks a domain prefix; rename it to %s-{description}", plan.index.Name, e.name, domain))
+			case owner != domain:
+				moves[owner] = append(moves[owner], move{from: plan.index.Name, entry: e})
+			default:
+				seen[e.name] = e
+				kept = append(kept, e)
+			}
+		}
+		plan.entries = kept
+	}
+	for _, domain := range domains {
+		plan := plans[domain]
+		for _, m := range moves[domain] {
+			if plan.find(m.entry.name) == nil {
+				plan.entries = append(plan.entries, m.entry)
+				plan.change.Moved = append(plan.change.Moved, m.entry.name+" (from "+m.from+")")
+				plan.touched = !plan.rebuild
+			}
+		}
+	}
+
+	// Index orphans: memory files with a domain prefix that no index lists.
+	referenced := map[string]bool{}
+	for _, plan := range plans {
+		for _, name := range plan.change.Dropped {
+			referenced[name] = true // already reported
+		}
+		for _, e := range plan.entries {
+			referenced[e.name] = true
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(resolved, "*.md"))
+	if err != nil {
+		return result, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+		switch {
+		case name == "memory-index" || strings.HasSuffix(name, "-index") || referenced[name]:
+			continue
+		case strings.HasPrefix(name, "skill-") || strings.HasPrefix(name, "skills-"):
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"%s is not indexed: rename it to {domain}-{description} per ADR-017", name))
+			continue
+		}
+		plan := plans[memoryDomain(name, domains)]
+		if plan == nil || !plan.rebuild {
+			continue
+		}
+		plan.entries = append(plan.entries, &reindexEntry{name: name})
+		plan.change.Added = append(plan.change.Added, name)
+	}
+
+	// Rebalance keywords and render the indexes.
+	for _, domain := range domains {
+		plan := plans[domain]
+		if !plan.rebuild && !plan.touched {
+			continue
+		}
+		for _, e := range plan.entries {
+			e.pool = memoryKeywords(resolved, e.name, domain)
+		}
+		balanceKeywords(plan.entries, opts.MinKeywords)
+		for _, e := range plan.entries {
+			if len(e.keywords) < opts.MinKeywords {
+				result.Warnings = append(result.Warnings, fmt.Sprintf(
+					"%s: %s has %d keywords (want %d); add headings or tags to it", plan.index.Name, e.name, len(e.keywords), opts.MinKeywords))
+			}
+			if e.original != "" && strings.Join(e.keywords, " ") != e.original {
+				plan.change.Rekeyed = append(plan.change.Rekeyed, e.name)
+			}
+		}
+		plan.change.Content = renderDomainIndex(plan.entries)
+		result.Changes = append(result.Changes, plan.change)
+	}
+
+	memIndex, err := syncMemoryIndex(resolved, indices)
+	if err != nil {
+		return result, err
+	}
+	result.Changes = append(result.Changes, memIndex)
+
+	for i := range result.Changes {
+		c := &result.Changes[i]
+		if !c.Changed() {
+			continue
+		}
+		name := filepath.Base(c.Path)
+		c.Diff = unifiedDiff("a/"+name, "b/"+name, c.Original, c.Content)
+		if !opts.DryRun {
+			if err := os.WriteFile(c.Path, []byte(c.Content), 0644); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func hasDomain(indices []DomainIndex, domain string) bool {
+	for _, index := range indices {
+		if index.Domain == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *reindexPlan) find(name string) *reindexEntry {
+	for _, e := range p.entries {
+		if e.name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func appendUnique(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// memoryDomain returns the longest domain whose prefix name has, or "".
+func memoryDomain(name string, domains []string) string {
+	best := ""
+	for _, domain := range domains {
+		if strings.HasPrefix(name, domain+"-") && len(domain) > len(best) {
+			best = domain
+		}
+	}
+	return best
+}
+
+var (
+	keywordSplitPattern = regexp.MustCompile(`[^a-z0-9]+`)
+	keywordStopWords    = map[string]bool{
+		"the": true, "and": true, "for": true, "with": true, "from": true, "this": true,
+		"that": true, "into": true, "when": true, "how": true, "what": true, "are": true,
+		"was": true, "use": true, "using": true, "your": true, "you": true, "our": true,
+		"not": true, "all": true, "can": true, "via": true, "per": true, "its": true,
+		"has": true, "have": true, "will": true, "should": true, "must": true, "about": true,
+		"after": true, "before": true, "over": true, "under": true, "only": true, "notes": true,
+	}
+)
+
+// memoryKeywords returns candidate keywords for a memory file: its
+// frontmatter keywords, tags and title, its name without the domain prefix,
+// then the words of its headings.
+func memoryKeywords(memoryPath, name, domain string) []string {
+	content, _ := os.ReadFile(filepath.Join(memoryPath, name+".md"))
+	frontmatter, body := splitFrontmatter(string(content))
+
+	var texts []string
+	var meta map[string]any
+	if yaml.Unmarshal([]byte(frontmatter), &meta) == nil {
+		for _, key := range []string{"keywords", "tags", "title"} {
+			texts = append(texts, yamlStrings(meta[key])...)
+		}
+	}
+	texts = append(texts, strings.TrimPrefix(name, domain+"-"))
+	for _, line := range strings.Split(body, "\n") {
+		if h, ok := parseHeading(line); ok {
+			texts = append(texts, h.name)
+		}
+	}
+
+	var keywords []string
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, word := range keywordSplitPattern.Split(strings.ToLower(text), -1) {
+			if len(word) < 3 || keywordStopWords[word] || strings.Trim(word, "0123456789") == "" || seen[word] {
+				continue
+			}
+			seen[word] = true
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
+// splitFrontmatter splits leading YAML frontmatter from markdown content.
+func splitFrontmatter(content string) (frontmatter, body string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end < 0 {
+		return "", content
+	}
+	return content[4 : 4+end], content[4+end+4:]
+}
+
+func yamlStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, item := range v {
+			out = append(out, yamlStrings(item)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// balanceKeywords makes every entry's keywords at least 40% unique within
+// the index and tops entries up to min keywords from their candidate pools.
+// A shared keyword stays with the entries whose content mentions it, other
+// shared keywords are dropped next, unique candidates are added after that,
+// and an entry left without keywords gets its file name.
+func balanceKeywords(entries []*reindexEntry, min int) {
+	owners := map[string]int{}
+	for _, e := range entries {
+		e.keywords = dedupeKeywords(e.keywords)
+		for _, kw := range e.keywords {
+			owners[strings.ToLower(kw)]++
+		}
+	}
+
+	if len(entries) > 1 {
+		supporters := map[string]int{}
+		for _, e := range entries {
+			for _, kw := range e.keywords {
+				if containsString(e.pool, strings.ToLower(kw)) {
+					supporters[strings.ToLower(kw)]++
+				}
+			}
+		}
+		for _, e := range entries {
+			kept := e.keywords[:0]
+			for _, kw := range e.keywords {
+				key := strings.ToLower(kw)
+				if n := supporters[key]; n > 0 && n < owners[key] && !containsString(e.pool, key) {
+					owners[key]--
+					continue
+				}
+				kept = append(kept, kw)
+			}
+			e.keywords = kept
+		}
+		for _, e := range entries {
+			for keywordDensity(e.keywords, owners) < minKeywordDensity {
+				i := lastShared(e.keywords, owners)
+				if i < 0 {
+					break
+				}
+				owners[strings.ToLower(e.keywords[i])]--
+				e.keywords = append(e.keywords[:i], e.keywords[i+1:]...)
+			}
+		}
+	}
+
+	for _, e := range entries {
+		for _, kw := range e.pool {
+			if len(e.keywords) >= min {
+				break
+			}
+			if owners[kw] == 0 {
+				owners[kw]++
+				e.keywords = append(e.keywords, kw)
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if len(e.keywords) > 0 {
+			continue
+		}
+		id := strings.ToLower(e.name)
+		for _, other := range entries {
+			for i, kw := range other.keywords {
+				if strings.ToLower(kw) == id {
+					other.keywords = append(other.keywords[:i], other.keywords[i+1:]...)
+					break
+				}
+			}
+		}
+		owners[id] = 1
+		e.keywords = []string{id}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupeKeywords(keywords []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, kw := range keywords {
+		if key := strings.ToLower(kw); !seen[key] {
+			seen[key] = true
+			out = append(out, kw)
+		}
+	}
+	return out
+}
+
+// keywordDensity is the share of keywords no other entry has.
+func keywordDensity(keywords []string, owners map[string]int) float64 {
+	if len(keywords) == 0 {
+		return 0
+	}
+	unique := 0
+	for _, kw := range keywords {
+		if owners[strings.ToLower(kw)] == 1 {
+			unique++
+		}
+	}
+	return float64(unique) / float64(len(keywords))
+}
+
+func lastShared(keywords []string, owners map[string]int) int {
+	for i := len(keywords) - 1; i >= 0; i-- {
+		if owners[strings.ToLower(keywords[i])] > 1 {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderDomainIndex renders a domain index as the pure lookup table ADR-017
+// requires.
+func renderDomainIndex(entries []*reindexEntry) string {
+	var b strings.Builder
+	b.WriteString("| Keywords | File |\n|----------|------|\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | %s |\n", strings.Join(e.keywords, " "), e.name)
+	}
+	return b.String()
+}
+
+var memoryIndexRowPattern = regexp.MustCompile(`^\|\s*([^|]+)\s*\|\s*([^|]+)\s*\|$`)
+
+// syncMemoryIndex removes references to missing files from memory-index.md
+// and adds a row for each domain index it does not mention, creating the
+// file if needed.
+func syncMemoryIndex(memoryPath string, indices []DomainIndex) (MemoryIndexChange, error) {
+	path := filepath.Join(memoryPath, "memory-index.md")
+	change := MemoryIndexChange{Path: path}
+	content, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		change.Created = true
+		content = []byte("| Keywords | File |\n|----------|------|\n")
+	case err != nil:
+		return change, err
+	default:
+		change.Original = string(content)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	var out []string
+	tableEnd := -1
+	for _, line := range lines {
+		match := memoryIndexRowPattern.FindStringSubmatch(line)
+		if match == nil {
+			out = append(out, line)
+			continue
+		}
+		files := strings.TrimSpace(match[2])
+		if files == "File" || strings.HasPrefix(files, "-") || files == "Essential Memories" || files == "Memory" {
+			out = append(out, line)
+			tableEnd = len(out)
+			continue
+		}
+		var kept []string
+		for _, name := range strings.Split(files, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if FileExists(filepath.Join(memoryPath, name+".md")) || isPlannedIndex(name, indices) {
+				kept = append(kept, name)
+			} else {
+				change.Removed = append(change.Removed, name)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		if len(kept) != len(strings.Split(files, ",")) {
+			line = "| " + strings.TrimSpace(match[1]) + " | " + strings.Join(kept, ", ") + " |"
+		}
+		out = append(out, line)
+		tableEnd = len(out)
+	}
+
+	text := strings.Join(out, "\n")
+	var rows []string
+	for _, index := range indices {
+		if !strings.Contains(text, index.Name) {
+			rows = append(rows, "| "+strings.Join(keywordSplitPattern.Split(strings.ToLower(index.Domain), -1), " ")+" | "+index.Name+" |")
+			change.Added = append(change.Added, index.Name)
+		}
+	}
+	if len(rows) > 0 {
+		if tableEnd < 0 {
+			out = append(strings.Split(strings.TrimRight(text, "\n"), "\n"), "", "| Keywords | File |", "|----------|------|")
+			tableEnd = len(out)
+			out = append(out, "")
+		}
+		out = append(out[:tableEnd], append(rows, out[tableEnd:]...)...)
+	}
+	change.Content = strings.Join(out, "\n")
+	return change, nil
+}
+
+// isPlannedIndex reports whether name is a domain index about to be created.
+func isPlannedIndex(name string, indices []DomainIndex) bool {
+	for _, index := range indices {
+		if index.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package internal_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+// driftedMemories writes a memories directory whose auth index has a title,
+// a dead reference, a duplicate, a skill- entry, an entry from another
+// domain and shared keywords, with an orphan left unindexed.
+func driftedMemories(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "skills-auth-index.md"), `# Auth Index
+
+| Keywords | File |
+|----------|------|
+| login session token | auth-login |
+| login session token | auth-login |
+| login session token | auth-logout |
+| gone | auth-deleted |
+| legacy | skill-auth-old |
+| commit | git-commit-style |
+`)
+	writeTestFile(t, filepath.Join(dir, "skills-git-index.md"), "| Keywords | File |\n|----------|------|\n| branch naming rules prefix kebab | git-branch-naming |\n")
+	writeTestFile(t, filepath.Join(dir, "memory-index.md"), "| Task | File |\n|------|------|\n| auth | skills-auth-index, auth-removed |\n")
+
+	writeTestFile(t, filepath.Join(dir, "auth-login.md"), "# Login flow\n\n## Password grant\n")
+	writeTestFile(t, filepath.Join(dir, "auth-logout.md"), "# Logout\n\n## Revoking refresh tokens\n")
+	writeTestFile(t, filepath.Join(dir, "auth-mfa.md"), "---\ntitle: Multi factor\ntags: [totp, webauthn]\n---\n# Enrolment\n")
+	writeTestFile(t, filepath.Join(dir, "skill-auth-old.md"), "# Old\n")
+	writeTestFile(t, filepath.Join(dir, "git-commit-style.md"), "# Conventional commits\n")
+	writeTestFile(t, filepath.Join(dir, "git-branch-naming.md"), "# Branch naming\n")
+	return dir
+}
+
+func findChange(t *testing.T, result internal.MemoryReindexResult, name string) internal.MemoryIndexChange {
+	t.Helper()
+	for _, c := range result.Changes {
+		if filepath.Base(c.Path) == name {
+			return c
+		}
+	}
+	t.Fatalf("no change for %s in %+v", name, result.Changes)
+	return internal.MemoryIndexChange{}
+}
+
+func TestReindexMemories(t *testing.T) {
+	dir := driftedMemories(t)
+	if internal.ValidateMemoryIndex(dir).Valid {
+		t.Fatal("fixture should fail validation before reindexing")
+	}
+
+	result, err := internal.ReindexMemories(dir, internal.MemoryReindexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := findChange(t, result, "skills-auth-index.md")
+	if strings.Join(auth.Added, ",") != "auth-mfa" ||
+		strings.Join(auth.Removed, ",") != "auth-deleted" ||
+		strings.Join(auth.Deduplicated, ",") != "auth-login" ||
+		strings.Join(auth.Dropped, ",") != "skill-auth-old" {
+		t.Errorf("auth change = %+v", auth)
+	}
+	git := findChange(t, result, "skills-git-index.md")
+	if len(git.Moved) != 1 || !strings.HasPrefix(git.Moved[0], "git-commit-style") {
+		t.Errorf("git change = %+v", git)
+	}
+	if mem := findChange(t, result, "memory-index.md"); strings.Join(mem.Removed, ",") != "auth-removed" ||
+		strings.Join(mem.Added, ",") != "skills-git-index" {
+		t.Errorf("memory-index change = %+v", mem)
+	}
+	if strings.HasPrefix(auth.Content, "#") || !strings.Contains(auth.Content, "totp webauthn") {
+		t.Errorf("auth index:\n%s", auth.Content)
+	}
+
+	validation := internal.ValidateMemoryIndex(dir)
+	if !validation.Valid {
+		t.Fatalf("reindexed memories fail validation: %s\n%s", validation.Message, validation.Remediation)
+	}
+
+	// Reindexing again changes nothing.
+	again, err := internal.ReindexMemories(dir, internal.MemoryReindexOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := again.Changed(); len(changed) != 0 {
+		t.Errorf("second pass changed %d files:\n%s", len(changed), changed[0].Diff)
+	}
+}
+
+func TestReindexMemories_DryRun(t *testing.T) {
+	dir := driftedMemories(t)
+	before, _ := os.ReadFile(filepath.Join(dir, "skills-auth-index.md"))
+
+	result, err := internal.ReindexMemories(dir, internal.MemoryReindexOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, "skills-auth-index.md"))
+	if string(after) != string(before) {
+		t.Error("dry run wrote the index")
+	}
+	if diff := findChange(t, result, "skills-auth-index.md").Diff; !strings.Contains(diff, "-# Auth Index") {
+		t.Errorf("diff:\n%s", diff)
+	}
+}
+
+func TestReindexMemories_NewDomain(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "ci-runners.md"), "# Self-hosted runners\n\n## Labels\n")
+	writeTestFile(t, filepath.Join(dir, "ci-caching.md"), "# Dependency caching\n")
+
+	result, err := internal.ReindexMemories(dir, internal.MemoryReindexOptions{Domain: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ci := findChange(t, result, "skills-ci-index.md")
+	if !ci.Created || len(ci.Added) != 2 {
+		t.Errorf("ci change = %+v", ci)
+	}
+	if !findChange(t, result, "memory-index.md").Created {
+		t.Error("memory-index.md should be created")
+	}
+	if v := internal.ValidateMemoryIndex(dir); !v.Valid {
+		t.Errorf("new domain fails validation: %s", v.Message)
+	}
+}
+
+func TestReindexMemories_MissingPath(t *testing.T) {
+	if _, err := internal.ReindexMemories(filepath.Join(t.TempDir(), "nope"), internal.MemoryReindexOptions{}); err == nil {
+		t.Error("expected an error for a missing memories directory")
+	}
+}
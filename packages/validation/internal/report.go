@@ -0,0 +1,724 @@
+package internal
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finding severities. They match SARIF result levels.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+// Report output formats accepted by FormatReport.
+const (
+	ReportFormatConsole  = "console"
+	ReportFormatMarkdown = "markdown"
+	ReportFormatJSON     = "json"
+	ReportFormatSARIF    = "sarif"
+	ReportFormatJUnit    = "junit"
+)
+
+// ReportFormats lists the formats FormatReport accepts.
+var ReportFormats = []string{
+	ReportFormatConsole,
+	ReportFormatMarkdown,
+	ReportFormatJSON,
+	ReportFormatSARIF,
+	ReportFormatJUnit,
+}
+
+// Finding is one check outcome in a report, with the file and line it
+// concerns when the validator knows them.
+type Finding struct {
+	Check    string `json:"check"`
+	Passed   bool   `json:"passed"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// Reportable is implemented by every validator result. ValidationResult
+// provides both methods to the results that embed it; results that know
+// file locations override Findings.
+type Reportable interface {
+	Outcome() ValidationResult
+	Findings() []Finding
+}
+
+// Outcome returns the shared part of a validator result.
+func (r ValidationResult) Outcome() ValidationResult {
+	return r
+}
+
+// Findings converts each check into a finding: failed checks are errors,
+// passed checks are notes.
+func (r ValidationResult) Findings() []Finding {
+	if len(r.Checks) == 0 {
+		return []Finding{checkFinding(Check{Name: "result", Passed: r.Valid, Message: r.Message})}
+	}
+	findings := make([]Finding, 0, len(r.Checks))
+	for _, c := range r.Checks {
+		findings = append(findings, checkFinding(c))
+	}
+	return findings
+}
+
+func checkFinding(c Check) Finding {
+	severity := SeverityNote
+	if !c.Passed {
+		severity = SeverityError
+	}
+	return Finding{Check: c.Name, Passed: c.Passed, Severity: severity, Message: c.Message}
+}
+
+// withFile sets File on findings that have no location yet.
+func withFile(findings []Finding, file string) []Finding {
+	for i := range findings {
+		if findings[i].File == "" {
+			findings[i].File = file
+		}
+	}
+	return findings
+}
+
+// Findings reports each skill check against the skill file.
+func (r SkillFormatValidationResult) Findings() []Finding {
+	return withFile(r.ValidationResult.Findings(), r.FilePath)
+}
+
+// Findings reports each slash command check against the command file.
+func (r SlashCommandValidationResult) Findings() []Finding {
+	return withFile(r.ValidationResult.Findings(), r.FilePath)
+}
+
+// Findings reports each raw command at the line and column it appears on.
+// Violations of warn rules are passing warnings.
+func (r SkillViolationResult) Findings() []Finding {
+	if len(r.Violations) == 0 {
+		return r.ValidationResult.Findings()
+	}
+	findings := make([]Finding, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		f := Finding{
+			Check:    "skill_violation",
+			Severity: SeverityError,
+			Message:  "Raw command matches '" + v.Pattern + "'; use the GitHub skill scripts instead",
+			File:     v.File,
+			Line:     v.Line,
+			Column:   v.Column,
+		}
+		if v.Skill != "" {
+			f.Message = "Raw command '" + v.Match + "' matches rule " + v.Rule + "; use the " + v.Skill + " skill instead"
+		}
+		if v.Message != "" {
+			f.Message += ": " + v.Message
+		}
+		if v.Severity == CheckSeverityWarn {
+			f.Passed, f.Severity = true, SeverityWarning
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Findings reports each check. Parameter drift and the problems found at a
+// line are reported there; those of a passing check (such as
+// continue-on-error in CI) as warnings.
+func (r PrePRValidationResult) Findings() []Finding {
+	locatedFindings := append([]PrePRFinding(nil), r.Located...)
+	for _, d := range r.TestImplementation.Drift {
+		locatedFindings = append(locatedFindings, PrePRFinding{Check: "parameter_drift", File: d.File, Line: d.Line, Message: d.Message})
+	}
+	if len(locatedFindings) == 0 {
+		return r.ValidationResult.Findings()
+	}
+	located := make(map[string]bool, len(locatedFindings))
+	for _, f := range locatedFindings {
+		located[f.issue()] = true
+	}
+	failed := map[string]bool{}
+	var findings []Finding
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failed[c.Name] = true
+		}
+		if !located[c.Message] {
+			findings = append(findings, checkFinding(c))
+		}
+	}
+	for _, f := range locatedFindings {
+		finding := Finding{Check: f.Check, Passed: true, Severity: SeverityWarning, Message: f.Message, File: f.File, Line: f.Line}
+		if failed[f.Check] {
+			finding.Passed, finding.Severity = false, SeverityError
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Findings reports errors, warnings (failures only in strict mode) and info
+// against the spec file each issue is about.
+func (r TraceabilityValidationResult) Findings() []Finding {
+	var findings []Finding
+	add := func(issues []TraceabilityIssue, severity string, passed bool) {
+		for _, issue := range issues {
+			f := Finding{Check: issue.Rule, Passed: passed, Severity: severity, Message: issue.Message, File: issue.File}
+			if f.File != "" {
+				f.Line = 1 // the frontmatter holds the references
+			}
+			findings = append(findings, f)
+		}
+	}
+	add(r.Errors, SeverityError, false)
+	add(r.Warnings, SeverityWarning, !r.Strict)
+	add(r.Info, SeverityNote, true)
+	if len(findings) == 0 {
+		return r.ValidationResult.Findings()
+	}
+	return findings
+}
+
+// Findings adds a finding per source file without tests, or with a coverage
+// profile per file below the threshold. They fail only when coverage is
+// below the threshold.
+func (r TestCoverageGapResult) Findings() []Finding {
+	findings := r.ValidationResult.Findings()
+	severity := SeverityWarning
+	if !r.Valid {
+		severity = SeverityError
+	}
+	if r.CoverageProfile != "" {
+		for _, f := range r.Files {
+			if !f.BelowThreshold {
+				continue
+			}
+			finding := Finding{
+				Check:    "line_coverage",
+				Passed:   r.Valid,
+				Severity: severity,
+				Message:  "Line coverage " + formatCoveragePercent(f.Percent) + " below threshold " + formatCoveragePercent(r.Threshold),
+				File:     f.File,
+			}
+			if f.Uncovered != "" {
+				finding.Message += "; uncovered lines " + f.Uncovered
+				fmt.Sscanf(f.Uncovered, "%d", &finding.Line) // the first uncovered line
+			}
+			findings = append(findings, finding)
+		}
+		return findings
+	}
+	for _, m := range r.MissingTests {
+		findings = append(findings, Finding{
+			Check:    "missing_test",
+			Passed:   r.Valid,
+			Severity: severity,
+			Message:  "No test file; expected " + m.ExpectedTest,
+			File:     m.SourceFile,
+		})
+	}
+	return findings
+}
+
+// Findings adds a finding per mismatched file: critical issues are errors,
+// the rest warnings.
+func (r PRDescriptionValidationResult) Findings() []Finding {
+	findings := r.ValidationResult.Findings()
+	for _, issue := range r.Issues {
+		f := Finding{Check: issue.Type, Passed: true, Severity: SeverityWarning, Message: issue.Message, File: issue.File}
+		if issue.Severity == "CRITICAL" {
+			f.Passed = false
+			f.Severity = SeverityError
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Findings adds a finding per malformed line of each domain index.
+func (r MemoryIndexValidationResult) Findings() []Finding {
+	findings := r.ValidationResult.Findings()
+	domains := make([]string, 0, len(r.DomainResults))
+	for domain := range r.DomainResults {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		d := r.DomainResults[domain]
+		for _, line := range d.IndexFormat.ViolationLines {
+			findings = append(findings, Finding{
+				Check:    "index_format",
+				Severity: SeverityError,
+				Message:  "Index must be a pure lookup table",
+				File:     d.IndexPath,
+				Line:     line,
+			})
+		}
+	}
+	return findings
+}
+
+// ValidatorReport is the outcome of one validator run.
+type ValidatorReport struct {
+	Validator   string    `json:"validator"`
+	Target      string    `json:"target,omitempty"`  // file or feature, when a validator runs per item
+	Skipped     string    `json:"skipped,omitempty"` // why the validator did not run
+	Valid       bool      `json:"valid"`
+	Message     string    `json:"message,omitempty"`
+	Remediation string    `json:"remediation,omitempty"`
+	DurationMS  float64   `json:"durationMs"`
+	Findings    []Finding `json:"findings,omitempty"`
+	Suppressed  []Finding `json:"suppressed,omitempty"` // failures silenced by inline suppressions
+	Result      any       `json:"result,omitempty"`     // the validator's full result
+}
+
+// NewValidatorReport converts a validator result into a report entry.
+func NewValidatorReport(validator, target string, r Reportable) ValidatorReport {
+	outcome := r.Outcome()
+	return ValidatorReport{
+		Validator:   validator,
+		Target:      target,
+		Valid:       outcome.Valid,
+		Message:     outcome.Message,
+		Remediation: outcome.Remediation,
+		Findings:    r.Findings(),
+		Result:      r,
+	}
+}
+
+// SkippedValidatorReport records a validator that did not apply.
+func SkippedValidatorReport(validator, reason string) ValidatorReport {
+	return ValidatorReport{Validator: validator, Skipped: reason, Valid: true}
+}
+
+// Name returns the validator name with its target, if any.
+func (v ValidatorReport) Name() string {
+	if v.Target == "" {
+		return v.Validator
+	}
+	return v.Validator + " " + v.Target
+}
+
+// Report merges the results of several validators.
+type Report struct {
+	Root       string            `json:"root,omitempty"` // relative finding paths are relative to Root
+	Valid      bool              `json:"valid"`
+	Validators []ValidatorReport `json:"validators"`
+}
+
+// NewReport returns an empty, valid report for root.
+func NewReport(root string) *Report {
+	return &Report{Root: root, Valid: true, Validators: []ValidatorReport{}}
+}
+
+// Add appends a validator report; any invalid entry makes the report invalid.
+func (r *Report) Add(v ValidatorReport) {
+	r.Validators = append(r.Validators, v)
+	r.Valid = r.Valid && v.Valid
+}
+
+// Merge appends every validator report in other.
+func (r *Report) Merge(other *Report) {
+	for _, v := range other.Validators {
+		r.Add(v)
+	}
+}
+
+// Counts returns how many validator runs passed, failed and were skipped.
+func (r *Report) Counts() (passed, failed, skipped int) {
+	for _, v := range r.Validators {
+		switch {
+		case v.Skipped != "":
+			skipped++
+		case v.Valid:
+			passed++
+		default:
+			failed++
+		}
+	}
+	return passed, failed, skipped
+}
+
+// FormatReport renders the report in one of ReportFormats.
+func FormatReport(report *Report, format string) (string, error) {
+	switch format {
+	case ReportFormatConsole:
+		return formatReportConsole(report), nil
+	case ReportFormatMarkdown:
+		return formatReportMarkdown(report), nil
+	case ReportFormatJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		return string(data) + "\n", err
+	case ReportFormatSARIF:
+		return formatReportSARIF(report)
+	case ReportFormatJUnit:
+		return formatReportJUnit(report)
+	default:
+		return "", fmt.Errorf("unknown report format %q (want %s)", format, strings.Join(ReportFormats, ", "))
+	}
+}
+
+// location renders file:line[:column] for console and markdown output.
+func (f Finding) location() string {
+	if f.File == "" {
+		return ""
+	}
+	if f.Line > 0 && f.Column > 0 {
+		return f.File + ":" + Itoa(f.Line) + ":" + Itoa(f.Column)
+	}
+	if f.Line > 0 {
+		return f.File + ":" + Itoa(f.Line)
+	}
+	return f.File
+}
+
+func formatReportConsole(report *Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("Validation Report\n")
+	sb.WriteString("=================\n\n")
+
+	for _, v := range report.Validators {
+		if v.Skipped != "" {
+			sb.WriteString("[SKIP] " + v.Name() + ": " + v.Skipped + "\n\n")
+			continue
+		}
+		status := "[PASS]"
+		if !v.Valid {
+			status = "[FAIL]"
+		}
+		sb.WriteString(status + " " + v.Name())
+		if v.Message != "" {
+			sb.WriteString(": " + v.Message)
+		}
+		sb.WriteString(fmt.Sprintf(" (%.0fms)\n", v.DurationMS))
+
+		// The traceability validator has its own console report.
+		if tr, ok := v.Result.(TraceabilityValidationResult); ok {
+			sb.WriteString(indent(FormatTraceabilityResults(tr, ReportFormatConsole), "  "))
+			sb.WriteString("\n")
+			continue
+		}
+		for _, f := range v.Findings {
+			if f.Passed && f.Severity != SeverityWarning {
+				continue
+			}
+			tag := "[FAIL]"
+			if f.Passed {
+				tag = "[WARN]"
+			}
+			sb.WriteString("  " + tag + " " + f.Check + ": " + f.Message)
+			if loc := f.location(); loc != "" {
+				sb.WriteString(" (" + loc + ")")
+			}
+			sb.WriteString("\n")
+		}
+		if !v.Valid && v.Remediation != "" {
+			sb.WriteString(indent(v.Remediation, "  "))
+		}
+		sb.WriteString("\n")
+	}
+
+	passed, failed, skipped := report.Counts()
+	sb.WriteString("Summary: " + Itoa(passed) + " passed, " + Itoa(failed) + " failed, " + Itoa(skipped) + " skipped\n")
+	return sb.String()
+}
+
+func formatReportMarkdown(report *Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Validation Report\n\n")
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString("| Validator | Status | Failed Checks |\n")
+	sb.WriteString("|-----------|--------|---------------|\n")
+	for _, v := range report.Validators {
+		status := "PASS"
+		switch {
+		case v.Skipped != "":
+			status = "SKIP"
+		case !v.Valid:
+			status = "FAIL"
+		}
+		sb.WriteString("| " + v.Name() + " | " + status + " | " + Itoa(failedFindings(v)) + " |\n")
+	}
+	sb.WriteString("\n")
+
+	for _, v := range report.Validators {
+		if v.Skipped != "" || (v.Valid && failedFindings(v) == 0) {
+			continue
+		}
+		if tr, ok := v.Result.(TraceabilityValidationResult); ok {
+			// Nest the traceability report under the summary.
+			sb.WriteString(demoteHeadings(FormatTraceabilityResults(tr, ReportFormatMarkdown)))
+			continue
+		}
+		sb.WriteString("## " + v.Name() + "\n\n")
+		if v.Message != "" {
+			sb.WriteString(v.Message + "\n\n")
+		}
+		for _, f := range v.Findings {
+			if f.Passed {
+				continue
+			}
+			sb.WriteString("- **" + f.Check + "**: " + f.Message)
+			if loc := f.location(); loc != "" {
+				sb.WriteString(" (`" + loc + "`)")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func failedFindings(v ValidatorReport) int {
+	n := 0
+	for _, f := range v.Findings {
+		if !f.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// demoteHeadings moves every markdown heading in s down one level.
+func demoteHeadings(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func indent(s, prefix string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		if line != "" {
+			sb.WriteString(prefix)
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifSrcRoot is the uriBaseId relative locations are resolved against.
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// formatReportSARIF emits failed findings and warnings as SARIF results, one
+// rule per validator check.
+func formatReportSARIF(report *Report) (string, error) {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "brain-validate", Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	rules := map[string]bool{}
+	for _, v := range report.Validators {
+		for _, f := range v.Findings {
+			if f.Passed && f.Severity != SeverityWarning {
+				continue
+			}
+			ruleID := v.Validator + "/" + f.Check
+			if !rules[ruleID] {
+				rules[ruleID] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+					ID:               ruleID,
+					ShortDescription: sarifMessage{Text: v.Validator + ": " + f.Check},
+				})
+			}
+			result := sarifResult{
+				RuleID:  ruleID,
+				Level:   f.Severity,
+				Message: sarifMessage{Text: f.Message},
+			}
+			if f.File != "" {
+				result.Locations = []sarifLocation{sarifFileLocation(report.Root, f)}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// sarifFileLocation makes f's path relative to root where possible, so code
+// scanning can match it to repository files.
+func sarifFileLocation(root string, f Finding) sarifLocation {
+	uri := f.File
+	base := sarifSrcRoot
+	if filepath.IsAbs(uri) {
+		base = ""
+		if root != "" {
+			if absRoot, err := filepath.Abs(root); err == nil {
+				if rel, err := filepath.Rel(absRoot, uri); err == nil && !strings.HasPrefix(rel, "..") {
+					uri, base = rel, sarifSrcRoot
+				}
+			}
+		}
+	}
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(uri), URIBaseID: base},
+	}}
+	if f.Line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+	}
+	return loc
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// formatReportJUnit emits one test suite per validator run and one test case
+// per finding, so each check shows up in CI test dashboards.
+func formatReportJUnit(report *Report) (string, error) {
+	suites := junitTestSuites{Name: "brain validate"}
+	var total float64
+	for _, v := range report.Validators {
+		suite := junitTestSuite{Name: v.Name(), Time: junitTime(v.DurationMS)}
+		total += v.DurationMS
+		if v.Skipped != "" {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      v.Validator,
+				Classname: v.Validator,
+				Skipped:   &junitSkipped{Message: v.Skipped},
+			})
+			suite.Skipped++
+		}
+		for _, f := range v.Findings {
+			tc := junitTestCase{Name: f.Check, Classname: v.Validator, File: f.File, Line: f.Line}
+			if !f.Passed {
+				text := f.Message
+				if loc := f.location(); loc != "" {
+					text += "\n" + loc
+				}
+				tc.Failure = &junitFailure{Message: f.Message, Type: f.Severity, Text: text}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = junitTime(total)
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+func junitTime(ms float64) string {
+	return fmt.Sprintf("%.3f", ms/1000)
+}
@@ -0,0 +1,215 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+func sampleReport(root string) *internal.Report {
+	report := internal.NewReport(root)
+	report.Add(internal.NewValidatorReport("skills", "a/SKILL.md", internal.SkillFormatValidationResult{
+		ValidationResult: internal.ValidationResult{
+			Valid:   false,
+			Message: "1 check failed",
+			Checks: []internal.Check{
+				{Name: "frontmatter", Passed: true, Message: "present"},
+				{Name: "name", Passed: false, Message: "name is required"},
+			},
+		},
+		FilePath: filepath.Join(root, "a", "SKILL.md"),
+	}))
+	report.Add(internal.NewValidatorReport("skill-violations", "", internal.SkillViolationResult{
+		ValidationResult: internal.ValidationResult{Valid: false, Message: "1 violation"},
+		Violations:       []internal.SkillViolation{{File: "scripts/run.sh", Line: 7, Pattern: "gh pr create"}},
+	}))
+	report.Add(internal.SkippedValidatorReport("test-coverage", "not a git repository"))
+	return report
+}
+
+func TestValidationResult_Findings(t *testing.T) {
+	r := internal.ValidationResult{Checks: []internal.Check{
+		{Name: "a", Passed: true},
+		{Name: "b", Passed: false, Message: "broken"},
+	}}
+	findings := r.Findings()
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	if findings[0].Severity != internal.SeverityNote || findings[1].Severity != internal.SeverityError {
+		t.Errorf("severities = %s, %s", findings[0].Severity, findings[1].Severity)
+	}
+
+	// A result without checks still yields one finding.
+	findings = internal.ValidationResult{Valid: false, Message: "bad"}.Findings()
+	if len(findings) != 1 || findings[0].Passed || findings[0].Message != "bad" {
+		t.Errorf("findings = %+v", findings)
+	}
+}
+
+func TestTraceabilityFindings_StrictWarnings(t *testing.T) {
+	result := internal.TraceabilityValidationResult{
+		Warnings: []internal.TraceabilityIssue{{Rule: "untraced_task", Source: "TASK-001", Message: "no design", File: "tasks/TASK-001.md"}},
+	}
+	f := result.Findings()[0]
+	if !f.Passed || f.Severity != internal.SeverityWarning || f.File != "tasks/TASK-001.md" || f.Line != 1 {
+		t.Errorf("non-strict warning finding = %+v", f)
+	}
+
+	result.Strict = true
+	if f := result.Findings()[0]; f.Passed {
+		t.Errorf("strict warning should fail: %+v", f)
+	}
+}
+
+func TestReport_ValidAndCounts(t *testing.T) {
+	report := sampleReport(t.TempDir())
+	if report.Valid {
+		t.Error("report with failed validators should be invalid")
+	}
+	passed, failed, skipped := report.Counts()
+	if passed != 0 || failed != 2 || skipped != 1 {
+		t.Errorf("counts = %d/%d/%d, want 0/2/1", passed, failed, skipped)
+	}
+}
+
+func TestFormatReport_Console(t *testing.T) {
+	out, err := internal.FormatReport(sampleReport("/repo"), internal.ReportFormatConsole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"[FAIL] skills a/SKILL.md: 1 check failed",
+		"name: name is required (/repo/a/SKILL.md)",
+		"(scripts/run.sh:7)",
+		"[SKIP] test-coverage: not a git repository",
+		"Summary: 0 passed, 2 failed, 1 skipped",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("console output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatReport_IncludesTraceabilityReport(t *testing.T) {
+	report := internal.NewReport("")
+	report.Add(internal.NewValidatorReport("traceability", "", internal.TraceabilityValidationResult{
+		ValidationResult: internal.ValidationResult{Valid: false},
+		Errors:           []internal.TraceabilityIssue{{Rule: "broken_reference", Message: "REQ-9 not found"}},
+	}))
+
+	out, _ := internal.FormatReport(report, internal.ReportFormatConsole)
+	if !strings.Contains(out, "Traceability Validation Report") || !strings.Contains(out, "[broken_reference] REQ-9 not found") {
+		t.Errorf("console output lacks traceability report:\n%s", out)
+	}
+	out, _ = internal.FormatReport(report, internal.ReportFormatMarkdown)
+	if !strings.Contains(out, "## Traceability Validation Report") {
+		t.Errorf("markdown output lacks nested traceability report:\n%s", out)
+	}
+}
+
+func TestFormatReport_SARIF(t *testing.T) {
+	root := t.TempDir()
+	out, err := internal.FormatReport(sampleReport(root), internal.ReportFormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI       string `json:"uri"`
+							URIBaseID string `json:"uriBaseId"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, out)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version %q, %d runs", log.Version, len(log.Runs))
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want only the 2 failures: %+v", len(results), results)
+	}
+	if len(log.Runs[0].Tool.Driver.Rules) != 2 {
+		t.Errorf("rules = %+v", log.Runs[0].Tool.Driver.Rules)
+	}
+
+	skill := results[0]
+	if skill.RuleID != "skills/name" || skill.Level != "error" {
+		t.Errorf("skill result = %+v", skill)
+	}
+	loc := skill.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "a/SKILL.md" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" || loc.Region != nil {
+		t.Errorf("absolute path not made root-relative: %+v", loc)
+	}
+
+	violation := results[1].Locations[0].PhysicalLocation
+	if violation.ArtifactLocation.URI != "scripts/run.sh" || violation.Region == nil || violation.Region.StartLine != 7 {
+		t.Errorf("violation location = %+v", violation)
+	}
+}
+
+func TestFormatReport_JUnit(t *testing.T) {
+	out, err := internal.FormatReport(sampleReport("/repo"), internal.ReportFormatJUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name    string    `xml:"name,attr"`
+				Failure *struct{} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, out)
+	}
+	if suites.Tests != 4 || suites.Failures != 2 || suites.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped; want 4, 2, 1", suites.Tests, suites.Failures, suites.Skipped)
+	}
+	if len(suites.Suites) != 3 || suites.Suites[0].Name != "skills a/SKILL.md" {
+		t.Fatalf("suites = %+v", suites.Suites)
+	}
+	cases := suites.Suites[0].Cases
+	if len(cases) != 2 || cases[0].Failure != nil || cases[1].Failure == nil {
+		t.Errorf("skills test cases = %+v", cases)
+	}
+}
+
+func TestFormatReport_UnknownFormat(t *testing.T) {
+	if _, err := internal.FormatReport(internal.NewReport(""), "html"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
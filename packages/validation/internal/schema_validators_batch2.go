@@ -179,6 +179,12 @@ func ValidateTestCoverageGapsOptions(opts TestCoverageGapOptions) []ValidationEr
 		}
 		optsMap["customPatterns"] = patterns
 	}
+	if opts.CoverageProfile != "" {
+		optsMap["coverageProfile"] = opts.CoverageProfile
+	}
+	if opts.ProfileFormat != "" {
+		optsMap["profileFormat"] = opts.ProfileFormat
+	}
 
 	data := map[string]any{
 		"options": optsMap,
@@ -0,0 +1,244 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SkillRulesPath is where the repository skill violation rules live,
+// relative to the repository root.
+const SkillRulesPath = ".brain/skill-rules.yaml"
+
+// DefaultSkillsDir holds one directory per skill, relative to the repository
+// root.
+const DefaultSkillsDir = ".claude/skills"
+
+// SkillRule maps a raw command pattern to the skill that should be used
+// instead.
+type SkillRule struct {
+	ID        string   `json:"id"`
+	Pattern   string   `json:"pattern"`
+	Skill     string   `json:"skill"`
+	Severity  string   `json:"severity,omitempty"` // error or warn (empty = warn)
+	Message   string   `json:"message,omitempty"`
+	Languages []string `json:"languages,omitempty"` // empty = any code
+
+	re *regexp.Regexp
+}
+
+// SkillRuleSet is the skill violation rule file (.brain/skill-rules.yaml).
+type SkillRuleSet struct {
+	Version   int         `json:"version,omitempty"`
+	SkillsDir string      `json:"skillsDir,omitempty"`
+	Defaults  *bool       `json:"defaults,omitempty"`
+	Rules     []SkillRule `json:"rules,omitempty"`
+
+	// Path is the file the rules were loaded from; empty for defaults.
+	Path string `json:"-"`
+}
+
+// skillRulesSchema checks .brain/skill-rules.yaml.
+var skillRulesSchema = &yamlSchema{
+	name:   "skill-rules.schema.json",
+	label:  "skill rules",
+	setter: "SetSkillRulesSchemaData",
+}
+
+// SetSkillRulesSchemaData sets the schema data for skill rule files.
+func SetSkillRulesSchemaData(data []byte) {
+	skillRulesSchema.data = data
+}
+
+// BuiltinSkillRules are the rules for raw gh commands, one per pattern in
+// GhCommandPatterns.
+func BuiltinSkillRules() []SkillRule {
+	ids := []string{"gh-pr", "gh-issue", "gh-api", "gh-repo"}
+	rules := make([]SkillRule, 0, len(GhCommandPatterns))
+	for i, re := range GhCommandPatterns {
+		id := "gh-" + Itoa(i+1)
+		if i < len(ids) {
+			id = ids[i]
+		}
+		rules = append(rules, SkillRule{
+			ID:       id,
+			Pattern:  re.String(),
+			Skill:    "github",
+			Severity: CheckSeverityWarn,
+			Message:  "Use the GitHub skill scripts instead",
+			re:       re,
+		})
+	}
+	return rules
+}
+
+// DefaultSkillRules returns the rules used when a repository has no
+// .brain/skill-rules.yaml.
+func DefaultSkillRules() *SkillRuleSet {
+	return &SkillRuleSet{Version: 1, Rules: BuiltinSkillRules()}
+}
+
+// LoadSkillRules reads .brain/skill-rules.yaml from root. A missing file
+// yields DefaultSkillRules; an invalid one is an error.
+func LoadSkillRules(root string) (*SkillRuleSet, error) {
+	return LoadSkillRulesFile(filepath.Join(root, SkillRulesPath), true)
+}
+
+// LoadSkillRulesFile reads a skill rule file. When optional is set, a missing
+// file yields DefaultSkillRules.
+func LoadSkillRulesFile(path string, optional bool) (*SkillRuleSet, error) {
+	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, os.ErrNotExist) {
+		return DefaultSkillRules(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ParseSkillRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rules.Path = path
+	return rules, nil
+}
+
+// ParseSkillRules validates YAML rule data against the schema and parses
+// it. Unless the file sets defaults: false, the built-in rules come first,
+// replaced by file rules with the same id.
+func ParseSkillRules(data []byte) (*SkillRuleSet, error) {
+	file := &SkillRuleSet{Version: 1}
+	if err := decodeYAMLWithSchema(data, skillRulesSchema.compile, file); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(file.Rules))
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("rule %q: duplicate id", rule.ID)
+		}
+		seen[rule.ID] = true
+		var err error
+		if rule.re, err = regexp.Compile(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("rule %q: pattern: %w", rule.ID, err)
+		}
+		for j, lang := range rule.Languages {
+			rule.Languages[j] = normalizeCodeLanguage(lang)
+		}
+	}
+
+	if file.Defaults != nil && !*file.Defaults {
+		return file, nil
+	}
+	var rules []SkillRule
+	for _, builtin := range BuiltinSkillRules() {
+		if !seen[builtin.ID] {
+			rules = append(rules, builtin)
+		}
+	}
+	file.Rules = append(rules, file.Rules...)
+	return file, nil
+}
+
+// skillsDir returns the absolute skills directory under repoRoot.
+func (s *SkillRuleSet) skillsDir(repoRoot string) string {
+	dir := s.SkillsDir
+	if dir == "" {
+		dir = DefaultSkillsDir
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(repoRoot, filepath.FromSlash(dir))
+}
+
+// activeRules returns the rules whose skill exists under repoRoot.
+func (s *SkillRuleSet) activeRules(repoRoot string) []SkillRule {
+	dir := s.skillsDir(repoRoot)
+	var active []SkillRule
+	for _, rule := range s.Rules {
+		if DirExists(filepath.Join(dir, rule.Skill)) {
+			active = append(active, rule)
+		}
+	}
+	return active
+}
+
+// appliesTo reports whether the rule checks code of the given language; ""
+// is inline code or a command in markdown text.
+func (r SkillRule) appliesTo(language string) bool {
+	if len(r.Languages) == 0 {
+		return true
+	}
+	for _, lang := range r.Languages {
+		if lang == language {
+			return true
+		}
+	}
+	return false
+}
+
+// compiled returns the compiled pattern, compiling rules built in Go code.
+func (r SkillRule) compiled() *regexp.Regexp {
+	if r.re != nil {
+		return r.re
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// codeLanguageAliases maps fence info strings and file extensions to the
+// language names rules use.
+var codeLanguageAliases = map[string]string{
+	"sh":            "shell",
+	"bash":          "shell",
+	"zsh":           "shell",
+	"console":       "shell",
+	"shell-session": "shell",
+	"shellsession":  "shell",
+	"ps":            "powershell",
+	"ps1":           "powershell",
+	"psm1":          "powershell",
+	"pwsh":          "powershell",
+	"posh":          "powershell",
+	"yml":           "yaml",
+	"js":            "javascript",
+	"ts":            "typescript",
+	"py":            "python",
+}
+
+// normalizeCodeLanguage lowercases a language name and resolves aliases.
+func normalizeCodeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if alias, ok := codeLanguageAliases[lang]; ok {
+		return alias
+	}
+	return lang
+}
+
+var (
+	powerShellCodePattern = regexp.MustCompile(`(?m)^\s*\$\w+\s*=|\b(Get|Set|New|Remove|Invoke|Write|Import|Test)-[A-Z]\w+`)
+	shellCodePattern      = regexp.MustCompile(`(?m)^#!.*\b(ba|z)?sh\b|^\s*\$ |^\s*(export|sudo|cd|echo)\s|&&|\|\|`)
+)
+
+// fenceLanguage returns the language of a fenced code block: its info string
+// or, when there is none, a guess from the block content.
+func fenceLanguage(info string, lines []string) string {
+	if fields := strings.Fields(strings.Trim(info, "{}.")); len(fields) > 0 {
+		return normalizeCodeLanguage(fields[0])
+	}
+	content := strings.Join(lines, "\n")
+	switch {
+	case powerShellCodePattern.MatchString(content):
+		return "powershell"
+	case shellCodePattern.MatchString(content):
+		return "shell"
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package internal_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+func init() {
+	// Load schema data for tests
+	_, currentFile, _, _ := runtime.Caller(0)
+	packageRoot := filepath.Dir(filepath.Dir(currentFile))
+	schemaPath := filepath.Join(packageRoot, "schemas", "config", "skill-rules.schema.json")
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		panic("failed to load skill rules schema for tests: " + err.Error())
+	}
+	internal.SetSkillRulesSchemaData(data)
+}
+
+func ruleIDs(rules *internal.SkillRuleSet) string {
+	var ids []string
+	for _, r := range rules.Rules {
+		ids = append(ids, r.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestParseSkillRules_MergesBuiltins(t *testing.T) {
+	rules, err := internal.ParseSkillRules([]byte(`
+version: 1
+rules:
+  - id: npm-publish
+    pattern: 'npm\s+publish'
+    skill: release
+    severity: error
+    languages: [bash]
+  - id: gh-api
+    pattern: 'gh\s+api\s+graphql'
+    skill: github
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ruleIDs(rules); got != "gh-pr,gh-issue,gh-repo,npm-publish,gh-api" {
+		t.Errorf("rules = %s", got)
+	}
+	if langs := rules.Rules[3].Languages; len(langs) != 1 || langs[0] != "shell" {
+		t.Errorf("languages = %v, want bash normalized to shell", langs)
+	}
+
+	rules, err = internal.ParseSkillRules([]byte("defaults: false\nrules:\n  - {id: git-push, pattern: 'git\\s+push', skill: git}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ruleIDs(rules); got != "git-push" {
+		t.Errorf("rules without defaults = %s", got)
+	}
+}
+
+func TestParseSkillRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown key", "colour: blue\n"},
+		{"missing skill", "rules:\n  - {id: a, pattern: x}\n"},
+		{"bad severity", "rules:\n  - {id: a, pattern: x, skill: s, severity: loud}\n"},
+		{"bad id", "rules:\n  - {id: Not Valid, pattern: x, skill: s}\n"},
+		{"duplicate id", "rules:\n  - {id: a, pattern: x, skill: s}\n  - {id: a, pattern: y, skill: s}\n"},
+		{"bad regexp", "rules:\n  - {id: a, pattern: '([', skill: s}\n"},
+		{"not YAML", "rules: [\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := internal.ParseSkillRules([]byte(tt.yaml)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadSkillRules_MissingFileIsDefault(t *testing.T) {
+	rules, err := internal.LoadSkillRules(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Path != "" || len(rules.Rules) != len(internal.GhCommandPatterns) {
+		t.Errorf("default rules = %+v", rules)
+	}
+	if _, err := internal.LoadSkillRulesFile(filepath.Join(t.TempDir(), "rules.yaml"), false); err == nil {
+		t.Error("a missing explicit rule file should be an error")
+	}
+}
+
+func TestDetectSkillViolations_RuleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+	writeTestFile(t, filepath.Join(tmpDir, internal.SkillRulesPath), `version: 1
+rules:
+  - id: npm-publish
+    pattern: 'npm\s+publish'
+    skill: release
+    severity: error
+    message: Publish with the release skill
+    languages: [shell]
+  - id: docker-push
+    pattern: 'docker\s+push'
+    skill: containers
+`)
+	writeTestFile(t, filepath.Join(tmpDir, ".claude", "skills", "release", "SKILL.md"), "---\nname: release\n---\n")
+	writeTestFile(t, filepath.Join(tmpDir, "RELEASING.md"), "# Releasing\n\n"+
+		"```bash\nnpm ci\n  npm publish --access public\n```\n\n"+
+		"```powershell\nnpm publish\n```\n\n"+
+		"```sh\ndocker push app\n```\n")
+	writeTestFile(t, filepath.Join(tmpDir, "scripts", "release.sh"), "#!/bin/sh\nnpm publish\n")
+
+	result := internal.DetectSkillViolations(tmpDir, false)
+
+	if result.Valid {
+		t.Error("error-severity violations should fail validation")
+	}
+	if len(result.Violations) != 2 {
+		t.Fatalf("violations = %+v, want the bash block and the script", result.Violations)
+	}
+	v := result.Violations[0]
+	if v.File != "RELEASING.md" || v.Line != 5 || v.Column != 3 || v.Rule != "npm-publish" ||
+		v.Skill != "release" || v.Language != "shell" || v.Severity != "error" || v.Match != "npm publish" {
+		t.Errorf("violation = %+v", v)
+	}
+	if v := result.Violations[1]; v.File != "scripts/release.sh" || v.Line != 2 {
+		t.Errorf("script violation = %+v", v)
+	}
+	if !strings.Contains(result.Remediation, "npm publish (consider adding to .claude/skills/release/)") {
+		t.Errorf("remediation = %s", result.Remediation)
+	}
+
+	findings := result.Findings()
+	if f := findings[0]; f.Passed || f.Severity != internal.SeverityError || f.Column != 3 ||
+		!strings.Contains(f.Message, "Publish with the release skill") {
+		t.Errorf("finding = %+v", f)
+	}
+}
+
+func TestDetectSkillViolations_InvalidRuleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+	writeTestFile(t, filepath.Join(tmpDir, internal.SkillRulesPath), "rules:\n  - {id: a}\n")
+
+	result := internal.DetectSkillViolations(tmpDir, false)
+
+	if result.Valid || !strings.HasPrefix(result.Message, "Invalid skill rules") {
+		t.Errorf("result = %+v", result.ValidationResult)
+	}
+}
+
+func TestDetectSkillViolations_StagedAddedLinesOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+	writeTestFile(t, filepath.Join(tmpDir, ".claude", "skills", "github", "scripts", "New-PR.ps1"), "")
+	doc := filepath.Join(tmpDir, "docs.md")
+	writeTestFile(t, doc, "# Docs\n\ngh pr list\n")
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-qm", "init"}} {
+		if out, err := exec.Command("git", append([]string{"-C", tmpDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeTestFile(t, doc, "# Docs\n\ngh pr list\n\nThen run:\n\n    gh issue create\n")
+	if out, err := exec.Command("git", "-C", tmpDir, "add", "docs.md").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	result := internal.DetectSkillViolations(tmpDir, true)
+
+	if len(result.Violations) != 1 || result.Violations[0].Line != 7 || result.Violations[0].Column != 5 {
+		t.Errorf("violations = %+v, want only the added line 7", result.Violations)
+	}
+}
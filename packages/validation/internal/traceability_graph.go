@@ -0,0 +1,411 @@
+package internal
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Traceability node kinds, in chain order.
+const (
+	TraceKindRequirement = "requirement"
+	TraceKindDesign      = "design"
+	TraceKindTask        = "task"
+	TraceKindCode        = "code"
+	TraceKindTest        = "test"
+)
+
+// Traceability graph output formats.
+const (
+	TraceabilityGraphMatrix  = "matrix"
+	TraceabilityGraphCSV     = "csv"
+	TraceabilityGraphMermaid = "mermaid"
+	TraceabilityGraphDOT     = "dot"
+)
+
+// TraceabilityGraphFormats lists the formats FormatTraceabilityGraph accepts.
+var TraceabilityGraphFormats = []string{
+	TraceabilityGraphMatrix,
+	TraceabilityGraphCSV,
+	TraceabilityGraphMermaid,
+	TraceabilityGraphDOT,
+}
+
+// TraceabilityGraph links requirements to the designs that address them,
+// designs to the tasks that implement them, and tasks to their code and
+// tests.
+type TraceabilityGraph struct {
+	Nodes []TraceNode `json:"nodes"`
+	Edges []TraceEdge `json:"edges"`
+}
+
+// TraceNode is a spec, or a code or test file a task declares.
+type TraceNode struct {
+	ID      string `json:"id"` // spec ID, or path relative to the repository root
+	Kind    string `json:"kind"`
+	Status  string `json:"status,omitempty"`
+	File    string `json:"file,omitempty"`    // spec file, for spec nodes
+	Missing bool   `json:"missing,omitempty"` // declared path matches nothing
+}
+
+// TraceEdge points downstream: REQ -> DESIGN -> TASK -> code and tests.
+type TraceEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+var traceKindOrder = map[string]int{
+	TraceKindRequirement: 0,
+	TraceKindDesign:      1,
+	TraceKindTask:        2,
+	TraceKindCode:        3,
+	TraceKindTest:        4,
+}
+
+// BuildTraceabilityGraph builds the graph of specs and the files tasks
+// declare. References to missing specs are left out; they are Rule 4
+// errors. With specs.Root set, glob paths are expanded and paths that match
+// nothing are marked missing.
+func BuildTraceabilityGraph(specs *SpecCollection) TraceabilityGraph {
+	nodes := make(map[string]TraceNode)
+	edges := make(map[TraceEdge]bool)
+
+	addSpecs := func(kind string, specMap map[string]*SpecFrontmatter) {
+		for id, spec := range specMap {
+			nodes[id] = TraceNode{ID: id, Kind: kind, Status: spec.Status, File: spec.FilePath}
+		}
+	}
+	addSpecs(TraceKindRequirement, specs.Requirements)
+	addSpecs(TraceKindDesign, specs.Designs)
+	addSpecs(TraceKindTask, specs.Tasks)
+
+	for designID, design := range specs.Designs {
+		for _, relatedID := range design.Related {
+			if _, exists := specs.Requirements[relatedID]; exists {
+				edges[TraceEdge{From: relatedID, To: designID}] = true
+			}
+		}
+	}
+
+	for taskID, task := range specs.Tasks {
+		for _, relatedID := range task.Related {
+			if _, exists := specs.Designs[relatedID]; exists {
+				edges[TraceEdge{From: relatedID, To: taskID}] = true
+			}
+		}
+
+		addFiles := func(kind string, paths []string) {
+			for _, path := range paths {
+				files := []string{path}
+				missing := false
+				if specs.Root != "" {
+					files = resolveTracePath(specs.Root, path)
+					if kind == TraceKindTest {
+						files = expandTestDirs(specs.Root, files)
+					}
+					if len(files) == 0 {
+						files, missing = []string{path}, true
+					}
+				}
+				for _, file := range files {
+					// A code glob may also match test files; those are tests
+					if existing, exists := nodes[file]; !exists || (kind == TraceKindTest && existing.Kind == TraceKindCode) {
+						nodes[file] = TraceNode{ID: file, Kind: kind, Missing: missing}
+					}
+					edges[TraceEdge{From: taskID, To: file}] = true
+				}
+			}
+		}
+		addFiles(TraceKindCode, task.Code)
+		addFiles(TraceKindTest, task.Tests)
+	}
+
+	graph := TraceabilityGraph{Nodes: []TraceNode{}, Edges: []TraceEdge{}}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for edge := range edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	graph.sort()
+	return graph
+}
+
+func (g *TraceabilityGraph) sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		a, b := g.Nodes[i], g.Nodes[j]
+		if a.Kind != b.Kind {
+			return traceKindOrder[a.Kind] < traceKindOrder[b.Kind]
+		}
+		return a.ID < b.ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+}
+
+// Node returns the node with the given ID.
+func (g TraceabilityGraph) Node(id string) (TraceNode, bool) {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node, true
+		}
+	}
+	return TraceNode{}, false
+}
+
+// Trace returns the part of the graph upstream and downstream of id: for a
+// requirement, the designs, tasks, code and tests that trace to it; for a
+// test file, the tasks, designs and requirements it covers.
+func (g TraceabilityGraph) Trace(id string) TraceabilityGraph {
+	keep := map[string]bool{id: true}
+	walk := func(next func(TraceEdge) (from, to string)) {
+		queue := []string{id}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, edge := range g.Edges {
+				from, to := next(edge)
+				if from == current && !keep[to] {
+					keep[to] = true
+					queue = append(queue, to)
+				}
+			}
+		}
+	}
+	walk(func(e TraceEdge) (string, string) { return e.From, e.To })
+	walk(func(e TraceEdge) (string, string) { return e.To, e.From })
+
+	traced := TraceabilityGraph{Nodes: []TraceNode{}, Edges: []TraceEdge{}}
+	for _, node := range g.Nodes {
+		if keep[node.ID] {
+			traced.Nodes = append(traced.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if keep[edge.From] && keep[edge.To] {
+			traced.Edges = append(traced.Edges, edge)
+		}
+	}
+	return traced
+}
+
+// children returns the IDs of the nodes of a kind that id points to.
+func (g TraceabilityGraph) children(id, kind string, kinds map[string]string) []string {
+	var ids []string
+	for _, edge := range g.Edges {
+		if edge.From == id && kinds[edge.To] == kind {
+			ids = append(ids, edge.To)
+		}
+	}
+	return ids
+}
+
+// hasParent reports whether an edge points to id.
+func (g TraceabilityGraph) hasParent(id string) bool {
+	for _, edge := range g.Edges {
+		if edge.To == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TraceabilityRow is a row of the traceability matrix: one chain from a
+// requirement to a task, with the task's code and tests. Cells are empty
+// where the chain is broken.
+type TraceabilityRow struct {
+	Requirement string
+	Design      string
+	Task        string
+	Code        []string
+	Tests       []string
+}
+
+// Matrix flattens the graph into rows, one per REQ -> DESIGN -> TASK chain.
+// Specs without a downstream link get a row of their own, as do designs and
+// tasks without an upstream one.
+func (g TraceabilityGraph) Matrix() []TraceabilityRow {
+	kinds := make(map[string]string, len(g.Nodes))
+	for _, node := range g.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+
+	var rows []TraceabilityRow
+	addTasks := func(req, design string, tasks []string) {
+		if len(tasks) == 0 {
+			rows = append(rows, TraceabilityRow{Requirement: req, Design: design})
+			return
+		}
+		for _, task := range tasks {
+			rows = append(rows, TraceabilityRow{
+				Requirement: req,
+				Design:      design,
+				Task:        task,
+				Code:        g.children(task, TraceKindCode, kinds),
+				Tests:       g.children(task, TraceKindTest, kinds),
+			})
+		}
+	}
+	addDesigns := func(req string, designs []string) {
+		if len(designs) == 0 {
+			rows = append(rows, TraceabilityRow{Requirement: req})
+			return
+		}
+		for _, design := range designs {
+			addTasks(req, design, g.children(design, TraceKindTask, kinds))
+		}
+	}
+
+	for _, node := range g.Nodes {
+		switch {
+		case node.Kind == TraceKindRequirement:
+			addDesigns(node.ID, g.children(node.ID, TraceKindDesign, kinds))
+		case node.Kind == TraceKindDesign && !g.hasParent(node.ID):
+			addDesigns("", []string{node.ID})
+		case node.Kind == TraceKindTask && !g.hasParent(node.ID):
+			addTasks("", "", []string{node.ID})
+		}
+	}
+	return rows
+}
+
+// FormatTraceabilityGraph renders the graph as a markdown traceability
+// matrix, CSV, a Mermaid flowchart or a Graphviz DOT digraph.
+func FormatTraceabilityGraph(graph TraceabilityGraph, format string) (string, error) {
+	switch format {
+	case TraceabilityGraphMatrix:
+		return formatTraceabilityMatrix(graph), nil
+	case TraceabilityGraphCSV:
+		return formatTraceabilityCSV(graph)
+	case TraceabilityGraphMermaid:
+		return formatTraceabilityMermaid(graph), nil
+	case TraceabilityGraphDOT:
+		return formatTraceabilityDOT(graph), nil
+	}
+	return "", fmt.Errorf("unknown traceability graph format %q (want %s)", format, strings.Join(TraceabilityGraphFormats, ", "))
+}
+
+// tracePaths labels the files in a cell, marking declared paths that match
+// nothing.
+func tracePaths(graph TraceabilityGraph, ids []string) []string {
+	labels := make([]string, len(ids))
+	for i, id := range ids {
+		labels[i] = id
+		if node, ok := graph.Node(id); ok && node.Missing {
+			labels[i] += " (missing)"
+		}
+	}
+	return labels
+}
+
+func formatTraceabilityMatrix(graph TraceabilityGraph) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Traceability Matrix\n\n")
+	sb.WriteString("| Requirement | Design | Task | Code | Tests |\n")
+	sb.WriteString("|-------------|--------|------|------|-------|\n")
+	cell := func(s string) string {
+		return strings.ReplaceAll(s, "|", `\|`)
+	}
+	for _, row := range graph.Matrix() {
+		sb.WriteString("| " + cell(row.Requirement) +
+			" | " + cell(row.Design) +
+			" | " + cell(row.Task) +
+			" | " + cell(strings.Join(tracePaths(graph, row.Code), "<br>")) +
+			" | " + cell(strings.Join(tracePaths(graph, row.Tests), "<br>")) + " |\n")
+	}
+
+	return sb.String()
+}
+
+func formatTraceabilityCSV(graph TraceabilityGraph) (string, error) {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	records := [][]string{{"requirement", "design", "task", "code", "tests"}}
+	for _, row := range graph.Matrix() {
+		records = append(records, []string{
+			row.Requirement,
+			row.Design,
+			row.Task,
+			strings.Join(tracePaths(graph, row.Code), "; "),
+			strings.Join(tracePaths(graph, row.Tests), "; "),
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func formatTraceabilityMermaid(graph TraceabilityGraph) string {
+	var sb strings.Builder
+
+	// Paths are not valid Mermaid IDs, so nodes are numbered
+	ids := make(map[string]string, len(graph.Nodes))
+	sb.WriteString("flowchart LR\n")
+	for i, node := range graph.Nodes {
+		ids[node.ID] = "n" + Itoa(i)
+		label := strings.ReplaceAll(node.ID, `"`, "#quot;")
+		switch node.Kind {
+		case TraceKindCode, TraceKindTest:
+			sb.WriteString("  " + ids[node.ID] + "[/\"" + label + "\"/]\n")
+		default:
+			sb.WriteString("  " + ids[node.ID] + "[\"" + label + "\"]\n")
+		}
+	}
+	for _, edge := range graph.Edges {
+		sb.WriteString("  " + ids[edge.From] + " --> " + ids[edge.To] + "\n")
+	}
+	for _, node := range graph.Nodes {
+		if node.Missing {
+			sb.WriteString("  class " + ids[node.ID] + " missing\n")
+		}
+	}
+	sb.WriteString("  classDef missing stroke-dasharray: 5 5\n")
+
+	return sb.String()
+}
+
+func formatTraceabilityDOT(graph TraceabilityGraph) string {
+	var sb strings.Builder
+
+	shapes := map[string]string{
+		TraceKindRequirement: "box",
+		TraceKindDesign:      "box",
+		TraceKindTask:        "box",
+		TraceKindCode:        "note",
+		TraceKindTest:        "component",
+	}
+	sb.WriteString("digraph traceability {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, node := range graph.Nodes {
+		attrs := "shape=" + shapes[node.Kind]
+		if node.Missing {
+			attrs += ", style=dashed"
+		}
+		sb.WriteString("  " + strconv.Quote(node.ID) + " [" + attrs + "];\n")
+	}
+	for _, edge := range graph.Edges {
+		sb.WriteString("  " + strconv.Quote(edge.From) + " -> " + strconv.Quote(edge.To) + ";\n")
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// sortedSpecIDs returns the IDs of specs in order.
+func sortedSpecIDs(specs map[string]*SpecFrontmatter) []string {
+	ids := make([]string, 0, len(specs))
+	for id := range specs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,123 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+// traceabilityGraphFixture has two requirements sharing a design, a
+// requirement without designs and a task without a design.
+func traceabilityGraphFixture() internal.TraceabilityGraph {
+	result := internal.ValidateTraceabilityFromContent(
+		map[string]*internal.SpecFrontmatter{
+			"REQ-001": {Type: "requirement", ID: "REQ-001", Status: "approved"},
+			"REQ-002": {Type: "requirement", ID: "REQ-002", Status: "approved"},
+			"REQ-003": {Type: "requirement", ID: "REQ-003", Status: "draft"},
+		},
+		map[string]*internal.SpecFrontmatter{
+			"DESIGN-001": {Type: "design", ID: "DESIGN-001", Status: "approved", Related: []string{"REQ-001", "REQ-002"}},
+		},
+		map[string]*internal.SpecFrontmatter{
+			"TASK-001": {Type: "task", ID: "TASK-001", Status: "done", Related: []string{"DESIGN-001"},
+				Code: []string{"src/search.ts"}, Tests: []string{"src/search.test.ts", "e2e/search.spec.ts"}},
+			"TASK-002": {Type: "task", ID: "TASK-002", Status: "pending", Related: []string{"DESIGN-001"},
+				Code: []string{"src/index.ts"}},
+			"TASK-003": {Type: "task", ID: "TASK-003", Status: "pending", Tests: []string{"src/search.test.ts"}},
+		},
+		false,
+	)
+	return *result.Graph
+}
+
+func TestTraceabilityGraph_Matrix(t *testing.T) {
+	graph := traceabilityGraphFixture()
+
+	var rows []string
+	for _, row := range graph.Matrix() {
+		rows = append(rows, strings.Join([]string{row.Requirement, row.Design, row.Task,
+			strings.Join(row.Code, "+"), strings.Join(row.Tests, "+")}, " | "))
+	}
+	want := []string{
+		"REQ-001 | DESIGN-001 | TASK-001 | src/search.ts | e2e/search.spec.ts+src/search.test.ts",
+		"REQ-001 | DESIGN-001 | TASK-002 | src/index.ts | ",
+		"REQ-002 | DESIGN-001 | TASK-001 | src/search.ts | e2e/search.spec.ts+src/search.test.ts",
+		"REQ-002 | DESIGN-001 | TASK-002 | src/index.ts | ",
+		"REQ-003 |  |  |  | ",
+		" |  | TASK-003 |  | src/search.test.ts",
+	}
+	if got := strings.Join(rows, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("matrix =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestTraceabilityGraph_Trace(t *testing.T) {
+	graph := traceabilityGraphFixture()
+
+	// Which tests cover REQ-002?
+	var tests []string
+	for _, node := range graph.Trace("REQ-002").Nodes {
+		if node.Kind == internal.TraceKindTest {
+			tests = append(tests, node.ID)
+		}
+	}
+	if got := strings.Join(tests, ","); got != "e2e/search.spec.ts,src/search.test.ts" {
+		t.Errorf("tests covering REQ-002 = %s", got)
+	}
+
+	// Which requirements does a test cover?
+	var ids []string
+	for _, node := range graph.Trace("src/search.test.ts").Nodes {
+		ids = append(ids, node.ID)
+	}
+	want := "REQ-001,REQ-002,DESIGN-001,TASK-001,TASK-003,src/search.test.ts"
+	if got := strings.Join(ids, ","); got != want {
+		t.Errorf("trace = %s, want %s", got, want)
+	}
+}
+
+func TestFormatTraceabilityGraph(t *testing.T) {
+	graph := traceabilityGraphFixture().Trace("TASK-002")
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{internal.TraceabilityGraphMatrix, []string{
+			"| Requirement | Design | Task | Code | Tests |",
+			"| REQ-001 | DESIGN-001 | TASK-002 | src/index.ts |  |",
+		}},
+		{internal.TraceabilityGraphCSV, []string{
+			"requirement,design,task,code,tests\n",
+			"REQ-002,DESIGN-001,TASK-002,src/index.ts,\n",
+		}},
+		{internal.TraceabilityGraphMermaid, []string{
+			"flowchart LR\n",
+			`n0["REQ-001"]`,
+			`n4[/"src/index.ts"/]`,
+			"n2 --> n3\n",
+		}},
+		{internal.TraceabilityGraphDOT, []string{
+			"digraph traceability {\n",
+			`"src/index.ts" [shape=note];`,
+			`"DESIGN-001" -> "TASK-002";`,
+		}},
+	}
+	for _, tt := range tests {
+		out, err := internal.FormatTraceabilityGraph(graph, tt.format)
+		if err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, want, out)
+			}
+		}
+	}
+
+	if _, err := internal.FormatTraceabilityGraph(graph, "svg"); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
+}
@@ -0,0 +1,347 @@
+package internal
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// StatusRule constrains the status of a spec by the statuses of the specs
+// traced to or from it, or by its implementing commit. Rules that fail are
+// warnings unless their severity is error, so they fail validation only in
+// strict mode.
+type StatusRule struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type,omitempty"`     // spec type the rule applies to: requirement, design or task
+	Status   []string `json:"status,omitempty"`   // statuses of that type the rule applies to
+	Linked   string   `json:"linked,omitempty"`   // type of the traced specs to check, upstream or downstream
+	Require  []string `json:"require,omitempty"`  // traced specs must have one of these statuses
+	Forbid   []string `json:"forbid,omitempty"`   // traced specs must not have any of these statuses
+	Commit   *bool    `json:"commit,omitempty"`   // the spec needs an implementing commit in git
+	Suggest  string   `json:"suggest,omitempty"`  // status to suggest for the spec when the rule fails
+	Severity string   `json:"severity,omitempty"` // error, warn (default) or off
+}
+
+// checksCommit reports whether the rule needs an implementing commit.
+func (r StatusRule) checksCommit() bool {
+	return r.Commit != nil && *r.Commit
+}
+
+// completedSpecStatuses are the statuses that mark a spec finished.
+var completedSpecStatuses = []string{"done", "complete", "implemented"}
+
+// DefaultStatusRules returns the built-in status propagation rules. Each call
+// returns new rules that the caller may change.
+func DefaultStatusRules() []StatusRule {
+	checkCommit := true
+	return []StatusRule{
+		{
+			Name:    "requirement-complete",
+			Type:    TraceKindRequirement,
+			Status:  slices.Clone(completedSpecStatuses),
+			Linked:  TraceKindTask,
+			Require: slices.Clone(completedSpecStatuses),
+			Suggest: "in-progress",
+		},
+		{
+			Name:    "design-approved",
+			Type:    TraceKindDesign,
+			Status:  []string{"approved"},
+			Linked:  TraceKindRequirement,
+			Forbid:  []string{"draft"},
+			Suggest: "review",
+		},
+		{
+			Name:    "task-commit",
+			Type:    TraceKindTask,
+			Status:  slices.Clone(completedSpecStatuses),
+			Commit:  &checkCommit,
+			Suggest: "in-progress",
+		},
+	}
+}
+
+// MergeStatusRules applies configured rules to base. A rule named like one
+// in base replaces the fields it sets; other rules are added.
+func MergeStatusRules(base, rules []StatusRule) []StatusRule {
+	merged := slices.Clone(base)
+	for _, rule := range rules {
+		i := slices.IndexFunc(merged, func(r StatusRule) bool { return r.Name == rule.Name })
+		if i < 0 {
+			merged = append(merged, rule)
+			continue
+		}
+		r := &merged[i]
+		if rule.Type != "" {
+			r.Type = rule.Type
+		}
+		overrideList(&r.Status, rule.Status)
+		if rule.Linked != "" {
+			r.Linked = rule.Linked
+		}
+		overrideList(&r.Require, rule.Require)
+		overrideList(&r.Forbid, rule.Forbid)
+		if rule.Commit != nil {
+			r.Commit = rule.Commit
+		}
+		if rule.Suggest != "" {
+			r.Suggest = rule.Suggest
+		}
+		if rule.Severity != "" {
+			r.Severity = rule.Severity
+		}
+	}
+	return merged
+}
+
+// CheckStatusRules checks that each rule can apply: it names a spec type and
+// statuses, and traced specs with statuses or a commit to check.
+func CheckStatusRules(rules []StatusRule) error {
+	for _, r := range rules {
+		switch {
+		case specKindLabel(r.Type) == "":
+			return fmt.Errorf("status rule %q: unknown type %q", r.Name, r.Type)
+		case len(r.Status) == 0:
+			return fmt.Errorf("status rule %q: no status to apply to", r.Name)
+		case r.Linked != "" && specKindLabel(r.Linked) == "":
+			return fmt.Errorf("status rule %q: unknown linked type %q", r.Name, r.Linked)
+		case r.Linked == "" && (len(r.Require) > 0 || len(r.Forbid) > 0):
+			return fmt.Errorf("status rule %q: require and forbid need a linked type", r.Name)
+		case r.Linked != "" && len(r.Require) == 0 && len(r.Forbid) == 0:
+			return fmt.Errorf("status rule %q: linked needs require or forbid", r.Name)
+		case r.Linked == "" && !r.checksCommit():
+			return fmt.Errorf("status rule %q: nothing to check; set linked or commit", r.Name)
+		}
+	}
+	return nil
+}
+
+// specKindLabel returns the ID prefix of a spec type, or "" for other types.
+func specKindLabel(kind string) string {
+	switch kind {
+	case TraceKindRequirement:
+		return "REQ"
+	case TraceKindDesign:
+		return "DESIGN"
+	case TraceKindTask:
+		return "TASK"
+	}
+	return ""
+}
+
+// hasStatus reports whether status is one of statuses, ignoring case.
+func hasStatus(statuses []string, status string) bool {
+	return slices.ContainsFunc(statuses, func(s string) bool { return strings.EqualFold(s, status) })
+}
+
+// checkStatusRules checks each spec against the rules for its type and
+// status. Commit rules need specs.Root to be a git repository; elsewhere
+// they are skipped.
+func checkStatusRules(specs *SpecCollection, rules []StatusRule, result *TraceabilityTestResult) {
+	byKind := map[string]map[string]*SpecFrontmatter{
+		TraceKindRequirement: specs.Requirements,
+		TraceKindDesign:      specs.Designs,
+		TraceKindTask:        specs.Tasks,
+	}
+	graph := BuildTraceabilityGraph(&SpecCollection{Requirements: specs.Requirements, Designs: specs.Designs, Tasks: specs.Tasks})
+	commits := &specCommits{root: specs.Root}
+
+	for _, rule := range rules {
+		if rule.Severity == CheckSeverityOff {
+			continue
+		}
+		add := func(source, target, message, suggestion string) {
+			issue := TraceabilityIssue{
+				Rule:       "Status Rule: " + rule.Name,
+				Source:     source,
+				Target:     target,
+				Message:    message,
+				Suggestion: suggestion,
+			}
+			if rule.Severity == CheckSeverityError {
+				result.Errors = append(result.Errors, issue)
+			} else {
+				result.Warnings = append(result.Warnings, issue)
+			}
+		}
+
+		label := specKindLabel(rule.Type)
+		for _, id := range sortedSpecIDs(byKind[rule.Type]) {
+			spec := byKind[rule.Type][id]
+			if !hasStatus(rule.Status, spec.Status) {
+				continue
+			}
+			subject := label + " '" + id + "'"
+			is := subject + " is '" + spec.Status + "'"
+
+			if rule.Linked != "" {
+				linkedLabel := specKindLabel(rule.Linked)
+				for _, node := range graph.Trace(id).Nodes {
+					if node.Kind != rule.Linked {
+						continue
+					}
+					linked := linkedLabel + " '" + node.ID + "'"
+					switch {
+					case len(rule.Require) > 0 && !hasStatus(rule.Require, node.Status):
+						suggestion := "Set " + linked + " to '" + rule.Require[0] + "'"
+						if rule.Suggest != "" {
+							suggestion = "Set " + subject + " to '" + rule.Suggest + "' until " + linked + " is '" + rule.Require[0] + "'"
+						}
+						add(id, node.ID, is+" but "+linked+" is '"+node.Status+"'", suggestion)
+					case hasStatus(rule.Forbid, node.Status):
+						suggestion := "Move " + linked + " out of '" + node.Status + "'"
+						if rule.Suggest != "" {
+							suggestion = "Set " + subject + " to '" + rule.Suggest + "' until " + linked + " is no longer '" + node.Status + "'"
+						}
+						add(id, node.ID, is+" but "+linked+" is '"+node.Status+"'", suggestion)
+					}
+				}
+			}
+
+			if rule.checksCommit() && commits.available() {
+				suggestion := "List the implementing commit under 'commits:'"
+				if rule.Suggest != "" {
+					suggestion = "Set " + subject + " to '" + rule.Suggest + "' until its implementing commit exists, or list it under 'commits:'"
+				}
+				if len(spec.Commits) == 0 && !commits.mention(id, spec.FilePath) {
+					add(id, "", is+" but no commit on HEAD mentions it", suggestion)
+				}
+				for _, sha := range spec.Commits {
+					if !commits.onHead(sha) {
+						add(id, sha, is+" but commit '"+sha+"' is not on HEAD", suggestion)
+					}
+				}
+			}
+		}
+	}
+}
+
+// specCommits looks up implementing commits on HEAD in the git repository at
+// root, reading the history once.
+type specCommits struct {
+	root    string
+	loaded  bool
+	top     string         // repository top level
+	commits []commitRecord // empty outside a repository
+}
+
+// commitRecord is the message of a commit and the files it changed,
+// relative to the repository top level.
+type commitRecord struct {
+	message string
+	files   []string
+}
+
+func (c *specCommits) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	if c.root == "" {
+		return
+	}
+	top, err := exec.Command("git", "-C", c.root, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return
+	}
+	c.top = strings.TrimSpace(string(top))
+	out, err := exec.Command("git", "-C", c.root, "-c", "core.quotePath=false",
+		"log", "HEAD", "--name-only", "--format=%x1e%B%x1f").Output()
+	if err != nil {
+		return
+	}
+	for _, entry := range strings.Split(string(out), "\x1e")[1:] {
+		message, names, _ := strings.Cut(entry, "\x1f")
+		record := commitRecord{message: message}
+		for _, name := range strings.Split(names, "\n") {
+			if name = strings.TrimSpace(name); name != "" {
+				record.files = append(record.files, name)
+			}
+		}
+		c.commits = append(c.commits, record)
+	}
+}
+
+// available reports whether root is a git repository with commits.
+func (c *specCommits) available() bool {
+	c.load()
+	return len(c.commits) > 0
+}
+
+// mention reports whether a commit on HEAD mentions id as a whole word and
+// changes more than the spec file at specPath.
+func (c *specCommits) mention(id, specPath string) bool {
+	c.load()
+	spec := c.relative(specPath)
+	for _, commit := range c.commits {
+		if !containsWord(commit.message, id) {
+			continue
+		}
+		if slices.ContainsFunc(commit.files, func(f string) bool { return f != spec }) {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns path relative to the repository top level, with forward
+// slashes, or "" when it is outside.
+func (c *specCommits) relative(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	top := c.top
+	if resolved, err := filepath.EvalSymlinks(top); err == nil {
+		top = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(top, abs)
+	if err != nil || !insideTraceRoot(rel) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// onHead reports whether sha names a commit reachable from HEAD.
+func (c *specCommits) onHead(sha string) bool {
+	if strings.HasPrefix(sha, "-") {
+		return false
+	}
+	return exec.Command("git", "-C", c.root, "merge-base", "--is-ancestor", sha+"^{commit}", "HEAD").Run() == nil
+}
+
+// containsWord reports whether s contains word with no word character
+// (letter, digit or underscore) directly before or after it.
+func containsWord(s, word string) bool {
+	isWordByte := func(b byte) bool {
+		return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+	}
+	for i := 0; word != ""; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+	return false
+}
+
+// exists reports whether sha names a commit.
+func (c *specCommits) exists(sha string) bool {
+	if strings.HasPrefix(sha, "-") {
+		return false
+	}
+	return exec.Command("git", "-C", c.root, "cat-file", "-e", sha+"^{commit}").Run() == nil
+}
@@ -0,0 +1,199 @@
+package internal_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+func statusIssues(issues []internal.TraceabilityIssue) string {
+	var lines []string
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.Rule, "Status Rule: ") {
+			lines = append(lines, issue.Rule+": "+issue.Message+" => "+issue.Suggestion)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestStatusRules_Defaults(t *testing.T) {
+	requirements := map[string]*internal.SpecFrontmatter{
+		"REQ-001": {Type: "requirement", ID: "REQ-001", Status: "complete"},
+		"REQ-002": {Type: "requirement", ID: "REQ-002", Status: "draft"},
+	}
+	designs := map[string]*internal.SpecFrontmatter{
+		"DESIGN-001": {Type: "design", ID: "DESIGN-001", Status: "approved", Related: []string{"REQ-001", "REQ-002"}},
+	}
+	tasks := map[string]*internal.SpecFrontmatter{
+		"TASK-001": {Type: "task", ID: "TASK-001", Status: "done", Related: []string{"DESIGN-001"}},
+		"TASK-002": {Type: "task", ID: "TASK-002", Status: "in-progress", Related: []string{"DESIGN-001"}},
+	}
+
+	result := internal.ValidateTraceabilityFromContent(requirements, designs, tasks, false)
+
+	want := "Status Rule: requirement-complete: REQ 'REQ-001' is 'complete' but TASK 'TASK-002' is 'in-progress'" +
+		" => Set REQ 'REQ-001' to 'in-progress' until TASK 'TASK-002' is 'done'\n" +
+		"Status Rule: design-approved: DESIGN 'DESIGN-001' is 'approved' but REQ 'REQ-002' is 'draft'" +
+		" => Set DESIGN 'DESIGN-001' to 'review' until REQ 'REQ-002' is no longer 'draft'"
+	if got := statusIssues(result.Warnings); got != want {
+		t.Errorf("warnings =\n%s\nwant\n%s", got, want)
+	}
+	if !result.Valid {
+		t.Errorf("Expected status warnings to pass without strict, got: %s", result.Message)
+	}
+
+	strict := internal.ValidateTraceabilityFromContent(requirements, designs, tasks, true)
+	if strict.Valid || strict.ExitCode != 2 {
+		t.Errorf("Expected status warnings to fail in strict mode, got valid=%v exit=%d", strict.Valid, strict.ExitCode)
+	}
+
+	console := internal.FormatTraceabilityResults(result, "console")
+	if !strings.Contains(console, "    Suggested: Set REQ 'REQ-001' to 'in-progress' until TASK 'TASK-002' is 'done'\n") {
+		t.Errorf("console output missing the suggestion:\n%s", console)
+	}
+	markdown := internal.FormatTraceabilityResults(result, "markdown")
+	if !strings.Contains(markdown, "  - Suggested: Set DESIGN 'DESIGN-001' to 'review'") {
+		t.Errorf("markdown output missing the suggestion:\n%s", markdown)
+	}
+}
+
+func TestStatusRules_Commit(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+	specsDir := createSpecsStructure(t, tmpDir)
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", tmpDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	task := func(id, extra string) {
+		createSpecFile(t, filepath.Join(specsDir, "tasks"), id+".md",
+			"---\ntype: task\nid: "+id+"\nstatus: done\nrelated:\n  - DESIGN-001\n"+extra+"---\n")
+	}
+	createSpecFile(t, filepath.Join(specsDir, "design"), "DESIGN-001.md", "---\ntype: design\nid: DESIGN-001\nstatus: complete\n---\n")
+	task("TASK-001", "")
+	task("TASK-002", "")
+	task("TASK-003", "commits:\n  - 0123456789abcdef\n")
+	git("add", "-A")
+	git("commit", "-qm", "Implement TASK-001 ahead of TASK-0020")
+	head := git("rev-parse", "--short", "HEAD")
+
+	// A commit that only adds the spec is not an implementation
+	task("TASK-005", "")
+	git("add", "-A")
+	git("commit", "-qm", "Plan TASK-005")
+
+	// Commits on other branches are not on HEAD
+	git("checkout", "-qb", "other")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "six.go"), "package src\n")
+	git("add", "-A")
+	git("commit", "-qm", "Implement TASK-006")
+	other := git("rev-parse", "--short", "HEAD")
+	git("checkout", "-q", "-")
+	task("TASK-004", "commits:\n  - "+head+"\n")
+	task("TASK-006", "")
+	task("TASK-007", "commits:\n  - "+other+"\n")
+
+	result := internal.ValidateTraceability(specsDir, false)
+
+	suggestion := func(id string) string {
+		return " => Set TASK '" + id + "' to 'in-progress' until its implementing commit exists, or list it under 'commits:'"
+	}
+	want := strings.Join([]string{
+		"Status Rule: task-commit: TASK 'TASK-002' is 'done' but no commit on HEAD mentions it" + suggestion("TASK-002"),
+		"Status Rule: task-commit: TASK 'TASK-003' is 'done' but commit '0123456789abcdef' is not on HEAD" + suggestion("TASK-003"),
+		"Status Rule: task-commit: TASK 'TASK-005' is 'done' but no commit on HEAD mentions it" + suggestion("TASK-005"),
+		"Status Rule: task-commit: TASK 'TASK-006' is 'done' but no commit on HEAD mentions it" + suggestion("TASK-006"),
+		"Status Rule: task-commit: TASK 'TASK-007' is 'done' but commit '" + other + "' is not on HEAD" + suggestion("TASK-007"),
+	}, "\n")
+	if got := statusIssues(result.Warnings); got != want {
+		t.Errorf("warnings =\n%s\nwant\n%s", got, want)
+	}
+
+	// An explicit commit: false turns the built-in commit check off.
+	cfg := mustParseConfig(t, `
+traceability:
+  statusRules:
+    - name: task-commit
+      commit: false
+      linked: design
+      require: [done]
+`)
+	result = internal.ValidateTraceabilityWithRules(specsDir, false, cfg.StatusRules())
+	got := statusIssues(result.Warnings)
+	if strings.Contains(got, "mentions it") || strings.Contains(got, "not on HEAD") || !strings.Contains(got, "TASK 'TASK-002' is 'done' but DESIGN 'DESIGN-001' is 'complete'") {
+		t.Errorf("warnings =\n%s\nwant linked checks only", got)
+	}
+}
+
+func TestStatusRules_Configured(t *testing.T) {
+	cfg := mustParseConfig(t, `
+traceability:
+  statusRules:
+    - name: requirement-complete
+      severity: error
+    - name: design-approved
+      severity: "off"
+    - name: task-review
+      type: task
+      status: [review]
+      linked: design
+      forbid: [draft]
+`)
+	rules := cfg.StatusRules()
+	if len(rules) != 4 || rules[0].Severity != "error" || rules[0].Linked != "task" || rules[3].Name != "task-review" {
+		t.Fatalf("rules = %+v", rules)
+	}
+
+	specs := &internal.SpecCollection{
+		Requirements: map[string]*internal.SpecFrontmatter{
+			"REQ-001": {Type: "requirement", ID: "REQ-001", Status: "done"},
+		},
+		Designs: map[string]*internal.SpecFrontmatter{
+			"DESIGN-001": {Type: "design", ID: "DESIGN-001", Status: "draft", Related: []string{"REQ-001"}},
+		},
+		Tasks: map[string]*internal.SpecFrontmatter{
+			"TASK-001": {Type: "task", ID: "TASK-001", Status: "review", Related: []string{"DESIGN-001"}},
+		},
+		StatusRules: rules,
+	}
+	tested := internal.TestTraceabilityRules(specs)
+
+	if got := statusIssues(tested.Errors); got != "Status Rule: requirement-complete: REQ 'REQ-001' is 'done' but TASK 'TASK-001' is 'review'"+
+		" => Set REQ 'REQ-001' to 'in-progress' until TASK 'TASK-001' is 'done'" {
+		t.Errorf("errors = %s", got)
+	}
+	if got := statusIssues(tested.Warnings); got != "Status Rule: task-review: TASK 'TASK-001' is 'review' but DESIGN 'DESIGN-001' is 'draft'"+
+		" => Move DESIGN 'DESIGN-001' out of 'draft'" {
+		t.Errorf("warnings = %s", got)
+	}
+}
+
+func TestStatusRules_InvalidConfig(t *testing.T) {
+	for _, yaml := range []string{
+		"traceability:\n  statusRules:\n    - name: x\n      type: task\n      status: [done]\n",
+		"traceability:\n  statusRules:\n    - name: x\n      type: task\n      status: [done]\n      require: [done]\n      commit: true\n",
+		"traceability:\n  statusRules:\n    - name: x\n      type: epic\n      status: [done]\n      commit: true\n",
+	} {
+		if _, err := internal.ParseValidationConfig([]byte(yaml)); err == nil {
+			t.Errorf("Expected an error for:\n%s", yaml)
+		}
+	}
+}
+
+func TestDefaultStatusRules_Independent(t *testing.T) {
+	rules := internal.DefaultStatusRules()
+	*rules[2].Commit = false
+	rules[0].Status[0] = "changed"
+
+	fresh := internal.DefaultStatusRules()
+	if !*fresh[2].Commit || fresh[0].Status[0] != "done" {
+		t.Errorf("Changing returned rules changed the defaults: %+v", fresh)
+	}
+}
@@ -151,6 +151,10 @@ type TestImplementationResult struct {
 	MissingTestCoverage []string `json:"missingTestCoverage,omitempty"`
 	MockDivergence      []string `json:"mockDivergence,omitempty"`
 	CoveragePercent     float64  `json:"coveragePercent,omitempty"`
+
+	// Drift holds the uses behind ParameterDrift, which are "file:line -
+	// message" issues.
+	Drift []ParameterDrift `json:"drift,omitempty"`
 }
 
 // CIEnvironmentResult represents validation of CI environment compatibility.
@@ -210,6 +214,24 @@ type PrePRValidationResult struct {
 	TestImplementation   TestImplementationResult   `json:"testImplementation"`
 	CIEnvironment        CIEnvironmentResult        `json:"ciEnvironment"`
 	EnvironmentVariables EnvironmentVariablesResult `json:"environmentVariables"`
+
+	// Set when only changes were checked: the base ref (empty for a diff
+	// passed in) and the changed files.
+	Base         string   `json:"base,omitempty"`
+	ChangedFiles []string `json:"changedFiles,omitempty"`
+
+	// Located holds each problem found at a line of a file: on an added
+	// line when only changes were checked, anywhere in the source
+	// directories otherwise.
+	Located []PrePRFinding `json:"located,omitempty"`
+}
+
+// PrePRFinding is a pre-PR problem at a line of a file.
+type PrePRFinding struct {
+	Check   string `json:"check"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
 }
 
 // SkillFormatValidationResult extends ValidationResult with skill-specific fields.
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DiffFile is one file of a unified diff with the lines it adds.
+type DiffFile struct {
+	Path    string     `json:"path"`              // new path; empty for a deleted file
+	OldPath string     `json:"oldPath,omitempty"` // old path; empty for a new file
+	Added   []DiffLine `json:"added,omitempty"`
+}
+
+// DiffLine is an added line and its line number in the new file.
+type DiffLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// FirstLine returns the first added line number, or 1 when nothing was
+// added.
+func (f DiffFile) FirstLine() int {
+	if len(f.Added) == 0 {
+		return 1
+	}
+	return f.Added[0].Line
+}
+
+// diffHunkHeaderPattern matches a hunk header, capturing the old line count
+// and the new start line and count.
+var diffHunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff parses git or plain unified diff output, such as that of
+// 'git diff --cached', into the files it changes. Hunk bodies are read by
+// their line counts, so added lines that look like headers are kept.
+func ParseUnifiedDiff(diff string) []DiffFile {
+	var files []DiffFile
+	var current *DiffFile
+	oldRemain, newRemain, newLine := 0, 0, 0
+
+	for _, line := range strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n") {
+		if oldRemain > 0 || newRemain > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				current.Added = append(current.Added, DiffLine{Line: newLine, Text: line[1:]})
+				newLine++
+				newRemain--
+			case strings.HasPrefix(line, "-"):
+				oldRemain--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				newLine++
+				oldRemain--
+				newRemain--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, DiffFile{})
+			current = &files[len(files)-1]
+		case strings.HasPrefix(line, "--- "):
+			if current == nil || current.Path != "" || len(current.Added) > 0 {
+				files = append(files, DiffFile{})
+				current = &files[len(files)-1]
+			}
+			current.OldPath = diffPath(line[4:], "a/")
+		case strings.HasPrefix(line, "+++ ") && current != nil:
+			current.Path = diffPath(line[4:], "b/")
+		case strings.HasPrefix(line, "@@") && current != nil:
+			match := diffHunkHeaderPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			oldRemain = hunkCount(match[1])
+			newLine, _ = strconv.Atoi(match[2])
+			newRemain = hunkCount(match[3])
+		}
+	}
+
+	// Drop entries with neither path, e.g. mode-only changes
+	kept := files[:0]
+	for _, f := range files {
+		if f.Path != "" || f.OldPath != "" {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// hunkCount parses the optional ",count" of a hunk range; it defaults to 1.
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffPath returns the path of a ---/+++ header without its a/ or b/
+// prefix, timestamp or quoting; /dev/null is empty.
+func diffPath(header, prefix string) string {
+	path := header
+	if i := strings.Index(path, "\t"); i >= 0 {
+		path = path[:i] // plain diff timestamps
+	}
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+	}
+	if path == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// GitMergeBase returns the merge base of base and HEAD in the repository
+// holding dir.
+func GitMergeBase(dir, base string) (string, error) {
+	output, err := exec.Command("git", "-C", dir, "merge-base", base, "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("no merge base between %s and HEAD", base)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitDiffAgainst returns the diff, without context lines, from the merge
+// base of base and HEAD to the working tree of dir. Paths are relative to
+// dir and limited to files under it.
+func gitDiffAgainst(dir, base string) (string, error) {
+	mergeBase, err := GitMergeBase(dir, base)
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.Command("git", "-C", dir, "diff", "--relative", "--unified=0", "--no-color", "--no-ext-diff", mergeBase)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff: %w", err)
+	}
+	return string(output), nil
+}
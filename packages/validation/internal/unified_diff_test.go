@@ -0,0 +1,72 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+func TestParseUnifiedDiff_GitDiff(t *testing.T) {
+	diff := `diff --git a/src/app.go b/src/app.go
+index 1111111..2222222 100644
+--- a/src/app.go
++++ b/src/app.go
+@@ -3,2 +3,3 @@ import "os"
+ func a() {}
+-func b() {}
++func b() error { return nil }
++++ counter
+@@ -20 +21 @@ func c() {
+-	old()
++	new()
+diff --git a/old.sh b/old.sh
+deleted file mode 100644
+--- a/old.sh
++++ /dev/null
+@@ -1 +0,0 @@
+-echo hi
+diff --git a/new.txt b/new.txt
+new file mode 100644
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++one
++two
+\ No newline at end of file
+`
+
+	files := internal.ParseUnifiedDiff(diff)
+
+	if len(files) != 3 {
+		t.Fatalf("files = %+v, want 3", files)
+	}
+	app := files[0]
+	if app.Path != "src/app.go" || app.OldPath != "src/app.go" {
+		t.Errorf("app paths = %q, %q", app.Path, app.OldPath)
+	}
+	want := []internal.DiffLine{{Line: 4, Text: "func b() error { return nil }"}, {Line: 5, Text: "++ counter"}, {Line: 21, Text: "\tnew()"}}
+	if len(app.Added) != len(want) {
+		t.Fatalf("app added = %+v, want %+v", app.Added, want)
+	}
+	for i := range want {
+		if app.Added[i] != want[i] {
+			t.Errorf("app added[%d] = %+v, want %+v", i, app.Added[i], want[i])
+		}
+	}
+	if files[1].Path != "" || files[1].OldPath != "old.sh" || len(files[1].Added) != 0 {
+		t.Errorf("deleted file = %+v", files[1])
+	}
+	if files[2].OldPath != "" || files[2].Path != "new.txt" || len(files[2].Added) != 2 || files[2].FirstLine() != 1 {
+		t.Errorf("new file = %+v", files[2])
+	}
+}
+
+func TestParseUnifiedDiff_PlainDiff(t *testing.T) {
+	diff := "--- a.py\t2026-01-01 00:00:00\n+++ a.py\t2026-01-02 00:00:00\n@@ -1,2 +1,2 @@\n x = 1\n-y = 2\n+y = 3\n"
+
+	files := internal.ParseUnifiedDiff(diff)
+
+	if len(files) != 1 || files[0].Path != "a.py" || len(files[0].Added) != 1 || files[0].Added[0].Line != 2 {
+		t.Errorf("files = %+v, want a.py with line 2 added", files)
+	}
+}
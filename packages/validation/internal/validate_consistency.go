@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -142,6 +144,9 @@ type ConsistencyValidationResult struct {
 	NamingConventions   NamingConventionsResult   `json:"namingConventions"`
 	CrossReferences     CrossReferencesResult     `json:"crossReferences"`
 	TaskCompletion      TaskCompletionResult      `json:"taskCompletion"`
+	Cached              bool                      `json:"cached,omitempty"` // reused from the result cache
+
+	elapsed time.Duration // time taken by ValidateAllFeaturesContext
 }
 
 // FeatureArtifacts represents artifacts found for a feature.
@@ -614,13 +619,26 @@ func validateFileReferences(filePath string) CrossReferencesResult {
 		return result
 	}
 
-	fileDir := filepath.Dir(filePath)
+	for _, linkPath := range markdownFileLinks(content) {
+		result.References = append(result.References, linkPath)
 
-	// Find markdown links: [text](path)
-	linkPattern := regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
-	matches := linkPattern.FindAllSubmatch(content, -1)
+		if !FileExists(resolveFileLink(filePath, linkPath)) {
+			result.Passed = false
+			result.Issues = append(result.Issues, "Broken reference in "+filepath.Base(filePath)+": "+linkPath)
+		}
+	}
 
-	for _, match := range matches {
+	return result
+}
+
+// markdownLinkPattern matches markdown links: [text](path)
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+
+// markdownFileLinks returns the file paths markdown content links to,
+// without URLs, anchors or the anchor part of a path.
+func markdownFileLinks(content []byte) []string {
+	var links []string
+	for _, match := range markdownLinkPattern.FindAllSubmatch(content, -1) {
 		linkPath := string(match[2])
 
 		// Skip URLs and anchors
@@ -635,27 +653,19 @@ func validateFileReferences(filePath string) CrossReferencesResult {
 			linkPath = linkPath[:idx]
 		}
 
-		if linkPath == "" {
-			continue
-		}
-
-		result.References = append(result.References, linkPath)
-
-		// Resolve relative path
-		var fullPath string
-		if filepath.IsAbs(linkPath) {
-			fullPath = linkPath
-		} else {
-			fullPath = filepath.Join(fileDir, linkPath)
-		}
-
-		if !FileExists(fullPath) {
-			result.Passed = false
-			result.Issues = append(result.Issues, "Broken reference in "+filepath.Base(filePath)+": "+linkPath)
+		if linkPath != "" {
+			links = append(links, linkPath)
 		}
 	}
+	return links
+}
 
-	return result
+// resolveFileLink resolves a link in filePath against its directory.
+func resolveFileLink(filePath, linkPath string) string {
+	if filepath.IsAbs(linkPath) {
+		return linkPath
+	}
+	return filepath.Join(filepath.Dir(filePath), linkPath)
 }
 
 // ValidateTaskCompletion validates task completion status for Checkpoint 2.
@@ -719,14 +729,7 @@ func ValidateTaskCompletion(tasksPath string) TaskCompletionResult {
 
 // ValidateAllFeatures validates consistency for all features found in basePath.
 func ValidateAllFeatures(basePath string, checkpoint int) []ConsistencyValidationResult {
-	var results []ConsistencyValidationResult
-
-	features := GetAllFeatures(basePath)
-	for _, feature := range features {
-		result := ValidateConsistency(basePath, feature, checkpoint)
-		results = append(results, result)
-	}
-
+	results, _ := ValidateAllFeaturesContext(context.Background(), basePath, AllFeaturesOptions{Checkpoint: checkpoint})
 	return results
 }
 
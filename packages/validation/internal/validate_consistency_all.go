@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// consistencyCacheVersion is part of every cache key. Bump it when a change
+// to the consistency checks makes cached results stale.
+const consistencyCacheVersion = 1
+
+// AllFeaturesOptions configures ValidateAllFeaturesContext.
+type AllFeaturesOptions struct {
+	// Checkpoint is the validation checkpoint (1 = Pre-Critic, 2 =
+	// Post-Implementation).
+	Checkpoint int
+
+	// Workers bounds how many features are validated at once; 0 uses
+	// GOMAXPROCS.
+	Workers int
+
+	// CacheDir holds results keyed on the content of each feature's
+	// artifacts, so that only changed features are revalidated. Empty
+	// disables the cache.
+	CacheDir string
+}
+
+// ValidateAllFeaturesContext validates consistency for all features found in
+// basePath, several at a time. Results are in feature order. When ctx is done,
+// no further feature is started; the results of those already validated are
+// returned with ctx's error.
+func ValidateAllFeaturesContext(ctx context.Context, basePath string, opts AllFeaturesOptions) ([]ConsistencyValidationResult, error) {
+	features := GetAllFeatures(basePath)
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(features) {
+		workers = len(features)
+	}
+
+	results := make([]ConsistencyValidationResult, len(features))
+	done := make([]bool, len(features))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				start := time.Now()
+				results[i] = validateFeatureCached(basePath, features[i], opts)
+				results[i].elapsed = time.Since(start)
+				done[i] = true
+			}
+		}()
+	}
+
+feed:
+	for i := range features {
+		// select picks at random among ready cases, so check first
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var validated []ConsistencyValidationResult
+	for i := range features {
+		if done[i] {
+			validated = append(validated, results[i])
+		}
+	}
+	return validated, ctx.Err()
+}
+
+// consistencyCacheEntry is a cached consistency result and the key it is
+// valid for.
+type consistencyCacheEntry struct {
+	Key    string                      `json:"key"`
+	Result ConsistencyValidationResult `json:"result"`
+}
+
+// validateFeatureCached returns the cached result of a feature when its key
+// is unchanged, and otherwise validates the feature and caches the result.
+// The cache is best effort: unreadable or unwritable entries are ignored.
+func validateFeatureCached(basePath, feature string, opts AllFeaturesOptions) ConsistencyValidationResult {
+	if opts.CacheDir == "" {
+		return ValidateConsistency(basePath, feature, opts.Checkpoint)
+	}
+
+	key := consistencyCacheKey(FindFeatureArtifacts(feature, basePath), opts.Checkpoint)
+	path := consistencyCachePath(opts.CacheDir, basePath, feature, opts.Checkpoint)
+	if data, err := os.ReadFile(path); err == nil {
+		var entry consistencyCacheEntry
+		if json.Unmarshal(data, &entry) == nil && entry.Key == key {
+			entry.Result.Cached = true
+			return entry.Result
+		}
+	}
+
+	result := ValidateConsistency(basePath, feature, opts.Checkpoint)
+	if data, err := json.Marshal(consistencyCacheEntry{Key: key, Result: result}); err == nil {
+		writeCacheFile(path, data)
+	}
+	return result
+}
+
+// consistencyCacheKey hashes what the consistency result of a feature
+// depends on: the checkpoint, the paths and content of its artifacts, and
+// whether the files they link to exist.
+func consistencyCacheKey(artifacts FeatureArtifacts, checkpoint int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "v%d checkpoint %d\n", consistencyCacheVersion, checkpoint)
+	for _, path := range []string{artifacts.Epic, artifacts.PRD, artifacts.Tasks, artifacts.Plan} {
+		fmt.Fprintf(h, "artifact %q\n", path)
+		if path == "" {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(h, "unreadable\n")
+			continue
+		}
+		fmt.Fprintf(h, "%x\n", sha256.Sum256(content))
+		for _, link := range markdownFileLinks(content) {
+			fmt.Fprintf(h, "link %q %t\n", link, FileExists(resolveFileLink(path, link)))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// consistencyCachePath returns the cache file of a feature. Features of
+// different repositories and checkpoints have their own files.
+func consistencyCachePath(cacheDir, basePath, feature string, checkpoint int) string {
+	if abs, err := filepath.Abs(basePath); err == nil {
+		basePath = abs
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", basePath, feature, checkpoint)))
+	return filepath.Join(cacheDir, "consistency-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// writeCacheFile replaces path with data through a temporary file, so that
+// concurrent runs never read a partial entry.
+func writeCacheFile(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
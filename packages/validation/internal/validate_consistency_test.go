@@ -1,6 +1,7 @@
 package internal_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"slices"
@@ -1052,3 +1053,105 @@ func TestValidateAllFeatures(t *testing.T) {
 		t.Errorf("Expected 2 validation results, got %d", len(results))
 	}
 }
+
+// writeFeatures creates a PRD and tasks file for each feature.
+func writeFeatures(t *testing.T, planningDir string, features ...string) {
+	t.Helper()
+	for _, f := range features {
+		writeTestFile(t, filepath.Join(planningDir, "prd-"+f+".md"), "# PRD\n\n## Requirements\n\n- [ ] Req 1\n")
+		writeTestFile(t, filepath.Join(planningDir, "tasks-"+f+".md"), "# Tasks\n\n### Task 1\n")
+	}
+}
+
+func TestValidateAllFeaturesContext_Order(t *testing.T) {
+	tmpDir := t.TempDir()
+	features := []string{"alpha", "beta", "delta", "gamma", "omega"}
+	writeFeatures(t, filepath.Join(tmpDir, ".agents", "planning"), features...)
+
+	results, err := internal.ValidateAllFeaturesContext(context.Background(), tmpDir, internal.AllFeaturesOptions{Checkpoint: 1, Workers: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Feature)
+	}
+	if !slices.Equal(got, features) {
+		t.Errorf("features = %v, want %v", got, features)
+	}
+}
+
+func TestValidateAllFeaturesContext_Cancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFeatures(t, filepath.Join(tmpDir, ".agents", "planning"), "alpha", "beta", "delta", "gamma", "omega")
+	cacheDir := filepath.Join(tmpDir, "cache")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Repeat: whether a worker is ready to receive depends on scheduling.
+	for run := 0; run < 20; run++ {
+		results, err := internal.ValidateAllFeaturesContext(ctx, tmpDir, internal.AllFeaturesOptions{Checkpoint: 1, Workers: 4, CacheDir: cacheDir})
+		if err != context.Canceled {
+			t.Fatalf("err = %v, want %v", err, context.Canceled)
+		}
+		if len(results) != 0 {
+			t.Fatalf("run %d: validated %d features after cancellation, want none", run, len(results))
+		}
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("Expected no cache entries after cancellation, got %d", len(entries))
+	}
+}
+
+func TestValidateAllFeaturesContext_Cache(t *testing.T) {
+	tmpDir := t.TempDir()
+	planningDir := filepath.Join(tmpDir, ".agents", "planning")
+	writeFeatures(t, planningDir, "auth", "payment")
+	opts := internal.AllFeaturesOptions{Checkpoint: 1, CacheDir: filepath.Join(tmpDir, "cache")}
+
+	cached := func() map[string]bool {
+		t.Helper()
+		results, err := internal.ValidateAllFeaturesContext(context.Background(), tmpDir, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		m := map[string]bool{}
+		for _, r := range results {
+			m[r.Feature] = r.Cached
+		}
+		return m
+	}
+
+	if got := cached(); got["auth"] || got["payment"] {
+		t.Errorf("first run cached = %v, want none", got)
+	}
+	if got := cached(); !got["auth"] || !got["payment"] {
+		t.Errorf("second run cached = %v, want all", got)
+	}
+
+	// Editing one feature revalidates only that feature.
+	writeTestFile(t, filepath.Join(planningDir, "tasks-auth.md"), "# Tasks\n\n### Task 1\n\nSee [notes](notes.md)\n")
+	if got := cached(); got["auth"] || !got["payment"] {
+		t.Errorf("after edit cached = %v, want only payment", got)
+	}
+	results, _ := internal.ValidateAllFeaturesContext(context.Background(), tmpDir, opts)
+	if results[0].CrossReferences.Passed {
+		t.Error("Expected the broken link to fail cross references")
+	}
+
+	// Creating a linked file changes the result without editing the feature.
+	writeTestFile(t, filepath.Join(planningDir, "notes.md"), "# Notes\n")
+	results, _ = internal.ValidateAllFeaturesContext(context.Background(), tmpDir, opts)
+	if results[0].Cached || !results[0].CrossReferences.Passed {
+		t.Errorf("Expected the new link target to revalidate auth, got cached=%v passed=%v",
+			results[0].Cached, results[0].CrossReferences.Passed)
+	}
+
+	// Without a cache directory nothing is reused.
+	results, _ = internal.ValidateAllFeaturesContext(context.Background(), tmpDir, internal.AllFeaturesOptions{Checkpoint: 1})
+	if results[0].Cached || results[1].Cached {
+		t.Error("Expected no cached results without a cache directory")
+	}
+}
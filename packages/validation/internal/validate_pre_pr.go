@@ -22,6 +22,10 @@ type PrePRConfig struct {
 	TestDirs    []string `json:"testDirs"`
 	ConfigFiles []string `json:"configFiles"`
 	EnvFiles    []string `json:"envFiles"`
+
+	// Base is a git ref; when set, only changes since its merge base with
+	// HEAD are checked.
+	Base string `json:"base,omitempty"`
 }
 
 // PrePRConfigDefaults contains default values for PrePRConfig.
@@ -166,6 +170,136 @@ func DefaultPrePRConfig(basePath string) PrePRConfig {
 	}
 }
 
+// Patterns shared by the whole-tree and diff-scoped pre-PR checks.
+var (
+	// Hardcoded values
+	prePRHardcodedPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(password|secret|api[_-]?key|token)\s*[:=]\s*["'][^"']+["']`),
+		regexp.MustCompile(`(?i)localhost:\d{4,5}`),
+		regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`), // IP addresses
+	}
+
+	prePRTodoPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(TODO|FIXME|XXX|HACK)\b`),
+	}
+
+	// Patterns that indicate a script checks exit codes
+	prePRExitCheckPatterns = map[string][]*regexp.Regexp{
+		"powershell": {
+			regexp.MustCompile(`(?i)\$LASTEXITCODE`),
+			regexp.MustCompile(`(?i)-ErrorAction\s+Stop`),
+		},
+		"bash": {
+			regexp.MustCompile(`\$\?`),
+			regexp.MustCompile(`set\s+-e`),
+		},
+	}
+
+	// Patterns that indicate silent failures
+	prePRSilentFailurePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)catch\s*\{\s*\}`),                 // Empty catch blocks
+		regexp.MustCompile(`(?i)catch\s*\([^)]*\)\s*\{\s*\}`),     // Empty catch blocks
+		regexp.MustCompile(`(?i)2>\s*/dev/null`),                  // Suppressed errors
+		regexp.MustCompile(`(?i)-ErrorAction\s+SilentlyContinue`), // PowerShell silent
+		regexp.MustCompile(`(?i)On\s+Error\s+Resume\s+Next`),      // VB-style
+	}
+
+	// Insecure default patterns
+	prePRInsecurePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)AllowAny|AllowAll`),
+		regexp.MustCompile(`(?i)disable.*security|security.*disable`),
+		regexp.MustCompile(`(?i)skip.*validation|validation.*skip`),
+		regexp.MustCompile(`(?i)InsecureSkipVerify\s*[:=]\s*true`),
+	}
+
+	// Environment variable references
+	prePREnvVarPattern = regexp.MustCompile(`(?i)(process\.env\.|os\.environ\[|os\.getenv\(|Environment\.GetEnvironmentVariable\(|\$env:|getenv\()\s*["']?([A-Z][A-Z0-9_]+)`)
+)
+
+// prePRText is text the pre-PR checks search: a line added by a diff or a
+// whole file.
+type prePRText struct {
+	file string
+	text string
+	line int // line of file the text starts on
+}
+
+// at returns the text matched at loc, on one line, and the line it starts
+// on.
+func (t prePRText) at(loc []int) (string, int) {
+	return strings.Join(strings.Fields(t.text[loc[0]:loc[1]]), " "), t.line + lineAt(t.text, loc[0]) - 1
+}
+
+// crossCuttingIn records the hardcoded values and TODO/FIXME comments in t;
+// one comment per line.
+func (fs *prePRFindings) crossCuttingIn(result *CrossCuttingConcernsResult, t prePRText) {
+	for _, pattern := range prePRHardcodedPatterns {
+		for _, loc := range pattern.FindAllStringIndex(t.text, -1) {
+			m, line := t.at(loc)
+			result.HardcodedValues = append(result.HardcodedValues, t.file+":"+Itoa(line)+": "+truncate(m, 50))
+			result.Issues = append(result.Issues,
+				fs.add("cross_cutting_concerns", t.file, line, "Hardcoded value: "+truncate(m, 50)))
+		}
+	}
+
+	todoLines := make(map[int]bool)
+	for _, pattern := range prePRTodoPatterns {
+		for _, loc := range pattern.FindAllStringIndex(t.text, -1) {
+			m, line := t.at(loc)
+			if todoLines[line] {
+				continue
+			}
+			todoLines[line] = true
+			result.TodoComments = append(result.TodoComments, t.file+":"+Itoa(line))
+			result.Issues = append(result.Issues,
+				fs.add("cross_cutting_concerns", t.file, line, m+" comment"))
+		}
+	}
+}
+
+// failSafeIn records the silent failures, one per line, and insecure
+// defaults in t.
+func (fs *prePRFindings) failSafeIn(result *FailSafeDesignResult, t prePRText) {
+	silentLines := make(map[int]bool)
+	for _, pattern := range prePRSilentFailurePatterns {
+		for _, loc := range pattern.FindAllStringIndex(t.text, -1) {
+			m, line := t.at(loc)
+			if silentLines[line] {
+				continue
+			}
+			silentLines[line] = true
+			result.SilentFailures = append(result.SilentFailures, t.file+":"+Itoa(line)+": Silent failure pattern detected")
+			result.Issues = append(result.Issues,
+				fs.add("fail_safe_design", t.file, line, "Silent failure pattern: "+truncate(m, 40)))
+		}
+	}
+
+	for _, pattern := range prePRInsecurePatterns {
+		for _, loc := range pattern.FindAllStringIndex(t.text, -1) {
+			m, line := t.at(loc)
+			result.InsecureDefaults = append(result.InsecureDefaults, t.file+":"+Itoa(line)+": "+truncate(m, 40))
+			result.Issues = append(result.Issues,
+				fs.add("fail_safe_design", t.file, line, "Insecure default: "+truncate(m, 40)))
+		}
+	}
+}
+
+// environmentVariablesIn records the first use in t of each variable that is
+// neither documented, well known nor already reported.
+func (fs *prePRFindings) environmentVariablesIn(result *EnvironmentVariablesResult, t prePRText, documented, reported map[string]bool) {
+	for _, loc := range prePREnvVarPattern.FindAllStringSubmatchIndex(t.text, -1) {
+		varName := t.text[loc[4]:loc[5]]
+		if documented[varName] || reported[varName] || isWellKnownEnvVar(varName) {
+			continue
+		}
+		reported[varName] = true
+		_, line := t.at(loc)
+		result.MissingDefaults = append(result.MissingDefaults, varName)
+		result.Issues = append(result.Issues,
+			fs.add("environment_variables", t.file, line, "Environment variable "+varName+" is not documented in an env file"))
+	}
+}
+
 // ValidatePrePR runs all pre-PR validation checks.
 // basePath is the root directory to validate.
 // quickMode skips slow validations.
@@ -176,119 +310,94 @@ func ValidatePrePR(basePath string, quickMode bool) PrePRValidationResult {
 }
 
 // ValidatePrePRWithConfig runs pre-PR validation with custom configuration.
+// With config.Base set, only the changes since the merge base of Base and
+// HEAD are checked; see ValidatePrePRAgainstBase.
 func ValidatePrePRWithConfig(config PrePRConfig) PrePRValidationResult {
-	var checks []Check
-	allPassed := true
+	if config.Base != "" {
+		return ValidatePrePRAgainstBase(config)
+	}
 
 	result := PrePRValidationResult{
-		Mode: "full",
-	}
-	if config.QuickMode {
-		result.Mode = "quick"
+		Mode: prePRMode(config.QuickMode),
 	}
+	tree := &prePRTree{config: config}
 
 	// 1. Cross-cutting concerns
-	crossCuttingResult := ValidateCrossCuttingConcerns(config)
-	result.CrossCuttingConcerns = crossCuttingResult
-	if !crossCuttingResult.Passed {
-		allPassed = false
-		for _, issue := range crossCuttingResult.Issues {
-			checks = append(checks, Check{
-				Name:    "cross_cutting_concerns",
-				Passed:  false,
-				Message: issue,
-			})
-		}
-	} else {
-		checks = append(checks, Check{
-			Name:    "cross_cutting_concerns",
-			Passed:  true,
-			Message: "No cross-cutting concern violations found",
-		})
-	}
+	result.CrossCuttingConcerns = tree.crossCuttingConcerns()
 
 	// 2. Fail-safe design
-	failSafeResult := ValidateFailSafeDesign(config)
-	result.FailSafeDesign = failSafeResult
-	if !failSafeResult.Passed {
-		allPassed = false
-		for _, issue := range failSafeResult.Issues {
-			checks = append(checks, Check{
-				Name:    "fail_safe_design",
-				Passed:  false,
-				Message: issue,
-			})
-		}
-	} else {
-		checks = append(checks, Check{
-			Name:    "fail_safe_design",
-			Passed:  true,
-			Message: "Fail-safe design patterns verified",
-		})
-	}
+	result.FailSafeDesign = tree.failSafeDesign()
 
-	// 3. Test-implementation alignment (skip if quickMode or skipTests)
+	// 3. Test-implementation alignment (skip if skipTests)
 	if !config.SkipTests {
-		testResult := ValidateTestImplementationAlignment(config)
-		result.TestImplementation = testResult
-		if !testResult.Passed {
-			allPassed = false
-			for _, issue := range testResult.Issues {
-				checks = append(checks, Check{
-					Name:    "test_implementation",
-					Passed:  false,
-					Message: issue,
-				})
-			}
-		} else {
-			checks = append(checks, Check{
-				Name:    "test_implementation",
-				Passed:  true,
-				Message: "Test-implementation alignment verified",
-			})
-		}
+		result.TestImplementation = ValidateTestImplementationAlignment(config)
 	}
 
 	// 4. CI environment (skip if quickMode)
 	if !config.QuickMode {
-		ciResult := ValidateCIEnvironment(config)
-		result.CIEnvironment = ciResult
-		if !ciResult.Passed {
-			allPassed = false
-			for _, issue := range ciResult.Issues {
-				checks = append(checks, Check{
-					Name:    "ci_environment",
-					Passed:  false,
-					Message: issue,
-				})
-			}
-		} else {
+		result.CIEnvironment = tree.ciEnvironment()
+	}
+
+	// 5. Environment variables
+	result.EnvironmentVariables = tree.environmentVariables()
+
+	result.Located = tree.prePRFindings
+	finishPrePRResult(&result, prePRSections(result, !config.SkipTests, !config.QuickMode, true))
+	return result
+}
+
+// prePRSection is the outcome of one pre-PR check.
+type prePRSection struct {
+	name        string
+	passed      bool
+	issues      []string
+	passMessage string
+}
+
+// prePRSections returns the sections of result in order. Cross-cutting and
+// fail-safe always run; the others only when requested.
+func prePRSections(result PrePRValidationResult, tests, ci, env bool) []prePRSection {
+	sections := []prePRSection{
+		{"cross_cutting_concerns", result.CrossCuttingConcerns.Passed, result.CrossCuttingConcerns.Issues, "No cross-cutting concern violations found"},
+		{"fail_safe_design", result.FailSafeDesign.Passed, result.FailSafeDesign.Issues, "Fail-safe design patterns verified"},
+	}
+	if tests {
+		ti := result.TestImplementation
+		sections = append(sections,
+			prePRSection{"test_implementation", len(ti.Issues) == 0, ti.Issues, "Test-implementation alignment verified"},
+			prePRSection{"parameter_drift", len(ti.ParameterDrift) == 0, ti.ParameterDrift, "No parameter drift found"})
+	}
+	if ci {
+		sections = append(sections, prePRSection{"ci_environment", result.CIEnvironment.Passed, result.CIEnvironment.Issues, "CI environment compatibility verified"})
+	}
+	if env {
+		sections = append(sections, prePRSection{"environment_variables", result.EnvironmentVariables.Passed, result.EnvironmentVariables.Issues, "Environment variables documented"})
+	}
+	return sections
+}
+
+// finishPrePRResult sets the checks, message and remediation of result: a
+// failing check per issue of a failed section, or one passing check.
+func finishPrePRResult(result *PrePRValidationResult, sections []prePRSection) {
+	var checks []Check
+	allPassed := true
+	for _, section := range sections {
+		if section.passed {
 			checks = append(checks, Check{
-				Name:    "ci_environment",
+				Name:    section.name,
 				Passed:  true,
-				Message: "CI environment compatibility verified",
+				Message: section.passMessage,
 			})
+			continue
 		}
-	}
-
-	// 5. Environment variables
-	envResult := ValidateEnvironmentVariables(config)
-	result.EnvironmentVariables = envResult
-	if !envResult.Passed {
 		allPassed = false
-		for _, issue := range envResult.Issues {
+		for _, issue := range section.issues {
 			checks = append(checks, Check{
-				Name:    "environment_variables",
+				Name:    section.name,
 				Passed:  false,
 				Message: issue,
 			})
 		}
-	} else {
-		checks = append(checks, Check{
-			Name:    "environment_variables",
-			Passed:  true,
-			Message: "Environment variables documented",
-		})
 	}
 
 	result.ValidationResult = ValidationResult{
@@ -302,232 +411,88 @@ func ValidatePrePRWithConfig(config PrePRConfig) PrePRValidationResult {
 		result.Message = "Pre-PR validation failed"
 		result.Remediation = buildPrePRRemediation(checks)
 	}
+}
 
-	return result
+func prePRMode(quickMode bool) string {
+	if quickMode {
+		return "quick"
+	}
+	return "full"
 }
 
 // ValidateCrossCuttingConcerns checks for hardcoded values, TODOs, and env vars.
 func ValidateCrossCuttingConcerns(config PrePRConfig) CrossCuttingConcernsResult {
-	result := CrossCuttingConcernsResult{
-		Passed: true,
-	}
+	return (&prePRTree{config: config}).crossCuttingConcerns()
+}
 
-	// Patterns to detect
-	hardcodedPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)(password|secret|api[_-]?key|token)\s*[:=]\s*["'][^"']+["']`),
-		regexp.MustCompile(`(?i)localhost:\d{4,5}`),
-		regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`), // IP addresses
-	}
+// ValidateFailSafeDesign checks for exit code validation and error handling.
+func ValidateFailSafeDesign(config PrePRConfig) FailSafeDesignResult {
+	return (&prePRTree{config: config}).failSafeDesign()
+}
 
-	todoPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)\b(TODO|FIXME|XXX|HACK)\b`),
-	}
+// prePRTree checks every file under the source directories of config and,
+// like prePRDiff for added lines, records each problem at its line.
+type prePRTree struct {
+	prePRFindings
+	config PrePRConfig
+}
 
-	for _, dir := range config.SourceDirs {
-		dirPath := filepath.Join(config.BasePath, dir)
+// walkSources calls fn with each file under the source directories for
+// which match is true. A file below several source directories is checked
+// once.
+func (t *prePRTree) walkSources(match func(path string) bool, fn func(file prePRText)) {
+	seen := make(map[string]bool)
+	for _, dir := range t.config.SourceDirs {
+		dirPath := filepath.Join(t.config.BasePath, dir)
 		if !DirExists(dirPath) {
 			continue
 		}
 
-		err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				return nil
-			}
-			if !isSourceFile(path) {
+		filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !match(path) || seen[path] {
 				return nil
 			}
+			seen[path] = true
 
 			content, err := os.ReadFile(path)
 			if err != nil {
 				return nil
 			}
-
-			relPath, _ := filepath.Rel(config.BasePath, path)
-
-			// Check for hardcoded values
-			for _, pattern := range hardcodedPatterns {
-				if matches := pattern.FindAllString(string(content), -1); len(matches) > 0 {
-					for _, m := range matches {
-						result.HardcodedValues = append(result.HardcodedValues,
-							relPath+": "+truncate(m, 50))
-					}
-				}
-			}
-
-			// Check for TODO/FIXME comments
-			for _, pattern := range todoPatterns {
-				if matches := pattern.FindAllString(string(content), -1); len(matches) > 0 {
-					for range matches {
-						result.TodoComments = append(result.TodoComments, relPath)
-					}
-				}
-			}
-
+			relPath, _ := filepath.Rel(t.config.BasePath, path)
+			fn(prePRText{file: filepath.ToSlash(relPath), text: string(content), line: 1})
 			return nil
 		})
-		if err != nil {
-			continue
-		}
 	}
+}
 
-	// Deduplicate TODO comments (file level)
-	result.TodoComments = deduplicate(result.TodoComments)
-
-	// Build issues
-	if len(result.HardcodedValues) > 0 {
-		result.Passed = false
-		result.Issues = append(result.Issues,
-			"Found "+Itoa(len(result.HardcodedValues))+" hardcoded values (secrets, IPs, ports)")
-	}
-	if len(result.TodoComments) > 0 {
-		result.Passed = false
-		result.Issues = append(result.Issues,
-			"Found TODO/FIXME comments in "+Itoa(len(result.TodoComments))+" files")
+func (t *prePRTree) crossCuttingConcerns() CrossCuttingConcernsResult {
+	result := CrossCuttingConcernsResult{
+		Passed: true,
 	}
-
+	t.walkSources(isSourceFile, func(file prePRText) {
+		t.crossCuttingIn(&result, file)
+	})
+	result.Passed = len(result.Issues) == 0
 	return result
 }
 
-// ValidateFailSafeDesign checks for exit code validation and error handling.
-func ValidateFailSafeDesign(config PrePRConfig) FailSafeDesignResult {
+func (t *prePRTree) failSafeDesign() FailSafeDesignResult {
 	result := FailSafeDesignResult{
 		Passed: true,
 	}
 
-	// Patterns that indicate missing exit code checks
-	exitCodePatterns := map[string][]*regexp.Regexp{
-		"powershell": {
-			regexp.MustCompile(`(?i)\$LASTEXITCODE`),
-			regexp.MustCompile(`(?i)-ErrorAction\s+Stop`),
-		},
-		"bash": {
-			regexp.MustCompile(`\$\?`),
-			regexp.MustCompile(`set\s+-e`),
-		},
-	}
-
-	// Patterns that indicate silent failures
-	silentFailurePatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)catch\s*\{\s*\}`),                 // Empty catch blocks
-		regexp.MustCompile(`(?i)catch\s*\([^)]*\)\s*\{\s*\}`),     // Empty catch blocks
-		regexp.MustCompile(`(?i)2>\s*/dev/null`),                  // Suppressed errors
-		regexp.MustCompile(`(?i)-ErrorAction\s+SilentlyContinue`), // PowerShell silent
-		regexp.MustCompile(`(?i)On\s+Error\s+Resume\s+Next`),      // VB-style
-	}
-
-	// Insecure default patterns
-	insecurePatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)AllowAny|AllowAll`),
-		regexp.MustCompile(`(?i)disable.*security|security.*disable`),
-		regexp.MustCompile(`(?i)skip.*validation|validation.*skip`),
-		regexp.MustCompile(`(?i)InsecureSkipVerify\s*[:=]\s*true`),
-	}
-
-	for _, dir := range config.SourceDirs {
-		dirPath := filepath.Join(config.BasePath, dir)
-		if !DirExists(dirPath) {
-			continue
-		}
-
-		err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				return nil
-			}
-			if !isScriptFile(path) && !isSourceFile(path) {
-				return nil
-			}
-
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return nil
-			}
-
-			relPath, _ := filepath.Rel(config.BasePath, path)
-			contentStr := string(content)
-
-			// Check for exit code validation in scripts
-			ext := strings.ToLower(filepath.Ext(path))
-			if ext == ".ps1" || ext == ".psm1" {
-				hasExitCheck := false
-				for _, pattern := range exitCodePatterns["powershell"] {
-					if pattern.MatchString(contentStr) {
-						hasExitCheck = true
-						break
-					}
-				}
-				// Only flag if file has external command calls
-				if !hasExitCheck && hasExternalCalls(contentStr, "powershell") {
-					result.MissingExitCodeChecks = append(result.MissingExitCodeChecks,
-						relPath+": No $LASTEXITCODE or -ErrorAction Stop")
-				}
-			} else if ext == ".sh" || ext == ".bash" {
-				hasExitCheck := false
-				for _, pattern := range exitCodePatterns["bash"] {
-					if pattern.MatchString(contentStr) {
-						hasExitCheck = true
-						break
-					}
-				}
-				if !hasExitCheck && hasExternalCalls(contentStr, "bash") {
-					result.MissingExitCodeChecks = append(result.MissingExitCodeChecks,
-						relPath+": No $? check or set -e")
-				}
-			}
-
-			// Check for silent failures
-			for _, pattern := range silentFailurePatterns {
-				if matches := pattern.FindAllString(contentStr, -1); len(matches) > 0 {
-					result.SilentFailures = append(result.SilentFailures,
-						relPath+": Silent failure pattern detected")
-					break
-				}
-			}
-
-			// Check for insecure defaults
-			for _, pattern := range insecurePatterns {
-				if matches := pattern.FindAllString(contentStr, -1); len(matches) > 0 {
-					for _, m := range matches {
-						result.InsecureDefaults = append(result.InsecureDefaults,
-							relPath+": "+truncate(m, 40))
-					}
-				}
-			}
-
-			return nil
-		})
-		if err != nil {
-			continue
+	isChecked := func(path string) bool { return isScriptFile(path) || isSourceFile(path) }
+	t.walkSources(isChecked, func(file prePRText) {
+		// A script without exit code checks is reported at its first line
+		if lang, message := scriptExitCheck(file.file); lang != "" && !hasExitCheck(file.text, lang) && hasExternalCalls(file.text, lang) {
+			result.MissingExitCodeChecks = append(result.MissingExitCodeChecks, file.file+": "+message)
+			result.Issues = append(result.Issues,
+				t.add("fail_safe_design", file.file, 1, message))
 		}
-	}
-
-	// Deduplicate
-	result.MissingExitCodeChecks = deduplicate(result.MissingExitCodeChecks)
-	result.SilentFailures = deduplicate(result.SilentFailures)
-	result.InsecureDefaults = deduplicate(result.InsecureDefaults)
-
-	// Build issues
-	if len(result.MissingExitCodeChecks) > 0 {
-		result.Passed = false
-		result.Issues = append(result.Issues,
-			Itoa(len(result.MissingExitCodeChecks))+" scripts missing exit code validation")
-	}
-	if len(result.SilentFailures) > 0 {
-		result.Passed = false
-		result.Issues = append(result.Issues,
-			Itoa(len(result.SilentFailures))+" files have silent failure patterns")
-	}
-	if len(result.InsecureDefaults) > 0 {
-		result.Passed = false
-		result.Issues = append(result.Issues,
-			Itoa(len(result.InsecureDefaults))+" insecure default patterns found")
-	}
+		t.failSafeIn(&result, file)
+	})
 
+	result.Passed = len(result.Issues) == 0
 	return result
 }
 
@@ -628,8 +593,10 @@ func ValidateTestImplementationAlignment(config PrePRConfig) TestImplementationR
 	}
 
 	// Check for test-implementation parameter drift
-	// This is a simplified check - in reality you'd need language-specific parsing
-	result.ParameterDrift = checkParameterDrift(config, testFiles)
+	result.Drift = checkParameterDrift(config)
+	for _, drift := range result.Drift {
+		result.ParameterDrift = append(result.ParameterDrift, drift.issue())
+	}
 
 	// Build issues
 	if len(result.MissingTestCoverage) > 5 {
@@ -639,9 +606,8 @@ func ValidateTestImplementationAlignment(config PrePRConfig) TestImplementationR
 			Itoa(len(result.MissingTestCoverage))+" source files lack test coverage")
 	}
 	if len(result.ParameterDrift) > 0 {
+		// Reported as parameter_drift checks, one per location
 		result.Passed = false
-		result.Issues = append(result.Issues,
-			Itoa(len(result.ParameterDrift))+" potential parameter drift issues")
 	}
 	if result.CoveragePercent < 50 && totalSourceFiles > 5 {
 		result.Passed = false
@@ -654,35 +620,23 @@ func ValidateTestImplementationAlignment(config PrePRConfig) TestImplementationR
 
 // ValidateCIEnvironment checks CI environment compatibility.
 func ValidateCIEnvironment(config PrePRConfig) CIEnvironmentResult {
+	return (&prePRTree{config: config}).ciEnvironment()
+}
+
+func (t *prePRTree) ciEnvironment() CIEnvironmentResult {
 	result := CIEnvironmentResult{
 		Passed: true,
 	}
 
-	// Check for CI configuration files
-	ciConfigFiles := []string{
-		".github/workflows",
-		".gitlab-ci.yml",
-		"azure-pipelines.yml",
-		"Jenkinsfile",
-		".circleci/config.yml",
-	}
-
-	ciConfigFound := false
-	for _, ciPath := range ciConfigFiles {
-		fullPath := filepath.Join(config.BasePath, ciPath)
-		if FileExists(fullPath) || DirExists(fullPath) {
-			ciConfigFound = true
-			result.ConfigDocumented = true
-			break
-		}
-	}
-
-	if !ciConfigFound {
+	ciConfigFound := hasCIConfig(t.config.BasePath)
+	if ciConfigFound {
+		result.ConfigDocumented = true
+	} else {
 		result.Issues = append(result.Issues, "No CI configuration found")
 	}
 
 	// Check for CI-specific environment variables in workflows
-	workflowsPath := filepath.Join(config.BasePath, ".github", "workflows")
+	workflowsPath := filepath.Join(t.config.BasePath, ".github", "workflows")
 	if DirExists(workflowsPath) {
 		filepath.Walk(workflowsPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil || info.IsDir() {
@@ -698,7 +652,8 @@ func ValidateCIEnvironment(config PrePRConfig) CIEnvironmentResult {
 				return nil
 			}
 
-			relPath, _ := filepath.Rel(config.BasePath, path)
+			relPath, _ := filepath.Rel(t.config.BasePath, path)
+			relPath = filepath.ToSlash(relPath)
 
 			// Check for CI flags usage
 			if strings.Contains(string(content), "GITHUB_ACTIONS") ||
@@ -713,9 +668,11 @@ func ValidateCIEnvironment(config PrePRConfig) CIEnvironmentResult {
 			}
 
 			// Check for potential issues
-			if strings.Contains(string(content), "continue-on-error: true") {
-				result.Issues = append(result.Issues,
-					relPath+": Uses continue-on-error which may hide failures")
+			for i, line := range strings.Split(string(content), "\n") {
+				if strings.Contains(line, "continue-on-error: true") {
+					result.Issues = append(result.Issues,
+						t.add("ci_environment", relPath, i+1, "Uses continue-on-error which may hide failures"))
+				}
 			}
 
 			return nil
@@ -730,51 +687,47 @@ func ValidateCIEnvironment(config PrePRConfig) CIEnvironmentResult {
 	return result
 }
 
+// hasCIConfig reports whether root holds a CI configuration.
+func hasCIConfig(root string) bool {
+	for _, ciPath := range []string{".github/workflows", ".gitlab-ci.yml", "azure-pipelines.yml", "Jenkinsfile", ".circleci/config.yml"} {
+		fullPath := filepath.Join(root, ciPath)
+		if FileExists(fullPath) || DirExists(fullPath) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateEnvironmentVariables checks environment variable documentation.
 func ValidateEnvironmentVariables(config PrePRConfig) EnvironmentVariablesResult {
+	return (&prePRTree{config: config}).environmentVariables()
+}
+
+func (t *prePRTree) environmentVariables() EnvironmentVariablesResult {
 	result := EnvironmentVariablesResult{
 		Passed: true,
 	}
 
-	// Find all env var references in code
-	envVarPattern := regexp.MustCompile(`(?i)(process\.env\.|os\.environ\[|os\.getenv\(|Environment\.GetEnvironmentVariable\(|\$env:|getenv\()\s*["']?([A-Z][A-Z0-9_]+)`)
-	envVarRefs := make(map[string]bool)
-
-	for _, srcDir := range config.SourceDirs {
-		srcPath := filepath.Join(config.BasePath, srcDir)
-		if !DirExists(srcPath) {
-			continue
-		}
-
-		filepath.Walk(srcPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() {
-				return nil
-			}
-			if !isSourceFile(path) {
-				return nil
-			}
-
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return nil
-			}
+	documentedVars, names := readDocumentedEnvVars(t.config.BasePath, t.config.EnvFiles)
+	result.DocumentedVars = names
 
-			matches := envVarPattern.FindAllStringSubmatch(string(content), -1)
-			for _, match := range matches {
-				if len(match) > 2 {
-					envVarRefs[match[2]] = true
-				}
-			}
+	reported := make(map[string]bool)
+	t.walkSources(isSourceFile, func(file prePRText) {
+		t.environmentVariablesIn(&result, file, documentedVars, reported)
+	})
 
-			return nil
-		})
-	}
+	result.Passed = len(result.Issues) == 0
+	return result
+}
 
-	// Check for documented env vars
+// readDocumentedEnvVars returns the variables set in the env files under
+// root, as a set and in file order.
+func readDocumentedEnvVars(root string, envFiles []string) (map[string]bool, []string) {
 	documentedVars := make(map[string]bool)
+	var names []string
 
-	for _, envFile := range config.EnvFiles {
-		envPath := filepath.Join(config.BasePath, envFile)
+	for _, envFile := range envFiles {
+		envPath := filepath.Join(root, envFile)
 		if !FileExists(envPath) {
 			continue
 		}
@@ -795,97 +748,28 @@ func ValidateEnvironmentVariables(config PrePRConfig) EnvironmentVariablesResult
 			if idx := strings.Index(line, "="); idx > 0 {
 				varName := strings.TrimSpace(line[:idx])
 				documentedVars[varName] = true
-				result.DocumentedVars = append(result.DocumentedVars, varName)
-			}
-		}
-	}
-
-	// Find undocumented env vars
-	for varName := range envVarRefs {
-		if !documentedVars[varName] {
-			// Skip common well-known vars
-			if isWellKnownEnvVar(varName) {
-				continue
+				names = append(names, varName)
 			}
-			result.MissingDefaults = append(result.MissingDefaults, varName)
 		}
 	}
 
-	// Build issues
-	if len(result.MissingDefaults) > 0 {
-		result.Passed = false
-		result.Issues = append(result.Issues,
-			Itoa(len(result.MissingDefaults))+" environment variables used but not documented")
-	}
-
-	return result
+	return documentedVars, names
 }
 
 // ValidatePrePRFromContent validates pre-PR checks from content strings.
 // Useful for WASM or testing without file system access.
 func ValidatePrePRFromContent(sourceContent map[string]string, quickMode bool) PrePRValidationResult {
-	var checks []Check
-	allPassed := true
-
 	result := PrePRValidationResult{
-		Mode: "full",
-	}
-	if quickMode {
-		result.Mode = "quick"
+		Mode: prePRMode(quickMode),
 	}
 
 	// Cross-cutting concerns from content
-	crossCuttingResult := validateCrossCuttingFromContent(sourceContent)
-	result.CrossCuttingConcerns = crossCuttingResult
-	if !crossCuttingResult.Passed {
-		allPassed = false
-		for _, issue := range crossCuttingResult.Issues {
-			checks = append(checks, Check{
-				Name:    "cross_cutting_concerns",
-				Passed:  false,
-				Message: issue,
-			})
-		}
-	} else {
-		checks = append(checks, Check{
-			Name:    "cross_cutting_concerns",
-			Passed:  true,
-			Message: "No cross-cutting concern violations found",
-		})
-	}
+	result.CrossCuttingConcerns = validateCrossCuttingFromContent(sourceContent)
 
 	// Fail-safe design from content
-	failSafeResult := validateFailSafeFromContent(sourceContent)
-	result.FailSafeDesign = failSafeResult
-	if !failSafeResult.Passed {
-		allPassed = false
-		for _, issue := range failSafeResult.Issues {
-			checks = append(checks, Check{
-				Name:    "fail_safe_design",
-				Passed:  false,
-				Message: issue,
-			})
-		}
-	} else {
-		checks = append(checks, Check{
-			Name:    "fail_safe_design",
-			Passed:  true,
-			Message: "Fail-safe design patterns verified",
-		})
-	}
-
-	result.ValidationResult = ValidationResult{
-		Valid:  allPassed,
-		Checks: checks,
-	}
-
-	if allPassed {
-		result.Message = "Pre-PR validation passed"
-	} else {
-		result.Message = "Pre-PR validation failed"
-		result.Remediation = buildPrePRRemediation(checks)
-	}
+	result.FailSafeDesign = validateFailSafeFromContent(sourceContent)
 
+	finishPrePRResult(&result, prePRSections(result, false, false, false))
 	return result
 }
 
@@ -958,12 +842,10 @@ func shouldHaveTests(path string) bool {
 	return true
 }
 
-func checkParameterDrift(_ PrePRConfig, _ map[string]string) []string {
-	var drifts []string
-	// This is a simplified implementation
-	// Real implementation would parse function signatures and compare
-	// For now, we check for common patterns
-	return drifts
+// checkParameterDrift finds uses of exported functions that still have
+// their old parameters, comparing with config.Base or, without one, HEAD.
+func checkParameterDrift(config PrePRConfig) []ParameterDrift {
+	return DetectParameterDrift(config.BasePath, config.Base)
 }
 
 func hasExternalCalls(content, lang string) bool {
@@ -0,0 +1,305 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ValidatePrePRAgainstBase runs the pre-PR checks on the changes since the
+// merge base of config.Base and HEAD, including uncommitted changes to
+// tracked files. The diff is the scope, so SourceDirs is not used: each check
+// looks only at the lines added to files it applies to, and every problem is
+// reported at its line in the changed file.
+func ValidatePrePRAgainstBase(config PrePRConfig) PrePRValidationResult {
+	diff, err := gitDiffAgainst(config.BasePath, config.Base)
+	if err != nil {
+		return PrePRValidationResult{
+			ValidationResult: ValidationResult{
+				Valid:       false,
+				Message:     "Pre-PR validation failed: " + err.Error(),
+				Checks:      []Check{{Name: "diff", Passed: false, Message: err.Error()}},
+				Remediation: "Fetch " + config.Base + " or pass a base ref that shares history with HEAD",
+			},
+			Mode: prePRMode(config.QuickMode),
+			Base: config.Base,
+		}
+	}
+
+	d := prePRDiff{root: config.BasePath, base: config.Base, files: ParseUnifiedDiff(diff)}
+	result := d.validate(config, !config.SkipTests, !config.QuickMode, true)
+	result.Base = config.Base
+	return result
+}
+
+// ValidatePrePRFromDiff runs the cross-cutting and fail-safe checks on the
+// lines a unified diff adds, such as the output of 'git diff --cached' in a
+// pre-commit hook. Like ValidatePrePRFromContent it needs no file system, so
+// scripts are only checked for exit code handling when the diff adds them.
+func ValidatePrePRFromDiff(diff string, quickMode bool) PrePRValidationResult {
+	d := prePRDiff{files: ParseUnifiedDiff(diff)}
+	return d.validate(PrePRConfig{QuickMode: quickMode}, false, false, false)
+}
+
+// prePRDiff checks the lines added by a diff.
+type prePRDiff struct {
+	prePRFindings
+	root  string // working tree the diff applies to; empty when unknown
+	base  string // ref whose merge base parameter drift is checked against
+	files []DiffFile
+}
+
+// validate runs cross-cutting and fail-safe checks and, when requested, the
+// test alignment, CI and environment variable checks.
+func (d *prePRDiff) validate(config PrePRConfig, tests, ci, env bool) PrePRValidationResult {
+	result := PrePRValidationResult{
+		Mode: prePRMode(config.QuickMode),
+	}
+	for _, f := range d.files {
+		if f.Path != "" {
+			result.ChangedFiles = append(result.ChangedFiles, f.Path)
+		}
+	}
+
+	result.CrossCuttingConcerns = d.crossCuttingConcerns()
+	result.FailSafeDesign = d.failSafeDesign()
+	if tests {
+		result.TestImplementation = d.testImplementationAlignment()
+	}
+	if ci {
+		result.CIEnvironment = d.ciEnvironment()
+	}
+	if env {
+		envFiles := config.EnvFiles
+		if len(envFiles) == 0 {
+			envFiles = PrePRConfigDefaults.EnvFiles
+		}
+		result.EnvironmentVariables = d.environmentVariables(envFiles)
+	}
+
+	result.Located = d.prePRFindings
+	finishPrePRResult(&result, prePRSections(result, tests, ci, env))
+	return result
+}
+
+// prePRFindings collects the problems pre-PR checks find at a line.
+type prePRFindings []PrePRFinding
+
+// add records a finding and returns it as an issue.
+func (fs *prePRFindings) add(check, file string, line int, message string) string {
+	finding := PrePRFinding{Check: check, File: file, Line: line, Message: message}
+	*fs = append(*fs, finding)
+	return finding.issue()
+}
+
+// issue is the finding as a check message.
+func (f PrePRFinding) issue() string {
+	return f.File + ":" + Itoa(f.Line) + " - " + f.Message
+}
+
+// content returns the full content of a changed file: from the working tree
+// or, without one, from the diff when it adds the whole file.
+func (d *prePRDiff) content(f DiffFile) (string, bool) {
+	if d.root != "" {
+		data, err := os.ReadFile(filepath.Join(d.root, filepath.FromSlash(f.Path)))
+		return string(data), err == nil
+	}
+	if f.OldPath != "" {
+		return "", false
+	}
+	lines := make([]string, len(f.Added))
+	for i, line := range f.Added {
+		lines[i] = line.Text
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// exists reports whether path is changed by the diff or is in the working tree.
+func (d *prePRDiff) exists(path string) bool {
+	path = filepath.ToSlash(path)
+	for _, f := range d.files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return d.root != "" && FileExists(filepath.Join(d.root, filepath.FromSlash(path)))
+}
+
+func (d *prePRDiff) crossCuttingConcerns() CrossCuttingConcernsResult {
+	result := CrossCuttingConcernsResult{
+		Passed: true,
+	}
+
+	for _, f := range d.files {
+		if f.Path == "" || !isSourceFile(f.Path) {
+			continue
+		}
+		for _, line := range f.Added {
+			d.crossCuttingIn(&result, prePRText{file: f.Path, text: line.Text, line: line.Line})
+		}
+	}
+
+	result.Passed = len(result.Issues) == 0
+	return result
+}
+
+func (d *prePRDiff) failSafeDesign() FailSafeDesignResult {
+	result := FailSafeDesignResult{
+		Passed: true,
+	}
+
+	for _, f := range d.files {
+		if f.Path == "" || len(f.Added) == 0 || (!isScriptFile(f.Path) && !isSourceFile(f.Path)) {
+			continue
+		}
+
+		// A changed script without exit code checks is reported at its
+		// first added line; the check needs the whole script.
+		if lang, message := scriptExitCheck(f.Path); lang != "" {
+			if content, ok := d.content(f); ok && !hasExitCheck(content, lang) && hasExternalCalls(content, lang) {
+				result.MissingExitCodeChecks = append(result.MissingExitCodeChecks, f.Path+": "+message)
+				result.Issues = append(result.Issues,
+					d.add("fail_safe_design", f.Path, f.FirstLine(), message))
+			}
+		}
+
+		for _, line := range f.Added {
+			d.failSafeIn(&result, prePRText{file: f.Path, text: line.Text, line: line.Line})
+		}
+	}
+
+	result.Passed = len(result.Issues) == 0
+	return result
+}
+
+// scriptExitCheck returns the exit code check language of a script and the
+// message for a script without one; "" for other files.
+func scriptExitCheck(path string) (lang, message string) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1", ".psm1":
+		return "powershell", "No $LASTEXITCODE or -ErrorAction Stop"
+	case ".sh", ".bash":
+		return "bash", "No $? check or set -e"
+	}
+	return "", ""
+}
+
+func hasExitCheck(content, lang string) bool {
+	for _, pattern := range prePRExitCheckPatterns[lang] {
+		if pattern.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *prePRDiff) testImplementationAlignment() TestImplementationResult {
+	result := TestImplementationResult{
+		Passed: true,
+	}
+
+	totalSourceFiles := 0
+	testedSourceFiles := 0
+	for _, f := range d.files {
+		if f.Path == "" || !isSourceFile(f.Path) || isTestFile(f.Path) {
+			continue
+		}
+		totalSourceFiles++
+
+		testPaths := generateTestPaths(f.Path)
+		hasCoverage := false
+		for _, testPath := range testPaths {
+			if d.exists(testPath) {
+				hasCoverage = true
+				testedSourceFiles++
+				break
+			}
+		}
+
+		if !hasCoverage && shouldHaveTests(f.Path) {
+			result.MissingTestCoverage = append(result.MissingTestCoverage, f.Path)
+			result.Issues = append(result.Issues,
+				d.add("test_implementation", f.Path, f.FirstLine(), "No test file; expected "+filepath.ToSlash(testPaths[0])))
+		}
+	}
+
+	if totalSourceFiles > 0 {
+		result.CoveragePercent = float64(testedSourceFiles) / float64(totalSourceFiles) * 100
+	}
+	if result.CoveragePercent < 50 && totalSourceFiles > 5 {
+		result.Issues = append(result.Issues,
+			"Test coverage of changed files is low: "+formatPercent(result.CoveragePercent)+"%")
+	}
+
+	// Stale uses of changed signatures are mostly on unchanged lines
+	if d.root != "" {
+		result.Drift = checkParameterDrift(PrePRConfig{BasePath: d.root, Base: d.base})
+		for _, drift := range result.Drift {
+			result.ParameterDrift = append(result.ParameterDrift, drift.issue())
+		}
+	}
+
+	result.Passed = len(result.Issues) == 0 && len(result.ParameterDrift) == 0
+	return result
+}
+
+func (d *prePRDiff) ciEnvironment() CIEnvironmentResult {
+	result := CIEnvironmentResult{
+		Passed: true,
+	}
+
+	ciConfigFound := hasCIConfig(d.root)
+	if ciConfigFound {
+		result.ConfigDocumented = true
+	} else {
+		result.Issues = append(result.Issues, "No CI configuration found")
+	}
+
+	for _, f := range d.files {
+		ext := strings.ToLower(filepath.Ext(f.Path))
+		if !strings.HasPrefix(f.Path, ".github/workflows/") || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+		for _, line := range f.Added {
+			if strings.Contains(line.Text, "GITHUB_ACTIONS") || strings.Contains(line.Text, "CI=true") {
+				result.CIFlagsVerified = true
+			}
+			if strings.Contains(line.Text, "build") || strings.Contains(line.Text, "test") {
+				result.BuildVerified = true
+			}
+			if strings.Contains(line.Text, "continue-on-error: true") {
+				result.Issues = append(result.Issues,
+					d.add("ci_environment", f.Path, line.Line, "Uses continue-on-error which may hide failures"))
+			}
+		}
+	}
+
+	// As for the whole tree, only a missing CI configuration fails
+	if len(result.Issues) > 0 && !ciConfigFound {
+		result.Passed = false
+	}
+
+	return result
+}
+
+func (d *prePRDiff) environmentVariables(envFiles []string) EnvironmentVariablesResult {
+	result := EnvironmentVariablesResult{
+		Passed: true,
+	}
+
+	documentedVars, names := readDocumentedEnvVars(d.root, envFiles)
+	result.DocumentedVars = names
+
+	reported := make(map[string]bool)
+	for _, f := range d.files {
+		if f.Path == "" || !isSourceFile(f.Path) {
+			continue
+		}
+		for _, line := range f.Added {
+			d.environmentVariablesIn(&result, prePRText{file: f.Path, text: line.Text, line: line.Line}, documentedVars, reported)
+		}
+	}
+
+	result.Passed = len(result.Issues) == 0
+	return result
+}
@@ -0,0 +1,161 @@
+package internal_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+// prePRBaseRepo commits a tree with existing problems on main and returns
+// the repository; changes after it are what a --base main run checks.
+func prePRBaseRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+	writeTestFile(t, filepath.Join(tmpDir, "src", "legacy.go"), "package src\n\n// TODO: old debt\nconst host = \"localhost:8080\"\n")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "app.go"), "package src\n\nfunc run() {}\n")
+	writeTestFile(t, filepath.Join(tmpDir, ".env.example"), "API_URL=\n")
+	writeTestFile(t, filepath.Join(tmpDir, ".github", "workflows", "ci.yml"), "on: push\n")
+	for _, args := range [][]string{{"checkout", "-qb", "main"}, {"add", "-A"}, {"commit", "-qm", "base"}, {"checkout", "-qb", "feature"}} {
+		if out, err := exec.Command("git", append([]string{"-C", tmpDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return tmpDir
+}
+
+func TestValidatePrePRAgainstBase_OnlyChangedLines(t *testing.T) {
+	tmpDir := prePRBaseRepo(t)
+	writeTestFile(t, filepath.Join(tmpDir, "src", "app.go"),
+		"package src\n\nimport \"os\"\n\nfunc run() {\n\t_ = os.Getenv(\"API_TOKEN\")\n\t_ = os.Getenv(\"API_URL\")\n}\n\n// FIXME: handle errors\n")
+
+	config := internal.DefaultPrePRConfig(tmpDir)
+	config.Base = "main"
+	result := internal.ValidatePrePRWithConfig(config)
+
+	if result.Valid {
+		t.Fatal("Expected the changes to fail validation")
+	}
+	if result.Base != "main" || strings.Join(result.ChangedFiles, ",") != "src/app.go" {
+		t.Errorf("base = %q, changed files = %v", result.Base, result.ChangedFiles)
+	}
+	want := []internal.PrePRFinding{
+		{Check: "cross_cutting_concerns", File: "src/app.go", Line: 10, Message: "FIXME comment"},
+		{Check: "environment_variables", File: "src/app.go", Line: 6, Message: "Environment variable API_TOKEN is not documented in an env file"},
+	}
+	if len(result.Located) != len(want) {
+		t.Fatalf("findings = %+v, want %+v", result.Located, want)
+	}
+	for i := range want {
+		if result.Located[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, result.Located[i], want[i])
+		}
+	}
+	for _, c := range result.Checks {
+		if strings.Contains(c.Message, "legacy.go") {
+			t.Errorf("unchanged file reported: %s", c.Message)
+		}
+	}
+	if !hasCheck(result.Checks, "cross_cutting_concerns", "src/app.go:10 - FIXME comment") {
+		t.Errorf("checks = %+v, want the located FIXME", result.Checks)
+	}
+}
+
+func TestValidatePrePRAgainstBase_NoChanges(t *testing.T) {
+	tmpDir := prePRBaseRepo(t)
+
+	result := internal.ValidatePrePRWithConfig(internal.PrePRConfig{BasePath: tmpDir, Base: "main"})
+
+	if !result.Valid {
+		t.Errorf("Expected no changes to pass, got: %+v", result.Checks)
+	}
+	if len(result.ChangedFiles) != 0 {
+		t.Errorf("changed files = %v, want none", result.ChangedFiles)
+	}
+}
+
+func TestValidatePrePRAgainstBase_UnknownBase(t *testing.T) {
+	tmpDir := prePRBaseRepo(t)
+
+	result := internal.ValidatePrePRWithConfig(internal.PrePRConfig{BasePath: tmpDir, Base: "origin/nope"})
+
+	if result.Valid || !strings.Contains(result.Message, "origin/nope") {
+		t.Errorf("Expected an unknown base to fail, got: %s", result.Message)
+	}
+}
+
+func TestValidatePrePRAgainstBase_Findings(t *testing.T) {
+	tmpDir := prePRBaseRepo(t)
+	writeTestFile(t, filepath.Join(tmpDir, ".github", "workflows", "ci.yml"), "on: push\njobs:\n  test:\n    continue-on-error: true\n")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "app.go"), "package src\n\nfunc run() {}\n\nvar skipValidation = true\n")
+
+	config := internal.DefaultPrePRConfig(tmpDir)
+	config.Base = "main"
+	findings := internal.ValidatePrePRWithConfig(config).Findings()
+
+	var located []string
+	for _, f := range findings {
+		if f.File != "" {
+			located = append(located, f.Check+" "+f.File+":"+internal.Itoa(f.Line)+" "+f.Severity)
+		}
+	}
+	want := "fail_safe_design src/app.go:5 error,ci_environment .github/workflows/ci.yml:4 warning"
+	if got := strings.Join(located, ","); got != want {
+		t.Errorf("located findings = %s, want %s", got, want)
+	}
+}
+
+func TestValidatePrePRFromDiff(t *testing.T) {
+	diff := `diff --git a/scripts/deploy.sh b/scripts/deploy.sh
+new file mode 100755
+--- /dev/null
++++ b/scripts/deploy.sh
+@@ -0,0 +1,3 @@
++#!/bin/bash
++rsync -a build/ server:/srv/app/ 2>/dev/null
++curl -fsS https://example.com/hooks/deployed --data "status=done&release=current"
+diff --git a/src/config.ts b/src/config.ts
+--- a/src/config.ts
++++ b/src/config.ts
+@@ -10,0 +11 @@ export const config = {
++  apiKey: "abc123",
+`
+
+	result := internal.ValidatePrePRFromDiff(diff, true)
+
+	if result.Valid {
+		t.Fatal("Expected the diff to fail validation")
+	}
+	if result.Mode != "quick" || result.Base != "" {
+		t.Errorf("mode = %q, base = %q", result.Mode, result.Base)
+	}
+	var got []string
+	for _, f := range result.Located {
+		got = append(got, f.File+":"+internal.Itoa(f.Line)+" "+f.Message)
+	}
+	want := []string{
+		"src/config.ts:11 Hardcoded value: apiKey: \"abc123\"",
+		"scripts/deploy.sh:1 No $? check or set -e",
+		"scripts/deploy.sh:2 Silent failure pattern: 2>/dev/null",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, c := range result.Checks {
+		if c.Name != "cross_cutting_concerns" && c.Name != "fail_safe_design" {
+			t.Errorf("unexpected check %s", c.Name)
+		}
+	}
+}
+
+func hasCheck(checks []internal.Check, name, message string) bool {
+	for _, c := range checks {
+		if c.Name == name && c.Message == message {
+			return true
+		}
+	}
+	return false
+}
@@ -815,6 +815,54 @@ func riskyOperation() {
 	}
 }
 
+func TestValidatePrePR_LocatesFindings(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "src", "app.js"), `const token = "abc123";
+function run() {
+  try {
+    work(process.env.APP_MODE);
+  } catch {
+  }
+}
+// TODO: FIXME twice on one line
+`)
+	// lib is below src, so its files are found once.
+	writeTestFile(t, filepath.Join(tmpDir, "src", "lib", "util.js"), "// TODO: tidy\n")
+
+	config := internal.DefaultPrePRConfig(tmpDir)
+	config.SourceDirs = []string{"src", "src/lib"}
+	config.QuickMode = true
+	config.SkipTests = true
+	result := internal.ValidatePrePRWithConfig(config)
+
+	want := []internal.PrePRFinding{
+		{Check: "cross_cutting_concerns", File: "src/app.js", Line: 1, Message: `Hardcoded value: token = "abc123"`},
+		{Check: "cross_cutting_concerns", File: "src/app.js", Line: 8, Message: "TODO comment"},
+		{Check: "cross_cutting_concerns", File: "src/lib/util.js", Line: 1, Message: "TODO comment"},
+		{Check: "fail_safe_design", File: "src/app.js", Line: 5, Message: "Silent failure pattern: catch { }"},
+		{Check: "environment_variables", File: "src/app.js", Line: 4, Message: "Environment variable APP_MODE is not documented in an env file"},
+	}
+	if len(result.Located) != len(want) {
+		t.Fatalf("located = %+v", result.Located)
+	}
+	for i := range want {
+		if result.Located[i] != want[i] {
+			t.Errorf("located[%d] = %+v, want %+v", i, result.Located[i], want[i])
+		}
+	}
+
+	// Each located problem is its own check and finding.
+	var located int
+	for _, f := range result.Findings() {
+		if f.File != "" {
+			located++
+		}
+	}
+	if result.Valid || len(result.Checks) != len(want) || located != len(want) {
+		t.Errorf("checks = %+v", result.Checks)
+	}
+}
+
 // Benchmark Tests
 
 func BenchmarkValidatePrePR(b *testing.B) {
@@ -376,6 +376,25 @@ func ValidateSkillDirectory(dirPath string) []SkillFormatValidationResult {
 	return results
 }
 
+// FindSkillFiles returns the SKILL.md files under dir, skipping hidden and
+// dependency directories.
+func FindSkillFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "SKILL.md" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
 // ValidateSkillFiles validates a list of specific skill files.
 // filePaths is a slice of file paths to validate.
 // Returns a slice of validation results, one per file.
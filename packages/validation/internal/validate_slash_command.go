@@ -464,6 +464,28 @@ func ValidateSlashCommandDirectoryRecursive(dirPath string) []SlashCommandValida
 	return results
 }
 
+// FindSlashCommandFiles returns the .md files in dirPath, and in its
+// subdirectories when recursive is set.
+func FindSlashCommandFiles(dirPath string, recursive bool) []string {
+	var files []string
+	_ = filepath.WalkDir(dirPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip directories with errors
+		}
+		if d.IsDir() {
+			if path != dirPath && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".md") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
 // ValidateSlashCommandFiles validates a list of specific slash command files.
 // filePaths is a slice of file paths to validate.
 // Returns a slice of validation results, one per file.
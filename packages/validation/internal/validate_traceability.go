@@ -22,6 +22,7 @@ type TraceabilityValidationResult struct {
 	Warnings  []TraceabilityIssue `json:"warnings,omitempty"`
 	Info      []TraceabilityIssue `json:"info,omitempty"`
 	ExitCode  int                 `json:"exitCode"`
+	Graph     *TraceabilityGraph  `json:"graph,omitempty"`
 }
 
 // TraceabilityStats contains counts of specs and valid chains.
@@ -34,10 +35,12 @@ type TraceabilityStats struct {
 
 // TraceabilityIssue represents a single traceability violation.
 type TraceabilityIssue struct {
-	Rule    string `json:"rule"`
-	Source  string `json:"source"`
-	Target  string `json:"target,omitempty"`
-	Message string `json:"message"`
+	Rule       string `json:"rule"`
+	Source     string `json:"source"`
+	Target     string `json:"target,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"` // status correction, for status rules
+	File       string `json:"file,omitempty"`       // spec file of Source, when loaded from disk
 }
 
 // SpecFrontmatter represents parsed YAML frontmatter from a spec file.
@@ -46,6 +49,9 @@ type SpecFrontmatter struct {
 	ID       string   `json:"id"`
 	Status   string   `json:"status"`
 	Related  []string `json:"related,omitempty"`
+	Code     []string `json:"code,omitempty"`    // TASK only: implementing files, relative to the repository root
+	Tests    []string `json:"tests,omitempty"`   // TASK only: test files that reference the task ID
+	Commits  []string `json:"commits,omitempty"` // TASK only: implementing commits
 	FilePath string   `json:"filePath,omitempty"`
 }
 
@@ -161,12 +167,27 @@ type SpecCollection struct {
 	Designs      map[string]*SpecFrontmatter
 	Tasks        map[string]*SpecFrontmatter
 	All          map[string]*SpecFrontmatter
+
+	// Root is the repository root that TASK code and test paths are
+	// relative to. When empty, those paths and implementing commits are not
+	// checked.
+	Root string
+
+	// StatusRules are the status propagation rules to check; nil checks
+	// DefaultStatusRules.
+	StatusRules []StatusRule
 }
 
 // ValidateTraceability validates traceability cross-references between spec artifacts.
 // specsPath is the path to the specs directory (default: ".agents/specs").
 // strict determines whether warnings cause failure (exit code 2 vs 0).
 func ValidateTraceability(specsPath string, strict bool) TraceabilityValidationResult {
+	return ValidateTraceabilityWithRules(specsPath, strict, nil)
+}
+
+// ValidateTraceabilityWithRules is ValidateTraceability with the given
+// status propagation rules; nil checks DefaultStatusRules.
+func ValidateTraceabilityWithRules(specsPath string, strict bool, rules []StatusRule) TraceabilityValidationResult {
 	result := TraceabilityValidationResult{
 		SpecsPath: specsPath,
 		Strict:    strict,
@@ -194,6 +215,7 @@ func ValidateTraceability(specsPath string, strict bool) TraceabilityValidationR
 
 	// Load all specs
 	specs := LoadAllSpecs(absPath)
+	specs.StatusRules = rules
 	result.Stats.Requirements = len(specs.Requirements)
 	result.Stats.Designs = len(specs.Designs)
 	result.Stats.Tasks = len(specs.Tasks)
@@ -204,6 +226,10 @@ func ValidateTraceability(specsPath string, strict bool) TraceabilityValidationR
 	result.Warnings = testResult.Warnings
 	result.Info = testResult.Info
 	result.Stats.ValidChains = testResult.ValidChains
+	graph := BuildTraceabilityGraph(specs)
+	result.Graph = &graph
+
+	setIssueFiles(specs, result.Errors, result.Warnings, result.Info)
 
 	// Build checks for ValidationResult
 	var checks []Check
@@ -260,12 +286,14 @@ func ValidateTraceability(specsPath string, strict bool) TraceabilityValidationR
 }
 
 // LoadAllSpecs loads all specification files from the specs directory.
+// TASK code and test paths are resolved against the repository holding it.
 func LoadAllSpecs(basePath string) *SpecCollection {
 	specs := &SpecCollection{
 		Requirements: make(map[string]*SpecFrontmatter),
 		Designs:      make(map[string]*SpecFrontmatter),
 		Tasks:        make(map[string]*SpecFrontmatter),
 		All:          make(map[string]*SpecFrontmatter),
+		Root:         FindRepoRoot(basePath),
 	}
 
 	// Load requirements
@@ -358,9 +386,58 @@ func ParseYAMLFrontmatter(filePath string) *SpecFrontmatter {
 		}
 	}
 
+	// Parse code and tests (TASK implementation links)
+	result.Code = parseFrontmatterList(yaml, "code")
+	result.Tests = parseFrontmatterList(yaml, "tests")
+	result.Commits = parseFrontmatterList(yaml, "commits")
+
 	return result
 }
 
+// parseFrontmatterList returns the values of a top-level frontmatter key
+// written as a block list, a flow list ([a, b]) or a single value.
+func parseFrontmatterList(yaml, key string) []string {
+	lines := strings.Split(yaml, "\n")
+	for i, line := range lines {
+		rest, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), key+":")
+		if !ok {
+			continue
+		}
+
+		var values []string
+		add := func(value string) {
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+
+		inline := strings.TrimSpace(rest)
+		switch {
+		case strings.HasPrefix(inline, "[") && strings.HasSuffix(inline, "]"):
+			for _, item := range strings.Split(inline[1:len(inline)-1], ",") {
+				add(item)
+			}
+		case inline != "":
+			add(inline)
+		default:
+			for _, line := range lines[i+1:] {
+				line = strings.TrimRight(line, "\r")
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				item := strings.TrimSpace(line)
+				if !strings.HasPrefix(item, "-") || line == item {
+					break
+				}
+				add(strings.TrimPrefix(item, "-"))
+			}
+		}
+		return values
+	}
+	return nil
+}
+
 // TraceabilityTestResult holds the results of traceability rule testing.
 type TraceabilityTestResult struct {
 	Errors      []TraceabilityIssue
@@ -509,9 +586,142 @@ func TestTraceabilityRules(specs *SpecCollection) TraceabilityTestResult {
 		}
 	}
 
+	// Rules 6 and 7: Implementation and Test Links
+	if specs.Root != "" {
+		checkTaskLinks(specs, &result)
+	}
+
+	// Status propagation
+	rules := specs.StatusRules
+	if rules == nil {
+		rules = DefaultStatusRules()
+	}
+	checkStatusRules(specs, rules, &result)
+
 	return result
 }
 
+// setIssueFiles points each issue at the file of the spec it is about.
+func setIssueFiles(specs *SpecCollection, lists ...[]TraceabilityIssue) {
+	for _, issues := range lists {
+		for i := range issues {
+			if spec, ok := specs.All[issues[i].Source]; ok {
+				issues[i].File = spec.FilePath
+			}
+		}
+	}
+}
+
+// checkTaskLinks checks that the code and test paths each TASK declares
+// exist inside the repository, and that its tests mention the task ID.
+func checkTaskLinks(specs *SpecCollection, result *TraceabilityTestResult) {
+	for _, taskID := range sortedSpecIDs(specs.Tasks) {
+		task := specs.Tasks[taskID]
+		issue := func(rule, target, message string) TraceabilityIssue {
+			return TraceabilityIssue{Rule: rule, Source: taskID, Target: target, Message: message}
+		}
+
+		for _, path := range task.Code {
+			switch {
+			case !insideTraceRoot(path):
+				result.Errors = append(result.Errors, issue("Rule 6: Implementation Links", path,
+					"TASK '"+taskID+"' declares code '"+path+"' outside the repository"))
+			case len(resolveTracePath(specs.Root, path)) == 0:
+				result.Errors = append(result.Errors, issue("Rule 6: Implementation Links", path,
+					"TASK '"+taskID+"' declares code '"+path+"' that does not exist"))
+			}
+		}
+
+		for _, path := range task.Tests {
+			if !insideTraceRoot(path) {
+				result.Errors = append(result.Errors, issue("Rule 7: Test Links", path,
+					"TASK '"+taskID+"' declares test '"+path+"' outside the repository"))
+				continue
+			}
+			matches := resolveTracePath(specs.Root, path)
+			if len(matches) == 0 {
+				result.Errors = append(result.Errors, issue("Rule 7: Test Links", path,
+					"TASK '"+taskID+"' declares test '"+path+"' that does not exist"))
+				continue
+			}
+			files := expandTestDirs(specs.Root, matches)
+			if len(files) == 0 {
+				result.Errors = append(result.Errors, issue("Rule 7: Test Links", path,
+					"TASK '"+taskID+"' declares test '"+path+"' that contains no test files"))
+				continue
+			}
+			for _, file := range files {
+				content, err := os.ReadFile(filepath.Join(specs.Root, filepath.FromSlash(file)))
+				if err != nil {
+					result.Warnings = append(result.Warnings, issue("Rule 7: Test Links", file,
+						"Test '"+file+"' could not be read: "+err.Error()))
+				} else if !containsWord(string(content), taskID) {
+					result.Warnings = append(result.Warnings, issue("Rule 7: Test Links", file,
+						"Test '"+file+"' does not reference TASK '"+taskID+"'"))
+				}
+			}
+		}
+	}
+}
+
+// insideTraceRoot reports whether a declared code or test path stays inside
+// the repository: it is relative and does not climb out with "..".
+func insideTraceRoot(path string) bool {
+	clean := filepath.Clean(filepath.FromSlash(path))
+	return !filepath.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
+
+// resolveTracePath returns the files or directories a declared code or test
+// path names, relative to root with forward slashes. The path may be a glob.
+// Paths outside root resolve to nothing.
+func resolveTracePath(root, path string) []string {
+	if !insideTraceRoot(path) {
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(path)))
+	if err != nil {
+		return nil
+	}
+	var resolved []string
+	for _, match := range matches {
+		if rel, err := filepath.Rel(root, match); err == nil && insideTraceRoot(rel) {
+			resolved = append(resolved, filepath.ToSlash(rel))
+		}
+	}
+	return resolved
+}
+
+// expandTestDirs replaces each directory among paths, relative to root, with
+// the test files under it.
+func expandTestDirs(root string, paths []string) []string {
+	var files []string
+	for _, path := range paths {
+		dir := filepath.Join(root, filepath.FromSlash(path))
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if p != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules" || d.Name() == "vendor") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if isTestFile(p) {
+				if rel, err := filepath.Rel(root, p); err == nil {
+					files = append(files, filepath.ToSlash(rel))
+				}
+			}
+			return nil
+		})
+	}
+	return files
+}
+
 // FormatTraceabilityResults formats the validation results in the specified format.
 // format can be "console", "markdown", or "json".
 func FormatTraceabilityResults(result TraceabilityValidationResult, format string) string {
@@ -552,6 +762,7 @@ func formatTraceabilityMarkdown(result TraceabilityValidationResult) string {
 		sb.WriteString("## Errors\n\n")
 		for _, e := range result.Errors {
 			sb.WriteString("- **" + e.Rule + "**: " + e.Message + "\n")
+			writeSuggestion(&sb, "  - ", e)
 		}
 		sb.WriteString("\n")
 	}
@@ -560,6 +771,7 @@ func formatTraceabilityMarkdown(result TraceabilityValidationResult) string {
 		sb.WriteString("## Warnings\n\n")
 		for _, w := range result.Warnings {
 			sb.WriteString("- **" + w.Rule + "**: " + w.Message + "\n")
+			writeSuggestion(&sb, "  - ", w)
 		}
 		sb.WriteString("\n")
 	}
@@ -590,6 +802,7 @@ func formatTraceabilityConsole(result TraceabilityValidationResult) string {
 		sb.WriteString("ERRORS (" + Itoa(len(result.Errors)) + "):\n")
 		for _, e := range result.Errors {
 			sb.WriteString("  [" + e.Rule + "] " + e.Message + "\n")
+			writeSuggestion(&sb, "    ", e)
 		}
 		sb.WriteString("\n")
 	}
@@ -598,6 +811,7 @@ func formatTraceabilityConsole(result TraceabilityValidationResult) string {
 		sb.WriteString("WARNINGS (" + Itoa(len(result.Warnings)) + "):\n")
 		for _, w := range result.Warnings {
 			sb.WriteString("  [" + w.Rule + "] " + w.Message + "\n")
+			writeSuggestion(&sb, "    ", w)
 		}
 		sb.WriteString("\n")
 	}
@@ -617,6 +831,13 @@ func formatTraceabilityConsole(result TraceabilityValidationResult) string {
 	return sb.String()
 }
 
+// writeSuggestion writes the suggested correction of an issue, if any.
+func writeSuggestion(sb *strings.Builder, prefix string, issue TraceabilityIssue) {
+	if issue.Suggestion != "" {
+		sb.WriteString(prefix + "Suggested: " + issue.Suggestion + "\n")
+	}
+}
+
 // ValidateTraceabilityFromContent validates traceability from in-memory spec content.
 // Useful for testing or when content is already loaded.
 func ValidateTraceabilityFromContent(requirements, designs, tasks map[string]*SpecFrontmatter, strict bool) TraceabilityValidationResult {
@@ -656,6 +877,10 @@ func ValidateTraceabilityFromContent(requirements, designs, tasks map[string]*Sp
 	result.Warnings = testResult.Warnings
 	result.Info = testResult.Info
 	result.Stats.ValidChains = testResult.ValidChains
+	graph := BuildTraceabilityGraph(specs)
+	result.Graph = &graph
+
+	setIssueFiles(specs, result.Errors, result.Warnings, result.Info)
 
 	// Build checks
 	var checks []Check
@@ -1065,3 +1065,116 @@ func TestValidateTraceability_ImplementedStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestParseYAMLFrontmatter_CodeAndTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `---
+type: task
+id: TASK-012
+status: in-progress
+related:
+  - DESIGN-003
+code:
+  - src/search/index.ts
+  - "src/search/query.ts"
+tests: [src/search/index.test.ts, tests/search/*.test.ts]
+---
+# Task
+`
+	filePath := createSpecFile(t, tmpDir, "TASK-012-search.md", content)
+
+	spec := internal.ParseYAMLFrontmatter(filePath)
+
+	if spec == nil {
+		t.Fatal("Expected spec to be parsed")
+	}
+	if got := strings.Join(spec.Code, ","); got != "src/search/index.ts,src/search/query.ts" {
+		t.Errorf("Code = %v", spec.Code)
+	}
+	if got := strings.Join(spec.Tests, ","); got != "src/search/index.test.ts,tests/search/*.test.ts" {
+		t.Errorf("Tests = %v", spec.Tests)
+	}
+	if len(spec.Related) != 1 || spec.Related[0] != "DESIGN-003" {
+		t.Errorf("Related = %v", spec.Related)
+	}
+}
+
+func TestValidateTraceability_TaskLinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	specsDir := createSpecsStructure(t, tmpDir)
+	createSpecFile(t, filepath.Join(specsDir, "requirements"), "REQ-001.md", "---\ntype: requirement\nid: REQ-001\nstatus: approved\n---\n")
+	createSpecFile(t, filepath.Join(specsDir, "design"), "DESIGN-001.md", "---\ntype: design\nid: DESIGN-001\nstatus: approved\nrelated:\n  - REQ-001\n---\n")
+	createSpecFile(t, filepath.Join(specsDir, "tasks"), "TASK-001.md", `---
+type: task
+id: TASK-001
+status: in-progress
+related:
+  - DESIGN-001
+code:
+  - src/*.go
+  - src/gone.go
+  - ../shared/util.go
+tests:
+  - src/a_test.go
+  - src/b_test.go
+  - src/missing_test.go
+  - e2e
+  - ../outside_test.go
+---
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "src", "a.go"), "package src\n")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "a_test.go"), "package src\n\n// Covers TASK-001.\n")
+	writeTestFile(t, filepath.Join(tmpDir, "src", "b_test.go"), "package src\n\n// Covers TASK-0012.\n")
+	writeTestFile(t, filepath.Join(tmpDir, "e2e", "login.spec.ts"), "// TASK-001\n")
+	writeTestFile(t, filepath.Join(tmpDir, "e2e", "fixtures", "users.json"), "[]\n")
+	writeTestFile(t, filepath.Join(tmpDir, "e2e", "flows", "logout.spec.ts"), "// untraced\n")
+	// Exists, but outside the repository
+	writeTestFile(t, filepath.Join(filepath.Dir(tmpDir), "outside_test.go"), "// TASK-001\n")
+
+	result := internal.ValidateTraceability(specsDir, false)
+
+	var errors, warnings []string
+	for _, e := range result.Errors {
+		errors = append(errors, e.Rule+": "+e.Message)
+	}
+	for _, w := range result.Warnings {
+		warnings = append(warnings, w.Rule+": "+w.Message)
+	}
+	wantErrors := "Rule 6: Implementation Links: TASK 'TASK-001' declares code 'src/gone.go' that does not exist\n" +
+		"Rule 6: Implementation Links: TASK 'TASK-001' declares code '../shared/util.go' outside the repository\n" +
+		"Rule 7: Test Links: TASK 'TASK-001' declares test 'src/missing_test.go' that does not exist\n" +
+		"Rule 7: Test Links: TASK 'TASK-001' declares test '../outside_test.go' outside the repository"
+	if got := strings.Join(errors, "\n"); got != wantErrors {
+		t.Errorf("errors =\n%s\nwant\n%s", got, wantErrors)
+	}
+	wantWarnings := "Rule 7: Test Links: Test 'src/b_test.go' does not reference TASK 'TASK-001'\n" +
+		"Rule 7: Test Links: Test 'e2e/flows/logout.spec.ts' does not reference TASK 'TASK-001'"
+	if got := strings.Join(warnings, "\n"); got != wantWarnings {
+		t.Errorf("warnings =\n%s\nwant\n%s", got, wantWarnings)
+	}
+	if result.Errors[0].File == "" {
+		t.Error("Expected the issue to point at the task spec")
+	}
+
+	if result.Graph == nil {
+		t.Fatal("Expected a traceability graph")
+	}
+	if node, ok := result.Graph.Node("src/gone.go"); !ok || !node.Missing {
+		t.Errorf("src/gone.go node = %+v, %v; want missing", node, ok)
+	}
+	if node, ok := result.Graph.Node("src/a_test.go"); !ok || node.Kind != internal.TraceKindTest {
+		t.Errorf("src/a_test.go node = %+v, %v; want a test also matched by the code glob", node, ok)
+	}
+	if node, ok := result.Graph.Node("e2e/login.spec.ts"); !ok || node.Kind != internal.TraceKindTest {
+		t.Errorf("e2e/login.spec.ts node = %+v, %v; want a test found in the e2e directory", node, ok)
+	}
+	if _, ok := result.Graph.Node("e2e/fixtures/users.json"); ok {
+		t.Error("Expected the fixture in the e2e directory not to be a test")
+	}
+	if rows := result.Graph.Matrix(); len(rows) != 1 || strings.Join(rows[0].Code, ",") != "../shared/util.go,src/a.go,src/gone.go" {
+		t.Errorf("matrix = %+v", rows)
+	}
+}
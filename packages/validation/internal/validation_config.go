@@ -0,0 +1,647 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationConfigPath is where the repository validation config lives,
+// relative to the repository root.
+const ValidationConfigPath = ".brain/validation.yaml"
+
+// Check severities in the validation config.
+const (
+	CheckSeverityError = "error"
+	CheckSeverityWarn  = "warn"
+	CheckSeverityOff   = "off"
+)
+
+// DefaultSuppressionMarker starts an inline suppression comment.
+const DefaultSuppressionMarker = "brain-ignore"
+
+// ValidationConfig is the repository validation config (.brain/validation.yaml).
+type ValidationConfig struct {
+	Version         int                     `json:"version,omitempty"`
+	Severity        map[string]string       `json:"severity,omitempty"`
+	Ignore          []string                `json:"ignore,omitempty"`
+	Languages       map[string]LanguageSpec `json:"languages,omitempty"`
+	SessionProtocol SessionProtocolConfig   `json:"sessionProtocol"`
+	PrePR           PrePRConfig             `json:"prePR"`
+	PRDescription   PRDescriptionConfig     `json:"prDescription"`
+	Suppressions    SuppressionConfig       `json:"suppressions"`
+	Traceability    TraceabilityConfig      `json:"traceability"`
+
+	// Path is the file the config was loaded from; empty for defaults.
+	Path string `json:"-"`
+
+	ignore []*regexp.Regexp
+}
+
+// LanguageSpec declares a test coverage language in the validation config.
+type LanguageSpec struct {
+	Extensions        []string     `json:"extensions"`
+	TestSuffix        string       `json:"testSuffix,omitempty"`
+	TestNames         []string     `json:"testNames,omitempty"`
+	TestPattern       string       `json:"testPattern"`
+	TestLayouts       []TestLayout `json:"testLayouts,omitempty"`
+	InlineTestPattern string       `json:"inlineTestPattern,omitempty"`
+	Ignore            []string     `json:"ignore,omitempty"`
+}
+
+// TraceabilityConfig configures traceability validation.
+type TraceabilityConfig struct {
+	// StatusRules change built-in status rules by name or add new ones.
+	StatusRules []StatusRule `json:"statusRules,omitempty"`
+}
+
+// SuppressionConfig configures inline suppression comments.
+type SuppressionConfig struct {
+	Marker               string `json:"marker,omitempty"`
+	RequireJustification *bool  `json:"requireJustification,omitempty"`
+}
+
+// validationConfigSchema checks .brain/validation.yaml.
+var validationConfigSchema = &yamlSchema{
+	name:   "validation-config.schema.json",
+	label:  "validation config",
+	setter: "SetValidationConfigSchemaData",
+}
+
+// SetValidationConfigSchemaData sets the schema data for validation config files.
+func SetValidationConfigSchemaData(data []byte) {
+	validationConfigSchema.data = data
+}
+
+// yamlSchema is the JSON schema of a YAML file, compiled the first time it
+// is used.
+type yamlSchema struct {
+	name   string // resource name of the schema
+	label  string // what the schema describes, for errors
+	setter string // the function that sets data
+
+	once     sync.Once
+	data     []byte
+	compiled *jsonschema.Schema
+	err      error
+}
+
+// compile returns the compiled schema, loading it once.
+func (s *yamlSchema) compile() (*jsonschema.Schema, error) {
+	s.once.Do(func() {
+		if s.data == nil {
+			s.err = fmt.Errorf("%s schema data not set; call %s first", s.label, s.setter)
+			return
+		}
+
+		var schemaDoc any
+		if err := json.Unmarshal(s.data, &schemaDoc); err != nil {
+			s.err = fmt.Errorf("failed to parse %s schema: %w", s.label, err)
+			return
+		}
+
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource(s.name, schemaDoc); err != nil {
+			s.err = fmt.Errorf("failed to add %s schema resource: %w", s.label, err)
+			return
+		}
+
+		s.compiled, s.err = c.Compile(s.name)
+	})
+	return s.compiled, s.err
+}
+
+// decodeYAMLWithSchema validates YAML data against schema and decodes it
+// into out. An empty document leaves out unchanged.
+func decodeYAMLWithSchema(data []byte, schema func() (*jsonschema.Schema, error), out any) error {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse YAML: %w", err)
+	}
+	if doc == nil {
+		return nil
+	}
+
+	// Round-trip through JSON so the schema sees JSON types.
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("convert YAML: %w", err)
+	}
+	var jsonDoc any
+	if err := json.Unmarshal(raw, &jsonDoc); err != nil {
+		return fmt.Errorf("convert YAML: %w", err)
+	}
+
+	compiled, err := schema()
+	if err != nil {
+		return fmt.Errorf("schema error: %w", err)
+	}
+	if err := compiled.Validate(jsonDoc); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// DefaultValidationConfig returns the config used when a repository has no
+// .brain/validation.yaml.
+func DefaultValidationConfig() *ValidationConfig {
+	return &ValidationConfig{Version: 1}
+}
+
+// FindRepoRoot walks up from start to the nearest directory holding a
+// validation config or a .git entry. It returns start if there is none.
+func FindRepoRoot(start string) string {
+	abs, err := filepath.Abs(start)
+	if err != nil {
+		return start
+	}
+	if !DirExists(abs) {
+		abs = filepath.Dir(abs)
+	}
+	for dir := abs; ; {
+		if FileExists(filepath.Join(dir, ValidationConfigPath)) {
+			return dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		dir = parent
+	}
+}
+
+// LoadValidationConfig reads .brain/validation.yaml from root. A missing file
+// yields DefaultValidationConfig; an invalid one is an error.
+func LoadValidationConfig(root string) (*ValidationConfig, error) {
+	path := filepath.Join(root, ValidationConfigPath)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultValidationConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseValidationConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Path = path
+	return cfg, nil
+}
+
+// ParseValidationConfig validates YAML config data against the schema and
+// parses it.
+func ParseValidationConfig(data []byte) (*ValidationConfig, error) {
+	cfg := DefaultValidationConfig()
+	if err := decodeYAMLWithSchema(data, validationConfigSchema.compile, cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.compile(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// compile checks the regular expressions and globs the schema cannot.
+func (c *ValidationConfig) compile() error {
+	for _, glob := range c.Ignore {
+		re, err := globRegexp(glob)
+		if err != nil {
+			return fmt.Errorf("ignore %q: %w", glob, err)
+		}
+		c.ignore = append(c.ignore, re)
+	}
+	for name, lang := range c.Languages {
+		if _, err := regexp.Compile(lang.TestPattern); err != nil {
+			return fmt.Errorf("languages.%s.testPattern: %w", name, err)
+		}
+		if _, err := regexp.Compile(lang.InlineTestPattern); err != nil {
+			return fmt.Errorf("languages.%s.inlineTestPattern: %w", name, err)
+		}
+		for _, pattern := range lang.Ignore {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("languages.%s.ignore: %w", name, err)
+			}
+		}
+	}
+	sp := c.SessionProtocol
+	if sp.FilenamePattern != "" {
+		if _, err := regexp.Compile(sp.FilenamePattern); err != nil {
+			return fmt.Errorf("sessionProtocol.filenamePattern: %w", err)
+		}
+	}
+	for _, pattern := range sp.CommitSHAPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("sessionProtocol.commitShaPatterns: %w", err)
+		}
+	}
+	if err := CheckStatusRules(c.StatusRules()); err != nil {
+		return fmt.Errorf("traceability.statusRules: %w", err)
+	}
+	return nil
+}
+
+// globRegexp converts a path glob to a regular expression. * and ? stay
+// within one path segment; ** spans directories.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimPrefix(filepath.ToSlash(glob), "./")
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch ch := glob[i]; ch {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	// A directory pattern also matches everything below it.
+	sb.WriteString("(?:/.*)?$")
+	return regexp.Compile(sb.String())
+}
+
+// Ignored reports whether path, relative to the repository root, matches an
+// ignore pattern.
+func (c *ValidationConfig) Ignored(path string) bool {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	for _, re := range c.ignore {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// IgnoredIn reports whether path is ignored, making it relative to root
+// first when it is absolute.
+func (c *ValidationConfig) IgnoredIn(root, path string) bool {
+	if filepath.IsAbs(path) && root != "" {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+		path = rel
+	}
+	return c.Ignored(path)
+}
+
+// IgnorePatterns returns the ignore globs as regular expressions over
+// absolute paths below root, for validators that take regexp ignore lists.
+func (c *ValidationConfig) IgnorePatterns(root string) []string {
+	prefix := "^" + regexp.QuoteMeta(filepath.ToSlash(root)+"/")
+	patterns := make([]string, 0, len(c.ignore))
+	for _, re := range c.ignore {
+		patterns = append(patterns, prefix+strings.TrimPrefix(re.String(), "^"))
+	}
+	return patterns
+}
+
+// SeverityFor returns the configured severity of check in validator, or ""
+// when none is set. "validator/check" wins over "check", which wins over
+// "validator".
+func (c *ValidationConfig) SeverityFor(validator, check string) string {
+	if check != "" {
+		if s, ok := c.Severity[validator+"/"+check]; ok {
+			return s
+		}
+		if s, ok := c.Severity[check]; ok {
+			return s
+		}
+	}
+	return c.Severity[validator]
+}
+
+// LanguageConfigs returns the coverage languages the config declares.
+func (c *ValidationConfig) LanguageConfigs() map[string]LanguageConfig {
+	if len(c.Languages) == 0 {
+		return nil
+	}
+	langs := make(map[string]LanguageConfig, len(c.Languages))
+	for name, spec := range c.Languages {
+		lang := LanguageConfig{
+			Extensions:    spec.Extensions,
+			TestSuffix:    spec.TestSuffix,
+			TestPattern:   regexp.MustCompile(spec.TestPattern), // checked in compile
+			DefaultIgnore: spec.Ignore,
+			TestNames:     spec.TestNames,
+			TestLayouts:   spec.TestLayouts,
+		}
+		if spec.InlineTestPattern != "" {
+			lang.InlineTests = regexp.MustCompile(spec.InlineTestPattern)
+		}
+		langs[name] = lang
+	}
+	return langs
+}
+
+// SessionProtocolConfig returns DefaultSessionProtocolConfig with the
+// configured overrides applied.
+func (c *ValidationConfig) SessionProtocolConfig() SessionProtocolConfig {
+	cfg := DefaultSessionProtocolConfig
+	o := c.SessionProtocol
+	if o.FilenamePattern != "" {
+		cfg.FilenamePattern = o.FilenamePattern
+	}
+	overrideList(&cfg.RequiredSections, o.RequiredSections)
+	overrideList(&cfg.BrainInitializationPatterns, o.BrainInitializationPatterns)
+	overrideList(&cfg.BrainUpdatePatterns, o.BrainUpdatePatterns)
+	overrideList(&cfg.BranchPatterns, o.BranchPatterns)
+	overrideList(&cfg.BranchPlaceholders, o.BranchPlaceholders)
+	overrideList(&cfg.CommitSHAPatterns, o.CommitSHAPatterns)
+	overrideList(&cfg.LintEvidencePatterns, o.LintEvidencePatterns)
+	return cfg
+}
+
+// PrePRConfig returns DefaultPrePRConfig for basePath with the configured
+// directories and env files.
+func (c *ValidationConfig) PrePRConfig(basePath string) PrePRConfig {
+	cfg := DefaultPrePRConfig(basePath)
+	o := c.PrePR
+	overrideList(&cfg.SourceDirs, o.SourceDirs)
+	overrideList(&cfg.TestDirs, o.TestDirs)
+	overrideList(&cfg.EnvFiles, o.EnvFiles)
+	return cfg
+}
+
+// PRDescriptionConfig returns the PR description config for description and
+// files, with the configured overrides applied.
+func (c *ValidationConfig) PRDescriptionConfig(description string, files []string) PRDescriptionConfig {
+	cfg := DefaultPRDescriptionConfig()
+	cfg.Description = description
+	cfg.FilesInPR = files
+	o := c.PRDescription
+	overrideList(&cfg.SignificantExtensions, o.SignificantExtensions)
+	overrideList(&cfg.SignificantPaths, o.SignificantPaths)
+	overrideList(&cfg.RequiredSections, o.RequiredSections)
+	if o.ValidateChecklist != nil {
+		cfg.ValidateChecklist = o.ValidateChecklist
+	}
+	return cfg
+}
+
+// StatusRules returns DefaultStatusRules with the configured rules merged in.
+func (c *ValidationConfig) StatusRules() []StatusRule {
+	return MergeStatusRules(DefaultStatusRules(), c.Traceability.StatusRules)
+}
+
+func overrideList(dst *[]string, src []string) {
+	if len(src) > 0 {
+		*dst = src
+	}
+}
+
+// ConfigurableResult is a validator result the validation config can be
+// applied to; every result that embeds ValidationResult is one.
+type ConfigurableResult interface {
+	Reportable
+	result() *ValidationResult
+}
+
+func (r *ValidationResult) result() *ValidationResult {
+	return r
+}
+
+// ApplyToResult applies severities, ignores and inline suppressions to the
+// result of validator, as ApplyToReport does to each report entry. Ignore
+// globs are relative to root; relative finding paths resolve against dir.
+// When the config changes the outcome, the checks of r are replaced by its
+// remaining findings. A validator set to off passes with nothing checked.
+func (c *ValidationConfig) ApplyToResult(root, dir, validator string, r ConfigurableResult) {
+	out := r.result()
+	if c.Severity[validator] == CheckSeverityOff {
+		*out = ValidationResult{Valid: true, Message: "Disabled in " + ValidationConfigPath}
+		return
+	}
+
+	v := NewValidatorReport(validator, "", r)
+	if !c.applyToFindings(root, dir, &v, map[string][]string{}) {
+		return
+	}
+	checks := make([]Check, 0, len(v.Findings))
+	for _, f := range v.Findings {
+		checks = append(checks, findingCheck(f))
+	}
+	out.Checks = checks
+	out.Valid = v.Valid
+	if out.Valid {
+		out.Remediation = ""
+	}
+}
+
+// findingCheck converts a finding back into a check that names its location.
+func findingCheck(f Finding) Check {
+	message := f.Message
+	if f.File != "" {
+		at := f.File
+		if f.Line > 0 {
+			at += ":" + Itoa(f.Line)
+		}
+		message = at + " - " + message
+	}
+	if f.Passed && f.Severity == SeverityWarning {
+		message += " (warning)"
+	}
+	return Check{Name: f.Check, Passed: f.Passed, Message: message}
+}
+
+// ApplyToReport applies severities, ignores and inline suppressions to every
+// entry of report.
+func (c *ValidationConfig) ApplyToReport(report *Report) {
+	sources := map[string][]string{}
+	report.Valid = true
+	for i := range report.Validators {
+		v := &report.Validators[i]
+		if v.Skipped == "" {
+			if c.Severity[v.Validator] == CheckSeverityOff {
+				*v = ValidatorReport{Validator: v.Validator, Target: v.Target, Skipped: "disabled in " + ValidationConfigPath, Valid: true}
+			} else {
+				c.applyToFindings(report.Root, report.Root, v, sources)
+			}
+		}
+		report.Valid = report.Valid && v.Valid
+	}
+}
+
+// applyToFindings drops the findings of ignored files and checks set to off,
+// applies the other severities and moves suppressed failures to
+// v.Suppressed. It reports whether anything changed.
+func (c *ValidationConfig) applyToFindings(root, dir string, v *ValidatorReport, sources map[string][]string) bool {
+	root = absPath(root)
+	changed := false
+	findings := v.Findings[:0:0]
+	for _, f := range v.Findings {
+		path := f.File
+		if path != "" && !filepath.IsAbs(path) {
+			path = absPath(filepath.Join(dir, path))
+		}
+		if path != "" && c.IgnoredIn(root, path) {
+			changed = true
+			continue
+		}
+
+		switch c.SeverityFor(v.Validator, f.Check) {
+		case CheckSeverityOff:
+			changed = true
+			continue
+		case CheckSeverityWarn:
+			if !f.Passed {
+				f.Passed, f.Severity = true, SeverityWarning
+				changed = true
+			}
+		case CheckSeverityError:
+			if f.Severity == SeverityWarning {
+				f.Passed, f.Severity = false, SeverityError
+				changed = true
+			}
+		}
+
+		if !f.Passed && path != "" && f.Line > 0 {
+			s, ok := c.suppression(path, f, v.Validator, sources)
+			switch {
+			case ok && s.justified():
+				v.Suppressed = append(v.Suppressed, f)
+				changed = true
+				continue
+			case ok:
+				findings = append(findings, Finding{
+					Check:    "suppression_justification",
+					Severity: SeverityError,
+					Message:  "Suppression of " + f.Check + " needs a justification: '" + c.marker() + " " + s.checks + ": <reason>'",
+					File:     f.File,
+					Line:     s.line,
+				})
+				changed = true
+			}
+		}
+		findings = append(findings, f)
+	}
+	if !changed {
+		return false
+	}
+	v.Findings = findings
+	v.Valid = true
+	for _, f := range findings {
+		v.Valid = v.Valid && f.Passed
+	}
+	return true
+}
+
+// absPath returns path made absolute, or path itself when that fails.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// suppressionComment is a parsed '<marker> checks: justification' comment.
+type suppressionComment struct {
+	checks        string
+	justification string
+	line          int
+	required      bool
+}
+
+func (s suppressionComment) justified() bool {
+	return !s.required || s.justification != ""
+}
+
+func (c *ValidationConfig) marker() string {
+	if c.Suppressions.Marker != "" {
+		return c.Suppressions.Marker
+	}
+	return DefaultSuppressionMarker
+}
+
+func (c *ValidationConfig) requireJustification() bool {
+	return c.Suppressions.RequireJustification == nil || *c.Suppressions.RequireJustification
+}
+
+// suppression finds a comment suppressing f, in the file at path, on its
+// line or the line above.
+func (c *ValidationConfig) suppression(path string, f Finding, validator string, sources map[string][]string) (suppressionComment, bool) {
+	lines, ok := sources[path]
+	if !ok {
+		lines = readLines(path)
+		sources[path] = lines
+	}
+
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(c.marker()) + `\s+([\w*/,-]+)\s*(?::\s*(.*))?$`)
+	for _, n := range []int{f.Line, f.Line - 1} {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+		m := re.FindStringSubmatch(lines[n-1])
+		if m == nil || !suppressesCheck(m[1], validator, f.Check) {
+			continue
+		}
+		return suppressionComment{
+			checks:        m[1],
+			justification: trimCommentCloser(m[2]),
+			line:          n,
+			required:      c.requireJustification(),
+		}, true
+	}
+	return suppressionComment{}, false
+}
+
+// suppressesCheck reports whether a comma-separated check list names check.
+func suppressesCheck(list, validator, check string) bool {
+	for _, name := range strings.Split(list, ",") {
+		if name == "*" || name == check || name == validator+"/"+check {
+			return true
+		}
+	}
+	return false
+}
+
+// trimCommentCloser strips a trailing '-->' or '*/' from a justification.
+func trimCommentCloser(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "-->")
+	s = strings.TrimSuffix(s, "*/")
+	return strings.TrimSpace(s)
+}
+
+func readLines(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
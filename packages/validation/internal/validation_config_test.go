@@ -0,0 +1,323 @@
+package internal_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/peterkloss/brain/packages/validation/internal"
+)
+
+func init() {
+	// Load schema data for tests
+	_, currentFile, _, _ := runtime.Caller(0)
+	packageRoot := filepath.Dir(filepath.Dir(currentFile))
+	schemaPath := filepath.Join(packageRoot, "schemas", "config", "validation-config.schema.json")
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		panic("failed to load validation config schema for tests: " + err.Error())
+	}
+	internal.SetValidationConfigSchemaData(data)
+}
+
+func mustParseConfig(t *testing.T, yaml string) *internal.ValidationConfig {
+	t.Helper()
+	cfg, err := internal.ParseValidationConfig([]byte(yaml))
+	if err != nil {
+		t.Fatalf("ParseValidationConfig: %v", err)
+	}
+	return cfg
+}
+
+func TestParseValidationConfig_Valid(t *testing.T) {
+	cfg := mustParseConfig(t, `
+version: 1
+severity:
+  ci_environment: warn
+  pre-pr/fail_safe_design: off
+ignore:
+  - vendor/**
+  - "docs/*.md"
+languages:
+  elixir:
+    extensions: [".ex"]
+    testSuffix: "_test.exs"
+    testPattern: "_test\\.exs$"
+sessionProtocol:
+  filenamePattern: "^LOG-.+\\.md$"
+  brainInitializationPatterns: ["brain ready"]
+prePR:
+  sourceDirs: [app]
+  envFiles: [.env.sample]
+prDescription:
+  requiredSections: ["Why"]
+suppressions:
+  marker: nolint-brain
+`)
+
+	if got := cfg.SeverityFor("pre-pr", "ci_environment"); got != "warn" {
+		t.Errorf("SeverityFor(ci_environment) = %q, want warn", got)
+	}
+	if got := cfg.SeverityFor("pre-pr", "fail_safe_design"); got != "off" {
+		t.Errorf("SeverityFor(pre-pr/fail_safe_design) = %q, want off", got)
+	}
+
+	sp := cfg.SessionProtocolConfig()
+	if sp.FilenamePattern != `^LOG-.+\.md$` || len(sp.BrainInitializationPatterns) != 1 {
+		t.Errorf("session protocol overrides not applied: %+v", sp)
+	}
+	if len(sp.RequiredSections) != len(internal.DefaultSessionProtocolConfig.RequiredSections) {
+		t.Error("unset session protocol fields should keep their defaults")
+	}
+	pre := cfg.PrePRConfig("repo")
+	if pre.BasePath != "repo" || len(pre.SourceDirs) != 1 || pre.SourceDirs[0] != "app" || pre.EnvFiles[0] != ".env.sample" {
+		t.Errorf("pre-PR overrides not applied: %+v", pre)
+	}
+	if len(pre.TestDirs) != len(internal.PrePRConfigDefaults.TestDirs) {
+		t.Error("unset pre-PR fields should keep their defaults")
+	}
+	if pr := cfg.PRDescriptionConfig("body", nil); len(pr.RequiredSections) != 1 || pr.RequiredSections[0] != "Why" {
+		t.Errorf("PR description overrides not applied: %+v", pr.RequiredSections)
+	}
+
+	lang, ok := cfg.LanguageConfigs()["elixir"]
+	if !ok || !lang.TestPattern.MatchString("lib/foo_test.exs") {
+		t.Errorf("elixir language = %+v", lang)
+	}
+}
+
+func TestParseValidationConfig_LanguageLayouts(t *testing.T) {
+	cfg := mustParseConfig(t, `
+languages:
+  scala:
+    extensions: [".scala"]
+    testNames: ["{name}Spec.scala", "{name}Test.scala"]
+    testPattern: "(Spec|Test)\\.scala$"
+    testLayouts:
+      - {source: src/main, test: src/test}
+      - {}
+    inlineTestPattern: "@inline-test"
+`)
+
+	lang := cfg.LanguageConfigs()["scala"]
+	if len(lang.TestNames) != 2 || lang.TestNames[0] != "{name}Spec.scala" {
+		t.Errorf("TestNames = %v", lang.TestNames)
+	}
+	if len(lang.TestLayouts) != 2 || lang.TestLayouts[0] != (internal.TestLayout{Source: "src/main", Test: "src/test"}) {
+		t.Errorf("TestLayouts = %+v", lang.TestLayouts)
+	}
+	if lang.InlineTests == nil || !lang.InlineTests.MatchString("// @inline-test") {
+		t.Errorf("InlineTests = %v", lang.InlineTests)
+	}
+}
+
+func TestParseValidationConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown key", "colour: blue\n"},
+		{"bad severity", "severity:\n  ci_environment: loud\n"},
+		{"bad version", "version: 2\n"},
+		{"pre-PR flag", "prePR:\n  quickMode: true\n"},
+		{"bad regexp", "sessionProtocol:\n  filenamePattern: \"([\"\n"},
+		{"language without pattern", "languages:\n  elixir:\n    extensions: [\".ex\"]\n"},
+		{"language without test names", "languages:\n  elixir:\n    extensions: [\".ex\"]\n    testPattern: x\n"},
+		{"layout without test", "languages:\n  elixir:\n    extensions: [\".ex\"]\n    testSuffix: _test.exs\n    testPattern: x\n    testLayouts: [{source: lib}]\n"},
+		{"bad inline pattern", "languages:\n  elixir:\n    extensions: [\".ex\"]\n    testSuffix: _test.exs\n    testPattern: x\n    inlineTestPattern: \"([\"\n"},
+		{"not YAML", "severity: [\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := internal.ParseValidationConfig([]byte(tt.yaml)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadValidationConfig_MissingFileIsDefault(t *testing.T) {
+	cfg, err := internal.LoadValidationConfig(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Path != "" || len(cfg.Severity) != 0 {
+		t.Errorf("default config = %+v", cfg)
+	}
+}
+
+func TestFindRepoRoot(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, internal.ValidationConfigPath), "version: 1\n")
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := internal.FindRepoRoot(nested); got != root {
+		t.Errorf("FindRepoRoot = %q, want %q", got, root)
+	}
+}
+
+func TestValidationConfig_Ignored(t *testing.T) {
+	cfg := mustParseConfig(t, "ignore: [vendor/**, \"docs/*.md\", generated]\n")
+	for path, want := range map[string]bool{
+		"vendor/x/y.go":      true,
+		"docs/readme.md":     true,
+		"docs/deep/x.md":     false,
+		"generated/types.ts": true,
+		"src/generated.go":   false,
+		"./vendor/a.go":      true,
+	} {
+		if got := cfg.Ignored(path); got != want {
+			t.Errorf("Ignored(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestValidationConfig_ApplyToResult(t *testing.T) {
+	cfg := mustParseConfig(t, "severity:\n  ci_environment: warn\n  test_implementation: off\n")
+	r := internal.ValidationResult{
+		Valid:       false,
+		Remediation: "Fix the CI",
+		Checks: []internal.Check{
+			{Name: "ci_environment", Passed: false, Message: "No CI configuration found"},
+			{Name: "test_implementation", Passed: false},
+			{Name: "fail_safe_design", Passed: true},
+		},
+	}
+	cfg.ApplyToResult("", "", "pre-pr", &r)
+	if !r.Valid || len(r.Checks) != 2 || r.Remediation != "" {
+		t.Errorf("result = %+v", r)
+	}
+	if !strings.HasSuffix(r.Checks[0].Message, "(warning)") {
+		t.Errorf("warned check message = %q", r.Checks[0].Message)
+	}
+
+	off := mustParseConfig(t, "severity:\n  pre-pr: off\n")
+	r = internal.ValidationResult{Valid: false}
+	off.ApplyToResult("", "", "pre-pr", &r)
+	if !r.Valid {
+		t.Error("a disabled validator should pass")
+	}
+}
+
+func TestValidationConfig_ApplyToResult_Findings(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "a.md"), "<!-- brain-ignore skill_violation: documents the raw command -->\ngh pr create\n")
+	writeTestFile(t, filepath.Join(root, "c.md"), "one\ntwo\ngh pr create\n")
+	cfg := mustParseConfig(t, "ignore: [b.md]\n")
+
+	r := violationResult()
+	cfg.ApplyToResult(root, root, "skill-violations", &r)
+	if r.Valid || len(r.Checks) != 1 {
+		t.Fatalf("result = %+v", r.ValidationResult)
+	}
+	if c := r.Checks[0]; c.Name != "skill_violation" || !strings.HasPrefix(c.Message, "c.md:3 - ") {
+		t.Errorf("check = %+v", c)
+	}
+
+	// Relative paths resolve against dir, globs against root.
+	writeTestFile(t, filepath.Join(root, "sub", "c.md"), "one\ntwo\ngh pr create\n")
+	cfg = mustParseConfig(t, "ignore: [\"sub/**\", a.md, b.md]\n")
+	r = violationResult()
+	cfg.ApplyToResult(root, filepath.Join(root, "sub"), "skill-violations", &r)
+	if !r.Valid || r.Remediation != "" {
+		t.Errorf("result = %+v", r.ValidationResult)
+	}
+}
+
+func violationResult() internal.SkillViolationResult {
+	return internal.SkillViolationResult{
+		ValidationResult: internal.ValidationResult{Valid: false, Remediation: "Use the skills"},
+		Violations: []internal.SkillViolation{
+			{File: "a.md", Line: 2, Pattern: "gh pr create"},
+			{File: "b.md", Line: 1, Pattern: "gh pr create"},
+			{File: "c.md", Line: 3, Pattern: "gh pr create"},
+		},
+	}
+}
+
+func violationReport(root string) *internal.Report {
+	report := internal.NewReport(root)
+	report.Add(internal.NewValidatorReport("skill-violations", "", violationResult()))
+	return report
+}
+
+func TestValidationConfig_ApplyToReport_Suppressions(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "a.md"), "<!-- brain-ignore skill_violation: documents the raw command -->\ngh pr create\n")
+	writeTestFile(t, filepath.Join(root, "b.md"), "gh pr create # brain-ignore skill_violation\n")
+	writeTestFile(t, filepath.Join(root, "c.md"), "one\ntwo\ngh pr create\n")
+
+	report := violationReport(root)
+	internal.DefaultValidationConfig().ApplyToReport(report)
+
+	v := report.Validators[0]
+	if len(v.Suppressed) != 1 || v.Suppressed[0].File != "a.md" {
+		t.Errorf("suppressed = %+v, want only a.md", v.Suppressed)
+	}
+	var checks []string
+	for _, f := range v.Findings {
+		checks = append(checks, f.Check+"@"+f.File)
+	}
+	want := "suppression_justification@b.md,skill_violation@b.md,skill_violation@c.md"
+	if strings.Join(checks, ",") != want {
+		t.Errorf("findings = %v, want %s", checks, want)
+	}
+	if report.Valid {
+		t.Error("unsuppressed violations should keep the report invalid")
+	}
+
+	// Without required justifications, b.md is suppressed too.
+	cfg := mustParseConfig(t, "suppressions:\n  requireJustification: false\n")
+	report = violationReport(root)
+	cfg.ApplyToReport(report)
+	if n := len(report.Validators[0].Suppressed); n != 2 {
+		t.Errorf("suppressed %d findings, want 2", n)
+	}
+}
+
+func TestValidationConfig_ApplyToReport_IgnoreAndSeverity(t *testing.T) {
+	root := t.TempDir()
+	cfg := mustParseConfig(t, "ignore: [\"c.md\"]\nseverity:\n  skill_violation: warn\n")
+	report := violationReport(root)
+	cfg.ApplyToReport(report)
+	v := report.Validators[0]
+	if !report.Valid || !v.Valid || len(v.Findings) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	for _, f := range v.Findings {
+		if f.Severity != internal.SeverityWarning || f.File == "c.md" {
+			t.Errorf("finding = %+v", f)
+		}
+	}
+
+	off := mustParseConfig(t, "severity:\n  skill-violations: off\n")
+	report = violationReport(root)
+	off.ApplyToReport(report)
+	if !report.Valid || !strings.Contains(report.Validators[0].Skipped, "disabled") {
+		t.Errorf("disabled validator = %+v", report.Validators[0])
+	}
+}
+
+func TestRegistry_LoadsRepoConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, internal.ValidationConfigPath), "ignore: [\".claude/skills/bad/**\"]\n")
+	writeTestFile(t, filepath.Join(root, ".claude", "skills", "bad", "SKILL.md"), "# No frontmatter\n")
+
+	report, err := internal.DefaultRegistry.Run(context.Background(), "skills", internal.Input{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid || len(report.Validators) != 0 {
+		t.Errorf("ignored skill was validated: %+v", report)
+	}
+
+	writeTestFile(t, filepath.Join(root, internal.ValidationConfigPath), "ignore: 12\n")
+	if _, err := internal.DefaultRegistry.Run(context.Background(), "skills", internal.Input{Root: root}); err == nil {
+		t.Error("an invalid config should fail the run")
+	}
+}